@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"regexp"
 	"strconv"
+	"strings"
 )
 
 const (
@@ -28,6 +30,26 @@ const (
 	redisTimeOut  = "REDIS_TIME_OUT"
 
 	jwtTimeOut = "JWT_TIME_OUT"
+	jwtKeyFile = "JWT_KEY_FILE"
+	jwtSecret  = "JWT_SECRET"
+	jwtKeyID   = "JWT_KEY_ID"
+
+	refreshTokenTimeOut = "REFRESH_TOKEN_TIME_OUT"
+	sessionStoreDriver  = "SESSION_STORE_DRIVER"
+
+	branchCode           = "BRANCH_CODE"
+	contractNumberFormat = "CONTRACT_NUMBER_FORMAT"
+
+	interestMethod = "INTEREST_METHOD"
+	interestRates  = "INTEREST_RATES"
+
+	fileStorageDriver    = "FILE_STORAGE_DRIVER"
+	fileStorageLocalPath = "FILE_STORAGE_LOCAL_PATH"
+
+	piiEncryptionKeyFile    = "PII_ENCRYPTION_KEY_FILE"
+	piiEncryptionKeys       = "PII_ENCRYPTION_KEYS"
+	piiEncryptionKeyVersion = "PII_ENCRYPTION_KEY_VERSION"
+	piiBlindIndexKey        = "PII_BLIND_INDEX_KEY"
 
 	whitelistedIps = "WHITELISTED_IPS"
 
@@ -43,6 +65,9 @@ var (
 	JwtActiveToken *string
 )
 
+// ContractNumberSequenceToken is the {SEQ} or {SEQ:width} token of a contract number format, which holds exactly one
+var ContractNumberSequenceToken = regexp.MustCompile(`\{SEQ(?::(\d+))?\}`)
+
 // Config contains application configuration
 type Config struct {
 	// Actives
@@ -70,6 +95,33 @@ type Config struct {
 
 	JwtTimeOut int
 
+	// JWT signing, either a key file or a single HS256 secret
+	JwtKeyFile string
+	JwtSecret  string
+	JwtKeyID   string
+
+	// Refresh tokens and revoked sessions
+	RefreshTokenTimeOut int
+	SessionStoreDriver  string
+
+	// Contract numbers
+	BranchCode           string
+	ContractNumberFormat string
+
+	// Pricing from before loan products, only used to price the seeded loan products
+	InterestMethod string
+	InterestRates  map[int]float64 // monthly rate in percent, keyed by tenor
+
+	// File storage
+	FileStorageDriver    string
+	FileStorageLocalPath string
+
+	// PII encryption, either a key file or the keys themselves
+	PIIEncryptionKeyFile    string
+	PIIEncryptionKeys       string
+	PIIEncryptionKeyVersion string
+	PIIBlindIndexKey        string
+
 	// Vultr
 	VultrAccessKey string
 	VultrBucket    string
@@ -124,6 +176,45 @@ func GetConfiguration() (*Config, error) {
 		return nil, fmt.Errorf("failed to parse active worker: %v", err)
 	}
 
+	refreshTokenTimeOutString, _ := result[refreshTokenTimeOut].(string)
+	refreshTokenTimeOut := 0
+	if refreshTokenTimeOutString != "" {
+		refreshTokenTimeOut, err = strconv.Atoi(refreshTokenTimeOutString)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse refresh token timeout: %v", err)
+		}
+	}
+
+	// these are optional, library falls back to its defaults when they are empty
+	branchCode, _ := result[branchCode].(string)
+	contractNumberFormat, _ := result[contractNumberFormat].(string)
+	fileStorageDriver, _ := result[fileStorageDriver].(string)
+	fileStorageLocalPath, _ := result[fileStorageLocalPath].(string)
+	piiEncryptionKeyFile, _ := result[piiEncryptionKeyFile].(string)
+	piiEncryptionKeys, _ := result[piiEncryptionKeys].(string)
+	piiEncryptionKeyVersion, _ := result[piiEncryptionKeyVersion].(string)
+	piiBlindIndexKey, _ := result[piiBlindIndexKey].(string)
+	jwtKeyFile, _ := result[jwtKeyFile].(string)
+	jwtSecret, _ := result[jwtSecret].(string)
+	jwtKeyID, _ := result[jwtKeyID].(string)
+	sessionStoreDriver, _ := result[sessionStoreDriver].(string)
+
+	// a bad format would fail every booking, so it is refused here, before the server starts
+	if contractNumberFormat != "" && len(ContractNumberSequenceToken.FindAllString(contractNumberFormat, -1)) != 1 {
+		return nil, fmt.Errorf("contract number format %q must contain exactly one {SEQ} token", contractNumberFormat)
+	}
+
+	interestMethodValue, _ := result[interestMethod].(string)
+	if interestMethodValue == "" {
+		interestMethodValue = "flat"
+	}
+
+	interestRatesValue, _ := result[interestRates].(string)
+	interestRatesMap, err := parseInterestRates(interestRatesValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse interest rates: %v", err)
+	}
+
 	config := &Config{
 		ActiveWorker: activeWorker,
 
@@ -142,6 +233,26 @@ func GetConfiguration() (*Config, error) {
 		RedisTimeOut:  redisTimeOut,
 
 		JwtTimeOut: jwtTimeOut,
+		JwtKeyFile: jwtKeyFile,
+		JwtSecret:  jwtSecret,
+		JwtKeyID:   jwtKeyID,
+
+		RefreshTokenTimeOut: refreshTokenTimeOut,
+		SessionStoreDriver:  sessionStoreDriver,
+
+		BranchCode:           branchCode,
+		ContractNumberFormat: contractNumberFormat,
+
+		InterestMethod: interestMethodValue,
+		InterestRates:  interestRatesMap,
+
+		FileStorageDriver:    fileStorageDriver,
+		FileStorageLocalPath: fileStorageLocalPath,
+
+		PIIEncryptionKeyFile:    piiEncryptionKeyFile,
+		PIIEncryptionKeys:       piiEncryptionKeys,
+		PIIEncryptionKeyVersion: piiEncryptionKeyVersion,
+		PIIBlindIndexKey:        piiBlindIndexKey,
 
 		VultrAccessKey: result[vultrAccessKey].(string),
 		VultrBucket:    result[vultrBucket].(string),
@@ -152,3 +263,34 @@ func GetConfiguration() (*Config, error) {
 
 	return config, nil
 }
+
+// parseInterestRates reads "tenor:rate" pairs separated by commas, e.g. "1:2.5,2:2.5,3:2.25,6:2"
+func parseInterestRates(value string) (map[int]float64, error) {
+	rates := map[int]float64{}
+
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid pair %q", pair)
+		}
+
+		tenor, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid tenor %q", parts[0])
+		}
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil || rate < 0 {
+			return nil, fmt.Errorf("invalid rate %q", parts[1])
+		}
+
+		rates[tenor] = rate
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,19 @@
+CREATE TABLE consumer_installments (
+  id VARCHAR(255) PRIMARY KEY NOT NULL,
+  consumer_transaction_id VARCHAR(255) NOT NULL,
+  installment_number INT UNSIGNED NOT NULL,
+  due_date DATE NOT NULL,
+  principal_amount DECIMAL(12,2) UNSIGNED NOT NULL,
+  interest_amount DECIMAL(12,2) UNSIGNED NOT NULL,
+  fee_amount DECIMAL(12,2) UNSIGNED NOT NULL,
+  installment_amount DECIMAL(12,2) UNSIGNED NOT NULL,
+  outstanding_balance DECIMAL(12,2) UNSIGNED NOT NULL,
+
+  status_id VARCHAR(255) DEFAULT "1",
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  created_by VARCHAR(255) NULL,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_by VARCHAR(255) NULL,
+  INDEX index_consumer_transaction_id (consumer_transaction_id),
+  INDEX index_due_date (due_date)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE consumer_installments
+  ADD COLUMN paid_principal_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0 AFTER outstanding_balance,
+  ADD COLUMN paid_interest_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0 AFTER paid_principal_amount,
+  ADD COLUMN paid_fee_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0 AFTER paid_interest_amount;
@@ -0,0 +1,19 @@
+CREATE TABLE consumer_payments (
+  id VARCHAR(255) PRIMARY KEY NOT NULL,
+  consumer_transaction_id VARCHAR(255) NOT NULL,
+  payment_type VARCHAR(50) NOT NULL,
+  payment_date DATE NOT NULL,
+  amount DECIMAL(12,2) UNSIGNED NOT NULL,
+  reference_number VARCHAR(255) NOT NULL DEFAULT "",
+  reversal_of_id VARCHAR(255) NOT NULL DEFAULT "",
+  reason VARCHAR(255) NOT NULL DEFAULT "",
+
+  status_id VARCHAR(255) DEFAULT "1",
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  created_by VARCHAR(255) NULL,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_by VARCHAR(255) NULL,
+  INDEX index_consumer_transaction_id (consumer_transaction_id),
+  INDEX index_reversal_of_id (reversal_of_id),
+  INDEX index_payment_date (payment_date)
+);
@@ -0,0 +1,16 @@
+CREATE TABLE consumer_payment_allocations (
+  id VARCHAR(255) PRIMARY KEY NOT NULL,
+  consumer_payment_id VARCHAR(255) NOT NULL,
+  consumer_installment_id VARCHAR(255) NOT NULL,
+  fee_amount DECIMAL(12,2) UNSIGNED NOT NULL,
+  interest_amount DECIMAL(12,2) UNSIGNED NOT NULL,
+  principal_amount DECIMAL(12,2) UNSIGNED NOT NULL,
+
+  status_id VARCHAR(255) DEFAULT "1",
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  created_by VARCHAR(255) NULL,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_by VARCHAR(255) NULL,
+  INDEX index_consumer_payment_id (consumer_payment_id),
+  INDEX index_consumer_installment_id (consumer_installment_id)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE consumer_transactions
+  ADD COLUMN interest_method VARCHAR(50) NOT NULL DEFAULT "flat" AFTER interest_amount,
+  ADD COLUMN interest_rate DECIMAL(6,4) UNSIGNED NOT NULL DEFAULT 0 AFTER interest_method;
@@ -0,0 +1,20 @@
+CREATE TABLE loan_products (
+  id VARCHAR(255) PRIMARY KEY NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  tenor INT UNSIGNED NOT NULL,
+  interest_method VARCHAR(50) NOT NULL DEFAULT "flat",
+  interest_rate DECIMAL(6,4) UNSIGNED NOT NULL DEFAULT 0,
+  admin_fee_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,
+  admin_fee_rate DECIMAL(6,4) UNSIGNED NOT NULL DEFAULT 0,
+  min_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,
+  max_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,
+  active_from DATE NULL,
+  active_to DATE NULL,
+
+  status_id VARCHAR(255) DEFAULT "1",
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  created_by VARCHAR(255) NULL,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_by VARCHAR(255) NULL,
+  INDEX index_tenor (tenor)
+);
@@ -0,0 +1,6 @@
+INSERT INTO loan_products (id, name, tenor, status_id)
+VALUES
+  ("5f0d6f4e-4c1a-4b7e-9a61-2f1d8c0b1a01", "1 Month", 1, "0"),
+  ("5f0d6f4e-4c1a-4b7e-9a61-2f1d8c0b1a02", "2 Months", 2, "0"),
+  ("5f0d6f4e-4c1a-4b7e-9a61-2f1d8c0b1a03", "3 Months", 3, "0"),
+  ("5f0d6f4e-4c1a-4b7e-9a61-2f1d8c0b1a06", "6 Months", 6, "0");
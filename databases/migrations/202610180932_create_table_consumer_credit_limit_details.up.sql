@@ -0,0 +1,14 @@
+CREATE TABLE consumer_credit_limit_details (
+  id VARCHAR(255) PRIMARY KEY NOT NULL,
+  consumer_credit_limit_id VARCHAR(255) NOT NULL,
+  loan_product_id VARCHAR(255) NOT NULL,
+  limit_amount DECIMAL(12,2) UNSIGNED NOT NULL,
+
+  status_id VARCHAR(255) DEFAULT "1",
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  created_by VARCHAR(255) NULL,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_by VARCHAR(255) NULL,
+  INDEX index_consumer_credit_limit_id (consumer_credit_limit_id),
+  INDEX index_loan_product_id (loan_product_id)
+);
@@ -0,0 +1,12 @@
+INSERT INTO consumer_credit_limit_details (id, consumer_credit_limit_id, loan_product_id, limit_amount, status_id, created_at, created_by, updated_at, updated_by)
+SELECT
+  UUID(), cl.id, lp.id,
+  CASE lp.tenor
+    WHEN 1 THEN cl.1_month
+    WHEN 2 THEN cl.2_month
+    WHEN 3 THEN cl.3_month
+    WHEN 6 THEN cl.6_month
+  END,
+  cl.status_id, cl.created_at, cl.created_by, cl.updated_at, cl.updated_by
+FROM consumer_credit_limits cl
+JOIN loan_products lp ON lp.tenor IN (1, 2, 3, 6);
@@ -0,0 +1,5 @@
+ALTER TABLE consumer_credit_limits
+  DROP COLUMN 1_month,
+  DROP COLUMN 2_month,
+  DROP COLUMN 3_month,
+  DROP COLUMN 6_month;
@@ -0,0 +1,3 @@
+ALTER TABLE consumer_transactions
+  ADD COLUMN loan_product_id VARCHAR(255) NOT NULL DEFAULT "" AFTER consumer_id,
+  ADD INDEX index_loan_product_id (loan_product_id);
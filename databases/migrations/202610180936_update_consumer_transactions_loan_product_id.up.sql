@@ -0,0 +1,3 @@
+UPDATE consumer_transactions
+JOIN loan_products ON loan_products.tenor = consumer_transactions.loan_term
+SET consumer_transactions.loan_product_id = loan_products.id;
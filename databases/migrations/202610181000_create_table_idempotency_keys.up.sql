@@ -0,0 +1,16 @@
+CREATE TABLE idempotency_keys (
+  id VARCHAR(255) PRIMARY KEY NOT NULL,
+  api_client_id INT NOT NULL,
+  idempotency_key VARCHAR(255) NOT NULL,
+  request_path VARCHAR(255) NOT NULL,
+  request_hash VARCHAR(64) NOT NULL,
+  response_status_code INT NOT NULL DEFAULT 0,
+  response_body MEDIUMTEXT NOT NULL,
+
+  status_id VARCHAR(255) DEFAULT "1",
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  created_by VARCHAR(255) NULL,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_by VARCHAR(255) NULL,
+  UNIQUE INDEX unique_api_client_id_idempotency_key (api_client_id, idempotency_key)
+);
@@ -0,0 +1,5 @@
+CREATE TABLE consumer_transaction_statuses (
+  id VARCHAR(50) PRIMARY KEY NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  consumes_limit TINYINT(1) NOT NULL DEFAULT 0
+);
@@ -0,0 +1,10 @@
+INSERT INTO consumer_transaction_statuses (id, name, consumes_limit)
+VALUES
+  ("pending", "Pending", 1),
+  ("approved", "Approved", 1),
+  ("rejected", "Rejected", 0),
+  ("disbursed", "Disbursed", 1),
+  ("active", "Active", 1),
+  ("paid_off", "Paid Off", 0),
+  ("cancelled", "Cancelled", 0),
+  ("written_off", "Written Off", 1);
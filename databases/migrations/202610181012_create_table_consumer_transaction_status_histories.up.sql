@@ -0,0 +1,13 @@
+CREATE TABLE consumer_transaction_status_histories (
+  id VARCHAR(255) PRIMARY KEY NOT NULL,
+  consumer_transaction_id VARCHAR(255) NOT NULL,
+  from_status_id VARCHAR(50) NOT NULL DEFAULT "",
+  to_status_id VARCHAR(50) NOT NULL,
+
+  status_id VARCHAR(255) DEFAULT "1",
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  created_by VARCHAR(255) NULL,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_by VARCHAR(255) NULL,
+  INDEX index_consumer_transaction_id (consumer_transaction_id)
+);
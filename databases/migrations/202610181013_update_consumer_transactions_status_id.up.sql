@@ -0,0 +1,2 @@
+UPDATE consumer_transactions
+SET status_id = CASE status_id WHEN "1" THEN "active" ELSE "cancelled" END;
@@ -0,0 +1,2 @@
+ALTER TABLE consumer_transactions
+  ALTER COLUMN status_id SET DEFAULT "pending";
@@ -0,0 +1,2 @@
+ALTER TABLE loan_products
+  ADD COLUMN cancellation_window_days INT NOT NULL DEFAULT 14 AFTER active_to;
@@ -0,0 +1,2 @@
+ALTER TABLE consumer_transaction_status_histories
+  ADD COLUMN reason VARCHAR(255) NOT NULL DEFAULT "" AFTER to_status_id;
@@ -0,0 +1,3 @@
+ALTER TABLE loan_products
+  ADD COLUMN early_settlement_penalty_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0 AFTER cancellation_window_days,
+  ADD COLUMN early_settlement_penalty_rate DECIMAL(5,2) UNSIGNED NOT NULL DEFAULT 0 AFTER early_settlement_penalty_amount;
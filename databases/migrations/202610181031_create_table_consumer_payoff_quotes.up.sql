@@ -0,0 +1,19 @@
+CREATE TABLE consumer_payoff_quotes (
+  id VARCHAR(255) PRIMARY KEY NOT NULL,
+  consumer_transaction_id VARCHAR(255) NOT NULL,
+  quote_date DATE NOT NULL,
+  principal_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,
+  interest_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,
+  fee_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,
+  penalty_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,
+  total_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,
+  expires_at DATETIME NOT NULL,
+  consumer_payment_id VARCHAR(255) NOT NULL DEFAULT "",
+
+  status_id VARCHAR(255) DEFAULT "1",
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  created_by VARCHAR(255) NULL,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_by VARCHAR(255) NULL,
+  INDEX index_consumer_transaction_id (consumer_transaction_id)
+);
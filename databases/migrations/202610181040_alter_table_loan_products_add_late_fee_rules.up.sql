@@ -0,0 +1,4 @@
+ALTER TABLE loan_products
+  ADD COLUMN late_fee_daily_rate DECIMAL(5,2) UNSIGNED NOT NULL DEFAULT 0 AFTER early_settlement_penalty_rate,
+  ADD COLUMN late_fee_cap_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0 AFTER late_fee_daily_rate,
+  ADD COLUMN late_fee_grace_days INT NOT NULL DEFAULT 0 AFTER late_fee_cap_amount;
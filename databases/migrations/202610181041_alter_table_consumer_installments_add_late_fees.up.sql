@@ -0,0 +1,5 @@
+ALTER TABLE consumer_installments
+  ADD COLUMN days_past_due INT NOT NULL DEFAULT 0 AFTER paid_fee_amount,
+  ADD COLUMN late_fee_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0 AFTER days_past_due,
+  ADD COLUMN paid_late_fee_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0 AFTER late_fee_amount,
+  ADD COLUMN late_fee_accrued_on DATE NULL AFTER paid_late_fee_amount;
@@ -0,0 +1,2 @@
+ALTER TABLE consumer_payment_allocations
+  ADD COLUMN late_fee_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0 AFTER consumer_installment_id;
@@ -0,0 +1,3 @@
+ALTER TABLE consumer_transactions
+  ADD COLUMN days_past_due INT NOT NULL DEFAULT 0 AFTER total_amount,
+  ADD INDEX index_days_past_due (days_past_due);
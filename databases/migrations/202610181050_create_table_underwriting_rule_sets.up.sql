@@ -0,0 +1,15 @@
+CREATE TABLE underwriting_rule_sets (
+  id VARCHAR(255) PRIMARY KEY NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  min_age INT NOT NULL DEFAULT 0,
+  max_age INT NOT NULL DEFAULT 0,
+  min_salary DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,
+  max_debt_service_ratio DECIMAL(5,2) UNSIGNED NOT NULL DEFAULT 0,
+  max_limit_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,
+
+  status_id VARCHAR(255) DEFAULT "1",
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  created_by VARCHAR(255) NULL,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_by VARCHAR(255) NULL
+);
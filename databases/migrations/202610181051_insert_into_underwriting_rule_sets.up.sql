@@ -0,0 +1,3 @@
+INSERT INTO underwriting_rule_sets (id, name, min_age, max_age, min_salary, max_debt_service_ratio, max_limit_amount)
+VALUES
+  ("3f0c1d52-8a6e-4b7a-9c1e-2d5f7a9b0c11", "Default", 21, 60, 0, 30, 0);
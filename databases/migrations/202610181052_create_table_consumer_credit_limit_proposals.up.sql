@@ -0,0 +1,21 @@
+CREATE TABLE consumer_credit_limit_proposals (
+  id VARCHAR(255) PRIMARY KEY NOT NULL,
+  consumer_id VARCHAR(255) NOT NULL,
+  underwriting_rule_set_id VARCHAR(255) NOT NULL,
+  salary DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,
+  age INT NOT NULL DEFAULT 0,
+  max_debt_service_ratio DECIMAL(5,2) UNSIGNED NOT NULL DEFAULT 0,
+  existing_obligation_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,
+  external_obligation_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,
+  affordable_installment_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,
+  decision VARCHAR(50) NOT NULL DEFAULT "proposed",
+  reason VARCHAR(255) NOT NULL DEFAULT "",
+  consumer_credit_limit_id VARCHAR(255) NOT NULL DEFAULT "",
+
+  status_id VARCHAR(255) DEFAULT "1",
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  created_by VARCHAR(255) NULL,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_by VARCHAR(255) NULL,
+  INDEX index_consumer_id (consumer_id)
+);
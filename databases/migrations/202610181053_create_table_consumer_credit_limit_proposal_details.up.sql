@@ -0,0 +1,14 @@
+CREATE TABLE consumer_credit_limit_proposal_details (
+  id VARCHAR(255) PRIMARY KEY NOT NULL,
+  consumer_credit_limit_proposal_id VARCHAR(255) NOT NULL,
+  loan_product_id VARCHAR(255) NOT NULL,
+  proposed_limit_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,
+  approved_limit_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,
+
+  status_id VARCHAR(255) DEFAULT "1",
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  created_by VARCHAR(255) NULL,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_by VARCHAR(255) NULL,
+  INDEX index_consumer_credit_limit_proposal_id (consumer_credit_limit_proposal_id)
+);
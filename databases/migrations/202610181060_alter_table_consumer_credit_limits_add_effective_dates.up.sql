@@ -0,0 +1,5 @@
+ALTER TABLE consumer_credit_limits
+  ADD COLUMN effective_from DATETIME NULL AFTER consumer_id,
+  ADD COLUMN effective_to DATETIME NULL AFTER effective_from,
+  ADD COLUMN reason VARCHAR(255) NOT NULL DEFAULT "" AFTER effective_to,
+  ADD INDEX index_consumer_id_effective_from (consumer_id, effective_from);
@@ -0,0 +1,2 @@
+UPDATE consumer_credit_limits
+SET effective_from = created_at, effective_to = IF(status_id = "1", NULL, updated_at);
@@ -0,0 +1,2 @@
+ALTER TABLE consumer_credit_limits
+  MODIFY COLUMN effective_from DATETIME NOT NULL;
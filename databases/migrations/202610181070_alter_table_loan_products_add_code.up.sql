@@ -0,0 +1,2 @@
+ALTER TABLE loan_products
+  ADD code VARCHAR(20) NOT NULL DEFAULT "" AFTER name;
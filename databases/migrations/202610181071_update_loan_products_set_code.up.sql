@@ -0,0 +1 @@
+UPDATE loan_products SET code = CONCAT("M", LPAD(tenor, 2, "0")) WHERE code = "";
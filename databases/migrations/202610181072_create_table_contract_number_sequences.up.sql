@@ -0,0 +1,7 @@
+CREATE TABLE contract_number_sequences (
+  sequence_key VARCHAR(255) PRIMARY KEY NOT NULL,
+  last_value INT UNSIGNED NOT NULL DEFAULT 0,
+
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,9 @@
+UPDATE consumer_transactions
+JOIN (
+  SELECT contract_number, MIN(id) first_id
+  FROM consumer_transactions
+  GROUP BY contract_number
+  HAVING COUNT(*) > 1 OR contract_number = ""
+) dupes ON dupes.contract_number = consumer_transactions.contract_number
+SET consumer_transactions.contract_number = CONCAT(IF(consumer_transactions.contract_number = "", "LEGACY", consumer_transactions.contract_number), "-", consumer_transactions.id)
+WHERE consumer_transactions.id <> dupes.first_id OR consumer_transactions.contract_number = "";
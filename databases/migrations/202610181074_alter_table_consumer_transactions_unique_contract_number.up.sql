@@ -0,0 +1,3 @@
+ALTER TABLE consumer_transactions
+  DROP INDEX index_contract_number,
+  ADD UNIQUE INDEX unique_contract_number (contract_number);
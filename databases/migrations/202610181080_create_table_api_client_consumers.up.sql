@@ -0,0 +1,13 @@
+CREATE TABLE api_client_consumers (
+  id VARCHAR(255) PRIMARY KEY NOT NULL,
+  api_client_id INT NOT NULL,
+  consumer_id VARCHAR(255) NOT NULL,
+
+  status_id VARCHAR(255) DEFAULT "1",
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  created_by VARCHAR(255) NULL,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_by VARCHAR(255) NULL,
+  UNIQUE INDEX unique_api_client_id_consumer_id (api_client_id, consumer_id),
+  INDEX index_consumer_id (consumer_id)
+);
@@ -0,0 +1,18 @@
+CREATE TABLE merchants (
+  id VARCHAR(255) PRIMARY KEY NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  email VARCHAR(255) NOT NULL DEFAULT "",
+  phone_number VARCHAR(50) NOT NULL DEFAULT "",
+  address VARCHAR(255) NOT NULL DEFAULT "",
+  settlement_bank_name VARCHAR(255) NOT NULL DEFAULT "",
+  settlement_account_number VARCHAR(50) NOT NULL DEFAULT "",
+  settlement_account_name VARCHAR(255) NOT NULL DEFAULT "",
+  fee_share_rate DECIMAL(6,4) UNSIGNED NOT NULL DEFAULT 0,
+
+  status_id VARCHAR(255) DEFAULT "1",
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  created_by VARCHAR(255) NULL,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_by VARCHAR(255) NULL,
+  INDEX index_name (name)
+);
@@ -0,0 +1,12 @@
+CREATE TABLE merchant_loan_products (
+  id VARCHAR(255) PRIMARY KEY NOT NULL,
+  merchant_id VARCHAR(255) NOT NULL,
+  loan_product_id VARCHAR(255) NOT NULL,
+
+  status_id VARCHAR(255) DEFAULT "1",
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  created_by VARCHAR(255) NULL,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_by VARCHAR(255) NULL,
+  UNIQUE INDEX unique_merchant_id_loan_product_id (merchant_id, loan_product_id)
+);
@@ -0,0 +1,3 @@
+ALTER TABLE api_client
+  ADD merchant_id VARCHAR(255) NOT NULL DEFAULT "",
+  ADD INDEX index_merchant_id (merchant_id);
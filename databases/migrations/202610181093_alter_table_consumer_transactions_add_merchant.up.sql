@@ -0,0 +1,6 @@
+ALTER TABLE consumer_transactions
+  ADD merchant_id VARCHAR(255) NOT NULL DEFAULT "" AFTER loan_product_id,
+  ADD api_client_id INT NOT NULL DEFAULT 0 AFTER merchant_id,
+  ADD merchant_fee_rate DECIMAL(6,4) UNSIGNED NOT NULL DEFAULT 0 AFTER admin_fee,
+  ADD merchant_fee_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0 AFTER merchant_fee_rate,
+  ADD INDEX index_merchant_id (merchant_id);
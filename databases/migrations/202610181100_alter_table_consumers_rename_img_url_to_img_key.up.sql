@@ -0,0 +1,3 @@
+ALTER TABLE consumers
+  CHANGE ktp_img_url ktp_img_key VARCHAR(255) NOT NULL DEFAULT "",
+  CHANGE selfie_img_url selfie_img_key VARCHAR(255) NOT NULL DEFAULT "";
@@ -0,0 +1,11 @@
+CREATE TABLE consumer_document_access_logs (
+  id VARCHAR(255) PRIMARY KEY NOT NULL,
+  consumer_id VARCHAR(255) NOT NULL,
+  document_type VARCHAR(20) NOT NULL,
+  object_key VARCHAR(255) NOT NULL,
+  user_id VARCHAR(255) NOT NULL DEFAULT "",
+  ip_address VARCHAR(64) NOT NULL DEFAULT "",
+  accessed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  INDEX index_consumer_id (consumer_id),
+  INDEX index_user_id (user_id)
+);
@@ -0,0 +1,5 @@
+CREATE TABLE regions (
+  code VARCHAR(6) PRIMARY KEY NOT NULL,
+  name VARCHAR(255) NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
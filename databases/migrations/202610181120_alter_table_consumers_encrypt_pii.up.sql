@@ -0,0 +1,10 @@
+ALTER TABLE consumers
+  MODIFY NIK VARCHAR(255) NOT NULL,
+  MODIFY date_of_birth VARCHAR(255) NOT NULL,
+  MODIFY salary VARCHAR(255) NOT NULL,
+  MODIFY ktp_img_key VARCHAR(512) NOT NULL DEFAULT "",
+  MODIFY selfie_img_key VARCHAR(512) NOT NULL DEFAULT "",
+  DROP INDEX index_NIK,
+  DROP INDEX index_date_of_birth,
+  ADD nik_index VARCHAR(64) NOT NULL DEFAULT "" AFTER NIK,
+  ADD INDEX index_nik_index (nik_index);
@@ -0,0 +1,4 @@
+CREATE TABLE permissions (
+  id VARCHAR(255) PRIMARY KEY NOT NULL,
+  description VARCHAR(255) NOT NULL DEFAULT ""
+);
@@ -0,0 +1,12 @@
+CREATE TABLE role_permissions (
+  id VARCHAR(255) PRIMARY KEY NOT NULL,
+  role_id VARCHAR(255) NOT NULL,
+  permission_id VARCHAR(255) NOT NULL,
+
+  status_id VARCHAR(255) DEFAULT "1",
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  created_by VARCHAR(255) NULL,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_by VARCHAR(255) NULL,
+  UNIQUE INDEX unique_role_id_permission_id (role_id, permission_id)
+);
@@ -0,0 +1,6 @@
+INSERT INTO roles (id, name, description)
+VALUES
+  ("8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e01", "Admin", "Manages users, roles and every back-office feature"),
+  ("8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e02", "Credit Analyst", "Sets credit limits and runs underwriting"),
+  ("8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e03", "Operations", "Onboards consumers and merchants, books transactions and payments"),
+  ("8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e04", "Auditor", "Reads everything, changes nothing");
@@ -0,0 +1,23 @@
+INSERT INTO permissions (id, description)
+VALUES
+  ("consumers.read", "View consumers"),
+  ("consumers.write", "Create and update consumers, upload their documents"),
+  ("consumer_documents.read", "View consumer KYC documents"),
+  ("credit_limits.read", "View credit limits"),
+  ("credit_limits.write", "Set credit limits"),
+  ("underwriting.read", "View underwriting rule sets and proposals"),
+  ("underwriting.write", "Manage underwriting rule sets, propose, accept and override credit limits"),
+  ("transactions.read", "View transactions and installments"),
+  ("transactions.write", "Book, update, cancel transactions and change their status"),
+  ("payments.read", "View payments and payoff quotes"),
+  ("payments.write", "Record and reverse payments, settle payoff quotes"),
+  ("loan_products.read", "View loan products"),
+  ("loan_products.write", "Manage loan products"),
+  ("merchants.read", "View merchants"),
+  ("merchants.write", "Manage merchants and link their API clients"),
+  ("api_clients.read", "View the consumers of API clients"),
+  ("api_clients.write", "Give API clients access to consumers"),
+  ("users.read", "View users"),
+  ("users.write", "Register users, update them and assign their role"),
+  ("roles.read", "View roles and permissions"),
+  ("roles.write", "Manage roles and their permissions");
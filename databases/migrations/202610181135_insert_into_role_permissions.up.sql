@@ -0,0 +1,15 @@
+INSERT INTO role_permissions (id, role_id, permission_id)
+SELECT UUID(), roles.id, permissions.id
+FROM roles
+JOIN permissions ON roles.id = "8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e01"
+  OR (roles.id = "8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e02" AND permissions.id IN (
+    "consumers.read", "consumer_documents.read", "credit_limits.read", "credit_limits.write",
+    "underwriting.read", "underwriting.write", "transactions.read", "payments.read",
+    "loan_products.read", "merchants.read"
+  ))
+  OR (roles.id = "8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e03" AND permissions.id IN (
+    "consumers.read", "consumers.write", "consumer_documents.read", "credit_limits.read",
+    "transactions.read", "transactions.write", "payments.read", "payments.write",
+    "loan_products.read", "merchants.read", "merchants.write", "api_clients.read", "api_clients.write"
+  ))
+  OR (roles.id = "8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e04" AND permissions.id LIKE "%.read");
@@ -0,0 +1,3 @@
+ALTER TABLE users
+  ADD COLUMN role_id VARCHAR(255) NOT NULL DEFAULT "" AFTER password,
+  ADD INDEX index_role_id (role_id);
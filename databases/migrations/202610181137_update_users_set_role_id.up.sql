@@ -0,0 +1 @@
+UPDATE users SET role_id = "8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e01" WHERE role_id = "";
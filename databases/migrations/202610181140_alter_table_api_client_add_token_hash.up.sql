@@ -0,0 +1,10 @@
+ALTER TABLE api_client
+  ADD token_hash VARCHAR(64) NOT NULL DEFAULT "" AFTER token,
+  ADD token_prefix VARCHAR(16) NOT NULL DEFAULT "" AFTER token_hash,
+  ADD expires_at DATETIME NULL,
+  ADD status_id VARCHAR(255) DEFAULT "1",
+  ADD created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  ADD created_by VARCHAR(255) NULL,
+  ADD updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  ADD updated_by VARCHAR(255) NULL,
+  ADD INDEX index_token_hash (token_hash);
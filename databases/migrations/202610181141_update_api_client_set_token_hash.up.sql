@@ -0,0 +1 @@
+UPDATE api_client SET token_hash = SHA2(token, 256), token_prefix = LEFT(token, 4) WHERE token <> "";
@@ -0,0 +1 @@
+ALTER TABLE api_client DROP COLUMN token;
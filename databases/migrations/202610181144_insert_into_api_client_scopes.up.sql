@@ -0,0 +1,9 @@
+INSERT INTO api_client_scopes (id, api_client_id, scope)
+SELECT UUID(), api_client.id, scopes.scope
+FROM api_client
+CROSS JOIN (
+  SELECT "transactions:create" scope
+  UNION ALL SELECT "transactions:read"
+  UNION ALL SELECT "merchants:all"
+) scopes
+WHERE api_client.name = "Account";
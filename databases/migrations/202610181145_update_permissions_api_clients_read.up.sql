@@ -0,0 +1 @@
+UPDATE permissions SET description = "View API clients, their scopes and the consumers they may see" WHERE id = "api_clients.read";
@@ -0,0 +1 @@
+UPDATE permissions SET description = "Create, rotate, expire and revoke API clients and give them access to consumers" WHERE id = "api_clients.write";
@@ -58,20 +58,461 @@ func init() {
 
 		Content: string("CREATE TABLE api_client (\r\n  id INT NOT NULL AUTO_INCREMENT,\r\n  name  VARCHAR(255) DEFAULT \"\",\r\n  token  VARCHAR(255) DEFAULT \"\",\r\n  PRIMARY KEY (id)\r\n);"),
 	}
+	file10 := &embedded.EmbeddedFile{
+		Filename:    "202610180900_create_table_consumer_installments.up.sql",
+		FileModTime: time.Unix(1792301127, 0),
+
+		Content: string("CREATE TABLE consumer_installments (\n  id VARCHAR(255) PRIMARY KEY NOT NULL,\n  consumer_transaction_id VARCHAR(255) NOT NULL,\n  installment_number INT UNSIGNED NOT NULL,\n  due_date DATE NOT NULL,\n  principal_amount DECIMAL(12,2) UNSIGNED NOT NULL,\n  interest_amount DECIMAL(12,2) UNSIGNED NOT NULL,\n  fee_amount DECIMAL(12,2) UNSIGNED NOT NULL,\n  installment_amount DECIMAL(12,2) UNSIGNED NOT NULL,\n  outstanding_balance DECIMAL(12,2) UNSIGNED NOT NULL,\n\n  status_id VARCHAR(255) DEFAULT \"1\",\n  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  created_by VARCHAR(255) NULL,\n  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  updated_by VARCHAR(255) NULL,\n  INDEX index_consumer_transaction_id (consumer_transaction_id),\n  INDEX index_due_date (due_date)\n);\n"),
+	}
+	file11 := &embedded.EmbeddedFile{
+		Filename:    "202610180910_alter_table_consumer_installments_add_paid_amounts.up.sql",
+		FileModTime: time.Unix(1792301288, 0),
+
+		Content: string("ALTER TABLE consumer_installments\n  ADD COLUMN paid_principal_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0 AFTER outstanding_balance,\n  ADD COLUMN paid_interest_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0 AFTER paid_principal_amount,\n  ADD COLUMN paid_fee_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0 AFTER paid_interest_amount;\n"),
+	}
+	file12 := &embedded.EmbeddedFile{
+		Filename:    "202610180911_create_table_consumer_payments.up.sql",
+		FileModTime: time.Unix(1792301288, 0),
+
+		Content: string("CREATE TABLE consumer_payments (\n  id VARCHAR(255) PRIMARY KEY NOT NULL,\n  consumer_transaction_id VARCHAR(255) NOT NULL,\n  payment_type VARCHAR(50) NOT NULL,\n  payment_date DATE NOT NULL,\n  amount DECIMAL(12,2) UNSIGNED NOT NULL,\n  reference_number VARCHAR(255) NOT NULL DEFAULT \"\",\n  reversal_of_id VARCHAR(255) NOT NULL DEFAULT \"\",\n  reason VARCHAR(255) NOT NULL DEFAULT \"\",\n\n  status_id VARCHAR(255) DEFAULT \"1\",\n  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  created_by VARCHAR(255) NULL,\n  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  updated_by VARCHAR(255) NULL,\n  INDEX index_consumer_transaction_id (consumer_transaction_id),\n  INDEX index_reversal_of_id (reversal_of_id),\n  INDEX index_payment_date (payment_date)\n);\n"),
+	}
+	file13 := &embedded.EmbeddedFile{
+		Filename:    "202610180912_create_table_consumer_payment_allocations.up.sql",
+		FileModTime: time.Unix(1792301288, 0),
+
+		Content: string("CREATE TABLE consumer_payment_allocations (\n  id VARCHAR(255) PRIMARY KEY NOT NULL,\n  consumer_payment_id VARCHAR(255) NOT NULL,\n  consumer_installment_id VARCHAR(255) NOT NULL,\n  fee_amount DECIMAL(12,2) UNSIGNED NOT NULL,\n  interest_amount DECIMAL(12,2) UNSIGNED NOT NULL,\n  principal_amount DECIMAL(12,2) UNSIGNED NOT NULL,\n\n  status_id VARCHAR(255) DEFAULT \"1\",\n  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  created_by VARCHAR(255) NULL,\n  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  updated_by VARCHAR(255) NULL,\n  INDEX index_consumer_payment_id (consumer_payment_id),\n  INDEX index_consumer_installment_id (consumer_installment_id)\n);\n"),
+	}
+	file14 := &embedded.EmbeddedFile{
+		Filename:    "202610180920_alter_table_consumer_transactions_add_interest_method.up.sql",
+		FileModTime: time.Unix(1792301652, 0),
+
+		Content: string("ALTER TABLE consumer_transactions\n  ADD COLUMN interest_method VARCHAR(50) NOT NULL DEFAULT \"flat\" AFTER interest_amount,\n  ADD COLUMN interest_rate DECIMAL(6,4) UNSIGNED NOT NULL DEFAULT 0 AFTER interest_method;\n"),
+	}
+	file15 := &embedded.EmbeddedFile{
+		Filename:    "202610180930_create_table_loan_products.up.sql",
+		FileModTime: time.Unix(1792301763, 0),
+
+		Content: string("CREATE TABLE loan_products (\n  id VARCHAR(255) PRIMARY KEY NOT NULL,\n  name VARCHAR(255) NOT NULL,\n  tenor INT UNSIGNED NOT NULL,\n  interest_method VARCHAR(50) NOT NULL DEFAULT \"flat\",\n  interest_rate DECIMAL(6,4) UNSIGNED NOT NULL DEFAULT 0,\n  admin_fee_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,\n  admin_fee_rate DECIMAL(6,4) UNSIGNED NOT NULL DEFAULT 0,\n  min_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,\n  max_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,\n  active_from DATE NULL,\n  active_to DATE NULL,\n\n  status_id VARCHAR(255) DEFAULT \"1\",\n  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  created_by VARCHAR(255) NULL,\n  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  updated_by VARCHAR(255) NULL,\n  INDEX index_tenor (tenor)\n);\n"),
+	}
+	file16 := &embedded.EmbeddedFile{
+		Filename:    "202610180931_insert_loan_products_data.up.sql",
+		FileModTime: time.Unix(1792301763, 0),
+
+		Content: string("INSERT INTO loan_products (id, name, tenor, status_id)\nVALUES\n  (\"5f0d6f4e-4c1a-4b7e-9a61-2f1d8c0b1a01\", \"1 Month\", 1, \"0\"),\n  (\"5f0d6f4e-4c1a-4b7e-9a61-2f1d8c0b1a02\", \"2 Months\", 2, \"0\"),\n  (\"5f0d6f4e-4c1a-4b7e-9a61-2f1d8c0b1a03\", \"3 Months\", 3, \"0\"),\n  (\"5f0d6f4e-4c1a-4b7e-9a61-2f1d8c0b1a06\", \"6 Months\", 6, \"0\");\n"),
+	}
+	file17 := &embedded.EmbeddedFile{
+		Filename:    "202610180932_create_table_consumer_credit_limit_details.up.sql",
+		FileModTime: time.Unix(1792301763, 0),
+
+		Content: string("CREATE TABLE consumer_credit_limit_details (\n  id VARCHAR(255) PRIMARY KEY NOT NULL,\n  consumer_credit_limit_id VARCHAR(255) NOT NULL,\n  loan_product_id VARCHAR(255) NOT NULL,\n  limit_amount DECIMAL(12,2) UNSIGNED NOT NULL,\n\n  status_id VARCHAR(255) DEFAULT \"1\",\n  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  created_by VARCHAR(255) NULL,\n  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  updated_by VARCHAR(255) NULL,\n  INDEX index_consumer_credit_limit_id (consumer_credit_limit_id),\n  INDEX index_loan_product_id (loan_product_id)\n);\n"),
+	}
+	file18 := &embedded.EmbeddedFile{
+		Filename:    "202610180933_insert_consumer_credit_limit_details_data.up.sql",
+		FileModTime: time.Unix(1792301763, 0),
+
+		Content: string("INSERT INTO consumer_credit_limit_details (id, consumer_credit_limit_id, loan_product_id, limit_amount, status_id, created_at, created_by, updated_at, updated_by)\nSELECT\n  UUID(), cl.id, lp.id,\n  CASE lp.tenor\n    WHEN 1 THEN cl.1_month\n    WHEN 2 THEN cl.2_month\n    WHEN 3 THEN cl.3_month\n    WHEN 6 THEN cl.6_month\n  END,\n  cl.status_id, cl.created_at, cl.created_by, cl.updated_at, cl.updated_by\nFROM consumer_credit_limits cl\nJOIN loan_products lp ON lp.tenor IN (1, 2, 3, 6);\n"),
+	}
+	file19 := &embedded.EmbeddedFile{
+		Filename:    "202610180934_alter_table_consumer_credit_limits_drop_tenor_columns.up.sql",
+		FileModTime: time.Unix(1792301763, 0),
+
+		Content: string("ALTER TABLE consumer_credit_limits\n  DROP COLUMN 1_month,\n  DROP COLUMN 2_month,\n  DROP COLUMN 3_month,\n  DROP COLUMN 6_month;\n"),
+	}
+	file20 := &embedded.EmbeddedFile{
+		Filename:    "202610180935_alter_table_consumer_transactions_add_loan_product_id.up.sql",
+		FileModTime: time.Unix(1792301763, 0),
+
+		Content: string("ALTER TABLE consumer_transactions\n  ADD COLUMN loan_product_id VARCHAR(255) NOT NULL DEFAULT \"\" AFTER consumer_id,\n  ADD INDEX index_loan_product_id (loan_product_id);\n"),
+	}
+	file21 := &embedded.EmbeddedFile{
+		Filename:    "202610180936_update_consumer_transactions_loan_product_id.up.sql",
+		FileModTime: time.Unix(1792301763, 0),
+
+		Content: string("UPDATE consumer_transactions\nJOIN loan_products ON loan_products.tenor = consumer_transactions.loan_term\nSET consumer_transactions.loan_product_id = loan_products.id;\n"),
+	}
+	file22 := &embedded.EmbeddedFile{
+		Filename:    "202610181000_create_table_idempotency_keys.up.sql",
+		FileModTime: time.Unix(1792302277, 0),
+
+		Content: string("CREATE TABLE idempotency_keys (\n  id VARCHAR(255) PRIMARY KEY NOT NULL,\n  api_client_id INT NOT NULL,\n  idempotency_key VARCHAR(255) NOT NULL,\n  request_path VARCHAR(255) NOT NULL,\n  request_hash VARCHAR(64) NOT NULL,\n  response_status_code INT NOT NULL DEFAULT 0,\n  response_body MEDIUMTEXT NOT NULL,\n\n  status_id VARCHAR(255) DEFAULT \"1\",\n  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  created_by VARCHAR(255) NULL,\n  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  updated_by VARCHAR(255) NULL,\n  UNIQUE INDEX unique_api_client_id_idempotency_key (api_client_id, idempotency_key)\n);\n"),
+	}
+	file23 := &embedded.EmbeddedFile{
+		Filename:    "202610181010_create_table_consumer_transaction_statuses.up.sql",
+		FileModTime: time.Unix(1792302422, 0),
+
+		Content: string("CREATE TABLE consumer_transaction_statuses (\n  id VARCHAR(50) PRIMARY KEY NOT NULL,\n  name VARCHAR(255) NOT NULL,\n  consumes_limit TINYINT(1) NOT NULL DEFAULT 0\n);\n"),
+	}
+	file24 := &embedded.EmbeddedFile{
+		Filename:    "202610181011_insert_consumer_transaction_statuses_data.up.sql",
+		FileModTime: time.Unix(1792302422, 0),
+
+		Content: string("INSERT INTO consumer_transaction_statuses (id, name, consumes_limit)\nVALUES\n  (\"pending\", \"Pending\", 1),\n  (\"approved\", \"Approved\", 1),\n  (\"rejected\", \"Rejected\", 0),\n  (\"disbursed\", \"Disbursed\", 1),\n  (\"active\", \"Active\", 1),\n  (\"paid_off\", \"Paid Off\", 0),\n  (\"cancelled\", \"Cancelled\", 0),\n  (\"written_off\", \"Written Off\", 1);\n"),
+	}
+	file25 := &embedded.EmbeddedFile{
+		Filename:    "202610181012_create_table_consumer_transaction_status_histories.up.sql",
+		FileModTime: time.Unix(1792302422, 0),
+
+		Content: string("CREATE TABLE consumer_transaction_status_histories (\n  id VARCHAR(255) PRIMARY KEY NOT NULL,\n  consumer_transaction_id VARCHAR(255) NOT NULL,\n  from_status_id VARCHAR(50) NOT NULL DEFAULT \"\",\n  to_status_id VARCHAR(50) NOT NULL,\n\n  status_id VARCHAR(255) DEFAULT \"1\",\n  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  created_by VARCHAR(255) NULL,\n  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  updated_by VARCHAR(255) NULL,\n  INDEX index_consumer_transaction_id (consumer_transaction_id)\n);\n"),
+	}
+	file26 := &embedded.EmbeddedFile{
+		Filename:    "202610181013_update_consumer_transactions_status_id.up.sql",
+		FileModTime: time.Unix(1792302422, 0),
+
+		Content: string("UPDATE consumer_transactions\nSET status_id = CASE status_id WHEN \"1\" THEN \"active\" ELSE \"cancelled\" END;\n"),
+	}
+	file27 := &embedded.EmbeddedFile{
+		Filename:    "202610181014_alter_table_consumer_transactions_status_id_default.up.sql",
+		FileModTime: time.Unix(1792302422, 0),
+
+		Content: string("ALTER TABLE consumer_transactions\n  ALTER COLUMN status_id SET DEFAULT \"pending\";\n"),
+	}
+	file28 := &embedded.EmbeddedFile{
+		Filename:    "202610181020_alter_table_loan_products_add_cancellation_window_days.up.sql",
+		FileModTime: time.Unix(1792303728, 0),
+
+		Content: string("ALTER TABLE loan_products\n  ADD COLUMN cancellation_window_days INT NOT NULL DEFAULT 14 AFTER active_to;\n"),
+	}
+	file29 := &embedded.EmbeddedFile{
+		Filename:    "202610181021_alter_table_consumer_transaction_status_histories_add_reason.up.sql",
+		FileModTime: time.Unix(1792303728, 0),
+
+		Content: string("ALTER TABLE consumer_transaction_status_histories\n  ADD COLUMN reason VARCHAR(255) NOT NULL DEFAULT \"\" AFTER to_status_id;\n"),
+	}
+	file30 := &embedded.EmbeddedFile{
+		Filename:    "202610181030_alter_table_loan_products_add_early_settlement_penalty.up.sql",
+		FileModTime: time.Unix(1792303828, 0),
+
+		Content: string("ALTER TABLE loan_products\n  ADD COLUMN early_settlement_penalty_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0 AFTER cancellation_window_days,\n  ADD COLUMN early_settlement_penalty_rate DECIMAL(5,2) UNSIGNED NOT NULL DEFAULT 0 AFTER early_settlement_penalty_amount;\n"),
+	}
+	file31 := &embedded.EmbeddedFile{
+		Filename:    "202610181031_create_table_consumer_payoff_quotes.up.sql",
+		FileModTime: time.Unix(1792303828, 0),
+
+		Content: string("CREATE TABLE consumer_payoff_quotes (\n  id VARCHAR(255) PRIMARY KEY NOT NULL,\n  consumer_transaction_id VARCHAR(255) NOT NULL,\n  quote_date DATE NOT NULL,\n  principal_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,\n  interest_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,\n  fee_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,\n  penalty_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,\n  total_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,\n  expires_at DATETIME NOT NULL,\n  consumer_payment_id VARCHAR(255) NOT NULL DEFAULT \"\",\n\n  status_id VARCHAR(255) DEFAULT \"1\",\n  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  created_by VARCHAR(255) NULL,\n  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  updated_by VARCHAR(255) NULL,\n  INDEX index_consumer_transaction_id (consumer_transaction_id)\n);\n"),
+	}
+	file32 := &embedded.EmbeddedFile{
+		Filename:    "202610181040_alter_table_loan_products_add_late_fee_rules.up.sql",
+		FileModTime: time.Unix(1792303995, 0),
+
+		Content: string("ALTER TABLE loan_products\n  ADD COLUMN late_fee_daily_rate DECIMAL(5,2) UNSIGNED NOT NULL DEFAULT 0 AFTER early_settlement_penalty_rate,\n  ADD COLUMN late_fee_cap_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0 AFTER late_fee_daily_rate,\n  ADD COLUMN late_fee_grace_days INT NOT NULL DEFAULT 0 AFTER late_fee_cap_amount;\n"),
+	}
+	file33 := &embedded.EmbeddedFile{
+		Filename:    "202610181041_alter_table_consumer_installments_add_late_fees.up.sql",
+		FileModTime: time.Unix(1792303995, 0),
+
+		Content: string("ALTER TABLE consumer_installments\n  ADD COLUMN days_past_due INT NOT NULL DEFAULT 0 AFTER paid_fee_amount,\n  ADD COLUMN late_fee_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0 AFTER days_past_due,\n  ADD COLUMN paid_late_fee_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0 AFTER late_fee_amount,\n  ADD COLUMN late_fee_accrued_on DATE NULL AFTER paid_late_fee_amount;\n"),
+	}
+	file34 := &embedded.EmbeddedFile{
+		Filename:    "202610181042_alter_table_consumer_payment_allocations_add_late_fee_amount.up.sql",
+		FileModTime: time.Unix(1792303995, 0),
+
+		Content: string("ALTER TABLE consumer_payment_allocations\n  ADD COLUMN late_fee_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0 AFTER consumer_installment_id;\n"),
+	}
+	file35 := &embedded.EmbeddedFile{
+		Filename:    "202610181043_alter_table_consumer_transactions_add_days_past_due.up.sql",
+		FileModTime: time.Unix(1792303995, 0),
+
+		Content: string("ALTER TABLE consumer_transactions\n  ADD COLUMN days_past_due INT NOT NULL DEFAULT 0 AFTER total_amount,\n  ADD INDEX index_days_past_due (days_past_due);\n"),
+	}
+	file36 := &embedded.EmbeddedFile{
+		Filename:    "202610181050_create_table_underwriting_rule_sets.up.sql",
+		FileModTime: time.Unix(1792304311, 0),
+
+		Content: string("CREATE TABLE underwriting_rule_sets (\n  id VARCHAR(255) PRIMARY KEY NOT NULL,\n  name VARCHAR(255) NOT NULL,\n  min_age INT NOT NULL DEFAULT 0,\n  max_age INT NOT NULL DEFAULT 0,\n  min_salary DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,\n  max_debt_service_ratio DECIMAL(5,2) UNSIGNED NOT NULL DEFAULT 0,\n  max_limit_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,\n\n  status_id VARCHAR(255) DEFAULT \"1\",\n  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  created_by VARCHAR(255) NULL,\n  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  updated_by VARCHAR(255) NULL\n);\n"),
+	}
+	file37 := &embedded.EmbeddedFile{
+		Filename:    "202610181051_insert_into_underwriting_rule_sets.up.sql",
+		FileModTime: time.Unix(1792304332, 0),
+
+		Content: string("INSERT INTO underwriting_rule_sets (id, name, min_age, max_age, min_salary, max_debt_service_ratio, max_limit_amount)\nVALUES\n  (\"3f0c1d52-8a6e-4b7a-9c1e-2d5f7a9b0c11\", \"Default\", 21, 60, 0, 30, 0);\n"),
+	}
+	file38 := &embedded.EmbeddedFile{
+		Filename:    "202610181052_create_table_consumer_credit_limit_proposals.up.sql",
+		FileModTime: time.Unix(1792304340, 0),
+
+		Content: string("CREATE TABLE consumer_credit_limit_proposals (\n  id VARCHAR(255) PRIMARY KEY NOT NULL,\n  consumer_id VARCHAR(255) NOT NULL,\n  underwriting_rule_set_id VARCHAR(255) NOT NULL,\n  salary DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,\n  age INT NOT NULL DEFAULT 0,\n  max_debt_service_ratio DECIMAL(5,2) UNSIGNED NOT NULL DEFAULT 0,\n  existing_obligation_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,\n  external_obligation_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,\n  affordable_installment_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,\n  decision VARCHAR(50) NOT NULL DEFAULT \"proposed\",\n  reason VARCHAR(255) NOT NULL DEFAULT \"\",\n  consumer_credit_limit_id VARCHAR(255) NOT NULL DEFAULT \"\",\n\n  status_id VARCHAR(255) DEFAULT \"1\",\n  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  created_by VARCHAR(255) NULL,\n  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  updated_by VARCHAR(255) NULL,\n  INDEX index_consumer_id (consumer_id)\n);\n"),
+	}
+	file39 := &embedded.EmbeddedFile{
+		Filename:    "202610181053_create_table_consumer_credit_limit_proposal_details.up.sql",
+		FileModTime: time.Unix(1792304311, 0),
+
+		Content: string("CREATE TABLE consumer_credit_limit_proposal_details (\n  id VARCHAR(255) PRIMARY KEY NOT NULL,\n  consumer_credit_limit_proposal_id VARCHAR(255) NOT NULL,\n  loan_product_id VARCHAR(255) NOT NULL,\n  proposed_limit_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,\n  approved_limit_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0,\n\n  status_id VARCHAR(255) DEFAULT \"1\",\n  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  created_by VARCHAR(255) NULL,\n  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  updated_by VARCHAR(255) NULL,\n  INDEX index_consumer_credit_limit_proposal_id (consumer_credit_limit_proposal_id)\n);\n"),
+	}
+	file40 := &embedded.EmbeddedFile{
+		Filename:    "202610181060_alter_table_consumer_credit_limits_add_effective_dates.up.sql",
+		FileModTime: time.Unix(1792304555, 0),
+
+		Content: string("ALTER TABLE consumer_credit_limits\n  ADD COLUMN effective_from DATETIME NULL AFTER consumer_id,\n  ADD COLUMN effective_to DATETIME NULL AFTER effective_from,\n  ADD COLUMN reason VARCHAR(255) NOT NULL DEFAULT \"\" AFTER effective_to,\n  ADD INDEX index_consumer_id_effective_from (consumer_id, effective_from);\n"),
+	}
+	file41 := &embedded.EmbeddedFile{
+		Filename:    "202610181061_update_consumer_credit_limits_set_effective_dates.up.sql",
+		FileModTime: time.Unix(1792304555, 0),
+
+		Content: string("UPDATE consumer_credit_limits\nSET effective_from = created_at, effective_to = IF(status_id = \"1\", NULL, updated_at);\n"),
+	}
+	file42 := &embedded.EmbeddedFile{
+		Filename:    "202610181062_alter_table_consumer_credit_limits_modify_effective_from.up.sql",
+		FileModTime: time.Unix(1792304555, 0),
+
+		Content: string("ALTER TABLE consumer_credit_limits\n  MODIFY COLUMN effective_from DATETIME NOT NULL;\n"),
+	}
+	file43 := &embedded.EmbeddedFile{
+		Filename:    "202610181070_alter_table_loan_products_add_code.up.sql",
+		FileModTime: time.Unix(1792304712, 0),
+
+		Content: string("ALTER TABLE loan_products\n  ADD code VARCHAR(20) NOT NULL DEFAULT \"\" AFTER name;\n"),
+	}
+	file44 := &embedded.EmbeddedFile{
+		Filename:    "202610181071_update_loan_products_set_code.up.sql",
+		FileModTime: time.Unix(1792304712, 0),
+
+		Content: string("UPDATE loan_products SET code = CONCAT(\"M\", LPAD(tenor, 2, \"0\")) WHERE code = \"\";\n"),
+	}
+	file45 := &embedded.EmbeddedFile{
+		Filename:    "202610181072_create_table_contract_number_sequences.up.sql",
+		FileModTime: time.Unix(1792304712, 0),
+
+		Content: string("CREATE TABLE contract_number_sequences (\n  sequence_key VARCHAR(255) PRIMARY KEY NOT NULL,\n  last_value INT UNSIGNED NOT NULL DEFAULT 0,\n\n  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP\n);\n"),
+	}
+	file46 := &embedded.EmbeddedFile{
+		Filename:    "202610181073_update_consumer_transactions_deduplicate_contract_number.up.sql",
+		FileModTime: time.Unix(1792304719, 0),
+
+		Content: string("UPDATE consumer_transactions\nJOIN (\n  SELECT contract_number, MIN(id) first_id\n  FROM consumer_transactions\n  GROUP BY contract_number\n  HAVING COUNT(*) > 1 OR contract_number = \"\"\n) dupes ON dupes.contract_number = consumer_transactions.contract_number\nSET consumer_transactions.contract_number = CONCAT(IF(consumer_transactions.contract_number = \"\", \"LEGACY\", consumer_transactions.contract_number), \"-\", consumer_transactions.id)\nWHERE consumer_transactions.id <> dupes.first_id OR consumer_transactions.contract_number = \"\";\n"),
+	}
+	file47 := &embedded.EmbeddedFile{
+		Filename:    "202610181074_alter_table_consumer_transactions_unique_contract_number.up.sql",
+		FileModTime: time.Unix(1792304712, 0),
+
+		Content: string("ALTER TABLE consumer_transactions\n  DROP INDEX index_contract_number,\n  ADD UNIQUE INDEX unique_contract_number (contract_number);\n"),
+	}
+	file48 := &embedded.EmbeddedFile{
+		Filename:    "202610181080_create_table_api_client_consumers.up.sql",
+		FileModTime: time.Unix(1792304880, 0),
+
+		Content: string("CREATE TABLE api_client_consumers (\n  id VARCHAR(255) PRIMARY KEY NOT NULL,\n  api_client_id INT NOT NULL,\n  consumer_id VARCHAR(255) NOT NULL,\n\n  status_id VARCHAR(255) DEFAULT \"1\",\n  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  created_by VARCHAR(255) NULL,\n  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  updated_by VARCHAR(255) NULL,\n  UNIQUE INDEX unique_api_client_id_consumer_id (api_client_id, consumer_id),\n  INDEX index_consumer_id (consumer_id)\n);\n"),
+	}
+	file49 := &embedded.EmbeddedFile{
+		Filename:    "202610181090_create_table_merchants.up.sql",
+		FileModTime: time.Unix(1792305034, 0),
+
+		Content: string("CREATE TABLE merchants (\n  id VARCHAR(255) PRIMARY KEY NOT NULL,\n  name VARCHAR(255) NOT NULL,\n  email VARCHAR(255) NOT NULL DEFAULT \"\",\n  phone_number VARCHAR(50) NOT NULL DEFAULT \"\",\n  address VARCHAR(255) NOT NULL DEFAULT \"\",\n  settlement_bank_name VARCHAR(255) NOT NULL DEFAULT \"\",\n  settlement_account_number VARCHAR(50) NOT NULL DEFAULT \"\",\n  settlement_account_name VARCHAR(255) NOT NULL DEFAULT \"\",\n  fee_share_rate DECIMAL(6,4) UNSIGNED NOT NULL DEFAULT 0,\n\n  status_id VARCHAR(255) DEFAULT \"1\",\n  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  created_by VARCHAR(255) NULL,\n  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  updated_by VARCHAR(255) NULL,\n  INDEX index_name (name)\n);\n"),
+	}
+	file50 := &embedded.EmbeddedFile{
+		Filename:    "202610181091_create_table_merchant_loan_products.up.sql",
+		FileModTime: time.Unix(1792305034, 0),
+
+		Content: string("CREATE TABLE merchant_loan_products (\n  id VARCHAR(255) PRIMARY KEY NOT NULL,\n  merchant_id VARCHAR(255) NOT NULL,\n  loan_product_id VARCHAR(255) NOT NULL,\n\n  status_id VARCHAR(255) DEFAULT \"1\",\n  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  created_by VARCHAR(255) NULL,\n  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  updated_by VARCHAR(255) NULL,\n  UNIQUE INDEX unique_merchant_id_loan_product_id (merchant_id, loan_product_id)\n);\n"),
+	}
+	file51 := &embedded.EmbeddedFile{
+		Filename:    "202610181092_alter_table_api_client_add_merchant_id.up.sql",
+		FileModTime: time.Unix(1792305034, 0),
+
+		Content: string("ALTER TABLE api_client\n  ADD merchant_id VARCHAR(255) NOT NULL DEFAULT \"\",\n  ADD INDEX index_merchant_id (merchant_id);\n"),
+	}
+	file52 := &embedded.EmbeddedFile{
+		Filename:    "202610181093_alter_table_consumer_transactions_add_merchant.up.sql",
+		FileModTime: time.Unix(1792305227, 0),
+
+		Content: string("ALTER TABLE consumer_transactions\n  ADD merchant_id VARCHAR(255) NOT NULL DEFAULT \"\" AFTER loan_product_id,\n  ADD api_client_id INT NOT NULL DEFAULT 0 AFTER merchant_id,\n  ADD merchant_fee_rate DECIMAL(6,4) UNSIGNED NOT NULL DEFAULT 0 AFTER admin_fee,\n  ADD merchant_fee_amount DECIMAL(12,2) UNSIGNED NOT NULL DEFAULT 0 AFTER merchant_fee_rate,\n  ADD INDEX index_merchant_id (merchant_id);\n"),
+	}
+	file53 := &embedded.EmbeddedFile{
+		Filename:    "202610181100_alter_table_consumers_rename_img_url_to_img_key.up.sql",
+		FileModTime: time.Unix(1792305399, 0),
+
+		Content: string("ALTER TABLE consumers\n  CHANGE ktp_img_url ktp_img_key VARCHAR(255) NOT NULL DEFAULT \"\",\n  CHANGE selfie_img_url selfie_img_key VARCHAR(255) NOT NULL DEFAULT \"\";\n"),
+	}
+	file54 := &embedded.EmbeddedFile{
+		Filename:    "202610181101_create_table_consumer_document_access_logs.up.sql",
+		FileModTime: time.Unix(1792305518, 0),
+
+		Content: string("CREATE TABLE consumer_document_access_logs (\n  id VARCHAR(255) PRIMARY KEY NOT NULL,\n  consumer_id VARCHAR(255) NOT NULL,\n  document_type VARCHAR(20) NOT NULL,\n  object_key VARCHAR(255) NOT NULL,\n  user_id VARCHAR(255) NOT NULL DEFAULT \"\",\n  ip_address VARCHAR(64) NOT NULL DEFAULT \"\",\n  accessed_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  INDEX index_consumer_id (consumer_id),\n  INDEX index_user_id (user_id)\n);\n"),
+	}
+	file55 := &embedded.EmbeddedFile{
+		Filename:    "202610181110_create_table_regions.up.sql",
+		FileModTime: time.Unix(1792305768, 0),
+
+		Content: string("CREATE TABLE regions (\n  code VARCHAR(6) PRIMARY KEY NOT NULL,\n  name VARCHAR(255) NOT NULL,\n  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP\n);\n"),
+	}
+	file56 := &embedded.EmbeddedFile{
+		Filename:    "202610181111_insert_into_regions.up.sql",
+		FileModTime: time.Unix(1792305768, 0),
+
+		Content: string("INSERT INTO regions (code, name)\nVALUES\n  (\"11\", \"Aceh\"),\n  (\"12\", \"Sumatera Utara\"),\n  (\"13\", \"Sumatera Barat\"),\n  (\"14\", \"Riau\"),\n  (\"15\", \"Jambi\"),\n  (\"16\", \"Sumatera Selatan\"),\n  (\"17\", \"Bengkulu\"),\n  (\"18\", \"Lampung\"),\n  (\"19\", \"Kepulauan Bangka Belitung\"),\n  (\"21\", \"Kepulauan Riau\"),\n  (\"31\", \"DKI Jakarta\"),\n  (\"32\", \"Jawa Barat\"),\n  (\"33\", \"Jawa Tengah\"),\n  (\"34\", \"DI Yogyakarta\"),\n  (\"35\", \"Jawa Timur\"),\n  (\"36\", \"Banten\"),\n  (\"51\", \"Bali\"),\n  (\"52\", \"Nusa Tenggara Barat\"),\n  (\"53\", \"Nusa Tenggara Timur\"),\n  (\"61\", \"Kalimantan Barat\"),\n  (\"62\", \"Kalimantan Tengah\"),\n  (\"63\", \"Kalimantan Selatan\"),\n  (\"64\", \"Kalimantan Timur\"),\n  (\"65\", \"Kalimantan Utara\"),\n  (\"71\", \"Sulawesi Utara\"),\n  (\"72\", \"Sulawesi Tengah\"),\n  (\"73\", \"Sulawesi Selatan\"),\n  (\"74\", \"Sulawesi Tenggara\"),\n  (\"75\", \"Gorontalo\"),\n  (\"76\", \"Sulawesi Barat\"),\n  (\"81\", \"Maluku\"),\n  (\"82\", \"Maluku Utara\"),\n  (\"91\", \"Papua\"),\n  (\"92\", \"Papua Barat\"),\n  (\"93\", \"Papua Selatan\"),\n  (\"94\", \"Papua Tengah\"),\n  (\"95\", \"Papua Pegunungan\"),\n  (\"96\", \"Papua Barat Daya\");\n"),
+	}
+	file57 := &embedded.EmbeddedFile{
+		Filename:    "202610181120_alter_table_consumers_encrypt_pii.up.sql",
+		FileModTime: time.Unix(1792306034, 0),
+
+		Content: string("ALTER TABLE consumers\n  MODIFY NIK VARCHAR(255) NOT NULL,\n  MODIFY date_of_birth VARCHAR(255) NOT NULL,\n  MODIFY salary VARCHAR(255) NOT NULL,\n  MODIFY ktp_img_key VARCHAR(512) NOT NULL DEFAULT \"\",\n  MODIFY selfie_img_key VARCHAR(512) NOT NULL DEFAULT \"\",\n  DROP INDEX index_NIK,\n  DROP INDEX index_date_of_birth,\n  ADD nik_index VARCHAR(64) NOT NULL DEFAULT \"\" AFTER NIK,\n  ADD INDEX index_nik_index (nik_index);\n"),
+	}
+	file58 := &embedded.EmbeddedFile{
+		Filename:    "202610181130_create_table_roles.up.sql",
+		FileModTime: time.Unix(1792306246, 0),
+
+		Content: string("CREATE TABLE roles (\n  id VARCHAR(255) PRIMARY KEY NOT NULL,\n  name VARCHAR(255) NOT NULL,\n  description VARCHAR(255) NOT NULL DEFAULT \"\",\n\n  status_id VARCHAR(255) DEFAULT \"1\",\n  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  created_by VARCHAR(255) NULL,\n  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  updated_by VARCHAR(255) NULL,\n  INDEX index_name (name)\n);\n"),
+	}
+	file59 := &embedded.EmbeddedFile{
+		Filename:    "202610181131_create_table_permissions.up.sql",
+		FileModTime: time.Unix(1792306246, 0),
+
+		Content: string("CREATE TABLE permissions (\n  id VARCHAR(255) PRIMARY KEY NOT NULL,\n  description VARCHAR(255) NOT NULL DEFAULT \"\"\n);\n"),
+	}
+	file60 := &embedded.EmbeddedFile{
+		Filename:    "202610181132_create_table_role_permissions.up.sql",
+		FileModTime: time.Unix(1792306246, 0),
+
+		Content: string("CREATE TABLE role_permissions (\n  id VARCHAR(255) PRIMARY KEY NOT NULL,\n  role_id VARCHAR(255) NOT NULL,\n  permission_id VARCHAR(255) NOT NULL,\n\n  status_id VARCHAR(255) DEFAULT \"1\",\n  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  created_by VARCHAR(255) NULL,\n  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  updated_by VARCHAR(255) NULL,\n  UNIQUE INDEX unique_role_id_permission_id (role_id, permission_id)\n);\n"),
+	}
+	file61 := &embedded.EmbeddedFile{
+		Filename:    "202610181133_insert_into_roles.up.sql",
+		FileModTime: time.Unix(1792306246, 0),
+
+		Content: string("INSERT INTO roles (id, name, description)\nVALUES\n  (\"8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e01\", \"Admin\", \"Manages users, roles and every back-office feature\"),\n  (\"8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e02\", \"Credit Analyst\", \"Sets credit limits and runs underwriting\"),\n  (\"8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e03\", \"Operations\", \"Onboards consumers and merchants, books transactions and payments\"),\n  (\"8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e04\", \"Auditor\", \"Reads everything, changes nothing\");\n"),
+	}
+	file62 := &embedded.EmbeddedFile{
+		Filename:    "202610181134_insert_into_permissions.up.sql",
+		FileModTime: time.Unix(1792306246, 0),
+
+		Content: string("INSERT INTO permissions (id, description)\nVALUES\n  (\"consumers.read\", \"View consumers\"),\n  (\"consumers.write\", \"Create and update consumers, upload their documents\"),\n  (\"consumer_documents.read\", \"View consumer KYC documents\"),\n  (\"credit_limits.read\", \"View credit limits\"),\n  (\"credit_limits.write\", \"Set credit limits\"),\n  (\"underwriting.read\", \"View underwriting rule sets and proposals\"),\n  (\"underwriting.write\", \"Manage underwriting rule sets, propose, accept and override credit limits\"),\n  (\"transactions.read\", \"View transactions and installments\"),\n  (\"transactions.write\", \"Book, update, cancel transactions and change their status\"),\n  (\"payments.read\", \"View payments and payoff quotes\"),\n  (\"payments.write\", \"Record and reverse payments, settle payoff quotes\"),\n  (\"loan_products.read\", \"View loan products\"),\n  (\"loan_products.write\", \"Manage loan products\"),\n  (\"merchants.read\", \"View merchants\"),\n  (\"merchants.write\", \"Manage merchants and link their API clients\"),\n  (\"api_clients.read\", \"View the consumers of API clients\"),\n  (\"api_clients.write\", \"Give API clients access to consumers\"),\n  (\"users.read\", \"View users\"),\n  (\"users.write\", \"Register users, update them and assign their role\"),\n  (\"roles.read\", \"View roles and permissions\"),\n  (\"roles.write\", \"Manage roles and their permissions\");\n"),
+	}
+	file63 := &embedded.EmbeddedFile{
+		Filename:    "202610181135_insert_into_role_permissions.up.sql",
+		FileModTime: time.Unix(1792306246, 0),
+
+		Content: string("INSERT INTO role_permissions (id, role_id, permission_id)\nSELECT UUID(), roles.id, permissions.id\nFROM roles\nJOIN permissions ON roles.id = \"8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e01\"\n  OR (roles.id = \"8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e02\" AND permissions.id IN (\n    \"consumers.read\", \"consumer_documents.read\", \"credit_limits.read\", \"credit_limits.write\",\n    \"underwriting.read\", \"underwriting.write\", \"transactions.read\", \"payments.read\",\n    \"loan_products.read\", \"merchants.read\"\n  ))\n  OR (roles.id = \"8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e03\" AND permissions.id IN (\n    \"consumers.read\", \"consumers.write\", \"consumer_documents.read\", \"credit_limits.read\",\n    \"transactions.read\", \"transactions.write\", \"payments.read\", \"payments.write\",\n    \"loan_products.read\", \"merchants.read\", \"merchants.write\", \"api_clients.read\", \"api_clients.write\"\n  ))\n  OR (roles.id = \"8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e04\" AND permissions.id LIKE \"%.read\");\n"),
+	}
+	file64 := &embedded.EmbeddedFile{
+		Filename:    "202610181136_alter_table_users_add_role_id.up.sql",
+		FileModTime: time.Unix(1792306246, 0),
+
+		Content: string("ALTER TABLE users\n  ADD COLUMN role_id VARCHAR(255) NOT NULL DEFAULT \"\" AFTER password,\n  ADD INDEX index_role_id (role_id);\n"),
+	}
+	file65 := &embedded.EmbeddedFile{
+		Filename:    "202610181137_update_users_set_role_id.up.sql",
+		FileModTime: time.Unix(1792308928, 0),
+
+		Content: string("UPDATE users SET role_id = \"8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e01\" WHERE role_id = \"\";\n"),
+	}
+	file66 := &embedded.EmbeddedFile{
+		Filename:    "202610181140_alter_table_api_client_add_token_hash.up.sql",
+		FileModTime: time.Unix(1792307089, 0),
+
+		Content: string("ALTER TABLE api_client\n  ADD token_hash VARCHAR(64) NOT NULL DEFAULT \"\" AFTER token,\n  ADD token_prefix VARCHAR(16) NOT NULL DEFAULT \"\" AFTER token_hash,\n  ADD expires_at DATETIME NULL,\n  ADD status_id VARCHAR(255) DEFAULT \"1\",\n  ADD created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  ADD created_by VARCHAR(255) NULL,\n  ADD updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  ADD updated_by VARCHAR(255) NULL,\n  ADD INDEX index_token_hash (token_hash);\n"),
+	}
+	file67 := &embedded.EmbeddedFile{
+		Filename:    "202610181141_update_api_client_set_token_hash.up.sql",
+		FileModTime: time.Unix(1792307089, 0),
+
+		Content: string("UPDATE api_client SET token_hash = SHA2(token, 256), token_prefix = LEFT(token, 4) WHERE token <> \"\";\n"),
+	}
+	file68 := &embedded.EmbeddedFile{
+		Filename:    "202610181142_alter_table_api_client_drop_token.up.sql",
+		FileModTime: time.Unix(1792307089, 0),
+
+		Content: string("ALTER TABLE api_client DROP COLUMN token;\n"),
+	}
+	file69 := &embedded.EmbeddedFile{
+		Filename:    "202610181143_create_table_api_client_scopes.up.sql",
+		FileModTime: time.Unix(1792307089, 0),
+
+		Content: string("CREATE TABLE api_client_scopes (\n  id VARCHAR(255) PRIMARY KEY NOT NULL,\n  api_client_id INT NOT NULL,\n  scope VARCHAR(255) NOT NULL,\n\n  status_id VARCHAR(255) DEFAULT \"1\",\n  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  created_by VARCHAR(255) NULL,\n  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,\n  updated_by VARCHAR(255) NULL,\n  UNIQUE INDEX unique_api_client_id_scope (api_client_id, scope)\n);\n"),
+	}
+	file70 := &embedded.EmbeddedFile{
+		Filename:    "202610181144_insert_into_api_client_scopes.up.sql",
+		FileModTime: time.Unix(1792307089, 0),
+
+		Content: string("INSERT INTO api_client_scopes (id, api_client_id, scope)\nSELECT UUID(), api_client.id, scopes.scope\nFROM api_client\nCROSS JOIN (\n  SELECT \"transactions:create\" scope\n  UNION ALL SELECT \"transactions:read\"\n  UNION ALL SELECT \"merchants:all\"\n) scopes\nWHERE api_client.name = \"Account\";\n"),
+	}
+	file71 := &embedded.EmbeddedFile{
+		Filename:    "202610181145_update_permissions_api_clients_read.up.sql",
+		FileModTime: time.Unix(1792307092, 0),
+
+		Content: string("UPDATE permissions SET description = \"View API clients, their scopes and the consumers they may see\" WHERE id = \"api_clients.read\";\n"),
+	}
+	file72 := &embedded.EmbeddedFile{
+		Filename:    "202610181146_update_permissions_api_clients_write.up.sql",
+		FileModTime: time.Unix(1792307092, 0),
+
+		Content: string("UPDATE permissions SET description = \"Create, rotate, expire and revoke API clients and give them access to consumers\" WHERE id = \"api_clients.write\";\n"),
+	}
 
 	// define dirs
 	dir1 := &embedded.EmbeddedDir{
 		Filename:   "",
-		DirModTime: time.Unix(1745333977, 0),
+		DirModTime: time.Unix(1792308928, 0),
 		ChildFiles: []*embedded.EmbeddedFile{
-			file2, // "202504220900_create_table_status.up.sql"
-			file3, // "202504220901_insert_status_data.up.sql"
-			file4, // "202504220902_create_table_consumers.up.sql"
-			file5, // "202504220903_create_table_users.up.sql"
-			file6, // "202504220904_create_table_user_actions.up.sql"
-			file7, // "202504220905_create_table_consumer_credit_limits_.up.sql"
-			file8, // "202504220906_create_table_consumer_transactions.up.sql"
-			file9, // "202504220907_create_table_api_client.up.sql"
+			file2,  // "202504220900_create_table_status.up.sql"
+			file3,  // "202504220901_insert_status_data.up.sql"
+			file4,  // "202504220902_create_table_consumers.up.sql"
+			file5,  // "202504220903_create_table_users.up.sql"
+			file6,  // "202504220904_create_table_user_actions.up.sql"
+			file7,  // "202504220905_create_table_consumer_credit_limits_.up.sql"
+			file8,  // "202504220906_create_table_consumer_transactions.up.sql"
+			file9,  // "202504220907_create_table_api_client.up.sql"
+			file10, // "202610180900_create_table_consumer_installments.up.sql"
+			file11, // "202610180910_alter_table_consumer_installments_add_paid_amounts.up.sql"
+			file12, // "202610180911_create_table_consumer_payments.up.sql"
+			file13, // "202610180912_create_table_consumer_payment_allocations.up.sql"
+			file14, // "202610180920_alter_table_consumer_transactions_add_interest_method.up.sql"
+			file15, // "202610180930_create_table_loan_products.up.sql"
+			file16, // "202610180931_insert_loan_products_data.up.sql"
+			file17, // "202610180932_create_table_consumer_credit_limit_details.up.sql"
+			file18, // "202610180933_insert_consumer_credit_limit_details_data.up.sql"
+			file19, // "202610180934_alter_table_consumer_credit_limits_drop_tenor_columns.up.sql"
+			file20, // "202610180935_alter_table_consumer_transactions_add_loan_product_id.up.sql"
+			file21, // "202610180936_update_consumer_transactions_loan_product_id.up.sql"
+			file22, // "202610181000_create_table_idempotency_keys.up.sql"
+			file23, // "202610181010_create_table_consumer_transaction_statuses.up.sql"
+			file24, // "202610181011_insert_consumer_transaction_statuses_data.up.sql"
+			file25, // "202610181012_create_table_consumer_transaction_status_histories.up.sql"
+			file26, // "202610181013_update_consumer_transactions_status_id.up.sql"
+			file27, // "202610181014_alter_table_consumer_transactions_status_id_default.up.sql"
+			file28, // "202610181020_alter_table_loan_products_add_cancellation_window_days.up.sql"
+			file29, // "202610181021_alter_table_consumer_transaction_status_histories_add_reason.up.sql"
+			file30, // "202610181030_alter_table_loan_products_add_early_settlement_penalty.up.sql"
+			file31, // "202610181031_create_table_consumer_payoff_quotes.up.sql"
+			file32, // "202610181040_alter_table_loan_products_add_late_fee_rules.up.sql"
+			file33, // "202610181041_alter_table_consumer_installments_add_late_fees.up.sql"
+			file34, // "202610181042_alter_table_consumer_payment_allocations_add_late_fee_amount.up.sql"
+			file35, // "202610181043_alter_table_consumer_transactions_add_days_past_due.up.sql"
+			file36, // "202610181050_create_table_underwriting_rule_sets.up.sql"
+			file37, // "202610181051_insert_into_underwriting_rule_sets.up.sql"
+			file38, // "202610181052_create_table_consumer_credit_limit_proposals.up.sql"
+			file39, // "202610181053_create_table_consumer_credit_limit_proposal_details.up.sql"
+			file40, // "202610181060_alter_table_consumer_credit_limits_add_effective_dates.up.sql"
+			file41, // "202610181061_update_consumer_credit_limits_set_effective_dates.up.sql"
+			file42, // "202610181062_alter_table_consumer_credit_limits_modify_effective_from.up.sql"
+			file43, // "202610181070_alter_table_loan_products_add_code.up.sql"
+			file44, // "202610181071_update_loan_products_set_code.up.sql"
+			file45, // "202610181072_create_table_contract_number_sequences.up.sql"
+			file46, // "202610181073_update_consumer_transactions_deduplicate_contract_number.up.sql"
+			file47, // "202610181074_alter_table_consumer_transactions_unique_contract_number.up.sql"
+			file48, // "202610181080_create_table_api_client_consumers.up.sql"
+			file49, // "202610181090_create_table_merchants.up.sql"
+			file50, // "202610181091_create_table_merchant_loan_products.up.sql"
+			file51, // "202610181092_alter_table_api_client_add_merchant_id.up.sql"
+			file52, // "202610181093_alter_table_consumer_transactions_add_merchant.up.sql"
+			file53, // "202610181100_alter_table_consumers_rename_img_url_to_img_key.up.sql"
+			file54, // "202610181101_create_table_consumer_document_access_logs.up.sql"
+			file55, // "202610181110_create_table_regions.up.sql"
+			file56, // "202610181111_insert_into_regions.up.sql"
+			file57, // "202610181120_alter_table_consumers_encrypt_pii.up.sql"
+			file58, // "202610181130_create_table_roles.up.sql"
+			file59, // "202610181131_create_table_permissions.up.sql"
+			file60, // "202610181132_create_table_role_permissions.up.sql"
+			file61, // "202610181133_insert_into_roles.up.sql"
+			file62, // "202610181134_insert_into_permissions.up.sql"
+			file63, // "202610181135_insert_into_role_permissions.up.sql"
+			file64, // "202610181136_alter_table_users_add_role_id.up.sql"
+			file65, // "202610181137_update_users_set_role_id.up.sql"
+			file66, // "202610181140_alter_table_api_client_add_token_hash.up.sql"
+			file67, // "202610181141_update_api_client_set_token_hash.up.sql"
+			file68, // "202610181142_alter_table_api_client_drop_token.up.sql"
+			file69, // "202610181143_create_table_api_client_scopes.up.sql"
+			file70, // "202610181144_insert_into_api_client_scopes.up.sql"
+			file71, // "202610181145_update_permissions_api_clients_read.up.sql"
+			file72, // "202610181146_update_permissions_api_clients_write.up.sql"
 
 		},
 	}
@@ -82,19 +523,82 @@ func init() {
 	// register embeddedBox
 	embedded.RegisterEmbeddedBox(`./migrations`, &embedded.EmbeddedBox{
 		Name: `./migrations`,
-		Time: time.Unix(1745333977, 0),
+		Time: time.Unix(1792308928, 0),
 		Dirs: map[string]*embedded.EmbeddedDir{
 			"": dir1,
 		},
 		Files: map[string]*embedded.EmbeddedFile{
-			"202504220900_create_table_status.up.sql":                  file2,
-			"202504220901_insert_status_data.up.sql":                   file3,
-			"202504220902_create_table_consumers.up.sql":               file4,
-			"202504220903_create_table_users.up.sql":                   file5,
-			"202504220904_create_table_user_actions.up.sql":            file6,
-			"202504220905_create_table_consumer_credit_limits_.up.sql": file7,
-			"202504220906_create_table_consumer_transactions.up.sql":   file8,
-			"202504220907_create_table_api_client.up.sql":              file9,
+			"202504220900_create_table_status.up.sql":                                          file2,
+			"202504220901_insert_status_data.up.sql":                                           file3,
+			"202504220902_create_table_consumers.up.sql":                                       file4,
+			"202504220903_create_table_users.up.sql":                                           file5,
+			"202504220904_create_table_user_actions.up.sql":                                    file6,
+			"202504220905_create_table_consumer_credit_limits_.up.sql":                         file7,
+			"202504220906_create_table_consumer_transactions.up.sql":                           file8,
+			"202504220907_create_table_api_client.up.sql":                                      file9,
+			"202610180900_create_table_consumer_installments.up.sql":                           file10,
+			"202610180910_alter_table_consumer_installments_add_paid_amounts.up.sql":           file11,
+			"202610180911_create_table_consumer_payments.up.sql":                               file12,
+			"202610180912_create_table_consumer_payment_allocations.up.sql":                    file13,
+			"202610180920_alter_table_consumer_transactions_add_interest_method.up.sql":        file14,
+			"202610180930_create_table_loan_products.up.sql":                                   file15,
+			"202610180931_insert_loan_products_data.up.sql":                                    file16,
+			"202610180932_create_table_consumer_credit_limit_details.up.sql":                   file17,
+			"202610180933_insert_consumer_credit_limit_details_data.up.sql":                    file18,
+			"202610180934_alter_table_consumer_credit_limits_drop_tenor_columns.up.sql":        file19,
+			"202610180935_alter_table_consumer_transactions_add_loan_product_id.up.sql":        file20,
+			"202610180936_update_consumer_transactions_loan_product_id.up.sql":                 file21,
+			"202610181000_create_table_idempotency_keys.up.sql":                                file22,
+			"202610181010_create_table_consumer_transaction_statuses.up.sql":                   file23,
+			"202610181011_insert_consumer_transaction_statuses_data.up.sql":                    file24,
+			"202610181012_create_table_consumer_transaction_status_histories.up.sql":           file25,
+			"202610181013_update_consumer_transactions_status_id.up.sql":                       file26,
+			"202610181014_alter_table_consumer_transactions_status_id_default.up.sql":          file27,
+			"202610181020_alter_table_loan_products_add_cancellation_window_days.up.sql":       file28,
+			"202610181021_alter_table_consumer_transaction_status_histories_add_reason.up.sql": file29,
+			"202610181030_alter_table_loan_products_add_early_settlement_penalty.up.sql":       file30,
+			"202610181031_create_table_consumer_payoff_quotes.up.sql":                          file31,
+			"202610181040_alter_table_loan_products_add_late_fee_rules.up.sql":                 file32,
+			"202610181041_alter_table_consumer_installments_add_late_fees.up.sql":              file33,
+			"202610181042_alter_table_consumer_payment_allocations_add_late_fee_amount.up.sql": file34,
+			"202610181043_alter_table_consumer_transactions_add_days_past_due.up.sql":          file35,
+			"202610181050_create_table_underwriting_rule_sets.up.sql":                          file36,
+			"202610181051_insert_into_underwriting_rule_sets.up.sql":                           file37,
+			"202610181052_create_table_consumer_credit_limit_proposals.up.sql":                 file38,
+			"202610181053_create_table_consumer_credit_limit_proposal_details.up.sql":          file39,
+			"202610181060_alter_table_consumer_credit_limits_add_effective_dates.up.sql":       file40,
+			"202610181061_update_consumer_credit_limits_set_effective_dates.up.sql":            file41,
+			"202610181062_alter_table_consumer_credit_limits_modify_effective_from.up.sql":     file42,
+			"202610181070_alter_table_loan_products_add_code.up.sql":                           file43,
+			"202610181071_update_loan_products_set_code.up.sql":                                file44,
+			"202610181072_create_table_contract_number_sequences.up.sql":                       file45,
+			"202610181073_update_consumer_transactions_deduplicate_contract_number.up.sql":     file46,
+			"202610181074_alter_table_consumer_transactions_unique_contract_number.up.sql":     file47,
+			"202610181080_create_table_api_client_consumers.up.sql":                            file48,
+			"202610181090_create_table_merchants.up.sql":                                       file49,
+			"202610181091_create_table_merchant_loan_products.up.sql":                          file50,
+			"202610181092_alter_table_api_client_add_merchant_id.up.sql":                       file51,
+			"202610181093_alter_table_consumer_transactions_add_merchant.up.sql":               file52,
+			"202610181100_alter_table_consumers_rename_img_url_to_img_key.up.sql":              file53,
+			"202610181101_create_table_consumer_document_access_logs.up.sql":                   file54,
+			"202610181110_create_table_regions.up.sql":                                         file55,
+			"202610181111_insert_into_regions.up.sql":                                          file56,
+			"202610181120_alter_table_consumers_encrypt_pii.up.sql":                            file57,
+			"202610181130_create_table_roles.up.sql":                                           file58,
+			"202610181131_create_table_permissions.up.sql":                                     file59,
+			"202610181132_create_table_role_permissions.up.sql":                                file60,
+			"202610181133_insert_into_roles.up.sql":                                            file61,
+			"202610181134_insert_into_permissions.up.sql":                                      file62,
+			"202610181135_insert_into_role_permissions.up.sql":                                 file63,
+			"202610181136_alter_table_users_add_role_id.up.sql":                                file64,
+			"202610181137_update_users_set_role_id.up.sql":                                     file65,
+			"202610181140_alter_table_api_client_add_token_hash.up.sql":                        file66,
+			"202610181141_update_api_client_set_token_hash.up.sql":                             file67,
+			"202610181142_alter_table_api_client_drop_token.up.sql":                            file68,
+			"202610181143_create_table_api_client_scopes.up.sql":                               file69,
+			"202610181144_insert_into_api_client_scopes.up.sql":                                file70,
+			"202610181145_update_permissions_api_clients_read.up.sql":                          file71,
+			"202610181146_update_permissions_api_clients_write.up.sql":                         file72,
 		},
 	})
 }
@@ -21,6 +21,8 @@ require (
 	github.com/leekchan/accounting v1.0.0
 	github.com/pkg/errors v0.9.1
 	github.com/spf13/viper v1.19.0
+	golang.org/x/crypto v0.23.0
+	golang.org/x/time v0.11.0
 	gopkg.in/go-playground/validator.v9 v9.31.0
 )
 
@@ -87,12 +89,10 @@ require (
 	go.uber.org/atomic v1.9.0 // indirect
 	go.uber.org/multierr v1.9.0 // indirect
 	golang.org/x/arch v0.8.0 // indirect
-	golang.org/x/crypto v0.23.0 // indirect
 	golang.org/x/exp v0.0.0-20230905200255-921286631fa9 // indirect
 	golang.org/x/net v0.25.0 // indirect
 	golang.org/x/sys v0.20.0 // indirect
 	golang.org/x/text v0.15.0 // indirect
-	golang.org/x/time v0.11.0 // indirect
 	google.golang.org/protobuf v1.34.1 // indirect
 	gopkg.in/go-playground/assert.v1 v1.2.1 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
@@ -0,0 +1,36 @@
+package library
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+var (
+	// API_TOKEN_PREFIX starts every API client token, so a leaked one is easy to recognize
+	API_TOKEN_PREFIX = "kp_"
+	// API_TOKEN_PREFIX_LENGTH is how much of the token is kept in clear to tell tokens apart
+	API_TOKEN_PREFIX_LENGTH = 8
+)
+
+// NewAPIToken returns a random API client token, the hash it is stored under and its first characters.
+// The token itself is shown once and never stored.
+func NewAPIToken() (string, string, string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	token := API_TOKEN_PREFIX + base64.RawURLEncoding.EncodeToString(b)
+
+	return token, HashAPIToken(token), token[:API_TOKEN_PREFIX_LENGTH], nil
+}
+
+// HashAPIToken hashes a token for lookup. Tokens are random, so a plain SHA-256 is enough, and it matches the
+// SHA2(token, 256) the migration hashed the plaintext tokens with.
+func HashAPIToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -59,6 +59,21 @@ const (
 
 	// KeyRequestBody represents the body of the request
 	KeyRequestBody contextKey = "KeyRequestBody"
+
+	// KeyAPIClientID represents the api client of an external request
+	KeyAPIClientID contextKey = "APIClientID"
+
+	// KeyMerchantID represents the merchant of the api client of an external request
+	KeyMerchantID contextKey = "MerchantID"
+
+	// KeyAPIClientScopes represents the scopes of the api client of an external request
+	KeyAPIClientScopes contextKey = "APIClientScopes"
+
+	// KeyRoleID represents the role of the current logged-in UserID
+	KeyRoleID contextKey = "RoleID"
+
+	// KeyLoginSessionID represents the login session of the current access token
+	KeyLoginSessionID contextKey = "LoginSessionID"
 )
 
 // RequestStatus gets request status from context
@@ -172,6 +187,39 @@ func OutletID(ctx *gin.Context) int {
 	return 0
 }
 
+// RoleID gets the role of the current logged-in UserID, empty for tokens issued before roles existed
+func RoleID(ctx *gin.Context) string {
+	roleID := ctx.Value(fmt.Sprintf("%s", KeyRoleID))
+	if roleID != nil {
+		if v, ok := roleID.(string); ok {
+			return v
+		}
+	}
+	return ""
+}
+
+// APIClientScopes gets the scopes of the api client of an external request
+func APIClientScopes(ctx *gin.Context) []string {
+	scopes := ctx.Value(fmt.Sprintf("%s", KeyAPIClientScopes))
+	if scopes != nil {
+		if v, ok := scopes.([]string); ok {
+			return v
+		}
+	}
+	return nil
+}
+
+// LoginSessionID gets the login session the current access token belongs to
+func LoginSessionID(ctx *gin.Context) string {
+	sessionID := ctx.Value(fmt.Sprintf("%s", KeyLoginSessionID))
+	if sessionID != nil {
+		if v, ok := sessionID.(string); ok {
+			return v
+		}
+	}
+	return ""
+}
+
 // BusinessID gets current prefered BusinessID of UserID
 func BusinessID(ctx *gin.Context) int {
 	businessID := ctx.Value(fmt.Sprintf("%s", KeyBusinessID))
@@ -231,3 +279,23 @@ func CurrentXAccessToken(ctx *gin.Context) string {
 	}
 	return ""
 }
+
+// APIClientID gets the api client authenticated on an external request
+func APIClientID(ctx *gin.Context) int {
+	apiClientID := ctx.Value(fmt.Sprintf("%s", KeyAPIClientID))
+	if apiClientID != nil {
+		v := apiClientID.(int)
+		return v
+	}
+	return 0
+}
+
+// MerchantID gets the merchant the authenticated api client belongs to, empty when it has none
+func MerchantID(ctx *gin.Context) string {
+	merchantID := ctx.Value(fmt.Sprintf("%s", KeyMerchantID))
+	if merchantID != nil {
+		v := merchantID.(string)
+		return v
+	}
+	return ""
+}
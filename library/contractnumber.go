@@ -0,0 +1,42 @@
+package library
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"case-study-kredit-plus/configs"
+)
+
+var (
+	DEFAULT_CONTRACT_NUMBER_FORMAT = "{BRANCH}/{PRODUCT}/{YEAR}/{SEQ:6}"
+	DEFAULT_BRANCH_CODE            = "HO"
+)
+
+// ContractNumberSequenceKey fills every token of the pattern except the sequence. The result is the key of the
+// counter the sequence is drawn from, so the sequence restarts whenever branch, product or period change.
+// Supported tokens are {BRANCH}, {PRODUCT}, {YEAR}, {YY}, {MONTH} and {SEQ} or {SEQ:width}.
+func ContractNumberSequenceKey(pattern string, branch string, product string, on time.Time) (string, error) {
+	if len(configs.ContractNumberSequenceToken.FindAllString(pattern, -1)) != 1 {
+		return "", fmt.Errorf("contract number format %q must contain exactly one {SEQ} token", pattern)
+	}
+
+	replacer := strings.NewReplacer(
+		"{BRANCH}", branch,
+		"{PRODUCT}", product,
+		"{YEAR}", on.Format("2006"),
+		"{YY}", on.Format("06"),
+		"{MONTH}", on.Format("01"),
+	)
+
+	return replacer.Replace(pattern), nil
+}
+
+// FormatContractNumber puts the sequence into a key built by ContractNumberSequenceKey
+func FormatContractNumber(key string, sequence int) string {
+	return configs.ContractNumberSequenceToken.ReplaceAllStringFunc(key, func(token string) string {
+		width, _ := strconv.Atoi(configs.ContractNumberSequenceToken.FindStringSubmatch(token)[1])
+		return fmt.Sprintf("%0*d", width, sequence)
+	})
+}
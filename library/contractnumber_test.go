@@ -0,0 +1,85 @@
+package library_test
+
+import (
+	"testing"
+	"time"
+
+	"case-study-kredit-plus/library"
+)
+
+func TestContractNumber(t *testing.T) {
+	on := time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		pattern  string
+		branch   string
+		product  string
+		sequence int
+		wantKey  string
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "default format pads the sequence to six digits",
+			pattern:  library.DEFAULT_CONTRACT_NUMBER_FORMAT,
+			branch:   "HO",
+			product:  "M06",
+			sequence: 42,
+			wantKey:  "HO/M06/2026/{SEQ:6}",
+			want:     "HO/M06/2026/000042",
+		},
+		{
+			name:     "short year and month",
+			pattern:  "{BRANCH}-{YY}{MONTH}-{SEQ:4}",
+			branch:   "JKT",
+			product:  "M03",
+			sequence: 7,
+			wantKey:  "JKT-2603-{SEQ:4}",
+			want:     "JKT-2603-0007",
+		},
+		{
+			name:     "sequence without a width is not padded",
+			pattern:  "{PRODUCT}{SEQ}",
+			product:  "M01",
+			sequence: 7,
+			wantKey:  "M01{SEQ}",
+			want:     "M017",
+		},
+		{
+			name:     "sequence wider than its width is kept whole",
+			pattern:  "{BRANCH}/{SEQ:3}",
+			branch:   "HO",
+			sequence: 12345,
+			wantKey:  "HO/{SEQ:3}",
+			want:     "HO/12345",
+		},
+		{name: "no sequence", pattern: "{BRANCH}/{PRODUCT}/{YEAR}", wantErr: true},
+		{name: "two sequences", pattern: "{SEQ}/{SEQ:4}", wantErr: true},
+		{name: "malformed sequence", pattern: "{BRANCH}/{SEQ:}", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := library.ContractNumberSequenceKey(tt.pattern, tt.branch, tt.product, on)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ContractNumberSequenceKey(%s) = %s, want an error", tt.pattern, key)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ContractNumberSequenceKey(%s) error = %v", tt.pattern, err)
+			}
+
+			if key != tt.wantKey {
+				t.Errorf("ContractNumberSequenceKey(%s) = %s, want %s", tt.pattern, key, tt.wantKey)
+			}
+
+			if got := library.FormatContractNumber(key, tt.sequence); got != tt.want {
+				t.Errorf("FormatContractNumber(%s, %d) = %s, want %s", key, tt.sequence, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package library
+
+import "math"
+
+// RoundCurrency rounds an amount to 2 decimal places, matching DECIMAL(12,2) columns
+func RoundCurrency(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -29,6 +29,16 @@ func (m *Manager) RunInTransaction(ctx *gin.Context, f func(tctx *gin.Context) *
 		return err
 	}
 
+	// the context outlives the transaction, code running after it must not reach the finished tx
+	previous, hasPrevious := ctx.Get("transaction")
+	defer func() {
+		if hasPrevious {
+			ctx.Set("transaction", previous)
+			return
+		}
+		delete(ctx.Keys, "transaction")
+	}()
+
 	ctx = NewContext(ctx, tx)
 	if err != nil {
 		fmt.Printf("\n[RunInTransaction - Prepare] Error: %v\n", err)
@@ -0,0 +1,161 @@
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+
+	"case-study-kredit-plus/configs"
+)
+
+var PREFIX = "enc:"
+
+// Cipher encrypts single column values with AES-256-GCM. An encrypted value reads "enc:<key version>:<base64>".
+// Values without the prefix were written before encryption and are returned as they are, so a table can be
+// encrypted row by row.
+type Cipher struct {
+	keys KeyProvider
+}
+
+func NewCipher(keys KeyProvider) *Cipher {
+	return &Cipher{keys: keys}
+}
+
+// New builds the cipher from PII_ENCRYPTION_KEY_FILE, or from the PII_ENCRYPTION_* settings when no key file is set
+func New(config *configs.Config) (*Cipher, error) {
+	if config.PIIEncryptionKeyFile != "" {
+		keys, err := LoadKeyFile(config.PIIEncryptionKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		return NewCipher(keys), nil
+	}
+
+	if config.PIIEncryptionKeys == "" {
+		return nil, fmt.Errorf("PII_ENCRYPTION_KEY_FILE or PII_ENCRYPTION_KEYS must be set")
+	}
+
+	keys, err := ParseKeys(config.PIIEncryptionKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	blindIndexKey, err := base64.StdEncoding.DecodeString(config.PIIBlindIndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("blind index key is not base64: %v", err)
+	}
+
+	provider, err := NewLocalKeyProvider(config.PIIEncryptionKeyVersion, keys, blindIndexKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewCipher(provider), nil
+}
+
+// NewFromConfiguration builds the configured cipher, it stops the application when the keys are unusable
+func NewFromConfiguration() *Cipher {
+	config, err := configs.GetConfiguration()
+	if err != nil {
+		log.Fatalln("failed to get configuration: ", err)
+	}
+
+	fieldCipher, err := New(config)
+	if err != nil {
+		log.Fatalln("failed to set up field encryption: ", err)
+	}
+
+	return fieldCipher
+}
+
+// Encrypt seals the value under the current key. Empty values stay empty.
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	version, key := c.keys.CurrentKey()
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+
+	return PREFIX + version + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if !strings.HasPrefix(value, PREFIX) {
+		return value, nil
+	}
+
+	parts := strings.SplitN(strings.TrimPrefix(value, PREFIX), ":", 2)
+	if len(parts) != 2 {
+		return "", fmt.Errorf("encrypted value is malformed")
+	}
+
+	key, ok := c.keys.Key(parts[0])
+	if !ok {
+		return "", fmt.Errorf("encryption key %s is unknown", parts[0])
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", fmt.Errorf("encrypted value is malformed: %v", err)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("encrypted value is malformed")
+	}
+
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("encrypted value cannot be decrypted with key %s", parts[0])
+	}
+
+	return string(plaintext), nil
+}
+
+// IsCurrent tells whether the value is empty or encrypted under the current key, anything else needs re-encryption
+func (c *Cipher) IsCurrent(value string) bool {
+	version, _ := c.keys.CurrentKey()
+	return value == "" || strings.HasPrefix(value, PREFIX+version+":")
+}
+
+// BlindIndex is a keyed hash of the value. Equal values give equal hashes, so the hash column can be searched
+// for an exact match without revealing the value.
+func (c *Cipher) BlindIndex(plaintext string) string {
+	mac := hmac.New(sha256.New, c.keys.BlindIndexKey())
+	mac.Write([]byte(plaintext))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,115 @@
+package fieldcrypt
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// KeyProvider hands out the keys of the field encryption. Keys are versioned, the version is stored with every
+// value, so values written under an older key stay readable as long as the provider still knows that key.
+type KeyProvider interface {
+	// CurrentKey is the version and key new values are encrypted with
+	CurrentKey() (string, []byte)
+	Key(version string) ([]byte, bool)
+	// BlindIndexKey keys the hashes that allow exact-match lookups on encrypted columns
+	BlindIndexKey() []byte
+}
+
+// LocalKeyProvider keeps the keys in memory, loaded from a key file or from the configuration
+type LocalKeyProvider struct {
+	current       string
+	keys          map[string][]byte
+	blindIndexKey []byte
+}
+
+// keyFile is the JSON layout of PII_ENCRYPTION_KEY_FILE, keys are base64 encoded
+type keyFile struct {
+	CurrentVersion string            `json:"CurrentVersion"`
+	Keys           map[string]string `json:"Keys"`
+	BlindIndexKey  string            `json:"BlindIndexKey"`
+}
+
+func NewLocalKeyProvider(current string, keys map[string][]byte, blindIndexKey []byte) (*LocalKeyProvider, error) {
+	for version, key := range keys {
+		if version == "" || strings.Contains(version, ":") {
+			return nil, fmt.Errorf("encryption key version %q must not be empty or contain ':'", version)
+		}
+		if len(key) != 32 {
+			return nil, fmt.Errorf("encryption key %s must be 32 bytes, it is %d", version, len(key))
+		}
+	}
+
+	if _, ok := keys[current]; !ok {
+		return nil, fmt.Errorf("current encryption key version %q is not among the keys", current)
+	}
+
+	if len(blindIndexKey) < 32 {
+		return nil, fmt.Errorf("blind index key must be at least 32 bytes")
+	}
+
+	return &LocalKeyProvider{current: current, keys: keys, blindIndexKey: blindIndexKey}, nil
+}
+
+// LoadKeyFile reads a key file of the form {"CurrentVersion": "v2", "Keys": {"v1": "...", "v2": "..."}, "BlindIndexKey": "..."}
+func LoadKeyFile(path string) (*LocalKeyProvider, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file keyFile
+	err = json.Unmarshal(content, &file)
+	if err != nil {
+		return nil, fmt.Errorf("key file %s cannot be read: %v", path, err)
+	}
+
+	keys := map[string][]byte{}
+	for version, encoded := range file.Keys {
+		keys[version], err = base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %s is not base64: %v", version, err)
+		}
+	}
+
+	blindIndexKey, err := base64.StdEncoding.DecodeString(file.BlindIndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("blind index key is not base64: %v", err)
+	}
+
+	return NewLocalKeyProvider(file.CurrentVersion, keys, blindIndexKey)
+}
+
+// ParseKeys reads keys written as "v1:base64,v2:base64"
+func ParseKeys(value string) (map[string][]byte, error) {
+	keys := map[string][]byte{}
+	for _, entry := range strings.Split(value, ",") {
+		parts := strings.SplitN(strings.TrimSpace(entry), ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("encryption key %q must be written as version:base64", entry)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %s is not base64: %v", parts[0], err)
+		}
+		keys[parts[0]] = key
+	}
+
+	return keys, nil
+}
+
+func (p *LocalKeyProvider) CurrentKey() (string, []byte) {
+	return p.current, p.keys[p.current]
+}
+
+func (p *LocalKeyProvider) Key(version string) ([]byte, bool) {
+	key, ok := p.keys[version]
+	return key, ok
+}
+
+func (p *LocalKeyProvider) BlindIndexKey() []byte {
+	return p.blindIndexKey
+}
@@ -0,0 +1,61 @@
+package filestorage
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+var (
+	MAX_IMAGE_SIZE      int64 = 5 << 20
+	MAX_IMAGE_DIMENSION       = 8000
+
+	IMAGE_EXTENSIONS = map[string]string{
+		"image/jpeg": "jpg",
+		"image/png":  "png",
+	}
+)
+
+// SanitizeImage checks that the upload really is a JPEG or PNG of a sane size, and re-encodes it.
+// Re-encoding keeps the pixels only, so EXIF (camera, GPS location) and anything appended to the file is dropped.
+// It returns the cleaned file and its content type.
+func SanitizeImage(body []byte) ([]byte, string, error) {
+	if int64(len(body)) > MAX_IMAGE_SIZE {
+		return nil, "", fmt.Errorf("image is larger than %d MB", MAX_IMAGE_SIZE>>20)
+	}
+
+	// the content is sniffed, the file name and the header sent by the client are not trusted
+	contentType := http.DetectContentType(body)
+	if _, ok := IMAGE_EXTENSIONS[contentType]; !ok {
+		return nil, "", fmt.Errorf("image type %s is not allowed, only JPEG and PNG are", contentType)
+	}
+
+	imageConfig, _, err := image.DecodeConfig(bytes.NewReader(body))
+	if err != nil {
+		return nil, "", fmt.Errorf("image cannot be read: %v", err)
+	}
+
+	if imageConfig.Width > MAX_IMAGE_DIMENSION || imageConfig.Height > MAX_IMAGE_DIMENSION {
+		return nil, "", fmt.Errorf("image is larger than %dx%d pixels", MAX_IMAGE_DIMENSION, MAX_IMAGE_DIMENSION)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(body))
+	if err != nil {
+		return nil, "", fmt.Errorf("image cannot be read: %v", err)
+	}
+
+	var cleaned bytes.Buffer
+	if contentType == "image/png" {
+		err = png.Encode(&cleaned, img)
+	} else {
+		err = jpeg.Encode(&cleaned, img, &jpeg.Options{Quality: 90})
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return cleaned.Bytes(), contentType, nil
+}
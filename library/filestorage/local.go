@@ -0,0 +1,85 @@
+package filestorage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStorage stores files under a directory of the local filesystem, meant for development
+type LocalStorage struct {
+	root string
+}
+
+func NewLocalStorage(root string) *LocalStorage {
+	return &LocalStorage{root: root}
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o750)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, body, 0o640)
+}
+
+func (s *LocalStorage) Get(ctx context.Context, key string) ([]byte, string, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return body, http.DetectContentType(body), nil
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *LocalStorage) MakePrivate(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	return os.Chmod(path, 0o640)
+}
+
+// ObjectKey never matches, local files have no URL
+func (s *LocalStorage) ObjectKey(rawURL string) (string, bool) {
+	return "", false
+}
+
+// path maps the key below the root, keys climbing out of it are refused
+func (s *LocalStorage) path(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if key == "" || strings.HasSuffix(cleaned, "/") || cleaned == "/" {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
@@ -0,0 +1,117 @@
+package filestorage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3Storage stores files in a bucket of any S3-compatible service, Vultr object storage in production
+type S3Storage struct {
+	client *s3.Client
+	bucket string
+}
+
+func NewS3Storage(hostname string, region string, bucket string, accessKey string, secretKey string) (*S3Storage, error) {
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	endpoint := aws.Endpoint{
+		PartitionID:   "aws",
+		URL:           hostname,
+		SigningRegion: region,
+	}
+
+	endpointResolver := aws.EndpointResolverFunc(func(service, region string) (aws.Endpoint, error) { return endpoint, nil })
+
+	cfg, err := config.LoadDefaultConfig(
+		context.TODO(),
+		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")),
+		config.WithRegion(region),
+		config.WithEndpointResolver(endpointResolver),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) { o.UsePathStyle = true })
+
+	return &S3Storage{client: client, bucket: bucket}, nil
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String(contentType),
+		ACL:         s3types.ObjectCannedACLPrivate,
+	})
+
+	return err
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) ([]byte, string, error) {
+	result, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	defer result.Body.Close()
+
+	body, err := io.ReadAll(result.Body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return body, aws.ToString(result.ContentType), nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+
+	return err
+}
+
+func (s *S3Storage) MakePrivate(ctx context.Context, key string) error {
+	_, err := s.client.PutObjectAcl(ctx, &s3.PutObjectAclInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		ACL:    s3types.ObjectCannedACLPrivate,
+	})
+
+	return err
+}
+
+// ObjectKey understands both path style (host/bucket/key) and virtual host style (bucket.host/key) URLs
+func (s *S3Storage) ObjectKey(rawURL string) (string, bool) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return "", false
+	}
+
+	path := strings.TrimPrefix(parsed.Path, "/")
+	if strings.HasPrefix(parsed.Host, s.bucket+".") && path != "" {
+		return path, true
+	}
+
+	if strings.HasPrefix(path, s.bucket+"/") && len(path) > len(s.bucket)+1 {
+		return strings.TrimPrefix(path, s.bucket+"/"), true
+	}
+
+	return "", false
+}
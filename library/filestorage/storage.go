@@ -0,0 +1,61 @@
+package filestorage
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"case-study-kredit-plus/configs"
+)
+
+var (
+	DRIVER_S3    = "s3"
+	DRIVER_LOCAL = "local"
+
+	DEFAULT_LOCAL_PATH = "storage"
+)
+
+// Storage keeps files under object keys. Callers store the key, never a URL, so the backend can change
+// without touching the records pointing at it.
+// Files are private, they are only handed out through the application.
+type Storage interface {
+	Put(ctx context.Context, key string, body []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, string, error)
+	Delete(ctx context.Context, key string) error
+
+	// MakePrivate revokes public access to a file stored before files were private
+	MakePrivate(ctx context.Context, key string) error
+	// ObjectKey returns the key of a URL pointing into this storage, for records that still hold URLs
+	ObjectKey(rawURL string) (string, bool)
+}
+
+// New builds the backend picked by FILE_STORAGE_DRIVER, S3-compatible storage unless it says local
+func New(config *configs.Config) (Storage, error) {
+	switch config.FileStorageDriver {
+	case "", DRIVER_S3:
+		return NewS3Storage(config.VultrHostname, config.VultrRegion, config.VultrBucket, config.VultrAccessKey, config.VultrSecretKey)
+	case DRIVER_LOCAL:
+		path := config.FileStorageLocalPath
+		if path == "" {
+			path = DEFAULT_LOCAL_PATH
+		}
+		return NewLocalStorage(path), nil
+	}
+
+	return nil, fmt.Errorf("unknown file storage driver %q", config.FileStorageDriver)
+}
+
+// NewFromConfiguration builds the configured backend, it stops the application when the configuration is unusable
+func NewFromConfiguration() Storage {
+	config, err := configs.GetConfiguration()
+	if err != nil {
+		log.Fatalln("failed to get configuration: ", err)
+	}
+
+	storage, err := New(config)
+	if err != nil {
+		log.Fatalln("failed to set up file storage: ", err)
+	}
+
+	return storage
+}
@@ -0,0 +1,21 @@
+package helpers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestHash fingerprints the method, path and form values of a request,
+// so a retry can be told apart from a different request sent with the same key
+func RequestHash(c *gin.Context) string {
+	// parses url encoded bodies as well, the ErrNotMultipart it returns for them is expected
+	c.Request.ParseMultipartForm(32 << 20)
+
+	hash := sha256.New()
+	hash.Write([]byte(c.Request.Method + " " + c.FullPath() + "\n"))
+	hash.Write([]byte(c.Request.PostForm.Encode()))
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"regexp"
+	"sort"
 
 	"case-study-kredit-plus/library/types"
 
@@ -37,6 +39,15 @@ type DataError struct {
 	Status  int    `json:"Status"`
 }
 
+// sensitiveDigits matches NIKs and other long digit runs (account and phone numbers) that may end up in error
+// messages, database errors quote the offending values
+var sensitiveDigits = regexp.MustCompile(`\d{10,}`)
+
+// redact keeps personal data out of the logs
+func redact(message string) string {
+	return sensitiveDigits.ReplaceAllString(message, "[REDACTED]")
+}
+
 // MakeFieldError create field error object
 func MakeFieldError(field string, message string) *FieldError {
 	return &FieldError{
@@ -58,6 +69,8 @@ func Error(c *gin.Context, data string, status int, err types.Error) {
 		errorCode = "Unauthorized"
 	case http.StatusNotFound:
 		errorCode = "NotFound"
+	case http.StatusConflict:
+		errorCode = "Conflict"
 	case http.StatusBadRequest:
 		errorCode = "BadRequest"
 	case http.StatusUnprocessableEntity:
@@ -83,6 +96,16 @@ func Error(c *gin.Context, data string, status int, err types.Error) {
 		data = "Unprocessable Entity"
 		errorCode = "UnprocessableEntity"
 		status = http.StatusUnprocessableEntity
+	case types.FieldErrors:
+		fields := []string{}
+		for field := range err.Error.(types.FieldErrors) {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		for _, field := range fields {
+			errorFields = append(errorFields, MakeFieldError(field, err.Error.(types.FieldErrors)[field]))
+		}
 	}
 
 	c.JSON(status, ErrorResponse{
@@ -94,8 +117,8 @@ func Error(c *gin.Context, data string, status int, err types.Error) {
 	})
 
 	if err.Error != nil {
-		log.Printf("INFO: %v\n", err.Error.Error())
-		log.Printf("DETAIL [%s - %s]: %s\n", err.Path, err.Type, err.Message)
+		log.Printf("INFO: %v\n", redact(err.Error.Error()))
+		log.Printf("DETAIL [%s - %s]: %s\n", err.Path, err.Type, redact(err.Message))
 		type stackTracer interface {
 			StackTrace() errors.StackTrace
 		}
@@ -0,0 +1,42 @@
+package library
+
+import (
+	"fmt"
+	"math"
+)
+
+var (
+	INTEREST_METHOD_FLAT    = "flat"
+	INTEREST_METHOD_ANNUITY = "annuity"
+)
+
+func ValidateInterestMethod(method string) bool {
+	return method == INTEREST_METHOD_FLAT || method == INTEREST_METHOD_ANNUITY
+}
+
+// CalculateInterest returns the total interest on principal over tenor months, monthlyRate is in percent.
+// Flat charges the rate on the original principal every month, annuity charges it on the declining balance.
+func CalculateInterest(method string, principal float64, monthlyRate float64, tenor int) (float64, error) {
+	if tenor <= 0 {
+		return 0, fmt.Errorf("invalid tenor %d", tenor)
+	}
+
+	switch method {
+	case INTEREST_METHOD_FLAT:
+		return RoundCurrency(principal * monthlyRate / 100 * float64(tenor)), nil
+	case INTEREST_METHOD_ANNUITY:
+		return RoundCurrency(AnnuityPayment(principal, monthlyRate, tenor)*float64(tenor) - principal), nil
+	}
+
+	return 0, fmt.Errorf("unknown interest method %q", method)
+}
+
+// AnnuityPayment returns the fixed monthly principal and interest payment, monthlyRate is in percent
+func AnnuityPayment(principal float64, monthlyRate float64, tenor int) float64 {
+	rate := monthlyRate / 100
+	if rate == 0 {
+		return principal / float64(tenor)
+	}
+
+	return principal * rate / (1 - math.Pow(1+rate, -float64(tenor)))
+}
@@ -0,0 +1,73 @@
+package library_test
+
+import (
+	"math"
+	"testing"
+
+	"case-study-kredit-plus/library"
+)
+
+func TestCalculateInterest(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		principal   float64
+		monthlyRate float64
+		tenor       int
+		want        float64
+		wantErr     bool
+	}{
+		{name: "flat charges the rate on the original principal every month", method: "flat", principal: 1000000, monthlyRate: 2, tenor: 6, want: 120000},
+		{name: "flat rounds to cents", method: "flat", principal: 1234567, monthlyRate: 1.75, tenor: 3, want: 64814.77},
+		{name: "flat without a rate", method: "flat", principal: 1000000, monthlyRate: 0, tenor: 6, want: 0},
+		{name: "annuity charges the rate on the declining balance", method: "annuity", principal: 1000000, monthlyRate: 2, tenor: 6, want: 71154.87},
+		{name: "annuity over three months", method: "annuity", principal: 1500000, monthlyRate: 2.5, tenor: 3, want: 75617.25},
+		{name: "annuity without a rate", method: "annuity", principal: 1000000, monthlyRate: 0, tenor: 6, want: 0},
+		{name: "annuity over one month is the same as flat", method: "annuity", principal: 1000000, monthlyRate: 2, tenor: 1, want: 20000},
+		{name: "zero tenor", method: "flat", principal: 1000000, monthlyRate: 2, tenor: 0, wantErr: true},
+		{name: "unknown method", method: "effective", principal: 1000000, monthlyRate: 2, tenor: 6, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := library.CalculateInterest(tt.method, tt.principal, tt.monthlyRate, tt.tenor)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("CalculateInterest() = %v, want an error", got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("CalculateInterest() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("CalculateInterest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnnuityPayment(t *testing.T) {
+	tests := []struct {
+		name        string
+		principal   float64
+		monthlyRate float64
+		tenor       int
+		want        float64
+	}{
+		{name: "fixed payment", principal: 1000000, monthlyRate: 2, tenor: 6, want: 178525.81},
+		{name: "one month pays principal and one month of interest", principal: 1000000, monthlyRate: 2, tenor: 1, want: 1020000},
+		{name: "without a rate the principal is split evenly", principal: 900000, monthlyRate: 0, tenor: 3, want: 300000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := library.AnnuityPayment(tt.principal, tt.monthlyRate, tt.tenor)
+			if math.Abs(library.RoundCurrency(got)-tt.want) > 0.001 {
+				t.Errorf("AnnuityPayment() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
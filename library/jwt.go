@@ -6,6 +6,7 @@ import (
 
 	"case-study-kredit-plus/configs"
 	"case-study-kredit-plus/library/appcontext"
+	"case-study-kredit-plus/library/jwtkeys"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
@@ -16,6 +17,10 @@ type Credential struct {
 	Username string `json:"Username"`
 	Email    string `json:"Email"`
 	Type     string `json:"Type"`
+	RoleID   string `json:"RoleID"`
+
+	// SessionID is the login session the token belongs to, it goes in the "sid" claim
+	SessionID string `json:"SessionID"`
 
 	FsId         string `json:"fsid"`
 	ClientId     string `json:"clientid"`
@@ -40,40 +45,24 @@ const JwtSalt = "secret"
 func JwtSignString(c Credential) (string, error) {
 	config, _ := configs.GetConfiguration()
 
-	sign := jwt.New(jwt.GetSigningMethod("HS256"))
-	claims := sign.Claims.(jwt.MapClaims)
+	now := time.Now()
 
+	claims := jwt.MapClaims{}
 	claims["ID"] = c.ID
 	claims["Email"] = c.Email
 	claims["LoginTime"] = UTCPlus7()
-	claims["Exp"] = UTCPlus7().Add(time.Duration(config.JwtTimeOut) * time.Second)
 	claims["Type"] = c.Type
+	claims["RoleID"] = c.RoleID
+	claims["sid"] = c.SessionID
+	claims["iat"] = now.Unix()
+	claims["nbf"] = now.Unix()
+	claims["exp"] = now.Add(time.Duration(config.JwtTimeOut) * time.Second).Unix()
 
-	// redisClient := redis.NewClient(&redis.Options{
-	// 	Addr:     config.RedisAddr,
-	// 	Password: config.RedisPassword,
-	// 	DB:       config.RedisDB,
-	// })
-
-	token, err := sign.SignedString([]byte("secret"))
+	token, err := jwtkeys.FromConfiguration().Sign(claims)
 	if err != nil {
 		return "", err
 	}
 
-	// if errRedis := redisClient.Set(
-	// 	token,
-	// 	fmt.Sprintf(`{"id":%s}`, c.ID),
-	// 	time.Second*time.Duration(config.RedisTimeOut),
-	// ).Err(); errRedis != nil {
-	// 	log.Printf(`
-	// 	======================================================================
-	// 	Error Storing Caching in "Auth":
-	// 	Error: %v,
-	// 	======================================================================
-	// 	`, errRedis)
-	// 	return "", errRedis
-	// }
-
 	return token, nil
 }
 
@@ -118,12 +107,7 @@ func JwtSignMobileString(c Credential) (string, error) {
 }
 
 func extractClaims(tokenStr string) (jwt.MapClaims, bool) {
-	hmacSecretString := "secret" // Value
-	hmacSecret := []byte(hmacSecretString)
-	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		// check token signing method etc
-		return hmacSecret, nil
-	})
+	token, err := jwtkeys.FromConfiguration().Parse(tokenStr, time.Now())
 
 	if err != nil {
 		return nil, false
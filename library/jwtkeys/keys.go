@@ -0,0 +1,394 @@
+package jwtkeys
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"case-study-kredit-plus/configs"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+var (
+	ALGORITHM_HS256 = "HS256"
+	ALGORITHM_RS256 = "RS256"
+	ALGORITHM_EDDSA = "EdDSA"
+
+	DEFAULT_KEY_ID = "default"
+
+	// CLOCK_SKEW is how far the clocks of the servers may drift apart when exp, nbf and iat are checked
+	CLOCK_SKEW = 30 * time.Second
+
+	configured     *KeySet
+	configuredOnce sync.Once
+)
+
+// Key is a signing key with the ID ("kid") tokens signed with it carry in their header.
+// Keys with only a public key verify tokens but cannot sign them.
+type Key struct {
+	ID        string
+	Algorithm string
+
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+// KeySet signs tokens with its current key and verifies tokens signed with any of its keys, so a key can be
+// replaced without logging everybody out: make the new key current and keep the old one until its tokens expired.
+type KeySet struct {
+	current string
+	keys    map[string]*Key
+}
+
+// keyFile is the JSON layout of JWT_KEY_FILE. HS256 keys hold a base64 Secret, RS256 and EdDSA keys the path
+// of a PEM file, relative to the key file.
+type keyFile struct {
+	CurrentKeyID string `json:"CurrentKeyID"`
+	Keys         []struct {
+		KeyID          string `json:"KeyID"`
+		Algorithm      string `json:"Algorithm"`
+		Secret         string `json:"Secret"`
+		PrivateKeyFile string `json:"PrivateKeyFile"`
+		PublicKeyFile  string `json:"PublicKeyFile"`
+	} `json:"Keys"`
+}
+
+func NewKeySet(current string, keys []*Key) (*KeySet, error) {
+	set := &KeySet{current: current, keys: map[string]*Key{}}
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, fmt.Errorf("JWT key ID must not be empty")
+		}
+		if _, ok := set.keys[key.ID]; ok {
+			return nil, fmt.Errorf("JWT key ID %q is used twice", key.ID)
+		}
+		set.keys[key.ID] = key
+	}
+
+	key, ok := set.keys[current]
+	if !ok {
+		return nil, fmt.Errorf("current JWT key %q is not among the keys", current)
+	}
+
+	if key.signKey == nil {
+		return nil, fmt.Errorf("current JWT key %q has no private key", current)
+	}
+
+	return set, nil
+}
+
+// NewHMACKey builds an HS256 key, the secret must be at least 32 bytes
+func NewHMACKey(id string, secret []byte) (*Key, error) {
+	if len(secret) < 32 {
+		return nil, fmt.Errorf("JWT key %s: secret must be at least 32 bytes", id)
+	}
+
+	return &Key{ID: id, Algorithm: ALGORITHM_HS256, signKey: secret, verifyKey: secret}, nil
+}
+
+// NewPEMKey builds an RS256 or EdDSA key from a PEM private key, or from a PEM public key when privatePEM is empty
+func NewPEMKey(id string, algorithm string, privatePEM []byte, publicPEM []byte) (*Key, error) {
+	key := &Key{ID: id, Algorithm: algorithm}
+
+	switch algorithm {
+	case ALGORITHM_RS256:
+		if len(privatePEM) > 0 {
+			privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+			if err != nil {
+				return nil, fmt.Errorf("JWT key %s: %v", id, err)
+			}
+			if privateKey.N.BitLen() < 2048 {
+				return nil, fmt.Errorf("JWT key %s: RSA keys must be at least 2048 bits", id)
+			}
+			key.signKey, key.verifyKey = privateKey, &privateKey.PublicKey
+		} else {
+			publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicPEM)
+			if err != nil {
+				return nil, fmt.Errorf("JWT key %s: %v", id, err)
+			}
+			key.verifyKey = publicKey
+		}
+
+	case ALGORITHM_EDDSA:
+		if len(privatePEM) > 0 {
+			parsed, err := parsePEM(privatePEM, x509.ParsePKCS8PrivateKey)
+			if err != nil {
+				return nil, fmt.Errorf("JWT key %s: %v", id, err)
+			}
+			privateKey, ok := parsed.(ed25519.PrivateKey)
+			if !ok {
+				return nil, fmt.Errorf("JWT key %s: not an Ed25519 private key", id)
+			}
+			key.signKey, key.verifyKey = privateKey, privateKey.Public()
+		} else {
+			parsed, err := parsePEM(publicPEM, x509.ParsePKIXPublicKey)
+			if err != nil {
+				return nil, fmt.Errorf("JWT key %s: %v", id, err)
+			}
+			publicKey, ok := parsed.(ed25519.PublicKey)
+			if !ok {
+				return nil, fmt.Errorf("JWT key %s: not an Ed25519 public key", id)
+			}
+			key.verifyKey = publicKey
+		}
+
+	default:
+		return nil, fmt.Errorf("JWT key %s: algorithm %q is not supported, use HS256, RS256 or EdDSA", id, algorithm)
+	}
+
+	return key, nil
+}
+
+func parsePEM(content []byte, parse func([]byte) (interface{}, error)) (interface{}, error) {
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("key is not PEM encoded")
+	}
+
+	return parse(block.Bytes)
+}
+
+// LoadKeyFile reads a key file of the form
+// {"CurrentKeyID": "2026-10", "Keys": [{"KeyID": "2026-10", "Algorithm": "RS256", "PrivateKeyFile": "2026-10.pem"}]}
+func LoadKeyFile(path string) (*KeySet, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var file keyFile
+	err = json.Unmarshal(content, &file)
+	if err != nil {
+		return nil, fmt.Errorf("key file %s cannot be read: %v", path, err)
+	}
+
+	readPEM := func(name string) ([]byte, error) {
+		if name == "" {
+			return nil, nil
+		}
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(filepath.Dir(path), name)
+		}
+		return os.ReadFile(name)
+	}
+
+	keys := []*Key{}
+	for _, v := range file.Keys {
+		var key *Key
+		if v.Algorithm == ALGORITHM_HS256 {
+			secret, err := base64.StdEncoding.DecodeString(v.Secret)
+			if err != nil {
+				return nil, fmt.Errorf("JWT key %s: secret is not base64: %v", v.KeyID, err)
+			}
+			key, err = NewHMACKey(v.KeyID, secret)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			privatePEM, err := readPEM(v.PrivateKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("JWT key %s: %v", v.KeyID, err)
+			}
+			publicPEM, err := readPEM(v.PublicKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("JWT key %s: %v", v.KeyID, err)
+			}
+			key, err = NewPEMKey(v.KeyID, v.Algorithm, privatePEM, publicPEM)
+			if err != nil {
+				return nil, err
+			}
+		}
+		keys = append(keys, key)
+	}
+
+	return NewKeySet(file.CurrentKeyID, keys)
+}
+
+// New builds the key set from JWT_KEY_FILE, or from JWT_SECRET (base64) and JWT_KEY_ID when no key file is set
+func New(config *configs.Config) (*KeySet, error) {
+	if config.JwtKeyFile != "" {
+		return LoadKeyFile(config.JwtKeyFile)
+	}
+
+	if config.JwtSecret == "" {
+		return nil, fmt.Errorf("JWT_KEY_FILE or JWT_SECRET must be set")
+	}
+
+	secret, err := base64.StdEncoding.DecodeString(config.JwtSecret)
+	if err != nil {
+		return nil, fmt.Errorf("JWT secret is not base64: %v", err)
+	}
+
+	keyID := config.JwtKeyID
+	if keyID == "" {
+		keyID = DEFAULT_KEY_ID
+	}
+
+	key, err := NewHMACKey(keyID, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeySet(keyID, []*Key{key})
+}
+
+// FromConfiguration returns the configured key set, loaded once. It stops the application when the keys are unusable.
+func FromConfiguration() *KeySet {
+	configuredOnce.Do(func() {
+		config, err := configs.GetConfiguration()
+		if err != nil {
+			log.Fatalln("failed to get configuration: ", err)
+		}
+
+		configured, err = New(config)
+		if err != nil {
+			log.Fatalln("failed to set up JWT keys: ", err)
+		}
+	})
+
+	return configured
+}
+
+// Sign signs the claims with the current key, its ID goes in the "kid" header
+func (s *KeySet) Sign(claims jwt.MapClaims) (string, error) {
+	key := s.keys[s.current]
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(key.Algorithm), claims)
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.signKey)
+}
+
+// Parse verifies the signature with the key named by the "kid" header, refusing any other algorithm than the
+// key's, and checks the exp, nbf and iat claims. exp and iat are required.
+func (s *KeySet) Parse(tokenString string, now time.Time) (*jwt.Token, error) {
+	parser := &jwt.Parser{
+		ValidMethods:         []string{ALGORITHM_HS256, ALGORITHM_RS256, ALGORITHM_EDDSA},
+		SkipClaimsValidation: true,
+	}
+
+	token, err := parser.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		keyID, _ := token.Header["kid"].(string)
+		key, ok := s.keys[keyID]
+		if !ok {
+			return nil, fmt.Errorf("unknown key ID %q", keyID)
+		}
+
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("key %s does not sign with %v", keyID, token.Header["alg"])
+		}
+
+		return key.verifyKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, fmt.Errorf("token claims cannot be read")
+	}
+
+	exp, ok := numericClaim(claims, "exp")
+	if !ok {
+		return nil, fmt.Errorf("token has no exp claim")
+	}
+	if now.After(time.Unix(exp, 0).Add(CLOCK_SKEW)) {
+		return nil, fmt.Errorf("token is expired")
+	}
+
+	iat, ok := numericClaim(claims, "iat")
+	if !ok {
+		return nil, fmt.Errorf("token has no iat claim")
+	}
+	if now.Add(CLOCK_SKEW).Before(time.Unix(iat, 0)) {
+		return nil, fmt.Errorf("token is issued in the future")
+	}
+
+	if nbf, ok := numericClaim(claims, "nbf"); ok && now.Add(CLOCK_SKEW).Before(time.Unix(nbf, 0)) {
+		return nil, fmt.Errorf("token is not valid yet")
+	}
+
+	return token, nil
+}
+
+func numericClaim(claims jwt.MapClaims, name string) (int64, bool) {
+	switch v := claims[name].(type) {
+	case float64:
+		return int64(v), true
+	case json.Number:
+		n, err := v.Int64()
+		return n, err == nil
+	}
+
+	return 0, false
+}
+
+// JWK is a public key in the JSON Web Key format
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+
+	// RSA
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	// Ed25519
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS lists the public keys, HS256 secrets are never published
+func (s *KeySet) JWKS() JWKS {
+	ids := []string{}
+	for id := range s.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	result := JWKS{Keys: []JWK{}}
+	for _, id := range ids {
+		key := s.keys[id]
+
+		switch v := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			result.Keys = append(result.Keys, JWK{
+				KeyType:   "RSA",
+				Use:       "sig",
+				Algorithm: key.Algorithm,
+				KeyID:     key.ID,
+				N:         base64.RawURLEncoding.EncodeToString(v.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(v.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			result.Keys = append(result.Keys, JWK{
+				KeyType:   "OKP",
+				Use:       "sig",
+				Algorithm: key.Algorithm,
+				KeyID:     key.ID,
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(v),
+			})
+		}
+	}
+
+	return result
+}
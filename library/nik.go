@@ -0,0 +1,83 @@
+package library
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+var (
+	NIK_GENDER_MALE   = "M"
+	NIK_GENDER_FEMALE = "F"
+)
+
+// NIK is a parsed Nomor Induk Kependudukan: PPRRDD DDMMYY SSSS, region of registration, date of birth
+// (day plus 40 for women) and a serial number
+type NIK struct {
+	ProvinceCode string
+	RegencyCode  string
+	DistrictCode string
+	DateOfBirth  time.Time
+	Gender       string
+	Serial       string
+}
+
+// ParseNIK checks the structure of the NIK and takes it apart. The region codes are checked for shape only,
+// whether they exist is up to the caller. The NIK only holds the last two digits of the birth year, the century
+// is the latest one that does not put the birth date after today.
+func ParseNIK(nik string) (*NIK, error) {
+	if !ValidateNIK(nik) {
+		return nil, fmt.Errorf("NIK must be 16 digits")
+	}
+
+	result := &NIK{
+		ProvinceCode: nik[0:2],
+		RegencyCode:  nik[0:4],
+		DistrictCode: nik[0:6],
+		Gender:       NIK_GENDER_MALE,
+		Serial:       nik[12:16],
+	}
+
+	if nik[0:2] < "11" || nik[2:4] == "00" || nik[4:6] == "00" {
+		return nil, fmt.Errorf("NIK region code %s is not valid", result.DistrictCode)
+	}
+
+	day, _ := strconv.Atoi(nik[6:8])
+	month, _ := strconv.Atoi(nik[8:10])
+	year, _ := strconv.Atoi(nik[10:12])
+
+	if day > 40 {
+		day -= 40
+		result.Gender = NIK_GENDER_FEMALE
+	}
+
+	if day < 1 || month < 1 || month > 12 {
+		return nil, fmt.Errorf("NIK date of birth %s is not a valid date", nik[6:12])
+	}
+
+	now := UTCPlus7()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	year += 2000
+	if time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC).After(today) {
+		year -= 100
+	}
+
+	result.DateOfBirth = time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if result.DateOfBirth.Day() != day || result.DateOfBirth.After(today) {
+		return nil, fmt.Errorf("NIK date of birth %s is not a valid date", nik[6:12])
+	}
+
+	if result.Serial == "0000" {
+		return nil, fmt.Errorf("NIK serial number cannot be 0000")
+	}
+
+	return result, nil
+}
+
+// MatchesDateOfBirth compares day, month and the two year digits the NIK holds
+func (n *NIK) MatchesDateOfBirth(dateOfBirth time.Time) bool {
+	return n.DateOfBirth.Day() == dateOfBirth.Day() &&
+		n.DateOfBirth.Month() == dateOfBirth.Month() &&
+		n.DateOfBirth.Year()%100 == dateOfBirth.Year()%100
+}
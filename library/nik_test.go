@@ -0,0 +1,149 @@
+package library_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"case-study-kredit-plus/library"
+)
+
+// nik builds a NIK in Jakarta Selatan born on the given day, month and two year digits
+func nik(day int, month int, year int) string {
+	return fmt.Sprintf("317401%02d%02d%02d0001", day, month, year%100)
+}
+
+func TestParseNIK(t *testing.T) {
+	now := library.UTCPlus7()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	// on 31 December no later day of this year is left
+	skipLaterDay := ""
+	if today.Month() == 12 && today.Day() == 31 {
+		skipLaterDay = "today is the last day of the year"
+	}
+
+	tests := []struct {
+		name        string
+		nik         string
+		wantBirth   time.Time
+		wantGender  string
+		wantErr     bool
+		skipReason  string
+		wantRegions []string
+	}{
+		{
+			name:        "man",
+			nik:         "3174011505900001",
+			wantBirth:   time.Date(1990, 5, 15, 0, 0, 0, 0, time.UTC),
+			wantGender:  library.NIK_GENDER_MALE,
+			wantRegions: []string{"31", "3174", "317401"},
+		},
+		{
+			name:        "woman has 40 added to the day",
+			nik:         "3174015505900001",
+			wantBirth:   time.Date(1990, 5, 15, 0, 0, 0, 0, time.UTC),
+			wantGender:  library.NIK_GENDER_FEMALE,
+			wantRegions: []string{"31", "3174", "317401"},
+		},
+		{
+			name:       "woman born on the 31st",
+			nik:        "3174017112850001",
+			wantBirth:  time.Date(1985, 12, 31, 0, 0, 0, 0, time.UTC),
+			wantGender: library.NIK_GENDER_FEMALE,
+		},
+		{
+			name:       "born today is this century",
+			nik:        nik(today.Day(), int(today.Month()), today.Year()),
+			wantBirth:  today,
+			wantGender: library.NIK_GENDER_MALE,
+		},
+		{
+			name:       "born on a later day of this year's two digits is last century",
+			nik:        nik(31, 12, today.Year()),
+			wantBirth:  time.Date(today.Year()-100, 12, 31, 0, 0, 0, 0, time.UTC),
+			wantGender: library.NIK_GENDER_MALE,
+			skipReason: skipLaterDay,
+		},
+		{
+			name:       "born in a later year's two digits is last century",
+			nik:        nik(1, int(today.Month()), today.Year()+1),
+			wantBirth:  time.Date(today.Year()+1-100, today.Month(), 1, 0, 0, 0, 0, time.UTC),
+			wantGender: library.NIK_GENDER_MALE,
+		},
+		{name: "too short", nik: "317401150590001", wantErr: true},
+		{name: "not digits", nik: "31740115059000A1", wantErr: true},
+		{name: "province below 11", nik: "1074011505900001", wantErr: true},
+		{name: "regency 00", nik: "3100011505900001", wantErr: true},
+		{name: "district 00", nik: "3174001505900001", wantErr: true},
+		{name: "day 00", nik: "3174010005900001", wantErr: true},
+		{name: "day 32", nik: "3174013205900001", wantErr: true},
+		{name: "woman's day 72", nik: "3174017205900001", wantErr: true},
+		{name: "month 13", nik: "3174011513900001", wantErr: true},
+		{name: "30 February", nik: "3174013002900001", wantErr: true},
+		{name: "29 February outside a leap year", nik: "3174012902910001", wantErr: true},
+		{name: "serial 0000", nik: "3174011505900000", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.skipReason != "" {
+				t.Skip(tt.skipReason)
+			}
+
+			got, err := library.ParseNIK(tt.nik)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseNIK(%s) = %+v, want an error", tt.nik, got)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ParseNIK(%s) error = %v", tt.nik, err)
+			}
+
+			if !got.DateOfBirth.Equal(tt.wantBirth) {
+				t.Errorf("ParseNIK(%s) date of birth = %s, want %s", tt.nik, got.DateOfBirth.Format("2006-01-02"), tt.wantBirth.Format("2006-01-02"))
+			}
+
+			if got.Gender != tt.wantGender {
+				t.Errorf("ParseNIK(%s) gender = %s, want %s", tt.nik, got.Gender, tt.wantGender)
+			}
+
+			if tt.wantRegions != nil {
+				regions := []string{got.ProvinceCode, got.RegencyCode, got.DistrictCode}
+				if fmt.Sprint(regions) != fmt.Sprint(tt.wantRegions) {
+					t.Errorf("ParseNIK(%s) regions = %v, want %v", tt.nik, regions, tt.wantRegions)
+				}
+			}
+		})
+	}
+}
+
+func TestNIKMatchesDateOfBirth(t *testing.T) {
+	parsed, err := library.ParseNIK("3174015505900001")
+	if err != nil {
+		t.Fatalf("ParseNIK() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		dateOfBirth time.Time
+		want        bool
+	}{
+		{name: "same date, without the 40 added for women", dateOfBirth: time.Date(1990, 5, 15, 0, 0, 0, 0, time.UTC), want: true},
+		{name: "only the two year digits are compared", dateOfBirth: time.Date(2090, 5, 15, 0, 0, 0, 0, time.UTC), want: true},
+		{name: "other day", dateOfBirth: time.Date(1990, 5, 16, 0, 0, 0, 0, time.UTC), want: false},
+		{name: "other month", dateOfBirth: time.Date(1990, 6, 15, 0, 0, 0, 0, time.UTC), want: false},
+		{name: "other year", dateOfBirth: time.Date(1991, 5, 15, 0, 0, 0, 0, time.UTC), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parsed.MatchesDateOfBirth(tt.dateOfBirth); got != tt.want {
+				t.Errorf("MatchesDateOfBirth(%s) = %v, want %v", tt.dateOfBirth.Format("2006-01-02"), got, tt.want)
+			}
+		})
+	}
+}
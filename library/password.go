@@ -0,0 +1,120 @@
+package library
+
+import (
+	"crypto/md5"
+	"crypto/subtle"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	PASSWORD_MIN_LENGTH = 8
+	// bcrypt ignores everything after the 72nd byte
+	PASSWORD_MAX_LENGTH = 72
+	PASSWORD_HASH_COST  = 12
+
+	// PASSWORD_MD5_PREFIX marks a bcrypt hash of the MD5 hex digest the register form used to store, so old rows
+	// can be hashed without knowing the password
+	PASSWORD_MD5_PREFIX = "md5:"
+
+	md5HexRegex = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+	noPasswordOnce sync.Once
+	noPasswordHash []byte
+)
+
+// ValidatePassword checks the password policy: 8 to 72 bytes, with at least a letter and a digit,
+// and not the email address
+func ValidatePassword(password string, email string) error {
+	if len(password) < PASSWORD_MIN_LENGTH {
+		return fmt.Errorf("password must be at least %d characters", PASSWORD_MIN_LENGTH)
+	}
+
+	if len(password) > PASSWORD_MAX_LENGTH {
+		return fmt.Errorf("password must be at most %d bytes", PASSWORD_MAX_LENGTH)
+	}
+
+	hasLetter, hasDigit := false, false
+	for _, r := range password {
+		if unicode.IsLetter(r) {
+			hasLetter = true
+		}
+		if unicode.IsDigit(r) {
+			hasDigit = true
+		}
+	}
+
+	if !hasLetter || !hasDigit {
+		return fmt.Errorf("password must contain letters and digits")
+	}
+
+	if email != "" && strings.EqualFold(password, email) {
+		return fmt.Errorf("password must not be the email address")
+	}
+
+	return nil
+}
+
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), PASSWORD_HASH_COST)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// HashLegacyPassword hashes a stored password that is not a bcrypt hash yet. MD5 digests are wrapped and marked,
+// anything else is taken as the plaintext password.
+func HashLegacyPassword(stored string) (string, error) {
+	if md5HexRegex.MatchString(stored) {
+		hash, err := HashPassword(stored)
+		if err != nil {
+			return "", err
+		}
+		return PASSWORD_MD5_PREFIX + hash, nil
+	}
+
+	return HashPassword(stored)
+}
+
+// IsPasswordCurrent tells whether the stored password is a plain bcrypt hash at the current cost
+func IsPasswordCurrent(stored string) bool {
+	cost, err := bcrypt.Cost([]byte(stored))
+	return err == nil && cost == PASSWORD_HASH_COST
+}
+
+// CheckPassword compares the password with the stored one, which may be a bcrypt hash, a wrapped MD5 digest,
+// or a legacy MD5 digest or plaintext. Callers rehash the password when it matches and the stored one is not current.
+func CheckPassword(stored string, password string) bool {
+	digest := fmt.Sprintf("%x", md5.Sum([]byte(password)))
+
+	if strings.HasPrefix(stored, PASSWORD_MD5_PREFIX) {
+		return bcrypt.CompareHashAndPassword([]byte(strings.TrimPrefix(stored, PASSWORD_MD5_PREFIX)), []byte(digest)) == nil
+	}
+
+	if _, err := bcrypt.Cost([]byte(stored)); err == nil {
+		return bcrypt.CompareHashAndPassword([]byte(stored), []byte(password)) == nil
+	}
+
+	if md5HexRegex.MatchString(stored) {
+		return subtle.ConstantTimeCompare([]byte(stored), []byte(digest)) == 1
+	}
+
+	return subtle.ConstantTimeCompare([]byte(stored), []byte(password)) == 1
+}
+
+// CheckNoPassword spends the time of a password check, so a login for an unknown email is not faster
+// than one with a wrong password
+func CheckNoPassword(password string) {
+	noPasswordOnce.Do(func() {
+		noPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("no password"), PASSWORD_HASH_COST)
+	})
+
+	bcrypt.CompareHashAndPassword(noPasswordHash, []byte(password))
+}
@@ -0,0 +1,142 @@
+package session
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps sessions in the memory of the process, for a single instance and for tests.
+// Everything is lost on restart, which logs everybody out.
+type MemoryStore struct {
+	mu sync.Mutex
+
+	refreshTokens map[string]*memoryRefreshToken
+	sessions      map[string]time.Time
+	users         map[string]memoryUserRevocation
+
+	lastSweep time.Time
+}
+
+type memoryRefreshToken struct {
+	token RefreshToken
+	used  bool
+}
+
+type memoryUserRevocation struct {
+	at    time.Time
+	until time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		refreshTokens: map[string]*memoryRefreshToken{},
+		sessions:      map[string]time.Time{},
+		users:         map[string]memoryUserRevocation{},
+		lastSweep:     time.Now(),
+	}
+}
+
+func (s *MemoryStore) SaveRefreshToken(hash string, token RefreshToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep()
+	s.refreshTokens[hash] = &memoryRefreshToken{token: token}
+
+	return nil
+}
+
+func (s *MemoryStore) UseRefreshToken(hash string) (*RefreshToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.refreshTokens[hash]
+	if !ok || time.Now().After(v.token.ExpiresAt) {
+		return nil, ErrRefreshTokenNotFound
+	}
+
+	token := v.token
+	if v.used {
+		return &token, ErrRefreshTokenReused
+	}
+	v.used = true
+
+	return &token, nil
+}
+
+func (s *MemoryStore) RevokeSession(sessionID string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep()
+	if until.After(s.sessions[sessionID]) {
+		s.sessions[sessionID] = until
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) SessionRevoked(sessionID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	until, ok := s.sessions[sessionID]
+
+	return ok && time.Now().Before(until), nil
+}
+
+func (s *MemoryStore) RevokeUser(userID string, at time.Time, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep()
+	revocation := s.users[userID]
+	if at.After(revocation.at) {
+		revocation.at = at
+	}
+	if until.After(revocation.until) {
+		revocation.until = until
+	}
+	s.users[userID] = revocation
+
+	return nil
+}
+
+func (s *MemoryStore) UserRevokedAt(userID string) (time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	revocation, ok := s.users[userID]
+	if !ok || time.Now().After(revocation.until) {
+		return time.Time{}, nil
+	}
+
+	return revocation.at, nil
+}
+
+// sweep drops what has expired, at most once a minute. The caller holds the lock.
+func (s *MemoryStore) sweep() {
+	now := time.Now()
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+
+	for hash, v := range s.refreshTokens {
+		if now.After(v.token.ExpiresAt) {
+			delete(s.refreshTokens, hash)
+		}
+	}
+
+	for sessionID, until := range s.sessions {
+		if now.After(until) {
+			delete(s.sessions, sessionID)
+		}
+	}
+
+	for userID, revocation := range s.users {
+		if now.After(revocation.until) {
+			delete(s.users, userID)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+var (
+	REDIS_KEY_PREFIX = "session:"
+)
+
+// RedisStore keeps sessions in Redis, shared by every instance of the application. Keys expire with what they hold.
+type RedisStore struct {
+	client *redis.Client
+}
+
+func NewRedisStore(addr string, password string, db int) (*RedisStore, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     addr,
+		Password: password,
+		DB:       db,
+	})
+
+	err := client.Ping().Err()
+	if err != nil {
+		return nil, fmt.Errorf("redis is not reachable: %v", err)
+	}
+
+	return &RedisStore{client: client}, nil
+}
+
+func (s *RedisStore) SaveRefreshToken(hash string, token RefreshToken) error {
+	value, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	// go-redis keeps keys without a positive expiration forever
+	if time.Until(token.ExpiresAt) <= 0 {
+		return nil
+	}
+
+	return s.client.Set(REDIS_KEY_PREFIX+"refresh:"+hash, value, time.Until(token.ExpiresAt)).Err()
+}
+
+func (s *RedisStore) UseRefreshToken(hash string) (*RefreshToken, error) {
+	value, err := s.client.Get(REDIS_KEY_PREFIX + "refresh:" + hash).Bytes()
+	if err == redis.Nil {
+		return nil, ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var token RefreshToken
+	err = json.Unmarshal(value, &token)
+	if err != nil {
+		return nil, err
+	}
+
+	if time.Until(token.ExpiresAt) <= 0 {
+		return nil, ErrRefreshTokenNotFound
+	}
+
+	// the used marker is set at most once, so of two requests with the same token only one gets through
+	first, err := s.client.SetNX(REDIS_KEY_PREFIX+"refresh-used:"+hash, "1", time.Until(token.ExpiresAt)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !first {
+		return &token, ErrRefreshTokenReused
+	}
+
+	return &token, nil
+}
+
+func (s *RedisStore) RevokeSession(sessionID string, until time.Time) error {
+	if time.Until(until) <= 0 {
+		return nil
+	}
+
+	return s.client.Set(REDIS_KEY_PREFIX+"revoked-session:"+sessionID, "1", time.Until(until)).Err()
+}
+
+func (s *RedisStore) SessionRevoked(sessionID string) (bool, error) {
+	n, err := s.client.Exists(REDIS_KEY_PREFIX + "revoked-session:" + sessionID).Result()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+func (s *RedisStore) RevokeUser(userID string, at time.Time, until time.Time) error {
+	if time.Until(until) <= 0 {
+		return nil
+	}
+
+	return s.client.Set(REDIS_KEY_PREFIX+"revoked-user:"+userID, strconv.FormatInt(at.Unix(), 10), time.Until(until)).Err()
+}
+
+func (s *RedisStore) UserRevokedAt(userID string) (time.Time, error) {
+	value, err := s.client.Get(REDIS_KEY_PREFIX + "revoked-user:" + userID).Result()
+	if err == redis.Nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	at, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(at, 0), nil
+}
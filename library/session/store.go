@@ -0,0 +1,130 @@
+package session
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"case-study-kredit-plus/configs"
+)
+
+var (
+	DRIVER_MEMORY = "memory"
+	DRIVER_REDIS  = "redis"
+
+	// DEFAULT_REFRESH_TOKEN_TIME_OUT is 30 days, in seconds
+	DEFAULT_REFRESH_TOKEN_TIME_OUT = 30 * 24 * 60 * 60
+
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenReused   = errors.New("refresh token used before")
+
+	configured     Store
+	configuredOnce sync.Once
+)
+
+// RefreshToken is what is kept of a refresh token, the token itself is only known to the client
+type RefreshToken struct {
+	SessionID string    `json:"SessionID"`
+	UserID    string    `json:"UserID"`
+	LoginTime time.Time `json:"LoginTime"`
+	ExpiresAt time.Time `json:"ExpiresAt"`
+}
+
+// Store keeps refresh tokens and revoked sessions. A login starts a session, every access and refresh token
+// carries its ID. Refresh tokens are kept by hash and can be used once, each refresh hands out a new one.
+// Revocations are kept until every token they cover has expired.
+type Store interface {
+	SaveRefreshToken(hash string, token RefreshToken) error
+	// UseRefreshToken takes the refresh token out of use. A token used before is returned with ErrRefreshTokenReused,
+	// which means it was stolen or replayed.
+	UseRefreshToken(hash string) (*RefreshToken, error)
+
+	RevokeSession(sessionID string, until time.Time) error
+	SessionRevoked(sessionID string) (bool, error)
+
+	// RevokeUser ends every session of the user started up to at
+	RevokeUser(userID string, at time.Time, until time.Time) error
+	// UserRevokedAt is the zero time when the sessions of the user were never revoked
+	UserRevokedAt(userID string) (time.Time, error)
+}
+
+// New builds the store picked by SESSION_STORE_DRIVER, memory unless it says redis.
+// The memory store only works when a single instance of the application runs.
+func New(config *configs.Config) (Store, error) {
+	switch config.SessionStoreDriver {
+	case "", DRIVER_MEMORY:
+		return NewMemoryStore(), nil
+	case DRIVER_REDIS:
+		return NewRedisStore(config.RedisAddr, config.RedisPassword, config.RedisDB)
+	}
+
+	return nil, fmt.Errorf("unknown session store driver %q", config.SessionStoreDriver)
+}
+
+// FromConfiguration returns the configured store, built once so every request sees the same sessions.
+// It stops the application when the configuration is unusable.
+func FromConfiguration() Store {
+	configuredOnce.Do(func() {
+		config, err := configs.GetConfiguration()
+		if err != nil {
+			log.Fatalln("failed to get configuration: ", err)
+		}
+
+		configured, err = New(config)
+		if err != nil {
+			log.Fatalln("failed to set up session store: ", err)
+		}
+	})
+
+	return configured
+}
+
+// RefreshTokenTimeOut is how long a refresh token can be used, from REFRESH_TOKEN_TIME_OUT in seconds
+func RefreshTokenTimeOut(config *configs.Config) time.Duration {
+	if config.RefreshTokenTimeOut > 0 {
+		return time.Duration(config.RefreshTokenTimeOut) * time.Second
+	}
+
+	return time.Duration(DEFAULT_REFRESH_TOKEN_TIME_OUT) * time.Second
+}
+
+// NewRefreshToken returns a random refresh token and the hash it is stored under
+func NewRefreshToken() (string, string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", "", err
+	}
+
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	return token, HashRefreshToken(token), nil
+}
+
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Revoked tells whether a token of the session, issued at issuedAt, was revoked by a logout of the session or of
+// every session of the user
+func Revoked(store Store, sessionID string, userID string, issuedAt time.Time) (bool, error) {
+	revoked, err := store.SessionRevoked(sessionID)
+	if err != nil || revoked {
+		return revoked, err
+	}
+
+	revokedAt, err := store.UserRevokedAt(userID)
+	if err != nil {
+		return false, err
+	}
+
+	// revocations are kept to the second, a token issued in that second is revoked as well
+	return !revokedAt.IsZero() && issuedAt.Unix() <= revokedAt.Unix(), nil
+}
@@ -1,5 +1,10 @@
 package types
 
+import (
+	"sort"
+	"strings"
+)
+
 //Error represents customized error object
 //swagger:model
 type Error struct {
@@ -11,3 +16,16 @@ type Error struct {
 	IsIgnore   bool
 	Params     string
 }
+
+// FieldErrors carries one message per offending input field, the error response lists them under fields
+type FieldErrors map[string]string
+
+func (e FieldErrors) Error() string {
+	messages := []string{}
+	for field, message := range e {
+		messages = append(messages, field+": "+message)
+	}
+	sort.Strings(messages)
+
+	return strings.Join(messages, ", ")
+}
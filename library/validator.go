@@ -6,16 +6,6 @@ import (
 	"github.com/google/uuid"
 )
 
-var validTenors = map[int]struct{}{
-	1: {}, 2: {}, 3: {}, 6: {},
-}
-
-func ValidateTenor(tenor int) bool {
-	_, ok := validTenors[tenor]
-
-	return ok
-}
-
 func ValidateEmail(email string) bool {
 	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 	return emailRegex.MatchString(email)
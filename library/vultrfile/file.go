@@ -51,7 +51,7 @@ func UploadFile(file *bytes.Reader, dst string) error {
 		Bucket: aws.String(atomConfig.VultrBucket),
 		Key:    aws.String(dst),
 		Body:   file,
-		ACL:    "public-read",
+		ACL:    "private",
 	}
 
 	config := s3.NewFromConfig(cfg, func(o *s3.Options) { o.UsePathStyle = true })
@@ -7,8 +7,10 @@ import (
 
 	"case-study-kredit-plus/configs"
 	"case-study-kredit-plus/databases"
+	"case-study-kredit-plus/library"
 	"case-study-kredit-plus/library/data"
 	"case-study-kredit-plus/src/routes"
+	"case-study-kredit-plus/src/worker"
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/go-sql-driver/mysql"
@@ -40,6 +42,56 @@ func main() {
 
 	databases.MigrateUp()
 
+	// consumers stored before encryption have no NIK index, the duplicate NIK check cannot see them until they are indexed
+	if !worker.NewConsumerReencryption(db, dataManager).RunUnindexed() {
+		log.Fatalln("failed to index the NIK of every consumer, the server does not start until they are all indexed")
+	}
+
+	// the seeded loan products stay inactive until they are priced, from the rates configured before loan products
+	worker.NewLoanProductPricing(db, dataManager).Run(config.InterestMethod, config.InterestRates)
+
+	// `go run main.go accrue-late-fees` runs the daily late fee accrual once and exits
+	if len(os.Args) > 1 && os.Args[1] == "accrue-late-fees" {
+		worker.NewLateFeeWorker(db, dataManager).Run(library.UTCPlus7())
+		return
+	}
+
+	// `go run main.go privatize-documents` revokes public access to documents uploaded before storage was private
+	if len(os.Args) > 1 && os.Args[1] == "privatize-documents" {
+		worker.NewConsumerDocumentBackfill(db, dataManager).Run()
+		return
+	}
+
+	// `go run main.go reencrypt-consumers` moves consumer PII to the current encryption keys after a key rotation
+	if len(os.Args) > 1 && os.Args[1] == "reencrypt-consumers" {
+		worker.NewConsumerReencryption(db, dataManager).Run()
+		return
+	}
+
+	// `go run main.go hash-passwords` hashes the passwords of users who have not logged in since passwords were hashed
+	if len(os.Args) > 1 && os.Args[1] == "hash-passwords" {
+		worker.NewPasswordHash(db, dataManager).Run()
+		return
+	}
+
+	// `ADMIN_NAME=... ADMIN_PASSWORD=... go run main.go create-admin <email>` gives the admin role to the user,
+	// registering it first when the email is not taken. The password is read from the environment, not the
+	// arguments, so it stays out of the shell history.
+	if len(os.Args) > 2 && os.Args[1] == "create-admin" {
+		worker.NewAdminBootstrap(db, dataManager).Run(os.Args[2], os.Getenv("ADMIN_NAME"), os.Getenv("ADMIN_PASSWORD"))
+		return
+	}
+
+	// `go run main.go api-client create|rotate|expire|revoke ...` manages API clients, new tokens are printed once
+	if len(os.Args) > 2 && os.Args[1] == "api-client" {
+		worker.NewAPIClientCommand(db, dataManager).Run(os.Args[2:])
+		return
+	}
+
+	if config.ActiveWorker == 1 {
+		go worker.NewLateFeeWorker(db, dataManager).Start()
+	}
+
 	fmt.Println("Server Running...")
 	routes.RegisterRoutes(db, config, dataManager)
 }
@@ -10,6 +10,8 @@ import (
 
 	"case-study-kredit-plus/configs"
 	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/jwtkeys"
+	"case-study-kredit-plus/library/session"
 	"case-study-kredit-plus/library/types"
 
 	"github.com/dgrijalva/jwt-go"
@@ -26,20 +28,8 @@ func Auth(c *gin.Context) {
 
 	CheckIPClientIP(c, config)
 
-	// redisClient := redis.NewClient(&redis.Options{
-	// 	Addr:     config.RedisAddr,
-	// 	Password: config.RedisPassword,
-	// 	DB:       config.RedisDB,
-	// })
-
 	tokenString := c.Request.Header.Get("Authorization")
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if jwt.GetSigningMethod("HS256") != token.Method {
-			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
-		}
-
-		return []byte("secret"), nil
-	})
+	token, err := jwtkeys.FromConfiguration().Parse(tokenString, time.Now())
 	if err != nil {
 		response := types.Result{Status: "Warning", StatusCode: http.StatusUnauthorized, Message: "Token Invalid"}
 		result := gin.H{
@@ -60,55 +50,47 @@ func Auth(c *gin.Context) {
 		return
 	}
 
-	// val, errRedis := redisClient.Get(tokenString).Result()
-	// if errRedis != nil {
-	// 	log.Printf(`
-	// 	======================================================================
-	// 	Error Collecting Caching in "Auth":
-	// 	Error: %v
-	// 	======================================================================
-	// 	`, errRedis)
-	// 	response := types.Result{Status: "Warning", StatusCode: http.StatusUnauthorized, Message: "Token is Expired"}
-	// 	result := gin.H{
-	// 		"result": response,
-	// 	}
-	// 	c.JSON(http.StatusUnauthorized, result)
-	// 	c.Abort()
-	// 	return
-	// }
-	// if val == "" {
-	// 	response := types.Result{Status: "Warning", StatusCode: http.StatusUnauthorized, Message: "Token is Expired"}
-	// 	result := gin.H{
-	// 		"result": response,
-	// 	}
-	// 	c.JSON(http.StatusUnauthorized, result)
-	// 	c.Abort()
-	// 	return
-	// }
+	// tokens of a session that was logged out, or issued before all sessions of the user were ended, are refused
+	sessionID, _ := claimJWT["sid"].(string)
+	userID, _ := claimJWT["ID"].(string)
+	issuedAt, _ := claimJWT["iat"].(float64)
+	if sessionID == "" {
+		response := types.Result{Status: "Warning", StatusCode: http.StatusUnauthorized, Message: "Token Invalid"}
+		result := gin.H{
+			"result": response,
+		}
+		c.JSON(http.StatusUnauthorized, result)
+		c.Abort()
+		return
+	}
+
+	revoked, err := session.Revoked(session.FromConfiguration(), sessionID, userID, time.Unix(int64(issuedAt), 0))
+	if err != nil {
+		log.Println("failed to check session: ", err)
+		response := types.Result{Status: "Warning", StatusCode: http.StatusInternalServerError, Message: "Internal Server Error"}
+		result := gin.H{
+			"result": response,
+		}
+		c.JSON(http.StatusInternalServerError, result)
+		c.Abort()
+		return
+	}
+
+	if revoked {
+		response := types.Result{Status: "Warning", StatusCode: http.StatusUnauthorized, Message: "Token Revoked"}
+		result := gin.H{
+			"result": response,
+		}
+		c.JSON(http.StatusUnauthorized, result)
+		c.Abort()
+		return
+	}
 
 	c.Set("SessionID", token)
 	c.Set("UserID", claimJWT["ID"])
 	c.Set("Email", claimJWT["Email"])
-
-	// if errRedis := redisClient.Set(
-	// 	tokenString,
-	// 	fmt.Sprintf("{\"id\":%s}", claimJWT["ID"]),
-	// 	time.Second*time.Duration(config.RedisTimeOut),
-	// ).Err(); errRedis != nil {
-	// 	log.Printf(`
-	// 	======================================================================
-	// 	Error Storing Caching in "Auth":
-	// 	Error: %v,
-	// 	======================================================================
-	// 	`, err)
-	// 	response := types.Result{Status: "Warning", StatusCode: http.StatusUnauthorized, Message: "Token is Expired"}
-	// 	result := gin.H{
-	// 		"result": response,
-	// 	}
-	// 	c.JSON(http.StatusUnauthorized, result)
-	// 	c.Abort()
-	// 	return
-	// }
+	c.Set("RoleID", claimJWT["RoleID"])
+	c.Set("LoginSessionID", sessionID)
 }
 
 func AuthMobile(c *gin.Context) {
@@ -237,20 +219,29 @@ func AuthExternal(c *gin.Context) {
 	}
 	defer db.Close()
 
+	// partner clients are let in through their merchant, the shared 'Account' client has none
 	rows, err := db.Query(`
 	SELECT
-		api_client.id, api_client.name
+		api_client.id, api_client.name, api_client.merchant_id, IFNULL(merchants.status_id, "")
 	FROM api_client
-	WHERE api_client.token = ? and name = 'Account'
-	`, token)
+	LEFT JOIN merchants ON merchants.id = api_client.merchant_id
+	WHERE api_client.token_hash = ? AND api_client.status_id = "1"
+		AND (api_client.expires_at IS NULL OR api_client.expires_at > ?)
+		AND (api_client.name = 'Account' OR api_client.merchant_id <> "")
+	`, library.HashAPIToken(token), library.UTCPlus7().Format(library.StrToTimestampFormat))
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer rows.Close()
 
 	hasAccess := false
+	var apiClientID int
+	var apiClientName string
+	var merchantID string
+	var merchantStatusID string
 	if rows.Next() {
 		hasAccess = true
+		rows.Scan(&apiClientID, &apiClientName, &merchantID, &merchantStatusID)
 	}
 
 	if !hasAccess {
@@ -262,6 +253,39 @@ func AuthExternal(c *gin.Context) {
 		c.Abort()
 		return
 	}
+
+	if merchantID != "" && merchantStatusID != "1" {
+		response := types.Result{Status: "Warning", StatusCode: http.StatusForbidden, Message: "Merchant Not Active"}
+		result := gin.H{
+			"result": response,
+		}
+		c.JSON(http.StatusForbidden, result)
+		c.Abort()
+		return
+	}
+
+	scopeRows, err := db.Query(`
+	SELECT api_client_scopes.scope
+	FROM api_client_scopes
+	WHERE api_client_scopes.api_client_id = ? AND api_client_scopes.status_id = "1"
+	`, apiClientID)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer scopeRows.Close()
+
+	scopes := []string{}
+	for scopeRows.Next() {
+		var scope string
+		scopeRows.Scan(&scope)
+		scopes = append(scopes, scope)
+	}
+
+	c.Set("APIClientID", apiClientID)
+	c.Set("MerchantID", merchantID)
+	c.Set("APIClientScopes", scopes)
+	// external requests have no user, what they change is recorded against the api client
+	c.Set("UserID", fmt.Sprintf("api-client:%d", apiClientID))
 }
 
 func AuthCheckIP(c *gin.Context) {
@@ -403,8 +427,9 @@ func CheckSecretTokenWebApp(c *gin.Context) {
 	SELECT
 		api_client.id, api_client.name
 	FROM api_client
-	WHERE api_client.token = ? and name = 'External'
-	`, secretToken)
+	WHERE api_client.token_hash = ? AND name = 'External' AND api_client.status_id = "1"
+		AND (api_client.expires_at IS NULL OR api_client.expires_at > ?)
+	`, library.HashAPIToken(secretToken), library.UTCPlus7().Format(library.StrToTimestampFormat))
 	if err != nil {
 		log.Fatal(err)
 	}
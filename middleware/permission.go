@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+
+	"case-study-kredit-plus/configs"
+	"case-study-kredit-plus/library/appcontext"
+	"case-study-kredit-plus/library/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+)
+
+// Permission lets the request through when the role of the logged-in user grants the permission, it goes after Auth.
+// The role in the token must still be the role of the user, so a user moved to another role refreshes the token first.
+func Permission(permissionID string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		config, err := configs.GetConfiguration()
+		if err != nil {
+			log.Fatalln("failed to get configuration: ", err)
+		}
+
+		userID := appcontext.UserID(c)
+		roleID := appcontext.RoleID(c)
+		if userID == nil || roleID == "" {
+			permissionDenied(c)
+			return
+		}
+
+		db, err := sqlx.Open("mysql", config.DBConnectionString)
+		if err != nil {
+			log.Fatalln("failed to open database x: ", err)
+		}
+		defer db.Close()
+
+		rows, err := db.Query(`
+		SELECT
+			role_permissions.id
+		FROM users
+		JOIN roles ON roles.id = users.role_id
+		JOIN role_permissions ON role_permissions.role_id = roles.id
+		WHERE users.id = ? AND users.role_id = ? AND users.status_id = "1" AND roles.status_id = "1"
+			AND role_permissions.permission_id = ? AND role_permissions.status_id = "1"
+		`, *userID, roleID, permissionID)
+		if err != nil {
+			log.Println("failed to check permission: ", err)
+			response := types.Result{Status: "Warning", StatusCode: http.StatusInternalServerError, Message: "Internal Server Error"}
+			result := gin.H{
+				"result": response,
+			}
+			c.JSON(http.StatusInternalServerError, result)
+			c.Abort()
+			return
+		}
+		defer rows.Close()
+
+		if !rows.Next() {
+			permissionDenied(c)
+			return
+		}
+	}
+}
+
+func permissionDenied(c *gin.Context) {
+	response := types.Result{Status: "Warning", StatusCode: http.StatusForbidden, Message: "Permission Denied"}
+	result := gin.H{
+		"result": response,
+	}
+	c.JSON(http.StatusForbidden, result)
+	c.Abort()
+}
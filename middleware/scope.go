@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+
+	"case-study-kredit-plus/library/appcontext"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Scope lets the request through when the API client was given the scope, it goes after AuthExternal,
+// which reads the scopes with the client
+func Scope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		for _, v := range appcontext.APIClientScopes(c) {
+			if v == scope {
+				return
+			}
+		}
+
+		response := types.Result{Status: "Warning", StatusCode: http.StatusForbidden, Message: "Scope Not Granted"}
+		result := gin.H{
+			"result": response,
+		}
+		c.JSON(http.StatusForbidden, result)
+		c.Abort()
+	}
+}
+
+// MerchantScope keeps a client without a merchant, such as the shared 'Account' client, away from merchant data
+// unless it was given the merchants:all scope. Partner clients pass, the handlers limit them to their own merchant.
+func MerchantScope(c *gin.Context) {
+	if appcontext.MerchantID(c) != "" {
+		return
+	}
+
+	for _, v := range appcontext.APIClientScopes(c) {
+		if v == models.SCOPE_ALL_MERCHANTS {
+			return
+		}
+	}
+
+	response := types.Result{Status: "Warning", StatusCode: http.StatusForbidden, Message: "Scope Not Granted"}
+	result := gin.H{
+		"result": response,
+	}
+	c.JSON(http.StatusForbidden, result)
+	c.Abort()
+}
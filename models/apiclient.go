@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"case-study-kredit-plus/library/types"
+)
+
+var (
+	// API_CLIENT_EXTERNAL is the client whose token every external request sends in Access-Token
+	API_CLIENT_EXTERNAL = "External"
+	// API_CLIENT_ACCOUNT is the shared client that may call the external API without a merchant
+	API_CLIENT_ACCOUNT = "Account"
+
+	SCOPE_TRANSACTIONS_READ   = "transactions:read"
+	SCOPE_TRANSACTIONS_CREATE = "transactions:create"
+	SCOPE_TRANSACTIONS_CANCEL = "transactions:cancel"
+	SCOPE_INSTALLMENTS_READ   = "installments:read"
+	SCOPE_CREDIT_LIMITS_READ  = "credit_limits:read"
+	// SCOPE_ALL_MERCHANTS lets a client without a merchant read and cancel the transactions of every merchant
+	SCOPE_ALL_MERCHANTS = "merchants:all"
+
+	// API_CLIENT_SCOPES lists the scopes a client can be given, each external route needs one of them
+	API_CLIENT_SCOPES = []string{
+		SCOPE_TRANSACTIONS_READ,
+		SCOPE_TRANSACTIONS_CREATE,
+		SCOPE_TRANSACTIONS_CANCEL,
+		SCOPE_INSTALLMENTS_READ,
+		SCOPE_CREDIT_LIMITS_READ,
+		SCOPE_ALL_MERCHANTS,
+	}
+)
+
+type APIClientBulk struct {
+	ID          int        `json:"ID" db:"id"`
+	Name        string     `json:"Name" db:"name"`
+	TokenPrefix string     `json:"TokenPrefix" db:"token_prefix"`
+	MerchantID  string     `json:"MerchantID" db:"merchant_id"`
+	ExpiresAt   *time.Time `json:"ExpiresAt" db:"expires_at"`
+
+	StatusID   string `json:"StatusID" db:"status_id"`
+	StatusName string `json:"StatusName" db:"status_name"`
+}
+
+// APIClient is a partner or application calling the external API. Only a hash of its token is stored, the token
+// is in Token right after it was made and nowhere else.
+type APIClient struct {
+	ID          int        `json:"ID" db:"id"`
+	Name        string     `json:"Name" db:"name" validate:"required"`
+	TokenPrefix string     `json:"TokenPrefix" db:"token_prefix"`
+	MerchantID  string     `json:"MerchantID" db:"merchant_id"`
+	ExpiresAt   *time.Time `json:"ExpiresAt" db:"expires_at"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+	Status   Status `json:"Status"`
+
+	Scopes []*APIClientScope `json:"Scopes"`
+
+	Token string `json:"Token,omitempty"`
+}
+
+type APIClientScope struct {
+	ID          string `json:"ID" db:"id"`
+	APIClientID int    `json:"APIClientID" db:"api_client_id"`
+	Scope       string `json:"Scope" db:"scope" validate:"required"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+}
+
+type FindAllAPIClientParams struct {
+	FindAllParams types.FindAllParams
+	Name          string
+	MerchantID    string
+}
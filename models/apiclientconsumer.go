@@ -0,0 +1,36 @@
+package models
+
+import (
+	"case-study-kredit-plus/library/types"
+)
+
+type APIClientConsumerBulk struct {
+	ID          string `json:"ID" db:"id" validate:"omitempty,uuid"`
+	APIClientID int    `json:"APIClientID" db:"api_client_id" validate:"gt=0"`
+	ConsumerID  string `json:"ConsumerID" db:"consumer_id" validate:"required,uuid4"`
+
+	StatusID   string `json:"StatusID" db:"status_id"`
+	StatusName string `json:"StatusName" db:"status_name"`
+
+	APIClientName string `json:"APIClientName" db:"api_client_name"`
+	ConsumerName  string `json:"ConsumerName" db:"consumer_name"`
+}
+
+// APIClientConsumer grants an API client access to a consumer's data on the external API
+type APIClientConsumer struct {
+	ID          string `json:"ID" db:"id" validate:"omitempty,uuid"`
+	APIClientID int    `json:"APIClientID" db:"api_client_id" validate:"gt=0"`
+	ConsumerID  string `json:"ConsumerID" db:"consumer_id" validate:"required,uuid4"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+	Status   Status `json:"Status"`
+
+	APIClient *INTIDNameTemplate `json:"APIClient"`
+	Consumer  *IDNameTemplate    `json:"Consumer"`
+}
+
+type FindAllAPIClientConsumerParams struct {
+	FindAllParams types.FindAllParams
+	APIClientID   int    `validate:"omitempty,gt=0"`
+	ConsumerID    string `validate:"omitempty,uuid4"`
+}
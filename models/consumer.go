@@ -5,21 +5,44 @@ import (
 	"time"
 )
 
+var (
+	CONSUMER_DOCUMENT_KTP    = "KTP"
+	CONSUMER_DOCUMENT_SELFIE = "SELFIE"
+)
+
+// ConsumerBulk holds a consumers row as stored, NIK, DateOfBirth, Salary and the image keys are encrypted
 type ConsumerBulk struct {
-	ID           string    `json:"ID" db:"id" validate:"omitempty,uuid4"`
-	NIK          string    `json:"NIK" db:"NIK" validate:"len=16,numeric"`
-	FullName     string    `json:"FullName" db:"full_name"`
-	LegalName    string    `json:"LegalName" db:"legal_name"`
-	PlaceOfBirth string    `json:"PlaceOfBirth" db:"place_of_birth"`
-	DateOfBirth  time.Time `json:"DateOfBirth" db:"date_of_birth"`
-	Salary       float64   `json:"Salary" db:"salary" validate:"max=99999999999"`
-	KTPImgURL    string    `json:"KTPImgURL" db:"ktp_img_url" validate:"url"`
-	SelfieImgURL string    `json:"SelfieImgURL" db:"selfie_img_url" validate:"url"`
+	ID           string `json:"ID" db:"id"`
+	NIK          string `json:"NIK" db:"NIK"`
+	FullName     string `json:"FullName" db:"full_name"`
+	LegalName    string `json:"LegalName" db:"legal_name"`
+	PlaceOfBirth string `json:"PlaceOfBirth" db:"place_of_birth"`
+	DateOfBirth  string `json:"DateOfBirth" db:"date_of_birth"`
+	Salary       string `json:"Salary" db:"salary"`
+	KTPImgKey    string `json:"KTPImgKey" db:"ktp_img_key"`
+	SelfieImgKey string `json:"SelfieImgKey" db:"selfie_img_key"`
 
 	StatusID   string `json:"StatusID" db:"status_id"`
 	StatusName string `json:"StatusName" db:"status_name"`
 }
 
+// ConsumerEncrypted is what the consumers table is written with. NIKIndex is the blind index of the NIK,
+// exact NIK lookups go through it.
+type ConsumerEncrypted struct {
+	ID           string `json:"ID" db:"id"`
+	NIK          string `json:"NIK" db:"NIK"`
+	NIKIndex     string `json:"NIKIndex" db:"nik_index"`
+	FullName     string `json:"FullName" db:"full_name"`
+	LegalName    string `json:"LegalName" db:"legal_name"`
+	PlaceOfBirth string `json:"PlaceOfBirth" db:"place_of_birth"`
+	DateOfBirth  string `json:"DateOfBirth" db:"date_of_birth"`
+	Salary       string `json:"Salary" db:"salary"`
+	KTPImgKey    string `json:"KTPImgKey" db:"ktp_img_key"`
+	SelfieImgKey string `json:"SelfieImgKey" db:"selfie_img_key"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+}
+
 type Consumer struct {
 	ID           string    `json:"ID" db:"id" validate:"omitempty,uuid4"`
 	NIK          string    `json:"NIK" db:"NIK" validate:"len=16,numeric"`
@@ -28,8 +51,10 @@ type Consumer struct {
 	PlaceOfBirth string    `json:"PlaceOfBirth" db:"place_of_birth"`
 	DateOfBirth  time.Time `json:"DateOfBirth" db:"date_of_birth"`
 	Salary       float64   `json:"Salary" db:"salary" validate:"max=99999999999"`
-	KTPImgURL    string    `json:"KTPImgURL" db:"ktp_img_url" validate:"url"`
-	SelfieImgURL string    `json:"SelfieImgURL" db:"selfie_img_url" validate:"url"`
+
+	// KTPImgKey and SelfieImgKey are object keys in the file storage, set through the document upload
+	KTPImgKey    string `json:"KTPImgKey" db:"ktp_img_key"`
+	SelfieImgKey string `json:"SelfieImgKey" db:"selfie_img_key"`
 
 	StatusID string `json:"StatusID" db:"status_id"`
 	Status   Status `json:"Status"`
@@ -2,15 +2,17 @@ package models
 
 import (
 	"case-study-kredit-plus/library/types"
+	"time"
 )
 
 type ConsumerCreditLimitBulk struct {
-	ID         string  `json:"ID" db:"id" validate:"omitempty,uuid4"`
-	ConsumerID string  `json:"ConsumerID" db:"consumer_id" validate:"required,uuid4"`
-	Month1     float64 `json:"Month1" db:"1_month" validate:"numeric"`
-	Month2     float64 `json:"Month2" db:"2_month" validate:"numeric"`
-	Month3     float64 `json:"Month3" db:"3_month" validate:"numeric"`
-	Month6     float64 `json:"Month6" db:"6_month" validate:"numeric"`
+	ID         string `json:"ID" db:"id" validate:"omitempty,uuid4"`
+	ConsumerID string `json:"ConsumerID" db:"consumer_id" validate:"required,uuid4"`
+
+	EffectiveFrom time.Time  `json:"EffectiveFrom" db:"effective_from"`
+	EffectiveTo   *time.Time `json:"EffectiveTo" db:"effective_to"`
+	Reason        string     `json:"Reason" db:"reason"`
+	CreatedBy     string     `json:"CreatedBy" db:"created_by"`
 
 	StatusID   string `json:"StatusID" db:"status_id"`
 	StatusName string `json:"StatusName" db:"status_name"`
@@ -19,22 +21,51 @@ type ConsumerCreditLimitBulk struct {
 }
 
 type ConsumerCreditLimit struct {
-	ID         string  `json:"ID" db:"id" validate:"omitempty,uuid4"`
-	ConsumerID string  `json:"ConsumerID" db:"consumer_id" validate:"required,uuid4"`
-	Month1     float64 `json:"Month1" db:"1_month" validate:"numeric"`
-	Month2     float64 `json:"Month2" db:"2_month" validate:"numeric"`
-	Month3     float64 `json:"Month3" db:"3_month" validate:"numeric"`
-	Month6     float64 `json:"Month6" db:"6_month" validate:"numeric"`
+	ID         string `json:"ID" db:"id" validate:"omitempty,uuid4"`
+	ConsumerID string `json:"ConsumerID" db:"consumer_id" validate:"required,uuid4"`
+
+	// a limit is never changed in place, every change closes the current version and starts a new one
+	EffectiveFrom time.Time  `json:"EffectiveFrom" db:"effective_from"`
+	EffectiveTo   *time.Time `json:"EffectiveTo" db:"effective_to"`
+	Reason        string     `json:"Reason" db:"reason"`
+	CreatedBy     string     `json:"CreatedBy"`
 
 	StatusID string `json:"StatusID" db:"status_id"`
 	Status   Status `json:"Status"`
 
 	Consumer *IDNameTemplate `json:"Consumer"`
+
+	Details []*ConsumerCreditLimitDetail `json:"Details" validate:"required,min=1,dive"`
+}
+
+type ConsumerCreditLimitDetailBulk struct {
+	ID                    string  `json:"ID" db:"id"`
+	ConsumerCreditLimitID string  `json:"ConsumerCreditLimitID" db:"consumer_credit_limit_id"`
+	LoanProductID         string  `json:"LoanProductID" db:"loan_product_id" validate:"required,uuid"`
+	LimitAmount           float64 `json:"LimitAmount" db:"limit_amount" validate:"gte=0"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+
+	LoanProductName string `json:"LoanProductName" db:"loan_product_name"`
+	Tenor           int    `json:"Tenor" db:"tenor"`
+}
+
+type ConsumerCreditLimitDetail struct {
+	ID                    string  `json:"ID" db:"id"`
+	ConsumerCreditLimitID string  `json:"ConsumerCreditLimitID" db:"consumer_credit_limit_id"`
+	LoanProductID         string  `json:"LoanProductID" db:"loan_product_id" validate:"required,uuid"`
+	LimitAmount           float64 `json:"LimitAmount" db:"limit_amount" validate:"gte=0"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+
+	LoanProduct *IDNameTemplate `json:"LoanProduct"`
+	Tenor       int             `json:"Tenor"`
 }
 
 type FindAllConsumerCreditLimitParams struct {
 	FindAllParams types.FindAllParams
 	ConsumerID    string `validate:"omitempty,uuid4"`
+	EffectiveOn   string
 }
 
 // Credit Limit Avalability
@@ -42,3 +73,20 @@ type ConsumerCreditLimitAvailability struct {
 	ConsumerID     string  `json:"ConsumerID" db:"consumer_id" validate:"required,uuid4"`
 	RemainingLimit float64 `json:"RemainingLimit" db:"remaining_limit" validate:"numeric"`
 }
+
+type ConsumerCreditLimitTenorAvailabilityBulk struct {
+	LoanProductID   string  `json:"LoanProductID" db:"loan_product_id"`
+	LoanProductName string  `json:"LoanProductName" db:"loan_product_name"`
+	Tenor           int     `json:"Tenor" db:"tenor"`
+	LimitAmount     float64 `json:"LimitAmount" db:"limit_amount"`
+	UsedAmount      float64 `json:"UsedAmount" db:"used_amount"`
+}
+
+// ConsumerCreditLimitTenorAvailability is the granted, used and remaining limit of one loan product
+type ConsumerCreditLimitTenorAvailability struct {
+	LoanProduct     *IDNameTemplate `json:"LoanProduct"`
+	Tenor           int             `json:"Tenor"`
+	LimitAmount     float64         `json:"LimitAmount"`
+	UsedAmount      float64         `json:"UsedAmount"`
+	RemainingAmount float64         `json:"RemainingAmount"`
+}
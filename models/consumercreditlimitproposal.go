@@ -0,0 +1,98 @@
+package models
+
+import (
+	"case-study-kredit-plus/library/types"
+	"time"
+)
+
+var (
+	CREDIT_LIMIT_PROPOSAL_DECISION_PROPOSED   = "proposed"
+	CREDIT_LIMIT_PROPOSAL_DECISION_ACCEPTED   = "accepted"
+	CREDIT_LIMIT_PROPOSAL_DECISION_OVERRIDDEN = "overridden"
+)
+
+type ConsumerCreditLimitProposalBulk struct {
+	ID                          string    `json:"ID" db:"id" validate:"omitempty,uuid4"`
+	ConsumerID                  string    `json:"ConsumerID" db:"consumer_id" validate:"required,uuid4"`
+	UnderwritingRuleSetID       string    `json:"UnderwritingRuleSetID" db:"underwriting_rule_set_id"`
+	Salary                      float64   `json:"Salary" db:"salary"`
+	Age                         int       `json:"Age" db:"age"`
+	MaxDebtServiceRatio         float64   `json:"MaxDebtServiceRatio" db:"max_debt_service_ratio"`
+	ExistingObligationAmount    float64   `json:"ExistingObligationAmount" db:"existing_obligation_amount"`
+	ExternalObligationAmount    float64   `json:"ExternalObligationAmount" db:"external_obligation_amount" validate:"gte=0"`
+	AffordableInstallmentAmount float64   `json:"AffordableInstallmentAmount" db:"affordable_installment_amount"`
+	Decision                    string    `json:"Decision" db:"decision"`
+	Reason                      string    `json:"Reason" db:"reason"`
+	ConsumerCreditLimitID       string    `json:"ConsumerCreditLimitID" db:"consumer_credit_limit_id"`
+	CreatedAt                   time.Time `json:"CreatedAt" db:"created_at"`
+
+	StatusID   string `json:"StatusID" db:"status_id"`
+	StatusName string `json:"StatusName" db:"status_name"`
+
+	ConsumerName            string `json:"ConsumerName" db:"consumer_name"`
+	UnderwritingRuleSetName string `json:"UnderwritingRuleSetName" db:"underwriting_rule_set_name"`
+}
+
+// ConsumerCreditLimitProposal is the limit underwriting suggests for a consumer, one detail per loan product.
+// It becomes a credit limit once an analyst accepts it as is or overrides the amounts.
+type ConsumerCreditLimitProposal struct {
+	ID                          string    `json:"ID" db:"id" validate:"omitempty,uuid4"`
+	ConsumerID                  string    `json:"ConsumerID" db:"consumer_id" validate:"required,uuid4"`
+	UnderwritingRuleSetID       string    `json:"UnderwritingRuleSetID" db:"underwriting_rule_set_id"`
+	Salary                      float64   `json:"Salary" db:"salary"`
+	Age                         int       `json:"Age" db:"age"`
+	MaxDebtServiceRatio         float64   `json:"MaxDebtServiceRatio" db:"max_debt_service_ratio"`
+	ExistingObligationAmount    float64   `json:"ExistingObligationAmount" db:"existing_obligation_amount"`
+	ExternalObligationAmount    float64   `json:"ExternalObligationAmount" db:"external_obligation_amount" validate:"gte=0"`
+	AffordableInstallmentAmount float64   `json:"AffordableInstallmentAmount" db:"affordable_installment_amount"`
+	Decision                    string    `json:"Decision" db:"decision"`
+	Reason                      string    `json:"Reason" db:"reason"`
+	ConsumerCreditLimitID       string    `json:"ConsumerCreditLimitID" db:"consumer_credit_limit_id"`
+	CreatedAt                   time.Time `json:"CreatedAt" db:"created_at"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+	Status   Status `json:"Status"`
+
+	Consumer            *IDNameTemplate `json:"Consumer"`
+	UnderwritingRuleSet *IDNameTemplate `json:"UnderwritingRuleSet"`
+
+	Details []*ConsumerCreditLimitProposalDetail `json:"Details"`
+}
+
+type ConsumerCreditLimitProposalDetailBulk struct {
+	ID                            string  `json:"ID" db:"id"`
+	ConsumerCreditLimitProposalID string  `json:"ConsumerCreditLimitProposalID" db:"consumer_credit_limit_proposal_id"`
+	LoanProductID                 string  `json:"LoanProductID" db:"loan_product_id"`
+	ProposedLimitAmount           float64 `json:"ProposedLimitAmount" db:"proposed_limit_amount"`
+	ApprovedLimitAmount           float64 `json:"ApprovedLimitAmount" db:"approved_limit_amount"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+
+	LoanProductName string `json:"LoanProductName" db:"loan_product_name"`
+	Tenor           int    `json:"Tenor" db:"tenor"`
+}
+
+type ConsumerCreditLimitProposalDetail struct {
+	ID                            string  `json:"ID" db:"id"`
+	ConsumerCreditLimitProposalID string  `json:"ConsumerCreditLimitProposalID" db:"consumer_credit_limit_proposal_id"`
+	LoanProductID                 string  `json:"LoanProductID" db:"loan_product_id"`
+	ProposedLimitAmount           float64 `json:"ProposedLimitAmount" db:"proposed_limit_amount"`
+	ApprovedLimitAmount           float64 `json:"ApprovedLimitAmount" db:"approved_limit_amount"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+
+	LoanProduct *IDNameTemplate `json:"LoanProduct"`
+	Tenor       int             `json:"Tenor"`
+}
+
+type FindAllConsumerCreditLimitProposalParams struct {
+	FindAllParams types.FindAllParams
+	ConsumerID    string `validate:"omitempty,uuid4"`
+	Decision      string `validate:"omitempty,oneof=proposed accepted overridden"`
+}
+
+// ConsumerObligation is what a consumer already pays every month on transactions that hold their limit
+type ConsumerObligation struct {
+	ConsumerID        string  `json:"ConsumerID" db:"consumer_id"`
+	InstallmentAmount float64 `json:"InstallmentAmount" db:"installment_amount"`
+}
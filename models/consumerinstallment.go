@@ -0,0 +1,85 @@
+package models
+
+import (
+	"case-study-kredit-plus/library/types"
+	"time"
+)
+
+type ConsumerInstallmentBulk struct {
+	ID                    string    `json:"ID" db:"id" validate:"omitempty,uuid4"`
+	ConsumerTransactionID string    `json:"ConsumerTransactionID" db:"consumer_transaction_id" validate:"required,uuid4"`
+	InstallmentNumber     int       `json:"InstallmentNumber" db:"installment_number"`
+	DueDate               time.Time `json:"DueDate" db:"due_date"`
+	PrincipalAmount       float64   `json:"PrincipalAmount" db:"principal_amount" validate:"numeric"`
+	InterestAmount        float64   `json:"InterestAmount" db:"interest_amount" validate:"numeric"`
+	FeeAmount             float64   `json:"FeeAmount" db:"fee_amount" validate:"numeric"`
+	InstallmentAmount     float64   `json:"InstallmentAmount" db:"installment_amount" validate:"numeric"`
+	OutstandingBalance    float64   `json:"OutstandingBalance" db:"outstanding_balance" validate:"numeric"`
+	PaidPrincipalAmount   float64   `json:"PaidPrincipalAmount" db:"paid_principal_amount" validate:"numeric"`
+	PaidInterestAmount    float64   `json:"PaidInterestAmount" db:"paid_interest_amount" validate:"numeric"`
+	PaidFeeAmount         float64   `json:"PaidFeeAmount" db:"paid_fee_amount" validate:"numeric"`
+
+	DaysPastDue       int        `json:"DaysPastDue" db:"days_past_due"`
+	LateFeeAmount     float64    `json:"LateFeeAmount" db:"late_fee_amount" validate:"numeric"`
+	PaidLateFeeAmount float64    `json:"PaidLateFeeAmount" db:"paid_late_fee_amount" validate:"numeric"`
+	LateFeeAccruedOn  *time.Time `json:"LateFeeAccruedOn" db:"late_fee_accrued_on"`
+
+	StatusID   string `json:"StatusID" db:"status_id"`
+	StatusName string `json:"StatusName" db:"status_name"`
+
+	ContractNumber string `json:"ContractNumber" db:"contract_number"`
+}
+
+type ConsumerInstallment struct {
+	ID                    string    `json:"ID" db:"id" validate:"omitempty,uuid4"`
+	ConsumerTransactionID string    `json:"ConsumerTransactionID" db:"consumer_transaction_id" validate:"required,uuid4"`
+	InstallmentNumber     int       `json:"InstallmentNumber" db:"installment_number"`
+	DueDate               time.Time `json:"DueDate" db:"due_date"`
+	PrincipalAmount       float64   `json:"PrincipalAmount" db:"principal_amount" validate:"numeric"`
+	InterestAmount        float64   `json:"InterestAmount" db:"interest_amount" validate:"numeric"`
+	FeeAmount             float64   `json:"FeeAmount" db:"fee_amount" validate:"numeric"`
+	InstallmentAmount     float64   `json:"InstallmentAmount" db:"installment_amount" validate:"numeric"`
+	OutstandingBalance    float64   `json:"OutstandingBalance" db:"outstanding_balance" validate:"numeric"`
+	PaidPrincipalAmount   float64   `json:"PaidPrincipalAmount" db:"paid_principal_amount" validate:"numeric"`
+	PaidInterestAmount    float64   `json:"PaidInterestAmount" db:"paid_interest_amount" validate:"numeric"`
+	PaidFeeAmount         float64   `json:"PaidFeeAmount" db:"paid_fee_amount" validate:"numeric"`
+
+	DaysPastDue       int        `json:"DaysPastDue" db:"days_past_due"`
+	LateFeeAmount     float64    `json:"LateFeeAmount" db:"late_fee_amount" validate:"numeric"`
+	PaidLateFeeAmount float64    `json:"PaidLateFeeAmount" db:"paid_late_fee_amount" validate:"numeric"`
+	LateFeeAccruedOn  *time.Time `json:"LateFeeAccruedOn" db:"late_fee_accrued_on"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+	Status   Status `json:"Status"`
+
+	ConsumerTransaction *IDNameTemplate `json:"ConsumerTransaction"`
+}
+
+type FindAllConsumerInstallmentParams struct {
+	FindAllParams         types.FindAllParams
+	ConsumerTransactionID string `validate:"omitempty,uuid4"`
+	MinDueDate            string
+	MaxDueDate            string
+	IsUnpaid              bool
+	MerchantID            string `validate:"omitempty,uuid"`
+}
+
+// ConsumerInstallmentOverdue is an installment past its due date together with the late fee rules of its loan product
+type ConsumerInstallmentOverdue struct {
+	ID                    string     `json:"ID" db:"id"`
+	ConsumerTransactionID string     `json:"ConsumerTransactionID" db:"consumer_transaction_id"`
+	DueDate               time.Time  `json:"DueDate" db:"due_date"`
+	InstallmentAmount     float64    `json:"InstallmentAmount" db:"installment_amount"`
+	PaidPrincipalAmount   float64    `json:"PaidPrincipalAmount" db:"paid_principal_amount"`
+	PaidInterestAmount    float64    `json:"PaidInterestAmount" db:"paid_interest_amount"`
+	PaidFeeAmount         float64    `json:"PaidFeeAmount" db:"paid_fee_amount"`
+	DaysPastDue           int        `json:"DaysPastDue" db:"days_past_due"`
+	LateFeeAmount         float64    `json:"LateFeeAmount" db:"late_fee_amount"`
+	LateFeeAccruedOn      *time.Time `json:"LateFeeAccruedOn" db:"late_fee_accrued_on"`
+
+	ConsumerTransactionStatusID string `json:"ConsumerTransactionStatusID" db:"consumer_transaction_status_id"`
+
+	LateFeeDailyRate float64 `json:"LateFeeDailyRate" db:"late_fee_daily_rate"`
+	LateFeeCapAmount float64 `json:"LateFeeCapAmount" db:"late_fee_cap_amount"`
+	LateFeeGraceDays int     `json:"LateFeeGraceDays" db:"late_fee_grace_days"`
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"case-study-kredit-plus/library/types"
+	"time"
+)
+
+var (
+	PAYMENT_TYPE_PAYMENT    = "Payment"
+	PAYMENT_TYPE_REVERSAL   = "Reversal"
+	PAYMENT_TYPE_SETTLEMENT = "Settlement"
+)
+
+type ConsumerPaymentBulk struct {
+	ID                    string    `json:"ID" db:"id" validate:"omitempty,uuid4"`
+	ConsumerTransactionID string    `json:"ConsumerTransactionID" db:"consumer_transaction_id" validate:"required,uuid4"`
+	PaymentType           string    `json:"PaymentType" db:"payment_type"`
+	PaymentDate           time.Time `json:"PaymentDate" db:"payment_date"`
+	Amount                float64   `json:"Amount" db:"amount" validate:"gt=0"`
+	ReferenceNumber       string    `json:"ReferenceNumber" db:"reference_number"`
+	ReversalOfID          string    `json:"ReversalOfID" db:"reversal_of_id"`
+	Reason                string    `json:"Reason" db:"reason"`
+
+	StatusID   string `json:"StatusID" db:"status_id"`
+	StatusName string `json:"StatusName" db:"status_name"`
+
+	ContractNumber string `json:"ContractNumber" db:"contract_number"`
+}
+
+type ConsumerPayment struct {
+	ID                    string    `json:"ID" db:"id" validate:"omitempty,uuid4"`
+	ConsumerTransactionID string    `json:"ConsumerTransactionID" db:"consumer_transaction_id" validate:"required,uuid4"`
+	PaymentType           string    `json:"PaymentType" db:"payment_type"`
+	PaymentDate           time.Time `json:"PaymentDate" db:"payment_date"`
+	Amount                float64   `json:"Amount" db:"amount" validate:"gt=0"`
+	ReferenceNumber       string    `json:"ReferenceNumber" db:"reference_number"`
+	ReversalOfID          string    `json:"ReversalOfID" db:"reversal_of_id"`
+	Reason                string    `json:"Reason" db:"reason"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+	Status   Status `json:"Status"`
+
+	ConsumerTransaction *IDNameTemplate `json:"ConsumerTransaction"`
+
+	Allocations []*ConsumerPaymentAllocation `json:"Allocations,omitempty"`
+}
+
+type ConsumerPaymentAllocation struct {
+	ID                    string  `json:"ID" db:"id" validate:"omitempty,uuid4"`
+	ConsumerPaymentID     string  `json:"ConsumerPaymentID" db:"consumer_payment_id" validate:"required,uuid4"`
+	ConsumerInstallmentID string  `json:"ConsumerInstallmentID" db:"consumer_installment_id" validate:"required,uuid4"`
+	LateFeeAmount         float64 `json:"LateFeeAmount" db:"late_fee_amount" validate:"numeric"`
+	FeeAmount             float64 `json:"FeeAmount" db:"fee_amount" validate:"numeric"`
+	InterestAmount        float64 `json:"InterestAmount" db:"interest_amount" validate:"numeric"`
+	PrincipalAmount       float64 `json:"PrincipalAmount" db:"principal_amount" validate:"numeric"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+}
+
+type FindAllConsumerPaymentParams struct {
+	FindAllParams         types.FindAllParams
+	ConsumerTransactionID string `validate:"omitempty,uuid4"`
+	PaymentType           string `validate:"omitempty,oneof=Payment Reversal Settlement"`
+	MinPaymentDate        string
+	MaxPaymentDate        string
+}
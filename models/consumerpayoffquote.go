@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+type ConsumerPayoffQuote struct {
+	ID                    string    `json:"ID" db:"id" validate:"omitempty,uuid4"`
+	ConsumerTransactionID string    `json:"ConsumerTransactionID" db:"consumer_transaction_id" validate:"required,uuid4"`
+	QuoteDate             time.Time `json:"QuoteDate" db:"quote_date"`
+	PrincipalAmount       float64   `json:"PrincipalAmount" db:"principal_amount"`
+	InterestAmount        float64   `json:"InterestAmount" db:"interest_amount"`
+	FeeAmount             float64   `json:"FeeAmount" db:"fee_amount"`
+	PenaltyAmount         float64   `json:"PenaltyAmount" db:"penalty_amount"`
+	TotalAmount           float64   `json:"TotalAmount" db:"total_amount"`
+	ExpiresAt             time.Time `json:"ExpiresAt" db:"expires_at"`
+	ConsumerPaymentID     string    `json:"ConsumerPaymentID" db:"consumer_payment_id"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+}
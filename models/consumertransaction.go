@@ -2,47 +2,99 @@ package models
 
 import (
 	"case-study-kredit-plus/library/types"
+	"time"
 )
 
 type ConsumerTransactionBulk struct {
 	ID                string  `json:"ID" db:"id" validate:"omitempty,uuid4"`
 	ConsumerID        string  `json:"ConsumerID" db:"consumer_id" validate:"required,uuid4"`
 	ContractNumber    string  `json:"ContractNumber" db:"contract_number"`
+	LoanProductID     string  `json:"LoanProductID" db:"loan_product_id" validate:"omitempty,uuid"`
 	OTR               float64 `json:"OTR" db:"OTR" validate:"numeric"`
 	AdminFee          float64 `json:"AdminFee" db:"admin_fee" validate:"numeric"`
 	InstallmentAmount float64 `json:"InstallmentAmount" db:"installment_amount" validate:"numeric"`
-	LoanTerm          int     `json:"LoanTerm" db:"loan_term" validate:"oneof=1 2 3 6"`
+	LoanTerm          int     `json:"LoanTerm" db:"loan_term" validate:"omitempty,gt=0"`
 	InterestAmount    float64 `json:"InterestAmount" db:"interest_amount" validate:"numeric"`
+	InterestMethod    string  `json:"InterestMethod" db:"interest_method"`
+	InterestRate      float64 `json:"InterestRate" db:"interest_rate" validate:"numeric"`
 	TotalAmount       float64 `json:"TotalAmount" db:"total_amount" validate:"numeric"`
 	AssetName         string  `json:"AssetName" db:"asset_name"`
+	DaysPastDue       int     `json:"DaysPastDue" db:"days_past_due"`
+	MerchantID        string  `json:"MerchantID" db:"merchant_id"`
+	APIClientID       int     `json:"APIClientID" db:"api_client_id"`
+	MerchantFeeRate   float64 `json:"MerchantFeeRate" db:"merchant_fee_rate"`
+	MerchantFeeAmount float64 `json:"MerchantFeeAmount" db:"merchant_fee_amount"`
+
+	CreatedAt time.Time `json:"CreatedAt" db:"created_at"`
 
 	StatusID   string `json:"StatusID" db:"status_id"`
 	StatusName string `json:"StatusName" db:"status_name"`
 
-	ConsumerName string `json:"ConsumerName" db:"consumer_name"`
+	ConsumerName    string `json:"ConsumerName" db:"consumer_name"`
+	LoanProductName string `json:"LoanProductName" db:"loan_product_name"`
+	MerchantName    string `json:"MerchantName" db:"merchant_name"`
 }
 
 type ConsumerTransaction struct {
 	ID                string  `json:"ID" db:"id" validate:"omitempty,uuid4"`
 	ConsumerID        string  `json:"ConsumerID" db:"consumer_id" validate:"required,uuid4"`
 	ContractNumber    string  `json:"ContractNumber" db:"contract_number"`
+	LoanProductID     string  `json:"LoanProductID" db:"loan_product_id" validate:"omitempty,uuid"`
 	OTR               float64 `json:"OTR" db:"OTR" validate:"numeric"`
 	AdminFee          float64 `json:"AdminFee" db:"admin_fee" validate:"numeric"`
 	InstallmentAmount float64 `json:"InstallmentAmount" db:"installment_amount" validate:"numeric"`
-	LoanTerm          int     `json:"LoanTerm" db:"loan_term" validate:"oneof=1 2 3 6"`
+	LoanTerm          int     `json:"LoanTerm" db:"loan_term" validate:"omitempty,gt=0"`
 	InterestAmount    float64 `json:"InterestAmount" db:"interest_amount" validate:"numeric"`
+	InterestMethod    string  `json:"InterestMethod" db:"interest_method"`
+	InterestRate      float64 `json:"InterestRate" db:"interest_rate" validate:"numeric"`
 	TotalAmount       float64 `json:"TotalAmount" db:"total_amount" validate:"numeric"`
 	AssetName         string  `json:"AssetName" db:"asset_name"`
 
+	// MerchantID and APIClientID stamp the partner that booked the transaction through the external API.
+	// MerchantFeeRate is the merchant's fee share at booking, MerchantFeeAmount its share of the admin fee
+	MerchantID        string  `json:"MerchantID" db:"merchant_id"`
+	APIClientID       int     `json:"APIClientID" db:"api_client_id"`
+	MerchantFeeRate   float64 `json:"MerchantFeeRate" db:"merchant_fee_rate"`
+	MerchantFeeAmount float64 `json:"MerchantFeeAmount" db:"merchant_fee_amount"`
+
+	// DaysPastDue is kept by the late fee worker, it is never written through the generic storage
+	DaysPastDue int `json:"DaysPastDue"`
+
+	CreatedAt time.Time `json:"CreatedAt" db:"created_at"`
+
 	StatusID string `json:"StatusID" db:"status_id"`
 	Status   Status `json:"Status"`
 
-	Consumer *IDNameTemplate `json:"Consumer"`
+	Consumer    *IDNameTemplate `json:"Consumer"`
+	LoanProduct *IDNameTemplate `json:"LoanProduct"`
+	Merchant    *IDNameTemplate `json:"Merchant,omitempty"`
+
+	Installments    []*ConsumerInstallment              `json:"Installments,omitempty"`
+	StatusHistories []*ConsumerTransactionStatusHistory `json:"StatusHistories,omitempty"`
+}
+
+// ConsumerTransactionSimulation is one installment option for a prospective transaction, it is never stored
+type ConsumerTransactionSimulation struct {
+	LoanProduct       *IDNameTemplate `json:"LoanProduct"`
+	LoanTerm          int             `json:"LoanTerm"`
+	OTR               float64         `json:"OTR"`
+	AdminFee          float64         `json:"AdminFee"`
+	InterestMethod    string          `json:"InterestMethod"`
+	InterestRate      float64         `json:"InterestRate"`
+	InterestAmount    float64         `json:"InterestAmount"`
+	InstallmentAmount float64         `json:"InstallmentAmount"`
+	TotalAmount       float64         `json:"TotalAmount"`
+	AssetName         string          `json:"AssetName"`
+	RemainingLimit    float64         `json:"RemainingLimit"`
+	LimitCovered      bool            `json:"LimitCovered"`
 }
 
 type FindAllConsumerTransactionParams struct {
 	FindAllParams  types.FindAllParams
 	ConsumerID     string `validate:"omitempty,uuid4"`
 	ContractNumber string
-	LoanTerm       int `validate:"omitempty,oneof=1 2 3 6"`
+	LoanProductID  string `validate:"omitempty,uuid"`
+	LoanTerm       int    `validate:"omitempty,gt=0"`
+	MinDaysPastDue int    `validate:"omitempty,gt=0"`
+	MerchantID     string `validate:"omitempty,uuid"`
 }
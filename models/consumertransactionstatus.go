@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+var (
+	TRANSACTION_STATUS_PENDING     = "pending"
+	TRANSACTION_STATUS_APPROVED    = "approved"
+	TRANSACTION_STATUS_REJECTED    = "rejected"
+	TRANSACTION_STATUS_DISBURSED   = "disbursed"
+	TRANSACTION_STATUS_ACTIVE      = "active"
+	TRANSACTION_STATUS_PAID_OFF    = "paid_off"
+	TRANSACTION_STATUS_CANCELLED   = "cancelled"
+	TRANSACTION_STATUS_WRITTEN_OFF = "written_off"
+
+	// TRANSACTION_STATUS_TRANSITIONS lists the statuses a transaction may move to from each status.
+	// Rejected, paid off, cancelled and written off are final, paid off only reopens when a payment is reversed.
+	TRANSACTION_STATUS_TRANSITIONS = map[string][]string{
+		TRANSACTION_STATUS_PENDING:   {TRANSACTION_STATUS_APPROVED, TRANSACTION_STATUS_REJECTED, TRANSACTION_STATUS_CANCELLED},
+		TRANSACTION_STATUS_APPROVED:  {TRANSACTION_STATUS_DISBURSED, TRANSACTION_STATUS_CANCELLED},
+		TRANSACTION_STATUS_DISBURSED: {TRANSACTION_STATUS_ACTIVE, TRANSACTION_STATUS_CANCELLED},
+		TRANSACTION_STATUS_ACTIVE:    {TRANSACTION_STATUS_PAID_OFF, TRANSACTION_STATUS_WRITTEN_OFF, TRANSACTION_STATUS_CANCELLED},
+	}
+)
+
+type ConsumerTransactionStatus struct {
+	ID            string `json:"ID" db:"id"`
+	Name          string `json:"Name" db:"name"`
+	ConsumesLimit bool   `json:"ConsumesLimit" db:"consumes_limit"`
+}
+
+type ConsumerTransactionStatusHistoryBulk struct {
+	ID                    string    `json:"ID" db:"id"`
+	ConsumerTransactionID string    `json:"ConsumerTransactionID" db:"consumer_transaction_id"`
+	FromStatusID          string    `json:"FromStatusID" db:"from_status_id"`
+	ToStatusID            string    `json:"ToStatusID" db:"to_status_id"`
+	Reason                string    `json:"Reason" db:"reason"`
+	CreatedAt             time.Time `json:"CreatedAt" db:"created_at"`
+	CreatedBy             string    `json:"CreatedBy" db:"created_by"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+
+	FromStatusName string `json:"FromStatusName" db:"from_status_name"`
+	ToStatusName   string `json:"ToStatusName" db:"to_status_name"`
+}
+
+type ConsumerTransactionStatusHistory struct {
+	ID                    string    `json:"ID" db:"id"`
+	ConsumerTransactionID string    `json:"ConsumerTransactionID" db:"consumer_transaction_id"`
+	FromStatusID          string    `json:"FromStatusID" db:"from_status_id"`
+	ToStatusID            string    `json:"ToStatusID" db:"to_status_id"`
+	Reason                string    `json:"Reason" db:"reason"`
+	CreatedAt             time.Time `json:"CreatedAt" db:"created_at"`
+	CreatedBy             string    `json:"CreatedBy"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+
+	FromStatus *IDNameTemplate `json:"FromStatus"`
+	ToStatus   *IDNameTemplate `json:"ToStatus"`
+}
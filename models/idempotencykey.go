@@ -0,0 +1,13 @@
+package models
+
+type IdempotencyKey struct {
+	ID                 string `json:"ID" db:"id" validate:"omitempty,uuid4"`
+	APIClientID        int    `json:"APIClientID" db:"api_client_id" validate:"gt=0"`
+	IdempotencyKey     string `json:"IdempotencyKey" db:"idempotency_key" validate:"required,max=255"`
+	RequestPath        string `json:"RequestPath" db:"request_path"`
+	RequestHash        string `json:"RequestHash" db:"request_hash"`
+	ResponseStatusCode int    `json:"ResponseStatusCode" db:"response_status_code"`
+	ResponseBody       string `json:"ResponseBody" db:"response_body"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"case-study-kredit-plus/library/types"
+	"time"
+)
+
+type LoanProductBulk struct {
+	ID             string     `json:"ID" db:"id" validate:"omitempty,uuid"`
+	Name           string     `json:"Name" db:"name" validate:"required"`
+	Code           string     `json:"Code" db:"code" validate:"omitempty,alphanum,max=20"`
+	Tenor          int        `json:"Tenor" db:"tenor" validate:"gt=0"`
+	InterestMethod string     `json:"InterestMethod" db:"interest_method" validate:"oneof=flat annuity"`
+	InterestRate   float64    `json:"InterestRate" db:"interest_rate" validate:"gte=0"`
+	AdminFeeAmount float64    `json:"AdminFeeAmount" db:"admin_fee_amount" validate:"gte=0"`
+	AdminFeeRate   float64    `json:"AdminFeeRate" db:"admin_fee_rate" validate:"gte=0"`
+	MinAmount      float64    `json:"MinAmount" db:"min_amount" validate:"gte=0"`
+	MaxAmount      float64    `json:"MaxAmount" db:"max_amount" validate:"gte=0"`
+	ActiveFrom     *time.Time `json:"ActiveFrom" db:"active_from"`
+	ActiveTo       *time.Time `json:"ActiveTo" db:"active_to"`
+
+	CancellationWindowDays       int     `json:"CancellationWindowDays" db:"cancellation_window_days" validate:"gte=0"`
+	EarlySettlementPenaltyAmount float64 `json:"EarlySettlementPenaltyAmount" db:"early_settlement_penalty_amount" validate:"gte=0"`
+	EarlySettlementPenaltyRate   float64 `json:"EarlySettlementPenaltyRate" db:"early_settlement_penalty_rate" validate:"gte=0,lte=100"`
+	LateFeeDailyRate             float64 `json:"LateFeeDailyRate" db:"late_fee_daily_rate" validate:"gte=0,lte=100"`
+	LateFeeCapAmount             float64 `json:"LateFeeCapAmount" db:"late_fee_cap_amount" validate:"gte=0"`
+	LateFeeGraceDays             int     `json:"LateFeeGraceDays" db:"late_fee_grace_days" validate:"gte=0"`
+
+	StatusID   string `json:"StatusID" db:"status_id"`
+	StatusName string `json:"StatusName" db:"status_name"`
+}
+
+type LoanProduct struct {
+	ID             string     `json:"ID" db:"id" validate:"omitempty,uuid"`
+	Name           string     `json:"Name" db:"name" validate:"required"`
+	Code           string     `json:"Code" db:"code" validate:"omitempty,alphanum,max=20"`
+	Tenor          int        `json:"Tenor" db:"tenor" validate:"gt=0"`
+	InterestMethod string     `json:"InterestMethod" db:"interest_method" validate:"oneof=flat annuity"`
+	InterestRate   float64    `json:"InterestRate" db:"interest_rate" validate:"gte=0"`
+	AdminFeeAmount float64    `json:"AdminFeeAmount" db:"admin_fee_amount" validate:"gte=0"`
+	AdminFeeRate   float64    `json:"AdminFeeRate" db:"admin_fee_rate" validate:"gte=0"`
+	MinAmount      float64    `json:"MinAmount" db:"min_amount" validate:"gte=0"`
+	MaxAmount      float64    `json:"MaxAmount" db:"max_amount" validate:"gte=0"`
+	ActiveFrom     *time.Time `json:"ActiveFrom" db:"active_from"`
+	ActiveTo       *time.Time `json:"ActiveTo" db:"active_to"`
+
+	CancellationWindowDays       int     `json:"CancellationWindowDays" db:"cancellation_window_days" validate:"gte=0"`
+	EarlySettlementPenaltyAmount float64 `json:"EarlySettlementPenaltyAmount" db:"early_settlement_penalty_amount" validate:"gte=0"`
+	EarlySettlementPenaltyRate   float64 `json:"EarlySettlementPenaltyRate" db:"early_settlement_penalty_rate" validate:"gte=0,lte=100"`
+	LateFeeDailyRate             float64 `json:"LateFeeDailyRate" db:"late_fee_daily_rate" validate:"gte=0,lte=100"`
+	LateFeeCapAmount             float64 `json:"LateFeeCapAmount" db:"late_fee_cap_amount" validate:"gte=0"`
+	LateFeeGraceDays             int     `json:"LateFeeGraceDays" db:"late_fee_grace_days" validate:"gte=0"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+	Status   Status `json:"Status"`
+}
+
+type FindAllLoanProductParams struct {
+	FindAllParams types.FindAllParams
+	ID            string `validate:"omitempty,uuid"`
+	Tenor         int    `validate:"omitempty,gt=0"`
+	ActiveOn      string
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"case-study-kredit-plus/library/types"
+)
+
+type MerchantBulk struct {
+	ID                      string  `json:"ID" db:"id" validate:"omitempty,uuid"`
+	Name                    string  `json:"Name" db:"name" validate:"required"`
+	Email                   string  `json:"Email" db:"email" validate:"omitempty,email"`
+	PhoneNumber             string  `json:"PhoneNumber" db:"phone_number" validate:"omitempty,numeric,max=50"`
+	Address                 string  `json:"Address" db:"address"`
+	SettlementBankName      string  `json:"SettlementBankName" db:"settlement_bank_name" validate:"required"`
+	SettlementAccountNumber string  `json:"SettlementAccountNumber" db:"settlement_account_number" validate:"required,numeric,max=50"`
+	SettlementAccountName   string  `json:"SettlementAccountName" db:"settlement_account_name" validate:"required"`
+	FeeShareRate            float64 `json:"FeeShareRate" db:"fee_share_rate" validate:"gte=0,lte=100"`
+
+	StatusID   string `json:"StatusID" db:"status_id"`
+	StatusName string `json:"StatusName" db:"status_name"`
+}
+
+// Merchant is a partner selling on credit through the external API.
+// FeeShareRate is the percentage of the admin fee paid out to the merchant on every transaction it books.
+type Merchant struct {
+	ID                      string  `json:"ID" db:"id" validate:"omitempty,uuid"`
+	Name                    string  `json:"Name" db:"name" validate:"required"`
+	Email                   string  `json:"Email" db:"email" validate:"omitempty,email"`
+	PhoneNumber             string  `json:"PhoneNumber" db:"phone_number" validate:"omitempty,numeric,max=50"`
+	Address                 string  `json:"Address" db:"address"`
+	SettlementBankName      string  `json:"SettlementBankName" db:"settlement_bank_name" validate:"required"`
+	SettlementAccountNumber string  `json:"SettlementAccountNumber" db:"settlement_account_number" validate:"required,numeric,max=50"`
+	SettlementAccountName   string  `json:"SettlementAccountName" db:"settlement_account_name" validate:"required"`
+	FeeShareRate            float64 `json:"FeeShareRate" db:"fee_share_rate" validate:"gte=0,lte=100"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+	Status   Status `json:"Status"`
+
+	LoanProducts []*MerchantLoanProduct `json:"LoanProducts,omitempty"`
+	APIClients   []*INTIDNameTemplate   `json:"APIClients,omitempty"`
+}
+
+type MerchantLoanProductBulk struct {
+	ID            string `json:"ID" db:"id"`
+	MerchantID    string `json:"MerchantID" db:"merchant_id"`
+	LoanProductID string `json:"LoanProductID" db:"loan_product_id" validate:"required,uuid"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+
+	LoanProductName string `json:"LoanProductName" db:"loan_product_name"`
+	Tenor           int    `json:"Tenor" db:"tenor"`
+}
+
+// MerchantLoanProduct is a loan product the merchant may sell
+type MerchantLoanProduct struct {
+	ID            string `json:"ID" db:"id"`
+	MerchantID    string `json:"MerchantID" db:"merchant_id"`
+	LoanProductID string `json:"LoanProductID" db:"loan_product_id" validate:"required,uuid"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+
+	LoanProduct *IDNameTemplate `json:"LoanProduct"`
+	Tenor       int             `json:"Tenor"`
+}
+
+type FindAllMerchantParams struct {
+	FindAllParams types.FindAllParams
+	Name          string
+}
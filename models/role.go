@@ -0,0 +1,87 @@
+package models
+
+import (
+	"case-study-kredit-plus/library/types"
+)
+
+var (
+	// roles seeded by the migrations, the admin role cannot be changed or deactivated
+	ROLE_ADMIN          = "8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e01"
+	ROLE_CREDIT_ANALYST = "8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e02"
+	ROLE_OPERATIONS     = "8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e03"
+	ROLE_AUDITOR        = "8c2f4e1a-5b3d-4a6e-9f70-1d2c3b4a5e04"
+
+	PERMISSION_CONSUMERS_READ          = "consumers.read"
+	PERMISSION_CONSUMERS_WRITE         = "consumers.write"
+	PERMISSION_CONSUMER_DOCUMENTS_READ = "consumer_documents.read"
+	PERMISSION_CREDIT_LIMITS_READ      = "credit_limits.read"
+	PERMISSION_CREDIT_LIMITS_WRITE     = "credit_limits.write"
+	PERMISSION_UNDERWRITING_READ       = "underwriting.read"
+	PERMISSION_UNDERWRITING_WRITE      = "underwriting.write"
+	PERMISSION_TRANSACTIONS_READ       = "transactions.read"
+	PERMISSION_TRANSACTIONS_WRITE      = "transactions.write"
+	PERMISSION_PAYMENTS_READ           = "payments.read"
+	PERMISSION_PAYMENTS_WRITE          = "payments.write"
+	PERMISSION_LOAN_PRODUCTS_READ      = "loan_products.read"
+	PERMISSION_LOAN_PRODUCTS_WRITE     = "loan_products.write"
+	PERMISSION_MERCHANTS_READ          = "merchants.read"
+	PERMISSION_MERCHANTS_WRITE         = "merchants.write"
+	PERMISSION_API_CLIENTS_READ        = "api_clients.read"
+	PERMISSION_API_CLIENTS_WRITE       = "api_clients.write"
+	PERMISSION_USERS_READ              = "users.read"
+	PERMISSION_USERS_WRITE             = "users.write"
+	PERMISSION_ROLES_READ              = "roles.read"
+	PERMISSION_ROLES_WRITE             = "roles.write"
+)
+
+type RoleBulk struct {
+	ID          string `json:"ID" db:"id"`
+	Name        string `json:"Name" db:"name"`
+	Description string `json:"Description" db:"description"`
+
+	StatusID   string `json:"StatusID" db:"status_id"`
+	StatusName string `json:"StatusName" db:"status_name"`
+}
+
+// Role groups the permissions given to back-office users, every user has one role
+type Role struct {
+	ID          string `json:"ID" db:"id" validate:"omitempty,uuid"`
+	Name        string `json:"Name" db:"name" validate:"required"`
+	Description string `json:"Description" db:"description"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+	Status   Status `json:"Status"`
+
+	Permissions []*RolePermission `json:"Permissions,omitempty"`
+}
+
+// Permission is an action on the back office, its ID is the code checked by the routes, e.g. consumers.write
+type Permission struct {
+	ID          string `json:"ID" db:"id"`
+	Description string `json:"Description" db:"description"`
+}
+
+type RolePermissionBulk struct {
+	ID           string `json:"ID" db:"id"`
+	RoleID       string `json:"RoleID" db:"role_id"`
+	PermissionID string `json:"PermissionID" db:"permission_id"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+
+	PermissionDescription string `json:"PermissionDescription" db:"permission_description"`
+}
+
+type RolePermission struct {
+	ID           string `json:"ID" db:"id"`
+	RoleID       string `json:"RoleID" db:"role_id"`
+	PermissionID string `json:"PermissionID" db:"permission_id" validate:"required"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+
+	Permission *Permission `json:"Permission"`
+}
+
+type FindAllRoleParams struct {
+	FindAllParams types.FindAllParams
+	Name          string
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"case-study-kredit-plus/library/types"
+)
+
+type UnderwritingRuleSetBulk struct {
+	ID                  string  `json:"ID" db:"id" validate:"omitempty,uuid"`
+	Name                string  `json:"Name" db:"name" validate:"required"`
+	MinAge              int     `json:"MinAge" db:"min_age" validate:"gte=0"`
+	MaxAge              int     `json:"MaxAge" db:"max_age" validate:"gtfield=MinAge"`
+	MinSalary           float64 `json:"MinSalary" db:"min_salary" validate:"gte=0"`
+	MaxDebtServiceRatio float64 `json:"MaxDebtServiceRatio" db:"max_debt_service_ratio" validate:"gt=0,lte=100"`
+	MaxLimitAmount      float64 `json:"MaxLimitAmount" db:"max_limit_amount" validate:"gte=0"`
+
+	StatusID   string `json:"StatusID" db:"status_id"`
+	StatusName string `json:"StatusName" db:"status_name"`
+}
+
+// UnderwritingRuleSet decides who qualifies for a credit limit and how much of the salary may go to installments.
+// MaxLimitAmount caps every proposed limit, zero leaves it to the loan product's max amount.
+type UnderwritingRuleSet struct {
+	ID                  string  `json:"ID" db:"id" validate:"omitempty,uuid"`
+	Name                string  `json:"Name" db:"name" validate:"required"`
+	MinAge              int     `json:"MinAge" db:"min_age" validate:"gte=0"`
+	MaxAge              int     `json:"MaxAge" db:"max_age" validate:"gtfield=MinAge"`
+	MinSalary           float64 `json:"MinSalary" db:"min_salary" validate:"gte=0"`
+	MaxDebtServiceRatio float64 `json:"MaxDebtServiceRatio" db:"max_debt_service_ratio" validate:"gt=0,lte=100"`
+	MaxLimitAmount      float64 `json:"MaxLimitAmount" db:"max_limit_amount" validate:"gte=0"`
+
+	StatusID string `json:"StatusID" db:"status_id"`
+	Status   Status `json:"Status"`
+}
+
+type FindAllUnderwritingRuleSetParams struct {
+	FindAllParams types.FindAllParams
+	Age           int     `validate:"omitempty,gt=0"`
+	Salary        float64 `validate:"omitempty,gt=0"`
+}
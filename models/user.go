@@ -12,9 +12,12 @@ type UserBulk struct {
 	CountryCallingCode string `json:"CountryCallingCode" db:"country_calling_code"`
 	PhoneNumber        string `json:"PhoneNumber" db:"phone_number"`
 	Password           string `json:"Password" db:"password"`
+	RoleID             string `json:"RoleID" db:"role_id"`
 
 	StatusID   string `json:"StatusID" db:"status_id"`
 	StatusName string `json:"StatusName" db:"status_name"`
+
+	RoleName string `json:"RoleName" db:"role_name"`
 }
 
 type User struct {
@@ -25,16 +28,22 @@ type User struct {
 	CountryCallingCode string `json:"CountryCallingCode" db:"country_calling_code"`
 	PhoneNumber        string `json:"PhoneNumber" db:"phone_number"`
 	Password           string `json:"Password" db:"password"`
+	RoleID             string `json:"RoleID" db:"role_id"`
 
 	StatusID string `json:"StatusID" db:"status_id"`
 	Status   Status `json:"Status"`
+
+	Role *IDNameTemplate `json:"Role,omitempty"`
 }
 
 type UserJWTContent struct {
-	ID    string `json:"ID" db:"id"`
-	Name  string `json:"Name" db:"name" validate:"required"`
-	Token string `json:"Token"`
-	Email string `json:"Email" db:"email" validate:"required"`
+	ID           string `json:"ID" db:"id"`
+	Name         string `json:"Name" db:"name" validate:"required"`
+	Token        string `json:"Token"`
+	RefreshToken string `json:"RefreshToken"`
+	Email        string `json:"Email" db:"email" validate:"required"`
+
+	RoleID string `json:"RoleID" db:"role_id"`
 
 	StatusID string `json:"StatusID" db:"status_id"`
 	Status   Status `json:"Status"`
@@ -56,4 +65,5 @@ type FindAllUserParams struct {
 	CountryCallingCode string
 	PhoneNumber        string
 	Password           string
+	RoleID             string
 }
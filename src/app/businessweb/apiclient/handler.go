@@ -0,0 +1,437 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/helpers"
+	"case-study-kredit-plus/middleware"
+	"case-study-kredit-plus/models"
+	"case-study-kredit-plus/src/services/apiclient"
+
+	"github.com/gin-gonic/gin"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/http/response"
+	"case-study-kredit-plus/library/types"
+
+	apiclientRepository "case-study-kredit-plus/src/services/apiclient/repository"
+	apiclientUsecase "case-study-kredit-plus/src/services/apiclient/usecase"
+)
+
+var ()
+
+type APIClientHandler struct {
+	APIClientUsecase apiclient.Usecase
+	dataManager      *data.Manager
+	Result           gin.H
+	Status           int
+}
+
+func (h APIClientHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, router *gin.Engine, v *gin.RouterGroup) {
+	apiclientRepo := apiclientRepository.NewAPIClientRepository(
+		data.NewMySQLStorage(db, "api_client", models.APIClient{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "api_client_scopes", models.APIClientScope{}, data.MysqlConfig{}),
+	)
+
+	uAPIClient := apiclientUsecase.NewAPIClientUsecase(db, &apiclientRepo)
+
+	base := &APIClientHandler{APIClientUsecase: uAPIClient, dataManager: dataManager}
+
+	rs := v.Group("/api-clients")
+	{
+		rs.GET("", middleware.Auth, middleware.Permission(models.PERMISSION_API_CLIENTS_READ), base.FindAll)
+		rs.GET("/scopes", middleware.Auth, middleware.Permission(models.PERMISSION_API_CLIENTS_READ), base.FindScopes)
+		rs.GET("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_API_CLIENTS_READ), base.Find)
+		rs.POST("", middleware.Auth, middleware.Permission(models.PERMISSION_API_CLIENTS_WRITE), base.Create)
+		rs.PUT("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_API_CLIENTS_WRITE), base.Update)
+
+		rs.POST("/:id/rotate", middleware.Auth, middleware.Permission(models.PERMISSION_API_CLIENTS_WRITE), base.Rotate)
+		rs.PUT("/:id/expiry", middleware.Auth, middleware.Permission(models.PERMISSION_API_CLIENTS_WRITE), base.Expire)
+		rs.POST("/:id/revoke", middleware.Auth, middleware.Permission(models.PERMISSION_API_CLIENTS_WRITE), base.Revoke)
+	}
+
+	status := v.Group("/statuses")
+	{
+		status.GET("/api-clients", middleware.AuthCheckIP, base.FindStatus)
+	}
+}
+
+func (h *APIClientHandler) FindAll(c *gin.Context) {
+	var params models.FindAllAPIClientParams
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	params.FindAllParams = filterFindAllParams
+
+	if c.Query("Name") != "" && !library.ValidateTextInput(c.Query("Name")) {
+		err := &types.Error{
+			Path:       ".APIClientHandler->FindAll()",
+			Message:    "Name is not valid",
+			Error:      fmt.Errorf("Name is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	if c.Query("MerchantID") != "" && !library.ValidateUUID(c.Query("MerchantID")) {
+		err := &types.Error{
+			Path:       ".APIClientHandler->FindAll()",
+			Message:    "Merchant ID is not valid",
+			Error:      fmt.Errorf("Merchant ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	params.Name = c.Query("Name")
+	params.MerchantID = c.Query("MerchantID")
+
+	datas, err := h.APIClientUsecase.FindAll(c, params)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	length, err := h.APIClientUsecase.Count(c, params)
+	if err != nil {
+		err.Path = ".APIClientHandler->FindAll()" + err.Path
+		if err.Error != data.ErrNotFound {
+			response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	dataresponse := types.ResultAll{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *APIClientHandler) Find(c *gin.Context) {
+	id, err := h.bindID(c)
+	if err != nil {
+		err.Path = ".APIClientHandler->Find()" + err.Path
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	result, err := h.APIClientUsecase.Find(c, id)
+	if err != nil {
+		err.Path = ".APIClientHandler->Find()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, "API Client not found", http.StatusUnprocessableEntity, *err)
+			return
+		}
+		response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+// Create answers with the token of the new client, it is not shown again
+func (h *APIClientHandler) Create(c *gin.Context) {
+	var err *types.Error
+	var data *models.APIClient
+
+	obj, err := h.bindAPIClient(c)
+	if err != nil {
+		err.Path = ".APIClientHandler->Create()" + err.Path
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.APIClientUsecase.Create(c, obj)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".APIClientHandler->Create()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data created successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *APIClientHandler) Update(c *gin.Context) {
+	var err *types.Error
+	var data *models.APIClient
+
+	id, err := h.bindID(c)
+	if err != nil {
+		err.Path = ".APIClientHandler->Update()" + err.Path
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	obj, err := h.bindAPIClient(c)
+	if err != nil {
+		err.Path = ".APIClientHandler->Update()" + err.Path
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.APIClientUsecase.Update(c, id, obj)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".APIClientHandler->Update()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data updated successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *APIClientHandler) FindStatus(c *gin.Context) {
+	datas, err := h.APIClientUsecase.FindStatus(c)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data successfuly shown", Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(http.StatusOK, h.Result)
+}
+
+// Rotate answers with the new token of the client, it is not shown again
+func (h *APIClientHandler) Rotate(c *gin.Context) {
+	var err *types.Error
+	var data *models.APIClient
+
+	id, err := h.bindID(c)
+	if err != nil {
+		err.Path = ".APIClientHandler->Rotate()" + err.Path
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.APIClientUsecase.Rotate(c, id)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".APIClientHandler->Rotate()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Token rotated successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+// Expire takes ExpiresAt as a timestamp, or as a date meaning the end of that day. Empty removes the expiry.
+func (h *APIClientHandler) Expire(c *gin.Context) {
+	var err *types.Error
+	var data *models.APIClient
+
+	id, err := h.bindID(c)
+	if err != nil {
+		err.Path = ".APIClientHandler->Expire()" + err.Path
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	expiresAt, err := h.bindExpiresAt(c)
+	if err != nil {
+		err.Path = ".APIClientHandler->Expire()" + err.Path
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.APIClientUsecase.Expire(c, id, expiresAt)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".APIClientHandler->Expire()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data updated successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *APIClientHandler) Revoke(c *gin.Context) {
+	var err *types.Error
+	var data *models.APIClient
+
+	id, err := h.bindID(c)
+	if err != nil {
+		err.Path = ".APIClientHandler->Revoke()" + err.Path
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.APIClientUsecase.Revoke(c, id)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".APIClientHandler->Revoke()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "API Client revoked successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *APIClientHandler) FindScopes(c *gin.Context) {
+	datas := h.APIClientUsecase.FindScopes(c)
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *APIClientHandler) bindID(c *gin.Context) (int, *types.Error) {
+	id, errParseInt := strconv.Atoi(c.Param("id"))
+	if errParseInt != nil || id <= 0 {
+		return 0, &types.Error{
+			Path:       ".APIClientHandler->bindID()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	return id, nil
+}
+
+func (h *APIClientHandler) bindExpiresAt(c *gin.Context) (*time.Time, *types.Error) {
+	if c.PostForm("ExpiresAt") == "" {
+		return nil, nil
+	}
+
+	expiresAt, errParseTime := time.Parse(library.StrToTimestampFormat, c.PostForm("ExpiresAt"))
+	if errParseTime != nil {
+		expiresAt, errParseTime = time.Parse(library.StrToDateFormat, c.PostForm("ExpiresAt"))
+		if errParseTime != nil {
+			return nil, &types.Error{
+				Path:       ".APIClientHandler->bindExpiresAt()",
+				Message:    "Expires At Invalid",
+				Error:      errParseTime,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+		}
+
+		expiresAt = expiresAt.Add(24*time.Hour - time.Second)
+	}
+
+	return &expiresAt, nil
+}
+
+// bindAPIClient reads the API client form shared by Create and Update
+func (h *APIClientHandler) bindAPIClient(c *gin.Context) (models.APIClient, *types.Error) {
+	var obj models.APIClient
+
+	if !library.ValidateTextInput(c.PostForm("Name")) {
+		return obj, &types.Error{
+			Path:       ".APIClientHandler->bindAPIClient()",
+			Message:    "Name is not valid",
+			Error:      fmt.Errorf("Name is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	var scopes []*models.APIClientScope
+	if c.PostForm("Scopes") != "" {
+		errJson := json.Unmarshal([]byte(c.PostForm("Scopes")), &scopes)
+		if errJson != nil {
+			return obj, &types.Error{
+				Path:       ".APIClientHandler->bindAPIClient()",
+				Message:    "Scopes Invalid",
+				Error:      errJson,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+		}
+	}
+
+	expiresAt, err := h.bindExpiresAt(c)
+	if err != nil {
+		err.Path = ".APIClientHandler->bindAPIClient()" + err.Path
+		return obj, err
+	}
+
+	obj.Name = c.PostForm("Name")
+	obj.Scopes = scopes
+	obj.ExpiresAt = expiresAt
+
+	return obj, nil
+}
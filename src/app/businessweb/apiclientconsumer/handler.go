@@ -0,0 +1,279 @@
+package apiclientconsumer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/filestorage"
+	"case-study-kredit-plus/library/helpers"
+	"case-study-kredit-plus/middleware"
+	"case-study-kredit-plus/models"
+	"case-study-kredit-plus/src/services/apiclientconsumer"
+
+	"github.com/gin-gonic/gin"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/fieldcrypt"
+	"case-study-kredit-plus/library/http/response"
+	"case-study-kredit-plus/library/types"
+
+	apiclientconsumerRepository "case-study-kredit-plus/src/services/apiclientconsumer/repository"
+	apiclientconsumerUsecase "case-study-kredit-plus/src/services/apiclientconsumer/usecase"
+	consumerRepository "case-study-kredit-plus/src/services/consumer/repository"
+	consumerUsecase "case-study-kredit-plus/src/services/consumer/usecase"
+)
+
+var ()
+
+type APIClientConsumerHandler struct {
+	APIClientConsumerUsecase apiclientconsumer.Usecase
+	dataManager              *data.Manager
+	Result                   gin.H
+	Status                   int
+}
+
+func (h APIClientConsumerHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, router *gin.Engine, v *gin.RouterGroup) {
+	apiclientconsumerRepo := apiclientconsumerRepository.NewAPIClientConsumerRepository(
+		data.NewMySQLStorage(db, "api_client_consumers", models.APIClientConsumer{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	consumerRepo := consumerRepository.NewConsumerRepository(
+		data.NewMySQLStorage(db, "consumers", models.ConsumerEncrypted{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		fieldcrypt.NewFromConfiguration(),
+	)
+
+	uConsumer := consumerUsecase.NewConsumerUsecase(db, &consumerRepo, filestorage.NewFromConfiguration())
+	uAPIClientConsumer := apiclientconsumerUsecase.NewAPIClientConsumerUsecase(db, &apiclientconsumerRepo, uConsumer)
+
+	base := &APIClientConsumerHandler{APIClientConsumerUsecase: uAPIClientConsumer, dataManager: dataManager}
+
+	rs := v.Group("/api-clients/consumers")
+	{
+		rs.GET("", middleware.Auth, middleware.Permission(models.PERMISSION_API_CLIENTS_READ), base.FindAll)
+		rs.GET("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_API_CLIENTS_READ), base.Find)
+		rs.POST("", middleware.Auth, middleware.Permission(models.PERMISSION_API_CLIENTS_WRITE), base.Create)
+
+		rs.PUT("/status", middleware.Auth, middleware.Permission(models.PERMISSION_API_CLIENTS_WRITE), base.UpdateStatus)
+	}
+
+	status := v.Group("/statuses")
+	{
+		status.GET("/api-clients/consumers", middleware.AuthCheckIP, base.FindStatus)
+	}
+}
+
+func (h *APIClientConsumerHandler) FindAll(c *gin.Context) {
+	var params models.FindAllAPIClientConsumerParams
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	params.FindAllParams = filterFindAllParams
+
+	if c.Query("APIClientID") != "" {
+		apiClientID, errParseInt := strconv.Atoi(c.Query("APIClientID"))
+		if errParseInt != nil {
+			err := &types.Error{
+				Path:       ".APIClientConsumerHandler->FindAll()",
+				Message:    "API Client ID Invalid",
+				Error:      errParseInt,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		params.APIClientID = apiClientID
+	}
+
+	if c.Query("ConsumerID") != "" && !library.ValidateUUID(c.Query("ConsumerID")) {
+		err := &types.Error{
+			Path:       ".APIClientConsumerHandler->FindAll()",
+			Message:    "Consumer ID is not valid",
+			Error:      fmt.Errorf("Consumer ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	params.ConsumerID = c.Query("ConsumerID")
+
+	datas, err := h.APIClientConsumerUsecase.FindAll(c, params)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	length, err := h.APIClientConsumerUsecase.Count(c, params)
+	if err != nil {
+		err.Path = ".APIClientConsumerHandler->FindAll()" + err.Path
+		if err.Error != data.ErrNotFound {
+			response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	dataresponse := types.ResultAll{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *APIClientConsumerHandler) Find(c *gin.Context) {
+	id := c.Param("id")
+
+	if !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".APIClientConsumerHandler->Find()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	result, err := h.APIClientConsumerUsecase.Find(c, id)
+	if err != nil {
+		err.Path = ".APIClientConsumerHandler->Find()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, "APIClientConsumer not found", http.StatusUnprocessableEntity, *err)
+			return
+		}
+		response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *APIClientConsumerHandler) Create(c *gin.Context) {
+	var err *types.Error
+	var obj models.APIClientConsumer
+	var data *models.APIClientConsumer
+
+	apiClientID, errParseInt := strconv.Atoi(c.PostForm("APIClientID"))
+	if errParseInt != nil {
+		err := &types.Error{
+			Path:       ".APIClientConsumerHandler->Create()",
+			Message:    "API Client ID Invalid",
+			Error:      errParseInt,
+			Type:       "conversion-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	if !library.ValidateUUID(c.PostForm("ConsumerID")) {
+		err := &types.Error{
+			Path:       ".APIClientConsumerHandler->Create()",
+			Message:    "Consumer ID is not valid",
+			Error:      fmt.Errorf("Consumer ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	obj.APIClientID = apiClientID
+	obj.ConsumerID = c.PostForm("ConsumerID")
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.APIClientConsumerUsecase.Create(c, obj)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".APIClientConsumerHandler->Create()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data created successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *APIClientConsumerHandler) FindStatus(c *gin.Context) {
+	datas, err := h.APIClientConsumerUsecase.FindStatus(c)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data successfuly shown", Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *APIClientConsumerHandler) UpdateStatus(c *gin.Context) {
+	var err *types.Error
+	var data *models.APIClientConsumer
+
+	var ids []*models.IDNameTemplate
+
+	newStatusID := c.PostForm("NewStatusID")
+
+	errJson := json.Unmarshal([]byte(c.PostForm("ID")), &ids)
+	if errJson != nil {
+		err = &types.Error{
+			Path:  ".APIClientConsumerHandler->UpdateStatus()",
+			Error: errJson,
+			Type:  "convert-error",
+		}
+		response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		for _, id := range ids {
+			data, err = h.APIClientConsumerUsecase.UpdateStatus(c, id.ID, newStatusID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".APIClientConsumerHandler->UpdateStatus()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Status update success", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
@@ -3,13 +3,16 @@ package consumer
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jmoiron/sqlx"
 
 	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/filestorage"
 	"case-study-kredit-plus/library/helpers"
 	"case-study-kredit-plus/middleware"
 	"case-study-kredit-plus/models"
@@ -18,6 +21,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/fieldcrypt"
 	"case-study-kredit-plus/library/http/response"
 	"case-study-kredit-plus/library/types"
 
@@ -36,22 +40,26 @@ type ConsumerHandler struct {
 
 func (h ConsumerHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, router *gin.Engine, v *gin.RouterGroup) {
 	consumerRepo := consumerRepository.NewConsumerRepository(
-		data.NewMySQLStorage(db, "consumers", models.Consumer{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumers", models.ConsumerEncrypted{}, data.MysqlConfig{}),
 		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		fieldcrypt.NewFromConfiguration(),
 	)
 
-	uConsumer := consumerUsecase.NewConsumerUsecase(db, &consumerRepo)
+	uConsumer := consumerUsecase.NewConsumerUsecase(db, &consumerRepo, filestorage.NewFromConfiguration())
 
 	base := &ConsumerHandler{ConsumerUsecase: uConsumer, dataManager: dataManager}
 
 	rs := v.Group("/consumers")
 	{
-		rs.GET("", middleware.Auth, base.FindAll)
-		rs.GET("/:id", middleware.Auth, base.Find)
-		rs.POST("", middleware.Auth, base.Create)
-		rs.PUT("/:id", middleware.Auth, base.Update)
+		rs.GET("", middleware.Auth, middleware.Permission(models.PERMISSION_CONSUMERS_READ), base.FindAll)
+		rs.GET("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_CONSUMERS_READ), base.Find)
+		rs.POST("", middleware.Auth, middleware.Permission(models.PERMISSION_CONSUMERS_WRITE), base.Create)
+		rs.PUT("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_CONSUMERS_WRITE), base.Update)
 
-		rs.PUT("/status", middleware.Auth, base.UpdateStatus)
+		rs.PUT("/status", middleware.Auth, middleware.Permission(models.PERMISSION_CONSUMERS_WRITE), base.UpdateStatus)
+
+		rs.POST("/:id/documents", middleware.Auth, middleware.Permission(models.PERMISSION_CONSUMERS_WRITE), base.UploadDocument)
+		rs.GET("/:id/documents/:type", middleware.Auth, middleware.Permission(models.PERMISSION_CONSUMER_DOCUMENTS_READ), base.FindDocument)
 	}
 
 	status := v.Group("/statuses")
@@ -331,8 +339,6 @@ func (h *ConsumerHandler) Create(c *gin.Context) {
 	obj.FullName = c.PostForm("FullName")
 	obj.LegalName = c.PostForm("LegalName")
 	obj.PlaceOfBirth = c.PostForm("PlaceOfBirth")
-	obj.KTPImgURL = c.PostForm("KTPImgURL")
-	obj.SelfieImgURL = c.PostForm("SelfieImgURL")
 
 	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
 		data, err = h.ConsumerUsecase.Create(c, obj)
@@ -437,8 +443,6 @@ func (h *ConsumerHandler) Update(c *gin.Context) {
 			return
 		}
 
-		fmt.Println(">>>", dob)
-
 		obj.DateOfBirth = dob
 	}
 
@@ -460,8 +464,6 @@ func (h *ConsumerHandler) Update(c *gin.Context) {
 	obj.FullName = c.PostForm("FullName")
 	obj.LegalName = c.PostForm("LegalName")
 	obj.PlaceOfBirth = c.PostForm("PlaceOfBirth")
-	obj.KTPImgURL = c.PostForm("KTPImgURL")
-	obj.SelfieImgURL = c.PostForm("SelfieImgURL")
 
 	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
 		data, err = h.ConsumerUsecase.Update(c, id, obj)
@@ -544,3 +546,129 @@ func (h *ConsumerHandler) UpdateStatus(c *gin.Context) {
 
 	c.JSON(http.StatusOK, h.Result)
 }
+
+// UploadDocument takes a multipart upload of the KTP or selfie image in File, Type says which one it is
+func (h *ConsumerHandler) UploadDocument(c *gin.Context) {
+	var err *types.Error
+	var data *models.Consumer
+
+	id := c.Param("id")
+
+	if !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".ConsumerHandler->UploadDocument()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	file, errFile := c.FormFile("File")
+	if errFile != nil {
+		err := &types.Error{
+			Path:       ".ConsumerHandler->UploadDocument()",
+			Message:    "File is required",
+			Error:      errFile,
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	if file.Size > filestorage.MAX_IMAGE_SIZE {
+		err := &types.Error{
+			Path:       ".ConsumerHandler->UploadDocument()",
+			Message:    fmt.Sprintf("File is larger than %d MB", filestorage.MAX_IMAGE_SIZE>>20),
+			Error:      fmt.Errorf("file is %d bytes", file.Size),
+			Type:       "validation-error",
+			StatusCode: http.StatusRequestEntityTooLarge,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	opened, errFile := file.Open()
+	if errFile != nil {
+		err := &types.Error{
+			Path:       ".ConsumerHandler->UploadDocument()",
+			Message:    "File cannot be read",
+			Error:      errFile,
+			Type:       "conversion-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+	defer opened.Close()
+
+	body, errFile := io.ReadAll(io.LimitReader(opened, filestorage.MAX_IMAGE_SIZE+1))
+	if errFile != nil {
+		err := &types.Error{
+			Path:       ".ConsumerHandler->UploadDocument()",
+			Message:    "File cannot be read",
+			Error:      errFile,
+			Type:       "conversion-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	documentType := strings.ToUpper(c.PostForm("Type"))
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.ConsumerUsecase.UploadDocument(c, id, documentType, body)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".ConsumerHandler->UploadDocument()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Document uploaded successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+// FindDocument streams the KTP or selfie image, the files are private so this is the only way to see them
+func (h *ConsumerHandler) FindDocument(c *gin.Context) {
+	id := c.Param("id")
+
+	if !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".ConsumerHandler->FindDocument()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	body, contentType, err := h.ConsumerUsecase.FindDocument(c, id, strings.ToUpper(c.Param("type")))
+	if err != nil {
+		err.Path = ".ConsumerHandler->FindDocument()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, "Consumer not found", http.StatusUnprocessableEntity, *err)
+			return
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, contentType, body)
+}
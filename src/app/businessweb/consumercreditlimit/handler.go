@@ -4,7 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strconv"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
@@ -22,6 +22,8 @@ import (
 
 	consumercreditlimitRepository "case-study-kredit-plus/src/services/consumercreditlimit/repository"
 	consumercreditlimitUsecase "case-study-kredit-plus/src/services/consumercreditlimit/usecase"
+	loanproductRepository "case-study-kredit-plus/src/services/loanproduct/repository"
+	loanproductUsecase "case-study-kredit-plus/src/services/loanproduct/usecase"
 )
 
 var ()
@@ -37,17 +39,26 @@ func (h ConsumerCreditLimitHandler) RegisterAPI(db *sqlx.DB, dataManager *data.M
 	consumercreditlimitRepo := consumercreditlimitRepository.NewConsumerCreditLimitRepository(
 		data.NewMySQLStorage(db, "consumer_credit_limits", models.ConsumerCreditLimit{}, data.MysqlConfig{}),
 		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumer_credit_limit_details", models.ConsumerCreditLimitDetail{}, data.MysqlConfig{}),
 	)
 
-	uConsumerCreditLimit := consumercreditlimitUsecase.NewConsumerCreditLimitUsecase(db, &consumercreditlimitRepo)
+	loanproductRepo := loanproductRepository.NewLoanProductRepository(
+		data.NewMySQLStorage(db, "loan_products", models.LoanProduct{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	uLoanProduct := loanproductUsecase.NewLoanProductUsecase(db, &loanproductRepo)
+	uConsumerCreditLimit := consumercreditlimitUsecase.NewConsumerCreditLimitUsecase(db, &consumercreditlimitRepo, uLoanProduct)
 
 	base := &ConsumerCreditLimitHandler{ConsumerCreditLimitUsecase: uConsumerCreditLimit, dataManager: dataManager}
 
 	rs := v.Group("/consumers/credit-limits")
 	{
-		rs.GET("", middleware.Auth, base.FindAll)
-		rs.GET("/:id", middleware.Auth, base.Find)
-		rs.POST("", middleware.Auth, base.Create)
+		rs.GET("", middleware.Auth, middleware.Permission(models.PERMISSION_CREDIT_LIMITS_READ), base.FindAll)
+		rs.GET("/timeline", middleware.Auth, middleware.Permission(models.PERMISSION_CREDIT_LIMITS_READ), base.FindTimeline)
+		rs.GET("/effective", middleware.Auth, middleware.Permission(models.PERMISSION_CREDIT_LIMITS_READ), base.FindEffective)
+		rs.GET("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_CREDIT_LIMITS_READ), base.Find)
+		rs.POST("", middleware.Auth, middleware.Permission(models.PERMISSION_CREDIT_LIMITS_WRITE), base.Create)
 		// rs.PUT("/:id", middleware.Auth, base.Update)
 
 		// rs.PUT("/status", middleware.Auth, base.UpdateStatus)
@@ -135,14 +146,12 @@ func (h *ConsumerCreditLimitHandler) Find(c *gin.Context) {
 	c.JSON(http.StatusOK, h.Result)
 }
 
-func (h *ConsumerCreditLimitHandler) Create(c *gin.Context) {
-	var err *types.Error
-	var obj models.ConsumerCreditLimit
-	var data *models.ConsumerCreditLimit
+func (h *ConsumerCreditLimitHandler) FindTimeline(c *gin.Context) {
+	consumerID := c.Query("ConsumerID")
 
-	if c.PostForm("ConsumerID") != "" && !library.ValidateUUID(c.PostForm("ConsumerID")) {
+	if !library.ValidateUUID(consumerID) {
 		err := &types.Error{
-			Path:       ".ConsumerCreditLimitHandler->Create()",
+			Path:       ".ConsumerCreditLimitHandler->FindTimeline()",
 			Message:    "Consumer ID is not valid",
 			Error:      fmt.Errorf("Consumer ID is not valid"),
 			Type:       "validation-error",
@@ -152,51 +161,112 @@ func (h *ConsumerCreditLimitHandler) Create(c *gin.Context) {
 		return
 	}
 
-	month1, errParseFloat := strconv.ParseFloat(c.PostForm("Month1"), 64)
-	if errParseFloat != nil {
+	datas, err := h.ConsumerCreditLimitUsecase.FindTimeline(c, consumerID)
+	if err != nil {
+		err.Path = ".ConsumerCreditLimitHandler->FindTimeline()" + err.Path
+		if err.Error != data.ErrNotFound {
+			response.Error(c, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+// FindEffective takes EffectiveOn as a timestamp, or as a date meaning the limit in force at the end of that day
+func (h *ConsumerCreditLimitHandler) FindEffective(c *gin.Context) {
+	consumerID := c.Query("ConsumerID")
+
+	if !library.ValidateUUID(consumerID) {
 		err := &types.Error{
-			Path:       ".ConsumerCreditLimitHandler->Create()",
-			Message:    "1 Month Limit Invalid",
-			Error:      errParseFloat,
-			Type:       "conversion-error",
+			Path:       ".ConsumerCreditLimitHandler->FindEffective()",
+			Message:    "Consumer ID is not valid",
+			Error:      fmt.Errorf("Consumer ID is not valid"),
+			Type:       "validation-error",
 			StatusCode: http.StatusUnprocessableEntity,
 		}
 		response.Error(c, err.Message, err.StatusCode, *err)
 		return
 	}
 
-	month2, errParseFloat := strconv.ParseFloat(c.PostForm("Month2"), 64)
-	if errParseFloat != nil {
+	effectiveOn, errParseTime := time.Parse(library.StrToTimestampFormat, c.Query("EffectiveOn"))
+	if errParseTime != nil {
+		effectiveOn, errParseTime = time.Parse(library.StrToDateFormat, c.Query("EffectiveOn"))
+		if errParseTime != nil {
+			err := &types.Error{
+				Path:       ".ConsumerCreditLimitHandler->FindEffective()",
+				Message:    "Effective On Invalid",
+				Error:      errParseTime,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		effectiveOn = effectiveOn.Add(24*time.Hour - time.Second)
+	}
+
+	result, err := h.ConsumerCreditLimitUsecase.FindEffective(c, consumerID, effectiveOn)
+	if err != nil {
+		err.Path = ".ConsumerCreditLimitHandler->FindEffective()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, err.Message, http.StatusUnprocessableEntity, *err)
+			return
+		}
+		response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *ConsumerCreditLimitHandler) Create(c *gin.Context) {
+	var err *types.Error
+	var obj models.ConsumerCreditLimit
+	var data *models.ConsumerCreditLimit
+
+	if c.PostForm("ConsumerID") != "" && !library.ValidateUUID(c.PostForm("ConsumerID")) {
 		err := &types.Error{
 			Path:       ".ConsumerCreditLimitHandler->Create()",
-			Message:    "2 Month Limit Invalid",
-			Error:      errParseFloat,
-			Type:       "conversion-error",
+			Message:    "Consumer ID is not valid",
+			Error:      fmt.Errorf("Consumer ID is not valid"),
+			Type:       "validation-error",
 			StatusCode: http.StatusUnprocessableEntity,
 		}
 		response.Error(c, err.Message, err.StatusCode, *err)
 		return
 	}
 
-	month3, errParseFloat := strconv.ParseFloat(c.PostForm("Month3"), 64)
-	if errParseFloat != nil {
+	if c.PostForm("Reason") != "" && !library.ValidateTextInput(c.PostForm("Reason")) {
 		err := &types.Error{
 			Path:       ".ConsumerCreditLimitHandler->Create()",
-			Message:    "3 Month Limit Invalid",
-			Error:      errParseFloat,
-			Type:       "conversion-error",
+			Message:    "Reason is not valid",
+			Error:      fmt.Errorf("Reason is not valid"),
+			Type:       "validation-error",
 			StatusCode: http.StatusUnprocessableEntity,
 		}
 		response.Error(c, err.Message, err.StatusCode, *err)
 		return
 	}
 
-	month6, errParseFloat := strconv.ParseFloat(c.PostForm("Month6"), 64)
-	if errParseFloat != nil {
+	var details []*models.ConsumerCreditLimitDetail
+	errJson := json.Unmarshal([]byte(c.PostForm("Details")), &details)
+	if errJson != nil {
 		err := &types.Error{
 			Path:       ".ConsumerCreditLimitHandler->Create()",
-			Message:    "6 Month Limit Invalid",
-			Error:      errParseFloat,
+			Message:    "Details Invalid",
+			Error:      errJson,
 			Type:       "conversion-error",
 			StatusCode: http.StatusUnprocessableEntity,
 		}
@@ -205,10 +275,8 @@ func (h *ConsumerCreditLimitHandler) Create(c *gin.Context) {
 	}
 
 	obj.ConsumerID = c.PostForm("ConsumerID")
-	obj.Month1 = month1
-	obj.Month2 = month2
-	obj.Month3 = month3
-	obj.Month6 = month6
+	obj.Reason = c.PostForm("Reason")
+	obj.Details = details
 
 	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
 		data, err = h.ConsumerCreditLimitUsecase.Create(c, obj)
@@ -263,51 +331,25 @@ func (h *ConsumerCreditLimitHandler) Update(c *gin.Context) {
 		return
 	}
 
-	month1, errParseFloat := strconv.ParseFloat(c.PostForm("Month1"), 64)
-	if errParseFloat != nil {
+	if c.PostForm("Reason") != "" && !library.ValidateTextInput(c.PostForm("Reason")) {
 		err := &types.Error{
 			Path:       ".ConsumerCreditLimitHandler->Update()",
-			Message:    "1 Month Limit Invalid",
-			Error:      errParseFloat,
-			Type:       "conversion-error",
-			StatusCode: http.StatusUnprocessableEntity,
-		}
-		response.Error(c, err.Message, err.StatusCode, *err)
-		return
-	}
-
-	month2, errParseFloat := strconv.ParseFloat(c.PostForm("Month2"), 64)
-	if errParseFloat != nil {
-		err := &types.Error{
-			Path:       ".ConsumerCreditLimitHandler->Update()",
-			Message:    "2 Month Limit Invalid",
-			Error:      errParseFloat,
-			Type:       "conversion-error",
-			StatusCode: http.StatusUnprocessableEntity,
-		}
-		response.Error(c, err.Message, err.StatusCode, *err)
-		return
-	}
-
-	month3, errParseFloat := strconv.ParseFloat(c.PostForm("Month3"), 64)
-	if errParseFloat != nil {
-		err := &types.Error{
-			Path:       ".ConsumerCreditLimitHandler->Update()",
-			Message:    "3 Month Limit Invalid",
-			Error:      errParseFloat,
-			Type:       "conversion-error",
+			Message:    "Reason is not valid",
+			Error:      fmt.Errorf("Reason is not valid"),
+			Type:       "validation-error",
 			StatusCode: http.StatusUnprocessableEntity,
 		}
 		response.Error(c, err.Message, err.StatusCode, *err)
 		return
 	}
 
-	month6, errParseFloat := strconv.ParseFloat(c.PostForm("Month6"), 64)
-	if errParseFloat != nil {
+	var details []*models.ConsumerCreditLimitDetail
+	errJson := json.Unmarshal([]byte(c.PostForm("Details")), &details)
+	if errJson != nil {
 		err := &types.Error{
 			Path:       ".ConsumerCreditLimitHandler->Update()",
-			Message:    "6 Month Limit Invalid",
-			Error:      errParseFloat,
+			Message:    "Details Invalid",
+			Error:      errJson,
 			Type:       "conversion-error",
 			StatusCode: http.StatusUnprocessableEntity,
 		}
@@ -316,10 +358,8 @@ func (h *ConsumerCreditLimitHandler) Update(c *gin.Context) {
 	}
 
 	obj.ConsumerID = c.PostForm("ConsumerID")
-	obj.Month1 = month1
-	obj.Month2 = month2
-	obj.Month3 = month3
-	obj.Month6 = month6
+	obj.Reason = c.PostForm("Reason")
+	obj.Details = details
 
 	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
 		data, err = h.ConsumerCreditLimitUsecase.Update(c, id, obj)
@@ -0,0 +1,133 @@
+package consumerinstallment
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/helpers"
+	"case-study-kredit-plus/middleware"
+	"case-study-kredit-plus/models"
+	"case-study-kredit-plus/src/services/consumerinstallment"
+
+	"github.com/gin-gonic/gin"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/http/response"
+	"case-study-kredit-plus/library/types"
+
+	consumerinstallmentRepository "case-study-kredit-plus/src/services/consumerinstallment/repository"
+	consumerinstallmentUsecase "case-study-kredit-plus/src/services/consumerinstallment/usecase"
+)
+
+var ()
+
+type ConsumerInstallmentHandler struct {
+	ConsumerInstallmentUsecase consumerinstallment.Usecase
+	dataManager                *data.Manager
+	Result                     gin.H
+	Status                     int
+}
+
+func (h ConsumerInstallmentHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, router *gin.Engine, v *gin.RouterGroup) {
+	consumerinstallmentRepo := consumerinstallmentRepository.NewConsumerInstallmentRepository(
+		data.NewMySQLStorage(db, "consumer_installments", models.ConsumerInstallment{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	uConsumerInstallment := consumerinstallmentUsecase.NewConsumerInstallmentUsecase(db, &consumerinstallmentRepo)
+
+	base := &ConsumerInstallmentHandler{ConsumerInstallmentUsecase: uConsumerInstallment, dataManager: dataManager}
+
+	rs := v.Group("/consumers/installments")
+	{
+		rs.GET("", middleware.Auth, middleware.Permission(models.PERMISSION_TRANSACTIONS_READ), base.FindAll)
+		rs.GET("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_TRANSACTIONS_READ), base.Find)
+	}
+}
+
+func (h *ConsumerInstallmentHandler) FindAll(c *gin.Context) {
+	if c.Query("ConsumerTransactionID") != "" && !library.ValidateUUID(c.Query("ConsumerTransactionID")) {
+		err := &types.Error{
+			Path:       ".ConsumerInstallmentHandler->FindAll()",
+			Message:    "Consumer Transaction ID is not valid",
+			Error:      fmt.Errorf("Consumer Transaction ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	var params models.FindAllConsumerInstallmentParams
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	params.FindAllParams = filterFindAllParams
+	params.ConsumerTransactionID = c.Query("ConsumerTransactionID")
+	params.MinDueDate = c.Query("MinDueDate")
+	params.MaxDueDate = c.Query("MaxDueDate")
+
+	// a schedule reads in due order unless asked otherwise
+	if c.Query("SortName") == "" {
+		params.FindAllParams.SortBy = "consumer_installments.consumer_transaction_id ASC, consumer_installments.installment_number ASC"
+	}
+
+	datas, err := h.ConsumerInstallmentUsecase.FindAll(c, params)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	length, err := h.ConsumerInstallmentUsecase.Count(c, params)
+	if err != nil {
+		err.Path = ".ConsumerInstallmentHandler->FindAll()" + err.Path
+		if err.Error != data.ErrNotFound {
+			response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	dataresponse := types.ResultAll{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *ConsumerInstallmentHandler) Find(c *gin.Context) {
+	id := c.Param("id")
+
+	if id != "" && !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".ConsumerInstallmentHandler->Find()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	result, err := h.ConsumerInstallmentUsecase.Find(c, id)
+	if err != nil {
+		err.Path = ".ConsumerInstallmentHandler->Find()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, "ConsumerInstallment not found", http.StatusUnprocessableEntity, *err)
+			return
+		}
+		response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
@@ -0,0 +1,431 @@
+package consumerpayment
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/helpers"
+	"case-study-kredit-plus/middleware"
+	"case-study-kredit-plus/models"
+	"case-study-kredit-plus/src/services/consumerpayment"
+
+	"github.com/gin-gonic/gin"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/http/response"
+	"case-study-kredit-plus/library/types"
+
+	consumerpaymentRepository "case-study-kredit-plus/src/services/consumerpayment/repository"
+	consumerpaymentUsecase "case-study-kredit-plus/src/services/consumerpayment/usecase"
+
+	consumerinstallmentRepository "case-study-kredit-plus/src/services/consumerinstallment/repository"
+	consumerinstallmentUsecase "case-study-kredit-plus/src/services/consumerinstallment/usecase"
+
+	consumertransactionRepository "case-study-kredit-plus/src/services/consumertransaction/repository"
+	consumertransactionUsecase "case-study-kredit-plus/src/services/consumertransaction/usecase"
+
+	consumercreditlimitRepository "case-study-kredit-plus/src/services/consumercreditlimit/repository"
+	consumercreditlimitUsecase "case-study-kredit-plus/src/services/consumercreditlimit/usecase"
+
+	loanproductRepository "case-study-kredit-plus/src/services/loanproduct/repository"
+	loanproductUsecase "case-study-kredit-plus/src/services/loanproduct/usecase"
+	merchantRepository "case-study-kredit-plus/src/services/merchant/repository"
+	merchantUsecase "case-study-kredit-plus/src/services/merchant/usecase"
+)
+
+var ()
+
+type ConsumerPaymentHandler struct {
+	ConsumerPaymentUsecase consumerpayment.Usecase
+	dataManager            *data.Manager
+	Result                 gin.H
+	Status                 int
+}
+
+func (h ConsumerPaymentHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, router *gin.Engine, v *gin.RouterGroup) {
+	consumerpaymentRepo := consumerpaymentRepository.NewConsumerPaymentRepository(
+		data.NewMySQLStorage(db, "consumer_payments", models.ConsumerPayment{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumer_payment_allocations", models.ConsumerPaymentAllocation{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumer_payoff_quotes", models.ConsumerPayoffQuote{}, data.MysqlConfig{}),
+	)
+
+	consumerinstallmentRepo := consumerinstallmentRepository.NewConsumerInstallmentRepository(
+		data.NewMySQLStorage(db, "consumer_installments", models.ConsumerInstallment{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	consumertransactionRepo := consumertransactionRepository.NewConsumerTransactionRepository(
+		data.NewMySQLStorage(db, "consumer_transactions", models.ConsumerTransaction{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumer_transaction_statuses", models.ConsumerTransactionStatus{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumer_transaction_status_histories", models.ConsumerTransactionStatusHistory{}, data.MysqlConfig{}),
+	)
+
+	consumercreditlimitRepo := consumercreditlimitRepository.NewConsumerCreditLimitRepository(
+		data.NewMySQLStorage(db, "consumer_credit_limits", models.ConsumerCreditLimit{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumer_credit_limit_details", models.ConsumerCreditLimitDetail{}, data.MysqlConfig{}),
+	)
+
+	loanproductRepo := loanproductRepository.NewLoanProductRepository(
+		data.NewMySQLStorage(db, "loan_products", models.LoanProduct{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	merchantRepo := merchantRepository.NewMerchantRepository(
+		data.NewMySQLStorage(db, "merchants", models.Merchant{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "merchant_loan_products", models.MerchantLoanProduct{}, data.MysqlConfig{}),
+	)
+
+	uLoanProduct := loanproductUsecase.NewLoanProductUsecase(db, &loanproductRepo)
+	uMerchant := merchantUsecase.NewMerchantUsecase(db, &merchantRepo, uLoanProduct)
+	uConsumerCreditLimit := consumercreditlimitUsecase.NewConsumerCreditLimitUsecase(db, &consumercreditlimitRepo, uLoanProduct)
+	uConsumerInstallment := consumerinstallmentUsecase.NewConsumerInstallmentUsecase(db, &consumerinstallmentRepo)
+	uConsumerTransaction := consumertransactionUsecase.NewConsumerTransactionUsecase(db, &consumertransactionRepo, uConsumerCreditLimit, uConsumerInstallment, uLoanProduct, uMerchant)
+
+	uConsumerPayment := consumerpaymentUsecase.NewConsumerPaymentUsecase(db, &consumerpaymentRepo, uConsumerInstallment, uConsumerTransaction, uLoanProduct)
+
+	base := &ConsumerPaymentHandler{ConsumerPaymentUsecase: uConsumerPayment, dataManager: dataManager}
+
+	rs := v.Group("/consumers/payments")
+	{
+		rs.GET("", middleware.Auth, middleware.Permission(models.PERMISSION_PAYMENTS_READ), base.FindAll)
+		rs.GET("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_PAYMENTS_READ), base.Find)
+		rs.POST("", middleware.Auth, middleware.Permission(models.PERMISSION_PAYMENTS_WRITE), base.Create)
+		rs.POST("/:id/reverse", middleware.Auth, middleware.Permission(models.PERMISSION_PAYMENTS_WRITE), base.Reverse)
+
+		rs.POST("/payoff-quotes", middleware.Auth, middleware.Permission(models.PERMISSION_PAYMENTS_WRITE), base.Quote)
+		rs.POST("/payoff-quotes/:id/settle", middleware.Auth, middleware.Permission(models.PERMISSION_PAYMENTS_WRITE), base.Settle)
+	}
+}
+
+func (h *ConsumerPaymentHandler) FindAll(c *gin.Context) {
+	if c.Query("ConsumerTransactionID") != "" && !library.ValidateUUID(c.Query("ConsumerTransactionID")) {
+		err := &types.Error{
+			Path:       ".ConsumerPaymentHandler->FindAll()",
+			Message:    "Consumer Transaction ID is not valid",
+			Error:      fmt.Errorf("Consumer Transaction ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	var params models.FindAllConsumerPaymentParams
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	params.FindAllParams = filterFindAllParams
+	params.ConsumerTransactionID = c.Query("ConsumerTransactionID")
+	params.PaymentType = c.Query("PaymentType")
+	params.MinPaymentDate = c.Query("MinPaymentDate")
+	params.MaxPaymentDate = c.Query("MaxPaymentDate")
+
+	datas, err := h.ConsumerPaymentUsecase.FindAll(c, params)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	length, err := h.ConsumerPaymentUsecase.Count(c, params)
+	if err != nil {
+		err.Path = ".ConsumerPaymentHandler->FindAll()" + err.Path
+		if err.Error != data.ErrNotFound {
+			response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	dataresponse := types.ResultAll{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *ConsumerPaymentHandler) Find(c *gin.Context) {
+	id := c.Param("id")
+
+	if id != "" && !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".ConsumerPaymentHandler->Find()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	result, err := h.ConsumerPaymentUsecase.Find(c, id)
+	if err != nil {
+		err.Path = ".ConsumerPaymentHandler->Find()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, "ConsumerPayment not found", http.StatusUnprocessableEntity, *err)
+			return
+		}
+		response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *ConsumerPaymentHandler) Create(c *gin.Context) {
+	var err *types.Error
+	var obj models.ConsumerPayment
+	var data *models.ConsumerPayment
+
+	if !library.ValidateUUID(c.PostForm("ConsumerTransactionID")) {
+		err := &types.Error{
+			Path:       ".ConsumerPaymentHandler->Create()",
+			Message:    "Consumer Transaction ID is not valid",
+			Error:      fmt.Errorf("Consumer Transaction ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	if c.PostForm("ReferenceNumber") != "" && !library.ValidateTextInput(c.PostForm("ReferenceNumber")) {
+		err := &types.Error{
+			Path:       ".ConsumerPaymentHandler->Create()",
+			Message:    "Reference Number is not valid",
+			Error:      fmt.Errorf("Reference Number is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	amount, errParseFloat := strconv.ParseFloat(c.PostForm("Amount"), 64)
+	if errParseFloat != nil {
+		err := &types.Error{
+			Path:       ".ConsumerPaymentHandler->Create()",
+			Message:    "Amount Invalid",
+			Error:      errParseFloat,
+			Type:       "conversion-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	if c.PostForm("PaymentDate") != "" {
+		paymentDate, errParseTime := time.Parse(library.StrToDateFormat, c.PostForm("PaymentDate"))
+		if errParseTime != nil {
+			err := &types.Error{
+				Path:       ".ConsumerPaymentHandler->Create()",
+				Message:    "Payment Date Invalid",
+				Error:      errParseTime,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.PaymentDate = paymentDate
+	}
+
+	obj.ConsumerTransactionID = c.PostForm("ConsumerTransactionID")
+	obj.Amount = amount
+	obj.ReferenceNumber = c.PostForm("ReferenceNumber")
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.ConsumerPaymentUsecase.Create(c, obj)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".ConsumerPaymentHandler->Create()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data created successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *ConsumerPaymentHandler) Reverse(c *gin.Context) {
+	var err *types.Error
+	var data *models.ConsumerPayment
+
+	id := c.Param("id")
+
+	if !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".ConsumerPaymentHandler->Reverse()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	if c.PostForm("Reason") != "" && !library.ValidateTextInput(c.PostForm("Reason")) {
+		err := &types.Error{
+			Path:       ".ConsumerPaymentHandler->Reverse()",
+			Message:    "Reason is not valid",
+			Error:      fmt.Errorf("Reason is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.ConsumerPaymentUsecase.Reverse(c, id, c.PostForm("Reason"))
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".ConsumerPaymentHandler->Reverse()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Payment reversed successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *ConsumerPaymentHandler) Quote(c *gin.Context) {
+	var err *types.Error
+	var data *models.ConsumerPayoffQuote
+	var quoteDate time.Time
+
+	if !library.ValidateUUID(c.PostForm("ConsumerTransactionID")) {
+		err := &types.Error{
+			Path:       ".ConsumerPaymentHandler->Quote()",
+			Message:    "Consumer Transaction ID is not valid",
+			Error:      fmt.Errorf("Consumer Transaction ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	if c.PostForm("QuoteDate") != "" {
+		date, errParseTime := time.Parse(library.StrToDateFormat, c.PostForm("QuoteDate"))
+		if errParseTime != nil {
+			err := &types.Error{
+				Path:       ".ConsumerPaymentHandler->Quote()",
+				Message:    "Quote Date Invalid",
+				Error:      errParseTime,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		quoteDate = date
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.ConsumerPaymentUsecase.Quote(c, c.PostForm("ConsumerTransactionID"), quoteDate)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".ConsumerPaymentHandler->Quote()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Payoff quote created successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *ConsumerPaymentHandler) Settle(c *gin.Context) {
+	var err *types.Error
+	var data *models.ConsumerPayment
+
+	id := c.Param("id")
+
+	if !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".ConsumerPaymentHandler->Settle()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	if c.PostForm("ReferenceNumber") != "" && !library.ValidateTextInput(c.PostForm("ReferenceNumber")) {
+		err := &types.Error{
+			Path:       ".ConsumerPaymentHandler->Settle()",
+			Message:    "Reference Number is not valid",
+			Error:      fmt.Errorf("Reference Number is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.ConsumerPaymentUsecase.Settle(c, id, c.PostForm("ReferenceNumber"))
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".ConsumerPaymentHandler->Settle()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Transaction settled successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
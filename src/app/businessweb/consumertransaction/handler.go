@@ -25,6 +25,14 @@ import (
 
 	consumercreditlimitRepository "case-study-kredit-plus/src/services/consumercreditlimit/repository"
 	consumercreditlimitUsecase "case-study-kredit-plus/src/services/consumercreditlimit/usecase"
+
+	consumerinstallmentRepository "case-study-kredit-plus/src/services/consumerinstallment/repository"
+	consumerinstallmentUsecase "case-study-kredit-plus/src/services/consumerinstallment/usecase"
+
+	loanproductRepository "case-study-kredit-plus/src/services/loanproduct/repository"
+	loanproductUsecase "case-study-kredit-plus/src/services/loanproduct/usecase"
+	merchantRepository "case-study-kredit-plus/src/services/merchant/repository"
+	merchantUsecase "case-study-kredit-plus/src/services/merchant/usecase"
 )
 
 var ()
@@ -39,28 +47,51 @@ type ConsumerTransactionHandler struct {
 func (h ConsumerTransactionHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, router *gin.Engine, v *gin.RouterGroup) {
 	consumertransactionRepo := consumertransactionRepository.NewConsumerTransactionRepository(
 		data.NewMySQLStorage(db, "consumer_transactions", models.ConsumerTransaction{}, data.MysqlConfig{}),
-		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumer_transaction_statuses", models.ConsumerTransactionStatus{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumer_transaction_status_histories", models.ConsumerTransactionStatusHistory{}, data.MysqlConfig{}),
 	)
 
 	consumercreditlimitRepo := consumercreditlimitRepository.NewConsumerCreditLimitRepository(
 		data.NewMySQLStorage(db, "consumer_credit_limits", models.ConsumerCreditLimit{}, data.MysqlConfig{}),
 		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumer_credit_limit_details", models.ConsumerCreditLimitDetail{}, data.MysqlConfig{}),
+	)
+
+	consumerinstallmentRepo := consumerinstallmentRepository.NewConsumerInstallmentRepository(
+		data.NewMySQLStorage(db, "consumer_installments", models.ConsumerInstallment{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	loanproductRepo := loanproductRepository.NewLoanProductRepository(
+		data.NewMySQLStorage(db, "loan_products", models.LoanProduct{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
 	)
 
-	uConsumerCreditLimit := consumercreditlimitUsecase.NewConsumerCreditLimitUsecase(db, &consumercreditlimitRepo)
+	merchantRepo := merchantRepository.NewMerchantRepository(
+		data.NewMySQLStorage(db, "merchants", models.Merchant{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "merchant_loan_products", models.MerchantLoanProduct{}, data.MysqlConfig{}),
+	)
 
-	uConsumerTransaction := consumertransactionUsecase.NewConsumerTransactionUsecase(db, &consumertransactionRepo, uConsumerCreditLimit)
+	uLoanProduct := loanproductUsecase.NewLoanProductUsecase(db, &loanproductRepo)
+	uMerchant := merchantUsecase.NewMerchantUsecase(db, &merchantRepo, uLoanProduct)
+	uConsumerCreditLimit := consumercreditlimitUsecase.NewConsumerCreditLimitUsecase(db, &consumercreditlimitRepo, uLoanProduct)
+	uConsumerInstallment := consumerinstallmentUsecase.NewConsumerInstallmentUsecase(db, &consumerinstallmentRepo)
+
+	uConsumerTransaction := consumertransactionUsecase.NewConsumerTransactionUsecase(db, &consumertransactionRepo, uConsumerCreditLimit, uConsumerInstallment, uLoanProduct, uMerchant)
 
 	base := &ConsumerTransactionHandler{ConsumerTransactionUsecase: uConsumerTransaction, dataManager: dataManager}
 
 	rs := v.Group("/consumers/transactions")
 	{
-		rs.GET("", middleware.Auth, base.FindAll)
-		rs.GET("/:id", middleware.Auth, base.Find)
-		rs.POST("", middleware.Auth, base.Create)
-		rs.PUT("/:id", middleware.Auth, base.Update)
+		rs.GET("", middleware.Auth, middleware.Permission(models.PERMISSION_TRANSACTIONS_READ), base.FindAll)
+		rs.GET("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_TRANSACTIONS_READ), base.Find)
+		rs.POST("", middleware.Auth, middleware.Permission(models.PERMISSION_TRANSACTIONS_WRITE), base.Create)
+		rs.PUT("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_TRANSACTIONS_WRITE), base.Update)
+
+		rs.PUT("/status", middleware.Auth, middleware.Permission(models.PERMISSION_TRANSACTIONS_WRITE), base.UpdateStatus)
 
-		rs.PUT("/status", middleware.Auth, base.UpdateStatus)
+		rs.POST("/:id/cancel", middleware.Auth, middleware.Permission(models.PERMISSION_TRANSACTIONS_WRITE), base.Cancel)
 	}
 
 	status := v.Group("/statuses")
@@ -94,13 +125,40 @@ func (h *ConsumerTransactionHandler) FindAll(c *gin.Context) {
 		return
 	}
 
+	if c.Query("LoanProductID") != "" && !library.ValidateUUID(c.Query("LoanProductID")) {
+		err := &types.Error{
+			Path:       ".ConsumerTransactionHandler->FindAll()",
+			Message:    "Loan Product ID is not valid",
+			Error:      fmt.Errorf("Loan Product ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	if c.Query("MerchantID") != "" && !library.ValidateUUID(c.Query("MerchantID")) {
+		err := &types.Error{
+			Path:       ".ConsumerTransactionHandler->FindAll()",
+			Message:    "Merchant ID is not valid",
+			Error:      fmt.Errorf("Merchant ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
 	var params models.FindAllConsumerTransactionParams
 	page, size := helpers.FilterFindAll(c)
 	filterFindAllParams := helpers.FilterFindAllParam(c)
 	params.FindAllParams = filterFindAllParams
 	params.ConsumerID = c.Query("ConsumerID")
 	params.ContractNumber = c.Query("ContractNumber")
+	params.LoanProductID = c.Query("LoanProductID")
 	params.LoanTerm, _ = strconv.Atoi(c.Query("LoanTerm"))
+	params.MinDaysPastDue, _ = strconv.Atoi(c.Query("MinDaysPastDue"))
+	params.MerchantID = c.Query("MerchantID")
 	datas, err := h.ConsumerTransactionUsecase.FindAll(c, params)
 	if err != nil {
 		if err.Error != data.ErrNotFound {
@@ -176,11 +234,11 @@ func (h *ConsumerTransactionHandler) Create(c *gin.Context) {
 		return
 	}
 
-	if c.PostForm("ContractNumber") != "" && !library.ValidateTextInput(c.PostForm("ContractNumber")) {
+	if c.PostForm("LoanProductID") != "" && !library.ValidateUUID(c.PostForm("LoanProductID")) {
 		err := &types.Error{
 			Path:       ".ConsumerTransactionHandler->Create()",
-			Message:    "Contract Number is not valid",
-			Error:      fmt.Errorf("Contract Number is not valid"),
+			Message:    "Loan Product ID is not valid",
+			Error:      fmt.Errorf("Loan Product ID is not valid"),
 			Type:       "validation-error",
 			StatusCode: http.StatusUnprocessableEntity,
 		}
@@ -213,17 +271,21 @@ func (h *ConsumerTransactionHandler) Create(c *gin.Context) {
 		return
 	}
 
-	adminFee, errParseFloat := strconv.ParseFloat(c.PostForm("AdminFee"), 64)
-	if errParseFloat != nil {
-		err := &types.Error{
-			Path:       ".ConsumerTransactionHandler->Create()",
-			Message:    "Admin Fee Invalid",
-			Error:      errParseFloat,
-			Type:       "conversion-error",
-			StatusCode: http.StatusUnprocessableEntity,
+	if c.PostForm("AdminFee") != "" {
+		adminFee, errParseFloat := strconv.ParseFloat(c.PostForm("AdminFee"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".ConsumerTransactionHandler->Create()",
+				Message:    "Admin Fee Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
 		}
-		response.Error(c, err.Message, err.StatusCode, *err)
-		return
+
+		obj.AdminFee = adminFee
 	}
 
 	if c.PostForm("InstallmentAmount") != "" {
@@ -243,30 +305,38 @@ func (h *ConsumerTransactionHandler) Create(c *gin.Context) {
 		obj.InstallmentAmount = installmentAmount
 	}
 
-	loanTerm, errParseInt := strconv.Atoi(c.PostForm("LoanTerm"))
-	if errParseInt != nil {
-		err := &types.Error{
-			Path:       ".ConsumerTransactionHandler->Create()",
-			Message:    "Loan Term Invalid",
-			Error:      errParseInt,
-			Type:       "conversion-error",
-			StatusCode: http.StatusUnprocessableEntity,
+	if c.PostForm("LoanTerm") != "" {
+		loanTerm, errParseInt := strconv.Atoi(c.PostForm("LoanTerm"))
+		if errParseInt != nil {
+			err := &types.Error{
+				Path:       ".ConsumerTransactionHandler->Create()",
+				Message:    "Loan Term Invalid",
+				Error:      errParseInt,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
 		}
-		response.Error(c, err.Message, err.StatusCode, *err)
-		return
+
+		obj.LoanTerm = loanTerm
 	}
 
-	interestAmount, errParseFloat := strconv.ParseFloat(c.PostForm("InterestAmount"), 64)
-	if errParseFloat != nil {
-		err := &types.Error{
-			Path:       ".ConsumerTransactionHandler->Create()",
-			Message:    "Interest Amount Invalid",
-			Error:      errParseFloat,
-			Type:       "conversion-error",
-			StatusCode: http.StatusUnprocessableEntity,
+	if c.PostForm("InterestAmount") != "" {
+		interestAmount, errParseFloat := strconv.ParseFloat(c.PostForm("InterestAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".ConsumerTransactionHandler->Create()",
+				Message:    "Interest Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
 		}
-		response.Error(c, err.Message, err.StatusCode, *err)
-		return
+
+		obj.InterestAmount = interestAmount
 	}
 
 	if c.PostForm("TotalAmount") != "" {
@@ -287,11 +357,8 @@ func (h *ConsumerTransactionHandler) Create(c *gin.Context) {
 	}
 
 	obj.ConsumerID = c.PostForm("ConsumerID")
-	obj.ContractNumber = c.PostForm("ContractNumber")
+	obj.LoanProductID = c.PostForm("LoanProductID")
 	obj.OTR = otr
-	obj.AdminFee = adminFee
-	obj.InterestAmount = interestAmount
-	obj.LoanTerm = loanTerm
 	obj.AssetName = c.PostForm("AssetName")
 
 	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
@@ -335,11 +402,11 @@ func (h *ConsumerTransactionHandler) Update(c *gin.Context) {
 		return
 	}
 
-	if c.PostForm("AssetName") != "" && !library.ValidateTextInput(c.PostForm("AssetName")) {
+	if c.PostForm("LoanProductID") != "" && !library.ValidateUUID(c.PostForm("LoanProductID")) {
 		err := &types.Error{
 			Path:       ".ConsumerTransactionHandler->Update()",
-			Message:    "Asset Name is not valid",
-			Error:      fmt.Errorf("Asset Name is not valid"),
+			Message:    "Loan Product ID is not valid",
+			Error:      fmt.Errorf("Loan Product ID is not valid"),
 			Type:       "validation-error",
 			StatusCode: http.StatusUnprocessableEntity,
 		}
@@ -347,24 +414,23 @@ func (h *ConsumerTransactionHandler) Update(c *gin.Context) {
 		return
 	}
 
-	otr, errParseFloat := strconv.ParseFloat(c.PostForm("OTR"), 64)
-	if errParseFloat != nil {
+	if c.PostForm("AssetName") != "" && !library.ValidateTextInput(c.PostForm("AssetName")) {
 		err := &types.Error{
 			Path:       ".ConsumerTransactionHandler->Update()",
-			Message:    "OTR Invalid",
-			Error:      errParseFloat,
-			Type:       "conversion-error",
+			Message:    "Asset Name is not valid",
+			Error:      fmt.Errorf("Asset Name is not valid"),
+			Type:       "validation-error",
 			StatusCode: http.StatusUnprocessableEntity,
 		}
 		response.Error(c, err.Message, err.StatusCode, *err)
 		return
 	}
 
-	adminFee, errParseFloat := strconv.ParseFloat(c.PostForm("AdminFee"), 64)
+	otr, errParseFloat := strconv.ParseFloat(c.PostForm("OTR"), 64)
 	if errParseFloat != nil {
 		err := &types.Error{
 			Path:       ".ConsumerTransactionHandler->Update()",
-			Message:    "Admin Fee Invalid",
+			Message:    "OTR Invalid",
 			Error:      errParseFloat,
 			Type:       "conversion-error",
 			StatusCode: http.StatusUnprocessableEntity,
@@ -373,66 +439,95 @@ func (h *ConsumerTransactionHandler) Update(c *gin.Context) {
 		return
 	}
 
-	installmentAmount, errParseFloat := strconv.ParseFloat(c.PostForm("InstallmentAmount"), 64)
-	if errParseFloat != nil {
-		err := &types.Error{
-			Path:       ".ConsumerTransactionHandler->Update()",
-			Message:    "Installment Amount Invalid",
-			Error:      errParseFloat,
-			Type:       "conversion-error",
-			StatusCode: http.StatusUnprocessableEntity,
+	if c.PostForm("AdminFee") != "" {
+		adminFee, errParseFloat := strconv.ParseFloat(c.PostForm("AdminFee"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".ConsumerTransactionHandler->Update()",
+				Message:    "Admin Fee Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
 		}
-		response.Error(c, err.Message, err.StatusCode, *err)
-		return
+
+		obj.AdminFee = adminFee
 	}
 
-	loanTerm, errParseInt := strconv.Atoi(c.PostForm("LoanTerm"))
-	if errParseInt != nil {
-		err := &types.Error{
-			Path:       ".ConsumerTransactionHandler->Update()",
-			Message:    "Loan Term Invalid",
-			Error:      errParseInt,
-			Type:       "conversion-error",
-			StatusCode: http.StatusUnprocessableEntity,
+	if c.PostForm("InstallmentAmount") != "" {
+		installmentAmount, errParseFloat := strconv.ParseFloat(c.PostForm("InstallmentAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".ConsumerTransactionHandler->Update()",
+				Message:    "Installment Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
 		}
-		response.Error(c, err.Message, err.StatusCode, *err)
-		return
+
+		obj.InstallmentAmount = installmentAmount
 	}
 
-	interestAmount, errParseFloat := strconv.ParseFloat(c.PostForm("InterestAmount"), 64)
-	if errParseFloat != nil {
-		err := &types.Error{
-			Path:       ".ConsumerTransactionHandler->Update()",
-			Message:    "Interest Amount Invalid",
-			Error:      errParseFloat,
-			Type:       "conversion-error",
-			StatusCode: http.StatusUnprocessableEntity,
+	if c.PostForm("LoanTerm") != "" {
+		loanTerm, errParseInt := strconv.Atoi(c.PostForm("LoanTerm"))
+		if errParseInt != nil {
+			err := &types.Error{
+				Path:       ".ConsumerTransactionHandler->Update()",
+				Message:    "Loan Term Invalid",
+				Error:      errParseInt,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
 		}
-		response.Error(c, err.Message, err.StatusCode, *err)
-		return
+
+		obj.LoanTerm = loanTerm
 	}
 
-	totalAmount, errParseFloat := strconv.ParseFloat(c.PostForm("TotalAmount"), 64)
-	if errParseFloat != nil {
-		err := &types.Error{
-			Path:       ".ConsumerTransactionHandler->Update()",
-			Message:    "Total Amount Invalid",
-			Error:      errParseFloat,
-			Type:       "conversion-error",
-			StatusCode: http.StatusUnprocessableEntity,
+	if c.PostForm("InterestAmount") != "" {
+		interestAmount, errParseFloat := strconv.ParseFloat(c.PostForm("InterestAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".ConsumerTransactionHandler->Update()",
+				Message:    "Interest Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
 		}
-		response.Error(c, err.Message, err.StatusCode, *err)
-		return
+
+		obj.InterestAmount = interestAmount
+	}
+
+	if c.PostForm("TotalAmount") != "" {
+		totalAmount, errParseFloat := strconv.ParseFloat(c.PostForm("TotalAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".ConsumerTransactionHandler->Update()",
+				Message:    "Total Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.TotalAmount = totalAmount
 	}
 
 	// obj.ConsumerID = c.PostForm("ConsumerID")
 	// obj.ContractNumber = c.PostForm("ContractNumber")
+	obj.LoanProductID = c.PostForm("LoanProductID")
 	obj.OTR = otr
-	obj.AdminFee = adminFee
-	obj.InstallmentAmount = installmentAmount
-	obj.LoanTerm = loanTerm
-	obj.InterestAmount = interestAmount
-	obj.TotalAmount = totalAmount
 	obj.AssetName = c.PostForm("AssetName")
 
 	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
@@ -516,3 +611,56 @@ func (h *ConsumerTransactionHandler) UpdateStatus(c *gin.Context) {
 
 	c.JSON(http.StatusOK, h.Result)
 }
+
+func (h *ConsumerTransactionHandler) Cancel(c *gin.Context) {
+	var err *types.Error
+	var data *models.ConsumerTransaction
+
+	id := c.Param("id")
+	reason := c.PostForm("Reason")
+
+	if !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".ConsumerTransactionHandler->Cancel()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	if !library.ValidateTextInput(reason) {
+		err := &types.Error{
+			Path:       ".ConsumerTransactionHandler->Cancel()",
+			Message:    "Reason is not valid",
+			Error:      fmt.Errorf("Reason is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.ConsumerTransactionUsecase.Cancel(c, id, reason)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".ConsumerTransactionHandler->Cancel()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Transaction cancelled successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
@@ -0,0 +1,33 @@
+package jwks
+
+import (
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+
+	"case-study-kredit-plus/library/jwtkeys"
+
+	"github.com/gin-gonic/gin"
+
+	"case-study-kredit-plus/library/data"
+)
+
+type JWKSHandler struct {
+	KeySet      *jwtkeys.KeySet
+	dataManager *data.Manager
+}
+
+// RegisterAPI serves the public keys at the well-known path on the root of the router, where JWT libraries look
+// for them, so other services can verify our tokens without sharing a secret
+func (h JWKSHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, router *gin.Engine, v *gin.RouterGroup) {
+	// loading the keys here keeps the server from starting without them
+	base := &JWKSHandler{KeySet: jwtkeys.FromConfiguration(), dataManager: dataManager}
+
+	router.GET("/.well-known/jwks.json", base.FindAll)
+}
+
+func (h *JWKSHandler) FindAll(c *gin.Context) {
+	// answered in the JWK Set format rather than the usual result, verifiers expect it as is
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.KeySet.JWKS())
+}
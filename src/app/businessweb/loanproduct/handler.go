@@ -0,0 +1,791 @@
+package loanproduct
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/helpers"
+	"case-study-kredit-plus/middleware"
+	"case-study-kredit-plus/models"
+	"case-study-kredit-plus/src/services/loanproduct"
+
+	"github.com/gin-gonic/gin"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/http/response"
+	"case-study-kredit-plus/library/types"
+
+	loanproductRepository "case-study-kredit-plus/src/services/loanproduct/repository"
+	loanproductUsecase "case-study-kredit-plus/src/services/loanproduct/usecase"
+)
+
+var ()
+
+type LoanProductHandler struct {
+	LoanProductUsecase loanproduct.Usecase
+	dataManager        *data.Manager
+	Result             gin.H
+	Status             int
+}
+
+func (h LoanProductHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, router *gin.Engine, v *gin.RouterGroup) {
+	loanproductRepo := loanproductRepository.NewLoanProductRepository(
+		data.NewMySQLStorage(db, "loan_products", models.LoanProduct{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	uLoanProduct := loanproductUsecase.NewLoanProductUsecase(db, &loanproductRepo)
+
+	base := &LoanProductHandler{LoanProductUsecase: uLoanProduct, dataManager: dataManager}
+
+	rs := v.Group("/loan-products")
+	{
+		rs.GET("", middleware.Auth, middleware.Permission(models.PERMISSION_LOAN_PRODUCTS_READ), base.FindAll)
+		rs.GET("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_LOAN_PRODUCTS_READ), base.Find)
+		rs.POST("", middleware.Auth, middleware.Permission(models.PERMISSION_LOAN_PRODUCTS_WRITE), base.Create)
+		rs.PUT("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_LOAN_PRODUCTS_WRITE), base.Update)
+
+		rs.PUT("/status", middleware.Auth, middleware.Permission(models.PERMISSION_LOAN_PRODUCTS_WRITE), base.UpdateStatus)
+	}
+
+	status := v.Group("/statuses")
+	{
+		status.GET("/loan-products", middleware.AuthCheckIP, base.FindStatus)
+	}
+}
+
+func (h *LoanProductHandler) FindAll(c *gin.Context) {
+	var params models.FindAllLoanProductParams
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	params.FindAllParams = filterFindAllParams
+
+	if c.Query("Tenor") != "" {
+		tenor, errParseInt := strconv.Atoi(c.Query("Tenor"))
+		if errParseInt != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->FindAll()",
+				Message:    "Tenor Invalid",
+				Error:      errParseInt,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		params.Tenor = tenor
+	}
+
+	if c.Query("ActiveOn") != "" {
+		_, errParseTime := time.Parse(library.StrToDateFormat, c.Query("ActiveOn"))
+		if errParseTime != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->FindAll()",
+				Message:    "Active On Invalid",
+				Error:      errParseTime,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		params.ActiveOn = c.Query("ActiveOn")
+	}
+
+	datas, err := h.LoanProductUsecase.FindAll(c, params)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	length, err := h.LoanProductUsecase.Count(c, params)
+	if err != nil {
+		err.Path = ".LoanProductHandler->FindAll()" + err.Path
+		if err.Error != data.ErrNotFound {
+			response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	dataresponse := types.ResultAll{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *LoanProductHandler) Find(c *gin.Context) {
+	id := c.Param("id")
+
+	if id != "" && !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".LoanProductHandler->Find()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	result, err := h.LoanProductUsecase.Find(c, id)
+	if err != nil {
+		err.Path = ".LoanProductHandler->Find()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, "LoanProduct not found", http.StatusUnprocessableEntity, *err)
+			return
+		}
+		response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *LoanProductHandler) Create(c *gin.Context) {
+	var err *types.Error
+	var obj models.LoanProduct
+	var data *models.LoanProduct
+
+	if !library.ValidateTextInput(c.PostForm("Name")) {
+		err := &types.Error{
+			Path:       ".LoanProductHandler->Create()",
+			Message:    "Name is not valid",
+			Error:      fmt.Errorf("Name is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	tenor, errParseInt := strconv.Atoi(c.PostForm("Tenor"))
+	if errParseInt != nil {
+		err := &types.Error{
+			Path:       ".LoanProductHandler->Create()",
+			Message:    "Tenor Invalid",
+			Error:      errParseInt,
+			Type:       "conversion-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	interestRate, errParseFloat := strconv.ParseFloat(c.PostForm("InterestRate"), 64)
+	if errParseFloat != nil {
+		err := &types.Error{
+			Path:       ".LoanProductHandler->Create()",
+			Message:    "Interest Rate Invalid",
+			Error:      errParseFloat,
+			Type:       "conversion-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	if c.PostForm("AdminFeeAmount") != "" {
+		adminFeeAmount, errParseFloat := strconv.ParseFloat(c.PostForm("AdminFeeAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Create()",
+				Message:    "Admin Fee Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.AdminFeeAmount = adminFeeAmount
+	}
+
+	if c.PostForm("AdminFeeRate") != "" {
+		adminFeeRate, errParseFloat := strconv.ParseFloat(c.PostForm("AdminFeeRate"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Create()",
+				Message:    "Admin Fee Rate Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.AdminFeeRate = adminFeeRate
+	}
+
+	if c.PostForm("MinAmount") != "" {
+		minAmount, errParseFloat := strconv.ParseFloat(c.PostForm("MinAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Create()",
+				Message:    "Min Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.MinAmount = minAmount
+	}
+
+	if c.PostForm("MaxAmount") != "" {
+		maxAmount, errParseFloat := strconv.ParseFloat(c.PostForm("MaxAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Create()",
+				Message:    "Max Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.MaxAmount = maxAmount
+	}
+
+	if c.PostForm("ActiveFrom") != "" {
+		activeFrom, errParseTime := time.Parse(library.StrToDateFormat, c.PostForm("ActiveFrom"))
+		if errParseTime != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Create()",
+				Message:    "Active From Invalid",
+				Error:      errParseTime,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.ActiveFrom = &activeFrom
+	}
+
+	if c.PostForm("ActiveTo") != "" {
+		activeTo, errParseTime := time.Parse(library.StrToDateFormat, c.PostForm("ActiveTo"))
+		if errParseTime != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Create()",
+				Message:    "Active To Invalid",
+				Error:      errParseTime,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.ActiveTo = &activeTo
+	}
+
+	if c.PostForm("CancellationWindowDays") != "" {
+		cancellationWindowDays, errParseInt := strconv.Atoi(c.PostForm("CancellationWindowDays"))
+		if errParseInt != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Create()",
+				Message:    "Cancellation Window Days Invalid",
+				Error:      errParseInt,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.CancellationWindowDays = cancellationWindowDays
+	}
+
+	if c.PostForm("EarlySettlementPenaltyAmount") != "" {
+		earlySettlementPenaltyAmount, errParseFloat := strconv.ParseFloat(c.PostForm("EarlySettlementPenaltyAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Create()",
+				Message:    "Early Settlement Penalty Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.EarlySettlementPenaltyAmount = earlySettlementPenaltyAmount
+	}
+
+	if c.PostForm("EarlySettlementPenaltyRate") != "" {
+		earlySettlementPenaltyRate, errParseFloat := strconv.ParseFloat(c.PostForm("EarlySettlementPenaltyRate"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Create()",
+				Message:    "Early Settlement Penalty Rate Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.EarlySettlementPenaltyRate = earlySettlementPenaltyRate
+	}
+
+	if c.PostForm("LateFeeDailyRate") != "" {
+		lateFeeDailyRate, errParseFloat := strconv.ParseFloat(c.PostForm("LateFeeDailyRate"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Create()",
+				Message:    "Late Fee Daily Rate Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.LateFeeDailyRate = lateFeeDailyRate
+	}
+
+	if c.PostForm("LateFeeCapAmount") != "" {
+		lateFeeCapAmount, errParseFloat := strconv.ParseFloat(c.PostForm("LateFeeCapAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Create()",
+				Message:    "Late Fee Cap Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.LateFeeCapAmount = lateFeeCapAmount
+	}
+
+	if c.PostForm("LateFeeGraceDays") != "" {
+		lateFeeGraceDays, errParseInt := strconv.Atoi(c.PostForm("LateFeeGraceDays"))
+		if errParseInt != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Create()",
+				Message:    "Late Fee Grace Days Invalid",
+				Error:      errParseInt,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.LateFeeGraceDays = lateFeeGraceDays
+	}
+
+	obj.Name = c.PostForm("Name")
+	obj.Code = c.PostForm("Code")
+	obj.Tenor = tenor
+	obj.InterestMethod = c.PostForm("InterestMethod")
+	obj.InterestRate = interestRate
+
+	if obj.InterestMethod == "" {
+		obj.InterestMethod = library.INTEREST_METHOD_FLAT
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.LoanProductUsecase.Create(c, obj)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".LoanProductHandler->Create()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data created successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *LoanProductHandler) Update(c *gin.Context) {
+	var err *types.Error
+	var obj models.LoanProduct
+	var data *models.LoanProduct
+
+	id := c.Param("id")
+
+	if id != "" && !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".LoanProductHandler->Update()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	if !library.ValidateTextInput(c.PostForm("Name")) {
+		err := &types.Error{
+			Path:       ".LoanProductHandler->Update()",
+			Message:    "Name is not valid",
+			Error:      fmt.Errorf("Name is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	tenor, errParseInt := strconv.Atoi(c.PostForm("Tenor"))
+	if errParseInt != nil {
+		err := &types.Error{
+			Path:       ".LoanProductHandler->Update()",
+			Message:    "Tenor Invalid",
+			Error:      errParseInt,
+			Type:       "conversion-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	interestRate, errParseFloat := strconv.ParseFloat(c.PostForm("InterestRate"), 64)
+	if errParseFloat != nil {
+		err := &types.Error{
+			Path:       ".LoanProductHandler->Update()",
+			Message:    "Interest Rate Invalid",
+			Error:      errParseFloat,
+			Type:       "conversion-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	if c.PostForm("AdminFeeAmount") != "" {
+		adminFeeAmount, errParseFloat := strconv.ParseFloat(c.PostForm("AdminFeeAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Update()",
+				Message:    "Admin Fee Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.AdminFeeAmount = adminFeeAmount
+	}
+
+	if c.PostForm("AdminFeeRate") != "" {
+		adminFeeRate, errParseFloat := strconv.ParseFloat(c.PostForm("AdminFeeRate"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Update()",
+				Message:    "Admin Fee Rate Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.AdminFeeRate = adminFeeRate
+	}
+
+	if c.PostForm("MinAmount") != "" {
+		minAmount, errParseFloat := strconv.ParseFloat(c.PostForm("MinAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Update()",
+				Message:    "Min Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.MinAmount = minAmount
+	}
+
+	if c.PostForm("MaxAmount") != "" {
+		maxAmount, errParseFloat := strconv.ParseFloat(c.PostForm("MaxAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Update()",
+				Message:    "Max Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.MaxAmount = maxAmount
+	}
+
+	if c.PostForm("ActiveFrom") != "" {
+		activeFrom, errParseTime := time.Parse(library.StrToDateFormat, c.PostForm("ActiveFrom"))
+		if errParseTime != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Update()",
+				Message:    "Active From Invalid",
+				Error:      errParseTime,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.ActiveFrom = &activeFrom
+	}
+
+	if c.PostForm("ActiveTo") != "" {
+		activeTo, errParseTime := time.Parse(library.StrToDateFormat, c.PostForm("ActiveTo"))
+		if errParseTime != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Update()",
+				Message:    "Active To Invalid",
+				Error:      errParseTime,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.ActiveTo = &activeTo
+	}
+
+	if c.PostForm("CancellationWindowDays") != "" {
+		cancellationWindowDays, errParseInt := strconv.Atoi(c.PostForm("CancellationWindowDays"))
+		if errParseInt != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Update()",
+				Message:    "Cancellation Window Days Invalid",
+				Error:      errParseInt,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.CancellationWindowDays = cancellationWindowDays
+	}
+
+	if c.PostForm("EarlySettlementPenaltyAmount") != "" {
+		earlySettlementPenaltyAmount, errParseFloat := strconv.ParseFloat(c.PostForm("EarlySettlementPenaltyAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Update()",
+				Message:    "Early Settlement Penalty Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.EarlySettlementPenaltyAmount = earlySettlementPenaltyAmount
+	}
+
+	if c.PostForm("EarlySettlementPenaltyRate") != "" {
+		earlySettlementPenaltyRate, errParseFloat := strconv.ParseFloat(c.PostForm("EarlySettlementPenaltyRate"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Update()",
+				Message:    "Early Settlement Penalty Rate Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.EarlySettlementPenaltyRate = earlySettlementPenaltyRate
+	}
+
+	if c.PostForm("LateFeeDailyRate") != "" {
+		lateFeeDailyRate, errParseFloat := strconv.ParseFloat(c.PostForm("LateFeeDailyRate"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Update()",
+				Message:    "Late Fee Daily Rate Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.LateFeeDailyRate = lateFeeDailyRate
+	}
+
+	if c.PostForm("LateFeeCapAmount") != "" {
+		lateFeeCapAmount, errParseFloat := strconv.ParseFloat(c.PostForm("LateFeeCapAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Update()",
+				Message:    "Late Fee Cap Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.LateFeeCapAmount = lateFeeCapAmount
+	}
+
+	if c.PostForm("LateFeeGraceDays") != "" {
+		lateFeeGraceDays, errParseInt := strconv.Atoi(c.PostForm("LateFeeGraceDays"))
+		if errParseInt != nil {
+			err := &types.Error{
+				Path:       ".LoanProductHandler->Update()",
+				Message:    "Late Fee Grace Days Invalid",
+				Error:      errParseInt,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.LateFeeGraceDays = lateFeeGraceDays
+	}
+
+	obj.Name = c.PostForm("Name")
+	obj.Code = c.PostForm("Code")
+	obj.Tenor = tenor
+	obj.InterestMethod = c.PostForm("InterestMethod")
+	obj.InterestRate = interestRate
+
+	if obj.InterestMethod == "" {
+		obj.InterestMethod = library.INTEREST_METHOD_FLAT
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.LoanProductUsecase.Update(c, id, obj)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".LoanProductHandler->Update()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "LoanProduct successfuly updated", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *LoanProductHandler) FindStatus(c *gin.Context) {
+	datas, err := h.LoanProductUsecase.FindStatus(c)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data successfuly shown", Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *LoanProductHandler) UpdateStatus(c *gin.Context) {
+	var err *types.Error
+	var data *models.LoanProduct
+
+	var ids []*models.IDNameTemplate
+
+	newStatusID := c.PostForm("NewStatusID")
+
+	errJson := json.Unmarshal([]byte(c.PostForm("ID")), &ids)
+	if errJson != nil {
+		err = &types.Error{
+			Path:  ".LoanProductHandler->UpdateStatus()",
+			Error: errJson,
+			Type:  "convert-error",
+		}
+		response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		for _, id := range ids {
+			data, err = h.LoanProductUsecase.UpdateStatus(c, id.ID, newStatusID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".LoanProductHandler->UpdateStatus()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Status update success", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
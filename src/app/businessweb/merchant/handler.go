@@ -0,0 +1,474 @@
+package merchant
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/helpers"
+	"case-study-kredit-plus/middleware"
+	"case-study-kredit-plus/models"
+	"case-study-kredit-plus/src/services/merchant"
+
+	"github.com/gin-gonic/gin"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/http/response"
+	"case-study-kredit-plus/library/types"
+
+	loanproductRepository "case-study-kredit-plus/src/services/loanproduct/repository"
+	loanproductUsecase "case-study-kredit-plus/src/services/loanproduct/usecase"
+	merchantRepository "case-study-kredit-plus/src/services/merchant/repository"
+	merchantUsecase "case-study-kredit-plus/src/services/merchant/usecase"
+)
+
+var ()
+
+type MerchantHandler struct {
+	MerchantUsecase merchant.Usecase
+	dataManager     *data.Manager
+	Result          gin.H
+	Status          int
+}
+
+func (h MerchantHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, router *gin.Engine, v *gin.RouterGroup) {
+	merchantRepo := merchantRepository.NewMerchantRepository(
+		data.NewMySQLStorage(db, "merchants", models.Merchant{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "merchant_loan_products", models.MerchantLoanProduct{}, data.MysqlConfig{}),
+	)
+
+	loanproductRepo := loanproductRepository.NewLoanProductRepository(
+		data.NewMySQLStorage(db, "loan_products", models.LoanProduct{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	uLoanProduct := loanproductUsecase.NewLoanProductUsecase(db, &loanproductRepo)
+	uMerchant := merchantUsecase.NewMerchantUsecase(db, &merchantRepo, uLoanProduct)
+
+	base := &MerchantHandler{MerchantUsecase: uMerchant, dataManager: dataManager}
+
+	rs := v.Group("/merchants")
+	{
+		rs.GET("", middleware.Auth, middleware.Permission(models.PERMISSION_MERCHANTS_READ), base.FindAll)
+		rs.GET("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_MERCHANTS_READ), base.Find)
+		rs.POST("", middleware.Auth, middleware.Permission(models.PERMISSION_MERCHANTS_WRITE), base.Create)
+		rs.PUT("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_MERCHANTS_WRITE), base.Update)
+
+		rs.PUT("/status", middleware.Auth, middleware.Permission(models.PERMISSION_MERCHANTS_WRITE), base.UpdateStatus)
+
+		rs.POST("/:id/api-clients", middleware.Auth, middleware.Permission(models.PERMISSION_MERCHANTS_WRITE), base.LinkAPIClient)
+		rs.DELETE("/:id/api-clients/:apiClientID", middleware.Auth, middleware.Permission(models.PERMISSION_MERCHANTS_WRITE), base.UnlinkAPIClient)
+	}
+
+	status := v.Group("/statuses")
+	{
+		status.GET("/merchants", middleware.AuthCheckIP, base.FindStatus)
+	}
+}
+
+func (h *MerchantHandler) FindAll(c *gin.Context) {
+	var params models.FindAllMerchantParams
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	params.FindAllParams = filterFindAllParams
+
+	if c.Query("Name") != "" && !library.ValidateTextInput(c.Query("Name")) {
+		err := &types.Error{
+			Path:       ".MerchantHandler->FindAll()",
+			Message:    "Name is not valid",
+			Error:      fmt.Errorf("Name is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	params.Name = c.Query("Name")
+
+	datas, err := h.MerchantUsecase.FindAll(c, params)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	length, err := h.MerchantUsecase.Count(c, params)
+	if err != nil {
+		err.Path = ".MerchantHandler->FindAll()" + err.Path
+		if err.Error != data.ErrNotFound {
+			response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	dataresponse := types.ResultAll{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *MerchantHandler) Find(c *gin.Context) {
+	id := c.Param("id")
+
+	if !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".MerchantHandler->Find()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	result, err := h.MerchantUsecase.Find(c, id)
+	if err != nil {
+		err.Path = ".MerchantHandler->Find()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, "Merchant not found", http.StatusUnprocessableEntity, *err)
+			return
+		}
+		response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *MerchantHandler) Create(c *gin.Context) {
+	var err *types.Error
+	var data *models.Merchant
+
+	obj, err := h.bindMerchant(c)
+	if err != nil {
+		err.Path = ".MerchantHandler->Create()" + err.Path
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.MerchantUsecase.Create(c, obj)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".MerchantHandler->Create()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data created successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *MerchantHandler) Update(c *gin.Context) {
+	var err *types.Error
+	var data *models.Merchant
+
+	id := c.Param("id")
+
+	if !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".MerchantHandler->Update()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	obj, err := h.bindMerchant(c)
+	if err != nil {
+		err.Path = ".MerchantHandler->Update()" + err.Path
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.MerchantUsecase.Update(c, id, obj)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".MerchantHandler->Update()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data updated successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *MerchantHandler) FindStatus(c *gin.Context) {
+	datas, err := h.MerchantUsecase.FindStatus(c)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data successfuly shown", Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *MerchantHandler) UpdateStatus(c *gin.Context) {
+	var err *types.Error
+	var data *models.Merchant
+
+	var ids []*models.IDNameTemplate
+
+	newStatusID := c.PostForm("NewStatusID")
+
+	errJson := json.Unmarshal([]byte(c.PostForm("ID")), &ids)
+	if errJson != nil {
+		err = &types.Error{
+			Path:  ".MerchantHandler->UpdateStatus()",
+			Error: errJson,
+			Type:  "convert-error",
+		}
+		response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		for _, id := range ids {
+			data, err = h.MerchantUsecase.UpdateStatus(c, id.ID, newStatusID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".MerchantHandler->UpdateStatus()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Status update success", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *MerchantHandler) LinkAPIClient(c *gin.Context) {
+	var err *types.Error
+	var data *models.Merchant
+
+	id := c.Param("id")
+
+	if !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".MerchantHandler->LinkAPIClient()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	apiClientID, errParseInt := strconv.Atoi(c.PostForm("APIClientID"))
+	if errParseInt != nil {
+		err := &types.Error{
+			Path:       ".MerchantHandler->LinkAPIClient()",
+			Message:    "API Client ID Invalid",
+			Error:      errParseInt,
+			Type:       "conversion-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.MerchantUsecase.LinkAPIClient(c, id, apiClientID)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".MerchantHandler->LinkAPIClient()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data updated successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *MerchantHandler) UnlinkAPIClient(c *gin.Context) {
+	var err *types.Error
+	var data *models.Merchant
+
+	id := c.Param("id")
+
+	if !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".MerchantHandler->UnlinkAPIClient()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	apiClientID, errParseInt := strconv.Atoi(c.Param("apiClientID"))
+	if errParseInt != nil {
+		err := &types.Error{
+			Path:       ".MerchantHandler->UnlinkAPIClient()",
+			Message:    "API Client ID Invalid",
+			Error:      errParseInt,
+			Type:       "conversion-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.MerchantUsecase.UnlinkAPIClient(c, id, apiClientID)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".MerchantHandler->UnlinkAPIClient()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data updated successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+// bindMerchant reads the merchant form shared by Create and Update
+func (h *MerchantHandler) bindMerchant(c *gin.Context) (models.Merchant, *types.Error) {
+	var obj models.Merchant
+
+	for _, field := range []string{"Name", "SettlementBankName", "SettlementAccountName"} {
+		if !library.ValidateTextInput(c.PostForm(field)) {
+			return obj, &types.Error{
+				Path:       ".MerchantHandler->bindMerchant()",
+				Message:    fmt.Sprintf("%s is not valid", field),
+				Error:      fmt.Errorf("%s is not valid", field),
+				Type:       "validation-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+		}
+	}
+
+	if c.PostForm("Address") != "" && !library.ValidateTextInput(c.PostForm("Address")) {
+		return obj, &types.Error{
+			Path:       ".MerchantHandler->bindMerchant()",
+			Message:    "Address is not valid",
+			Error:      fmt.Errorf("Address is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	if c.PostForm("FeeShareRate") != "" {
+		feeShareRate, errParseFloat := strconv.ParseFloat(c.PostForm("FeeShareRate"), 64)
+		if errParseFloat != nil {
+			return obj, &types.Error{
+				Path:       ".MerchantHandler->bindMerchant()",
+				Message:    "Fee Share Rate Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+		}
+
+		obj.FeeShareRate = feeShareRate
+	}
+
+	var loanProducts []*models.MerchantLoanProduct
+	if c.PostForm("LoanProducts") != "" {
+		errJson := json.Unmarshal([]byte(c.PostForm("LoanProducts")), &loanProducts)
+		if errJson != nil {
+			return obj, &types.Error{
+				Path:       ".MerchantHandler->bindMerchant()",
+				Message:    "Loan Products Invalid",
+				Error:      errJson,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+		}
+	}
+
+	for _, v := range loanProducts {
+		if !library.ValidateUUID(v.LoanProductID) {
+			return obj, &types.Error{
+				Path:       ".MerchantHandler->bindMerchant()",
+				Message:    "Loan Product ID is not valid",
+				Error:      fmt.Errorf("Loan Product ID is not valid"),
+				Type:       "validation-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+		}
+	}
+
+	obj.Name = c.PostForm("Name")
+	obj.Email = c.PostForm("Email")
+	obj.PhoneNumber = c.PostForm("PhoneNumber")
+	obj.Address = c.PostForm("Address")
+	obj.SettlementBankName = c.PostForm("SettlementBankName")
+	obj.SettlementAccountNumber = c.PostForm("SettlementAccountNumber")
+	obj.SettlementAccountName = c.PostForm("SettlementAccountName")
+	obj.LoanProducts = loanProducts
+
+	return obj, nil
+}
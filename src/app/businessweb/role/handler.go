@@ -0,0 +1,343 @@
+package role
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/helpers"
+	"case-study-kredit-plus/middleware"
+	"case-study-kredit-plus/models"
+	"case-study-kredit-plus/src/services/role"
+
+	"github.com/gin-gonic/gin"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/http/response"
+	"case-study-kredit-plus/library/types"
+
+	roleRepository "case-study-kredit-plus/src/services/role/repository"
+	roleUsecase "case-study-kredit-plus/src/services/role/usecase"
+)
+
+var ()
+
+type RoleHandler struct {
+	RoleUsecase role.Usecase
+	dataManager *data.Manager
+	Result      gin.H
+	Status      int
+}
+
+func (h RoleHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, router *gin.Engine, v *gin.RouterGroup) {
+	roleRepo := roleRepository.NewRoleRepository(
+		data.NewMySQLStorage(db, "roles", models.Role{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "role_permissions", models.RolePermission{}, data.MysqlConfig{}),
+	)
+
+	uRole := roleUsecase.NewRoleUsecase(db, &roleRepo)
+
+	base := &RoleHandler{RoleUsecase: uRole, dataManager: dataManager}
+
+	rs := v.Group("/roles")
+	{
+		rs.GET("", middleware.Auth, middleware.Permission(models.PERMISSION_ROLES_READ), base.FindAll)
+		rs.GET("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_ROLES_READ), base.Find)
+		rs.POST("", middleware.Auth, middleware.Permission(models.PERMISSION_ROLES_WRITE), base.Create)
+		rs.PUT("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_ROLES_WRITE), base.Update)
+
+		rs.PUT("/status", middleware.Auth, middleware.Permission(models.PERMISSION_ROLES_WRITE), base.UpdateStatus)
+	}
+
+	permissions := v.Group("/permissions")
+	{
+		permissions.GET("", middleware.Auth, middleware.Permission(models.PERMISSION_ROLES_READ), base.FindPermissions)
+	}
+
+	status := v.Group("/statuses")
+	{
+		status.GET("/roles", middleware.AuthCheckIP, base.FindStatus)
+	}
+}
+
+func (h *RoleHandler) FindAll(c *gin.Context) {
+	var params models.FindAllRoleParams
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	params.FindAllParams = filterFindAllParams
+
+	if c.Query("Name") != "" && !library.ValidateTextInput(c.Query("Name")) {
+		err := &types.Error{
+			Path:       ".RoleHandler->FindAll()",
+			Message:    "Name is not valid",
+			Error:      fmt.Errorf("Name is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	params.Name = c.Query("Name")
+
+	datas, err := h.RoleUsecase.FindAll(c, params)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	length, err := h.RoleUsecase.Count(c, params)
+	if err != nil {
+		err.Path = ".RoleHandler->FindAll()" + err.Path
+		if err.Error != data.ErrNotFound {
+			response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	dataresponse := types.ResultAll{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *RoleHandler) Find(c *gin.Context) {
+	id := c.Param("id")
+
+	if !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".RoleHandler->Find()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	result, err := h.RoleUsecase.Find(c, id)
+	if err != nil {
+		err.Path = ".RoleHandler->Find()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, "Role not found", http.StatusUnprocessableEntity, *err)
+			return
+		}
+		response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *RoleHandler) Create(c *gin.Context) {
+	var err *types.Error
+	var data *models.Role
+
+	obj, err := h.bindRole(c)
+	if err != nil {
+		err.Path = ".RoleHandler->Create()" + err.Path
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.RoleUsecase.Create(c, obj)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".RoleHandler->Create()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data created successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *RoleHandler) Update(c *gin.Context) {
+	var err *types.Error
+	var data *models.Role
+
+	id := c.Param("id")
+
+	if !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".RoleHandler->Update()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	obj, err := h.bindRole(c)
+	if err != nil {
+		err.Path = ".RoleHandler->Update()" + err.Path
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.RoleUsecase.Update(c, id, obj)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".RoleHandler->Update()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data updated successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *RoleHandler) FindStatus(c *gin.Context) {
+	datas, err := h.RoleUsecase.FindStatus(c)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data successfuly shown", Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *RoleHandler) UpdateStatus(c *gin.Context) {
+	var err *types.Error
+	var data *models.Role
+
+	var ids []*models.IDNameTemplate
+
+	newStatusID := c.PostForm("NewStatusID")
+
+	errJson := json.Unmarshal([]byte(c.PostForm("ID")), &ids)
+	if errJson != nil {
+		err = &types.Error{
+			Path:  ".RoleHandler->UpdateStatus()",
+			Error: errJson,
+			Type:  "convert-error",
+		}
+		response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		for _, id := range ids {
+			data, err = h.RoleUsecase.UpdateStatus(c, id.ID, newStatusID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".RoleHandler->UpdateStatus()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Status update success", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *RoleHandler) FindPermissions(c *gin.Context) {
+	datas, err := h.RoleUsecase.FindPermissions(c)
+	if err != nil {
+		err.Path = ".RoleHandler->FindPermissions()" + err.Path
+		response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+// bindRole reads the role form shared by Create and Update
+func (h *RoleHandler) bindRole(c *gin.Context) (models.Role, *types.Error) {
+	var obj models.Role
+
+	if !library.ValidateTextInput(c.PostForm("Name")) {
+		return obj, &types.Error{
+			Path:       ".RoleHandler->bindRole()",
+			Message:    "Name is not valid",
+			Error:      fmt.Errorf("Name is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	if c.PostForm("Description") != "" && !library.ValidateTextInput(c.PostForm("Description")) {
+		return obj, &types.Error{
+			Path:       ".RoleHandler->bindRole()",
+			Message:    "Description is not valid",
+			Error:      fmt.Errorf("Description is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	var permissions []*models.RolePermission
+	if c.PostForm("Permissions") != "" {
+		errJson := json.Unmarshal([]byte(c.PostForm("Permissions")), &permissions)
+		if errJson != nil {
+			return obj, &types.Error{
+				Path:       ".RoleHandler->bindRole()",
+				Message:    "Permissions Invalid",
+				Error:      errJson,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+		}
+	}
+
+	obj.Name = c.PostForm("Name")
+	obj.Description = c.PostForm("Description")
+	obj.Permissions = permissions
+
+	return obj, nil
+}
@@ -1,9 +1,18 @@
 package businessweb
 
 import (
+	http_apiclient "case-study-kredit-plus/src/app/businessweb/apiclient"
+	http_apiclientconsumer "case-study-kredit-plus/src/app/businessweb/apiclientconsumer"
 	http_consumer "case-study-kredit-plus/src/app/businessweb/consumer"
 	http_consumercreditlimit "case-study-kredit-plus/src/app/businessweb/consumercreditlimit"
+	http_consumerinstallment "case-study-kredit-plus/src/app/businessweb/consumerinstallment"
+	http_consumerpayment "case-study-kredit-plus/src/app/businessweb/consumerpayment"
 	http_consumertransaction "case-study-kredit-plus/src/app/businessweb/consumertransaction"
+	http_jwks "case-study-kredit-plus/src/app/businessweb/jwks"
+	http_loanproduct "case-study-kredit-plus/src/app/businessweb/loanproduct"
+	http_merchant "case-study-kredit-plus/src/app/businessweb/merchant"
+	http_role "case-study-kredit-plus/src/app/businessweb/role"
+	http_underwriting "case-study-kredit-plus/src/app/businessweb/underwriting"
 	http_user "case-study-kredit-plus/src/app/businessweb/user"
 
 	"case-study-kredit-plus/library/data"
@@ -13,18 +22,36 @@ import (
 )
 
 var (
+	apiclientHandler           http_apiclient.APIClientHandler
+	apiclientconsumerHandler   http_apiclientconsumer.APIClientConsumerHandler
 	consumerHandler            http_consumer.ConsumerHandler
 	consumercreditlimitHandler http_consumercreditlimit.ConsumerCreditLimitHandler
+	consumerinstallmentHandler http_consumerinstallment.ConsumerInstallmentHandler
+	consumerpaymentHandler     http_consumerpayment.ConsumerPaymentHandler
 	consumertransactionHandler http_consumertransaction.ConsumerTransactionHandler
+	jwksHandler                http_jwks.JWKSHandler
+	loanproductHandler         http_loanproduct.LoanProductHandler
+	merchantHandler            http_merchant.MerchantHandler
+	roleHandler                http_role.RoleHandler
+	underwritingHandler        http_underwriting.UnderwritingHandler
 	userHandler                http_user.UserHandler
 )
 
 func RegisterRoutes(db *sqlx.DB, dataManager *data.Manager, router *gin.Engine, v *gin.RouterGroup) {
 	v1 := v.Group("")
 	{
+		apiclientHandler.RegisterAPI(db, dataManager, router, v1)
+		apiclientconsumerHandler.RegisterAPI(db, dataManager, router, v1)
 		consumerHandler.RegisterAPI(db, dataManager, router, v1)
 		consumercreditlimitHandler.RegisterAPI(db, dataManager, router, v1)
+		consumerinstallmentHandler.RegisterAPI(db, dataManager, router, v1)
+		consumerpaymentHandler.RegisterAPI(db, dataManager, router, v1)
 		consumertransactionHandler.RegisterAPI(db, dataManager, router, v1)
+		jwksHandler.RegisterAPI(db, dataManager, router, v1)
+		loanproductHandler.RegisterAPI(db, dataManager, router, v1)
+		merchantHandler.RegisterAPI(db, dataManager, router, v1)
+		roleHandler.RegisterAPI(db, dataManager, router, v1)
+		underwritingHandler.RegisterAPI(db, dataManager, router, v1)
 		userHandler.RegisterAPI(db, dataManager, router, v1)
 	}
 }
@@ -0,0 +1,768 @@
+package underwriting
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/filestorage"
+	"case-study-kredit-plus/library/helpers"
+	"case-study-kredit-plus/middleware"
+	"case-study-kredit-plus/models"
+	"case-study-kredit-plus/src/services/underwriting"
+
+	"github.com/gin-gonic/gin"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/fieldcrypt"
+	"case-study-kredit-plus/library/http/response"
+	"case-study-kredit-plus/library/types"
+
+	consumerRepository "case-study-kredit-plus/src/services/consumer/repository"
+	consumerUsecase "case-study-kredit-plus/src/services/consumer/usecase"
+	consumercreditlimitRepository "case-study-kredit-plus/src/services/consumercreditlimit/repository"
+	consumercreditlimitUsecase "case-study-kredit-plus/src/services/consumercreditlimit/usecase"
+	loanproductRepository "case-study-kredit-plus/src/services/loanproduct/repository"
+	loanproductUsecase "case-study-kredit-plus/src/services/loanproduct/usecase"
+	underwritingRepository "case-study-kredit-plus/src/services/underwriting/repository"
+	underwritingUsecase "case-study-kredit-plus/src/services/underwriting/usecase"
+)
+
+var ()
+
+type UnderwritingHandler struct {
+	UnderwritingUsecase underwriting.Usecase
+	dataManager         *data.Manager
+	Result              gin.H
+	Status              int
+}
+
+func (h UnderwritingHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, router *gin.Engine, v *gin.RouterGroup) {
+	underwritingRepo := underwritingRepository.NewUnderwritingRepository(
+		data.NewMySQLStorage(db, "underwriting_rule_sets", models.UnderwritingRuleSet{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumer_credit_limit_proposals", models.ConsumerCreditLimitProposal{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumer_credit_limit_proposal_details", models.ConsumerCreditLimitProposalDetail{}, data.MysqlConfig{}),
+	)
+
+	consumerRepo := consumerRepository.NewConsumerRepository(
+		data.NewMySQLStorage(db, "consumers", models.ConsumerEncrypted{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		fieldcrypt.NewFromConfiguration(),
+	)
+
+	consumercreditlimitRepo := consumercreditlimitRepository.NewConsumerCreditLimitRepository(
+		data.NewMySQLStorage(db, "consumer_credit_limits", models.ConsumerCreditLimit{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumer_credit_limit_details", models.ConsumerCreditLimitDetail{}, data.MysqlConfig{}),
+	)
+
+	loanproductRepo := loanproductRepository.NewLoanProductRepository(
+		data.NewMySQLStorage(db, "loan_products", models.LoanProduct{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	uConsumer := consumerUsecase.NewConsumerUsecase(db, &consumerRepo, filestorage.NewFromConfiguration())
+	uLoanProduct := loanproductUsecase.NewLoanProductUsecase(db, &loanproductRepo)
+	uConsumerCreditLimit := consumercreditlimitUsecase.NewConsumerCreditLimitUsecase(db, &consumercreditlimitRepo, uLoanProduct)
+	uUnderwriting := underwritingUsecase.NewUnderwritingUsecase(db, &underwritingRepo, uConsumer, uConsumerCreditLimit, uLoanProduct)
+
+	base := &UnderwritingHandler{UnderwritingUsecase: uUnderwriting, dataManager: dataManager}
+
+	rs := v.Group("/underwriting/rule-sets")
+	{
+		rs.GET("", middleware.Auth, middleware.Permission(models.PERMISSION_UNDERWRITING_READ), base.FindAll)
+		rs.GET("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_UNDERWRITING_READ), base.Find)
+		rs.POST("", middleware.Auth, middleware.Permission(models.PERMISSION_UNDERWRITING_WRITE), base.Create)
+		rs.PUT("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_UNDERWRITING_WRITE), base.Update)
+
+		rs.PUT("/status", middleware.Auth, middleware.Permission(models.PERMISSION_UNDERWRITING_WRITE), base.UpdateStatus)
+	}
+
+	proposals := v.Group("/underwriting/proposals")
+	{
+		proposals.GET("", middleware.Auth, middleware.Permission(models.PERMISSION_UNDERWRITING_READ), base.FindAllProposals)
+		proposals.GET("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_UNDERWRITING_READ), base.FindProposal)
+		proposals.POST("", middleware.Auth, middleware.Permission(models.PERMISSION_UNDERWRITING_WRITE), base.Propose)
+		proposals.POST("/:id/accept", middleware.Auth, middleware.Permission(models.PERMISSION_UNDERWRITING_WRITE), base.Accept)
+		proposals.POST("/:id/override", middleware.Auth, middleware.Permission(models.PERMISSION_UNDERWRITING_WRITE), base.Override)
+	}
+
+	status := v.Group("/statuses")
+	{
+		status.GET("/underwriting/rule-sets", middleware.AuthCheckIP, base.FindStatus)
+	}
+}
+
+func (h *UnderwritingHandler) FindAll(c *gin.Context) {
+	var params models.FindAllUnderwritingRuleSetParams
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	params.FindAllParams = filterFindAllParams
+
+	if c.Query("Age") != "" {
+		age, errParseInt := strconv.Atoi(c.Query("Age"))
+		if errParseInt != nil {
+			err := &types.Error{
+				Path:       ".UnderwritingHandler->FindAll()",
+				Message:    "Age Invalid",
+				Error:      errParseInt,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		params.Age = age
+	}
+
+	if c.Query("Salary") != "" {
+		salary, errParseFloat := strconv.ParseFloat(c.Query("Salary"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".UnderwritingHandler->FindAll()",
+				Message:    "Salary Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		params.Salary = salary
+	}
+
+	datas, err := h.UnderwritingUsecase.FindAll(c, params)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	length, err := h.UnderwritingUsecase.Count(c, params)
+	if err != nil {
+		err.Path = ".UnderwritingHandler->FindAll()" + err.Path
+		if err.Error != data.ErrNotFound {
+			response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	dataresponse := types.ResultAll{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *UnderwritingHandler) Find(c *gin.Context) {
+	id := c.Param("id")
+
+	if !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".UnderwritingHandler->Find()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	result, err := h.UnderwritingUsecase.Find(c, id)
+	if err != nil {
+		err.Path = ".UnderwritingHandler->Find()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, "UnderwritingRuleSet not found", http.StatusUnprocessableEntity, *err)
+			return
+		}
+		response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *UnderwritingHandler) Create(c *gin.Context) {
+	var err *types.Error
+	var obj models.UnderwritingRuleSet
+	var data *models.UnderwritingRuleSet
+
+	if !library.ValidateTextInput(c.PostForm("Name")) {
+		err := &types.Error{
+			Path:       ".UnderwritingHandler->Create()",
+			Message:    "Name is not valid",
+			Error:      fmt.Errorf("Name is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	minAge, errParseInt := strconv.Atoi(c.PostForm("MinAge"))
+	if errParseInt != nil {
+		err := &types.Error{
+			Path:       ".UnderwritingHandler->Create()",
+			Message:    "Min Age Invalid",
+			Error:      errParseInt,
+			Type:       "conversion-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	obj.MinAge = minAge
+
+	maxAge, errParseInt := strconv.Atoi(c.PostForm("MaxAge"))
+	if errParseInt != nil {
+		err := &types.Error{
+			Path:       ".UnderwritingHandler->Create()",
+			Message:    "Max Age Invalid",
+			Error:      errParseInt,
+			Type:       "conversion-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	obj.MaxAge = maxAge
+
+	maxDebtServiceRatio, errParseFloat := strconv.ParseFloat(c.PostForm("MaxDebtServiceRatio"), 64)
+	if errParseFloat != nil {
+		err := &types.Error{
+			Path:       ".UnderwritingHandler->Create()",
+			Message:    "Max Debt Service Ratio Invalid",
+			Error:      errParseFloat,
+			Type:       "conversion-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	obj.MaxDebtServiceRatio = maxDebtServiceRatio
+
+	if c.PostForm("MinSalary") != "" {
+		minSalary, errParseFloat := strconv.ParseFloat(c.PostForm("MinSalary"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".UnderwritingHandler->Create()",
+				Message:    "Min Salary Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.MinSalary = minSalary
+	}
+
+	if c.PostForm("MaxLimitAmount") != "" {
+		maxLimitAmount, errParseFloat := strconv.ParseFloat(c.PostForm("MaxLimitAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".UnderwritingHandler->Create()",
+				Message:    "Max Limit Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.MaxLimitAmount = maxLimitAmount
+	}
+
+	obj.Name = c.PostForm("Name")
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.UnderwritingUsecase.Create(c, obj)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".UnderwritingHandler->Create()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data created successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *UnderwritingHandler) Update(c *gin.Context) {
+	var err *types.Error
+	var obj models.UnderwritingRuleSet
+	var data *models.UnderwritingRuleSet
+
+	id := c.Param("id")
+
+	if !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".UnderwritingHandler->Update()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	if !library.ValidateTextInput(c.PostForm("Name")) {
+		err := &types.Error{
+			Path:       ".UnderwritingHandler->Update()",
+			Message:    "Name is not valid",
+			Error:      fmt.Errorf("Name is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	minAge, errParseInt := strconv.Atoi(c.PostForm("MinAge"))
+	if errParseInt != nil {
+		err := &types.Error{
+			Path:       ".UnderwritingHandler->Update()",
+			Message:    "Min Age Invalid",
+			Error:      errParseInt,
+			Type:       "conversion-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	obj.MinAge = minAge
+
+	maxAge, errParseInt := strconv.Atoi(c.PostForm("MaxAge"))
+	if errParseInt != nil {
+		err := &types.Error{
+			Path:       ".UnderwritingHandler->Update()",
+			Message:    "Max Age Invalid",
+			Error:      errParseInt,
+			Type:       "conversion-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	obj.MaxAge = maxAge
+
+	maxDebtServiceRatio, errParseFloat := strconv.ParseFloat(c.PostForm("MaxDebtServiceRatio"), 64)
+	if errParseFloat != nil {
+		err := &types.Error{
+			Path:       ".UnderwritingHandler->Update()",
+			Message:    "Max Debt Service Ratio Invalid",
+			Error:      errParseFloat,
+			Type:       "conversion-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	obj.MaxDebtServiceRatio = maxDebtServiceRatio
+
+	if c.PostForm("MinSalary") != "" {
+		minSalary, errParseFloat := strconv.ParseFloat(c.PostForm("MinSalary"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".UnderwritingHandler->Update()",
+				Message:    "Min Salary Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.MinSalary = minSalary
+	}
+
+	if c.PostForm("MaxLimitAmount") != "" {
+		maxLimitAmount, errParseFloat := strconv.ParseFloat(c.PostForm("MaxLimitAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".UnderwritingHandler->Update()",
+				Message:    "Max Limit Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.MaxLimitAmount = maxLimitAmount
+	}
+
+	obj.Name = c.PostForm("Name")
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.UnderwritingUsecase.Update(c, id, obj)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".UnderwritingHandler->Update()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "UnderwritingRuleSet successfuly updated", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *UnderwritingHandler) FindStatus(c *gin.Context) {
+	datas, err := h.UnderwritingUsecase.FindStatus(c)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data successfuly shown", Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *UnderwritingHandler) UpdateStatus(c *gin.Context) {
+	var err *types.Error
+	var data *models.UnderwritingRuleSet
+
+	var ids []*models.IDNameTemplate
+
+	newStatusID := c.PostForm("NewStatusID")
+
+	errJson := json.Unmarshal([]byte(c.PostForm("ID")), &ids)
+	if errJson != nil {
+		err = &types.Error{
+			Path:  ".UnderwritingHandler->UpdateStatus()",
+			Error: errJson,
+			Type:  "convert-error",
+		}
+		response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		for _, id := range ids {
+			data, err = h.UnderwritingUsecase.UpdateStatus(c, id.ID, newStatusID)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if errTransaction != nil {
+		errTransaction.Path = ".UnderwritingHandler->UpdateStatus()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Status update success", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+// PROPOSAL
+
+func (h *UnderwritingHandler) FindAllProposals(c *gin.Context) {
+	if c.Query("ConsumerID") != "" && !library.ValidateUUID(c.Query("ConsumerID")) {
+		err := &types.Error{
+			Path:       ".UnderwritingHandler->FindAllProposals()",
+			Message:    "Consumer ID is not valid",
+			Error:      fmt.Errorf("Consumer ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	var params models.FindAllConsumerCreditLimitProposalParams
+	page, size := helpers.FilterFindAll(c)
+	filterFindAllParams := helpers.FilterFindAllParam(c)
+	params.FindAllParams = filterFindAllParams
+	params.ConsumerID = c.Query("ConsumerID")
+	params.Decision = c.Query("Decision")
+
+	datas, err := h.UnderwritingUsecase.FindAllProposals(c, params)
+	if err != nil {
+		if err.Error != data.ErrNotFound {
+			response.Error(c, err.Message, http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	length, err := h.UnderwritingUsecase.CountProposals(c, params)
+	if err != nil {
+		err.Path = ".UnderwritingHandler->FindAllProposals()" + err.Path
+		if err.Error != data.ErrNotFound {
+			response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+			return
+		}
+	}
+
+	dataresponse := types.ResultAll{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", TotalData: length, Page: page, Size: size, Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+	c.JSON(h.Status, h.Result)
+}
+
+func (h *UnderwritingHandler) FindProposal(c *gin.Context) {
+	id := c.Param("id")
+
+	if !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".UnderwritingHandler->FindProposal()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	result, err := h.UnderwritingUsecase.FindProposal(c, id)
+	if err != nil {
+		err.Path = ".UnderwritingHandler->FindProposal()" + err.Path
+		if err.Error == data.ErrNotFound {
+			response.Error(c, "ConsumerCreditLimitProposal not found", http.StatusUnprocessableEntity, *err)
+			return
+		}
+		response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *UnderwritingHandler) Propose(c *gin.Context) {
+	var err *types.Error
+	var data *models.ConsumerCreditLimitProposal
+
+	consumerID := c.PostForm("ConsumerID")
+
+	if !library.ValidateUUID(consumerID) {
+		err := &types.Error{
+			Path:       ".UnderwritingHandler->Propose()",
+			Message:    "Consumer ID is not valid",
+			Error:      fmt.Errorf("Consumer ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	externalObligationAmount := 0.0
+	if c.PostForm("ExternalObligationAmount") != "" {
+		amount, errParseFloat := strconv.ParseFloat(c.PostForm("ExternalObligationAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".UnderwritingHandler->Propose()",
+				Message:    "External Obligation Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		externalObligationAmount = amount
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.UnderwritingUsecase.Propose(c, consumerID, externalObligationAmount)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".UnderwritingHandler->Propose()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Credit limit proposed successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *UnderwritingHandler) Accept(c *gin.Context) {
+	var err *types.Error
+	var data *models.ConsumerCreditLimitProposal
+
+	id := c.Param("id")
+	reason := c.PostForm("Reason")
+
+	if !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".UnderwritingHandler->Accept()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	if reason != "" && !library.ValidateTextInput(reason) {
+		err := &types.Error{
+			Path:       ".UnderwritingHandler->Accept()",
+			Message:    "Reason is not valid",
+			Error:      fmt.Errorf("Reason is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.UnderwritingUsecase.Accept(c, id, reason)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".UnderwritingHandler->Accept()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Credit limit proposal accepted successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *UnderwritingHandler) Override(c *gin.Context) {
+	var err *types.Error
+	var data *models.ConsumerCreditLimitProposal
+
+	id := c.Param("id")
+	reason := c.PostForm("Reason")
+
+	if !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".UnderwritingHandler->Override()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	if !library.ValidateTextInput(reason) {
+		err := &types.Error{
+			Path:       ".UnderwritingHandler->Override()",
+			Message:    "Reason is not valid",
+			Error:      fmt.Errorf("Reason is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	var details []*models.ConsumerCreditLimitProposalDetail
+	errJson := json.Unmarshal([]byte(c.PostForm("Details")), &details)
+	if errJson != nil {
+		err := &types.Error{
+			Path:       ".UnderwritingHandler->Override()",
+			Message:    "Details Invalid",
+			Error:      errJson,
+			Type:       "conversion-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.UnderwritingUsecase.Override(c, id, details, reason)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".UnderwritingHandler->Override()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Credit limit proposal overridden successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
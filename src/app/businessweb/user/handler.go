@@ -1,10 +1,8 @@
 package user
 
 import (
-	"crypto/md5"
 	"encoding/json"
 	"fmt"
-	"io"
 	"net/http"
 
 	"github.com/jmoiron/sqlx"
@@ -19,8 +17,11 @@ import (
 
 	"case-study-kredit-plus/library/data"
 	"case-study-kredit-plus/library/http/response"
+	"case-study-kredit-plus/library/session"
 	"case-study-kredit-plus/library/types"
 
+	roleRepository "case-study-kredit-plus/src/services/role/repository"
+	roleUsecase "case-study-kredit-plus/src/services/role/usecase"
 	userRepository "case-study-kredit-plus/src/services/user/repository"
 	userUsecase "case-study-kredit-plus/src/services/user/usecase"
 )
@@ -40,19 +41,31 @@ func (h UserHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, router
 		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
 	)
 
-	uUser := userUsecase.NewUserUsecase(db, &userRepo)
+	roleRepo := roleRepository.NewRoleRepository(
+		data.NewMySQLStorage(db, "roles", models.Role{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "role_permissions", models.RolePermission{}, data.MysqlConfig{}),
+	)
+
+	uRole := roleUsecase.NewRoleUsecase(db, &roleRepo)
+	uUser := userUsecase.NewUserUsecase(db, &userRepo, uRole, session.FromConfiguration())
 
 	base := &UserHandler{UserUsecase: uUser, dataManager: dataManager}
 
 	rs := v.Group("/users")
 	{
 		// rs.GET("", middleware.Auth, base.FindAll)
-		rs.GET("/:id", middleware.Auth, base.Find)
-		rs.PUT("/:id", middleware.Auth, base.Update)
+		rs.GET("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_USERS_READ), base.Find)
+		rs.PUT("/:id", middleware.Auth, middleware.Permission(models.PERMISSION_USERS_WRITE), base.Update)
 		// rs.PUT("/status", middleware.Auth, base.UpdateStatus)
 
-		rs.POST("register", base.Create)
+		rs.PUT("/:id/role", middleware.Auth, middleware.Permission(models.PERMISSION_USERS_WRITE), base.UpdateRole)
+
+		rs.POST("register", middleware.Auth, middleware.Permission(models.PERMISSION_USERS_WRITE), base.Create)
 		rs.POST("auth/login", base.Login)
+		rs.POST("auth/refresh", base.Refresh)
+		rs.POST("auth/logout", middleware.Auth, base.Logout)
+		rs.POST("auth/logout-all", middleware.Auth, base.LogoutAll)
 	}
 
 	status := v.Group("/statuses")
@@ -227,14 +240,66 @@ func (h *UserHandler) UpdateStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, h.Result)
 }
 
+func (h *UserHandler) UpdateRole(c *gin.Context) {
+	var err *types.Error
+	var data *models.User
+
+	id := c.Param("id")
+
+	if !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".UserHandler->UpdateRole()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	if !library.ValidateUUID(c.PostForm("RoleID")) {
+		err := &types.Error{
+			Path:       ".UserHandler->UpdateRole()",
+			Message:    "Role ID is not valid",
+			Error:      fmt.Errorf("role id is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.UserUsecase.UpdateRole(c, id, c.PostForm("RoleID"))
+		if err != nil {
+			return err
+		}
+
+		data.Password = ""
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".UserHandler->UpdateRole()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data updated successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
 // REGISTER
 func (h *UserHandler) Create(c *gin.Context) {
 	var err *types.Error
 	var obj models.User
 	var data *models.User
 
-	c.Set("UserID", "0")
-
 	if !library.ValidateEmail(c.PostForm("Email")) {
 		err := &types.Error{
 			Path:       ".UserHandler->Create()",
@@ -281,23 +346,21 @@ func (h *UserHandler) Create(c *gin.Context) {
 	obj.CountryCallingCode = c.PostForm("CountryCallingCode")
 	obj.PhoneNumber = c.PostForm("PhoneNumber")
 
-	if len(c.PostForm("Password")) < 6 {
+	if !library.ValidateUUID(c.PostForm("RoleID")) {
 		err := &types.Error{
 			Path:       ".UserHandler->Create()",
-			Message:    "Password must be at least 6 characters",
-			Error:      fmt.Errorf("password must be at least 6 characters"),
+			Message:    "Role ID is not valid",
+			Error:      fmt.Errorf("role id is not valid"),
 			Type:       "validation-error",
 			StatusCode: http.StatusUnprocessableEntity,
 		}
 		response.Error(c, err.Message, err.StatusCode, *err)
 		return
 	}
+	obj.RoleID = c.PostForm("RoleID")
 
-	hash := md5.New()
-	io.WriteString(hash, c.PostForm("Password"))
-	password := fmt.Sprintf("%x", hash.Sum(nil))
-
-	obj.Password = password
+	// hashed by the usecase, after the password policy is checked
+	obj.Password = c.PostForm("Password")
 
 	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
 		data, err = h.UserUsecase.Create(c, obj)
@@ -327,11 +390,8 @@ func (h *UserHandler) Create(c *gin.Context) {
 
 // LOGIN
 func (h *UserHandler) Login(c *gin.Context) {
-	hash := md5.New()
-	io.WriteString(hash, c.PostForm("Password"))
-
 	email := c.PostForm("Email")
-	password := fmt.Sprintf("%x", hash.Sum(nil))
+	password := c.PostForm("Password")
 
 	if !library.ValidateEmail(c.PostForm("Email")) {
 		err := &types.Error{
@@ -373,3 +433,61 @@ func (h *UserHandler) Login(c *gin.Context) {
 }
 
 // // //
+
+func (h *UserHandler) Refresh(c *gin.Context) {
+	refreshToken := c.PostForm("RefreshToken")
+	if refreshToken == "" {
+		err := &types.Error{
+			Path:       ".UserHandler->Refresh()",
+			Message:    "RefreshToken is required",
+			Error:      fmt.Errorf("RefreshToken is required"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	datas, err := h.UserUsecase.Refresh(c, refreshToken)
+	if err != nil {
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Token refreshed", Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *UserHandler) Logout(c *gin.Context) {
+	err := h.UserUsecase.Logout(c)
+	if err != nil {
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Logout success"}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *UserHandler) LogoutAll(c *gin.Context) {
+	err := h.UserUsecase.LogoutAll(c)
+	if err != nil {
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "All sessions logged out"}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
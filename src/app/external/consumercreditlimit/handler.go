@@ -0,0 +1,116 @@
+package consumercreditlimit
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/jmoiron/sqlx"
+
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/appcontext"
+	"case-study-kredit-plus/library/filestorage"
+	"case-study-kredit-plus/middleware"
+	"case-study-kredit-plus/models"
+	"case-study-kredit-plus/src/services/apiclientconsumer"
+	"case-study-kredit-plus/src/services/consumercreditlimit"
+
+	"github.com/gin-gonic/gin"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/fieldcrypt"
+	"case-study-kredit-plus/library/http/response"
+	"case-study-kredit-plus/library/types"
+
+	apiclientconsumerRepository "case-study-kredit-plus/src/services/apiclientconsumer/repository"
+	apiclientconsumerUsecase "case-study-kredit-plus/src/services/apiclientconsumer/usecase"
+	consumerRepository "case-study-kredit-plus/src/services/consumer/repository"
+	consumerUsecase "case-study-kredit-plus/src/services/consumer/usecase"
+	consumercreditlimitRepository "case-study-kredit-plus/src/services/consumercreditlimit/repository"
+	consumercreditlimitUsecase "case-study-kredit-plus/src/services/consumercreditlimit/usecase"
+	loanproductRepository "case-study-kredit-plus/src/services/loanproduct/repository"
+	loanproductUsecase "case-study-kredit-plus/src/services/loanproduct/usecase"
+)
+
+var ()
+
+type ConsumerCreditLimitHandler struct {
+	ConsumerCreditLimitUsecase consumercreditlimit.Usecase
+	APIClientConsumerUsecase   apiclientconsumer.Usecase
+	dataManager                *data.Manager
+	Result                     gin.H
+	Status                     int
+}
+
+func (h ConsumerCreditLimitHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, router *gin.Engine, v *gin.RouterGroup) {
+	consumercreditlimitRepo := consumercreditlimitRepository.NewConsumerCreditLimitRepository(
+		data.NewMySQLStorage(db, "consumer_credit_limits", models.ConsumerCreditLimit{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumer_credit_limit_details", models.ConsumerCreditLimitDetail{}, data.MysqlConfig{}),
+	)
+
+	loanproductRepo := loanproductRepository.NewLoanProductRepository(
+		data.NewMySQLStorage(db, "loan_products", models.LoanProduct{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	apiclientconsumerRepo := apiclientconsumerRepository.NewAPIClientConsumerRepository(
+		data.NewMySQLStorage(db, "api_client_consumers", models.APIClientConsumer{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	consumerRepo := consumerRepository.NewConsumerRepository(
+		data.NewMySQLStorage(db, "consumers", models.ConsumerEncrypted{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		fieldcrypt.NewFromConfiguration(),
+	)
+
+	uLoanProduct := loanproductUsecase.NewLoanProductUsecase(db, &loanproductRepo)
+	uConsumerCreditLimit := consumercreditlimitUsecase.NewConsumerCreditLimitUsecase(db, &consumercreditlimitRepo, uLoanProduct)
+	uConsumer := consumerUsecase.NewConsumerUsecase(db, &consumerRepo, filestorage.NewFromConfiguration())
+	uAPIClientConsumer := apiclientconsumerUsecase.NewAPIClientConsumerUsecase(db, &apiclientconsumerRepo, uConsumer)
+
+	base := &ConsumerCreditLimitHandler{ConsumerCreditLimitUsecase: uConsumerCreditLimit, APIClientConsumerUsecase: uAPIClientConsumer, dataManager: dataManager}
+
+	rs := v.Group("/consumers/credit-limits")
+	{
+		rs.GET("/availability", middleware.AuthExternal, middleware.Scope(models.SCOPE_CREDIT_LIMITS_READ), base.FindAvailability)
+	}
+}
+
+// FindAvailability answers only for consumers the calling API client has been granted under /api-clients/consumers
+func (h *ConsumerCreditLimitHandler) FindAvailability(c *gin.Context) {
+	consumerID := c.Query("ConsumerID")
+
+	if !library.ValidateUUID(consumerID) {
+		err := &types.Error{
+			Path:       ".ConsumerCreditLimitHandler->FindAvailability()",
+			Message:    "Consumer ID is not valid",
+			Error:      fmt.Errorf("Consumer ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	err := h.APIClientConsumerUsecase.CheckAccess(c, appcontext.APIClientID(c), consumerID)
+	if err != nil {
+		err.Path = ".ConsumerCreditLimitHandler->FindAvailability()" + err.Path
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	datas, err := h.ConsumerCreditLimitUsecase.FindCreditLimitAvailabilities(c, consumerID)
+	if err != nil {
+		err.Path = ".ConsumerCreditLimitHandler->FindAvailability()" + err.Path
+		response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", Data: datas}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
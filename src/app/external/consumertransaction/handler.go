@@ -9,14 +9,19 @@ import (
 	"github.com/jmoiron/sqlx"
 
 	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/appcontext"
+	"case-study-kredit-plus/library/filestorage"
 	"case-study-kredit-plus/library/helpers"
 	"case-study-kredit-plus/middleware"
 	"case-study-kredit-plus/models"
+	"case-study-kredit-plus/src/services/apiclientconsumer"
 	"case-study-kredit-plus/src/services/consumertransaction"
+	"case-study-kredit-plus/src/services/idempotencykey"
 
 	"github.com/gin-gonic/gin"
 
 	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/fieldcrypt"
 	"case-study-kredit-plus/library/http/response"
 	"case-study-kredit-plus/library/types"
 
@@ -25,12 +30,30 @@ import (
 
 	consumercreditlimitRepository "case-study-kredit-plus/src/services/consumercreditlimit/repository"
 	consumercreditlimitUsecase "case-study-kredit-plus/src/services/consumercreditlimit/usecase"
+
+	consumerinstallmentRepository "case-study-kredit-plus/src/services/consumerinstallment/repository"
+	consumerinstallmentUsecase "case-study-kredit-plus/src/services/consumerinstallment/usecase"
+
+	loanproductRepository "case-study-kredit-plus/src/services/loanproduct/repository"
+	loanproductUsecase "case-study-kredit-plus/src/services/loanproduct/usecase"
+	merchantRepository "case-study-kredit-plus/src/services/merchant/repository"
+	merchantUsecase "case-study-kredit-plus/src/services/merchant/usecase"
+
+	idempotencykeyRepository "case-study-kredit-plus/src/services/idempotencykey/repository"
+	idempotencykeyUsecase "case-study-kredit-plus/src/services/idempotencykey/usecase"
+
+	apiclientconsumerRepository "case-study-kredit-plus/src/services/apiclientconsumer/repository"
+	apiclientconsumerUsecase "case-study-kredit-plus/src/services/apiclientconsumer/usecase"
+	consumerRepository "case-study-kredit-plus/src/services/consumer/repository"
+	consumerUsecase "case-study-kredit-plus/src/services/consumer/usecase"
 )
 
 var ()
 
 type ConsumerTransactionHandler struct {
 	ConsumerTransactionUsecase consumertransaction.Usecase
+	IdempotencyKeyUsecase      idempotencykey.Usecase
+	APIClientConsumerUsecase   apiclientconsumer.Usecase
 	dataManager                *data.Manager
 	Result                     gin.H
 	Status                     int
@@ -39,28 +62,72 @@ type ConsumerTransactionHandler struct {
 func (h ConsumerTransactionHandler) RegisterAPI(db *sqlx.DB, dataManager *data.Manager, router *gin.Engine, v *gin.RouterGroup) {
 	consumertransactionRepo := consumertransactionRepository.NewConsumerTransactionRepository(
 		data.NewMySQLStorage(db, "consumer_transactions", models.ConsumerTransaction{}, data.MysqlConfig{}),
-		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumer_transaction_statuses", models.ConsumerTransactionStatus{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumer_transaction_status_histories", models.ConsumerTransactionStatusHistory{}, data.MysqlConfig{}),
 	)
 
 	consumercreditlimitRepo := consumercreditlimitRepository.NewConsumerCreditLimitRepository(
 		data.NewMySQLStorage(db, "consumer_credit_limits", models.ConsumerCreditLimit{}, data.MysqlConfig{}),
 		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumer_credit_limit_details", models.ConsumerCreditLimitDetail{}, data.MysqlConfig{}),
+	)
+
+	consumerinstallmentRepo := consumerinstallmentRepository.NewConsumerInstallmentRepository(
+		data.NewMySQLStorage(db, "consumer_installments", models.ConsumerInstallment{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	loanproductRepo := loanproductRepository.NewLoanProductRepository(
+		data.NewMySQLStorage(db, "loan_products", models.LoanProduct{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	merchantRepo := merchantRepository.NewMerchantRepository(
+		data.NewMySQLStorage(db, "merchants", models.Merchant{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "merchant_loan_products", models.MerchantLoanProduct{}, data.MysqlConfig{}),
+	)
+
+	uLoanProduct := loanproductUsecase.NewLoanProductUsecase(db, &loanproductRepo)
+	uMerchant := merchantUsecase.NewMerchantUsecase(db, &merchantRepo, uLoanProduct)
+	uConsumerCreditLimit := consumercreditlimitUsecase.NewConsumerCreditLimitUsecase(db, &consumercreditlimitRepo, uLoanProduct)
+	uConsumerInstallment := consumerinstallmentUsecase.NewConsumerInstallmentUsecase(db, &consumerinstallmentRepo)
+
+	uConsumerTransaction := consumertransactionUsecase.NewConsumerTransactionUsecase(db, &consumertransactionRepo, uConsumerCreditLimit, uConsumerInstallment, uLoanProduct, uMerchant)
+
+	idempotencykeyRepo := idempotencykeyRepository.NewIdempotencyKeyRepository(
+		data.NewMySQLStorage(db, "idempotency_keys", models.IdempotencyKey{}, data.MysqlConfig{}),
+	)
+
+	uIdempotencyKey := idempotencykeyUsecase.NewIdempotencyKeyUsecase(db, &idempotencykeyRepo)
+
+	apiclientconsumerRepo := apiclientconsumerRepository.NewAPIClientConsumerRepository(
+		data.NewMySQLStorage(db, "api_client_consumers", models.APIClientConsumer{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
 	)
 
-	uConsumerCreditLimit := consumercreditlimitUsecase.NewConsumerCreditLimitUsecase(db, &consumercreditlimitRepo)
+	consumerRepo := consumerRepository.NewConsumerRepository(
+		data.NewMySQLStorage(db, "consumers", models.ConsumerEncrypted{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		fieldcrypt.NewFromConfiguration(),
+	)
 
-	uConsumerTransaction := consumertransactionUsecase.NewConsumerTransactionUsecase(db, &consumertransactionRepo, uConsumerCreditLimit)
+	uConsumer := consumerUsecase.NewConsumerUsecase(db, &consumerRepo, filestorage.NewFromConfiguration())
+	uAPIClientConsumer := apiclientconsumerUsecase.NewAPIClientConsumerUsecase(db, &apiclientconsumerRepo, uConsumer)
 
-	base := &ConsumerTransactionHandler{ConsumerTransactionUsecase: uConsumerTransaction, dataManager: dataManager}
+	base := &ConsumerTransactionHandler{ConsumerTransactionUsecase: uConsumerTransaction, IdempotencyKeyUsecase: uIdempotencyKey, APIClientConsumerUsecase: uAPIClientConsumer, dataManager: dataManager}
 
 	rs := v.Group("/consumers/transactions")
 	{
-		rs.GET("", middleware.AuthExternal, base.FindAll)
-		rs.GET("/:id", middleware.AuthExternal, base.Find)
-		rs.POST("", middleware.AuthExternal, base.Create)
+		rs.GET("", middleware.AuthExternal, middleware.Scope(models.SCOPE_TRANSACTIONS_READ), middleware.MerchantScope, base.FindAll)
+		rs.GET("/:id", middleware.AuthExternal, middleware.Scope(models.SCOPE_TRANSACTIONS_READ), middleware.MerchantScope, base.Find)
+		rs.POST("", middleware.AuthExternal, middleware.Scope(models.SCOPE_TRANSACTIONS_CREATE), base.Create)
+		rs.POST("/simulate", middleware.AuthExternal, middleware.Scope(models.SCOPE_TRANSACTIONS_READ), base.Simulate)
 		// rs.PUT("/:id", middleware.AuthExternal, base.Update)
 
 		// rs.PUT("/status", middleware.AuthExternal, base.UpdateStatus)
+
+		rs.POST("/:id/cancel", middleware.AuthExternal, middleware.Scope(models.SCOPE_TRANSACTIONS_CANCEL), middleware.MerchantScope, base.Cancel)
 	}
 
 	status := v.Group("/statuses")
@@ -94,13 +161,29 @@ func (h *ConsumerTransactionHandler) FindAll(c *gin.Context) {
 		return
 	}
 
+	if c.Query("LoanProductID") != "" && !library.ValidateUUID(c.Query("LoanProductID")) {
+		err := &types.Error{
+			Path:       ".ConsumerTransactionHandler->FindAll()",
+			Message:    "Loan Product ID is not valid",
+			Error:      fmt.Errorf("Loan Product ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
 	var params models.FindAllConsumerTransactionParams
 	page, size := helpers.FilterFindAll(c)
 	filterFindAllParams := helpers.FilterFindAllParam(c)
 	params.FindAllParams = filterFindAllParams
 	params.ConsumerID = c.Query("ConsumerID")
 	params.ContractNumber = c.Query("ContractNumber")
+	params.LoanProductID = c.Query("LoanProductID")
 	params.LoanTerm, _ = strconv.Atoi(c.Query("LoanTerm"))
+	params.MinDaysPastDue, _ = strconv.Atoi(c.Query("MinDaysPastDue"))
+	// partner clients only see the transactions of their own merchant
+	params.MerchantID = appcontext.MerchantID(c)
 	datas, err := h.ConsumerTransactionUsecase.FindAll(c, params)
 	if err != nil {
 		if err.Error != data.ErrNotFound {
@@ -141,6 +224,9 @@ func (h *ConsumerTransactionHandler) Find(c *gin.Context) {
 	}
 
 	result, err := h.ConsumerTransactionUsecase.Find(c, id)
+	if err == nil {
+		err = checkMerchantScope(c, result)
+	}
 	if err != nil {
 		err.Path = ".ConsumerTransactionHandler->Find()" + err.Path
 		if err.Error == data.ErrNotFound {
@@ -162,7 +248,16 @@ func (h *ConsumerTransactionHandler) Find(c *gin.Context) {
 func (h *ConsumerTransactionHandler) Create(c *gin.Context) {
 	var err *types.Error
 	var obj models.ConsumerTransaction
-	var data *models.ConsumerTransaction
+	var result *models.ConsumerTransaction
+
+	// retried requests carrying the same Idempotency-Key get the original response back
+	idempotencyKey := c.GetHeader("Idempotency-Key")
+	apiClientID := appcontext.APIClientID(c)
+	requestHash := helpers.RequestHash(c)
+
+	if idempotencyKey != "" && h.replayIdempotentRequest(c, apiClientID, idempotencyKey, requestHash) {
+		return
+	}
 
 	if c.PostForm("ConsumerID") != "" && !library.ValidateUUID(c.PostForm("ConsumerID")) {
 		err := &types.Error{
@@ -176,11 +271,11 @@ func (h *ConsumerTransactionHandler) Create(c *gin.Context) {
 		return
 	}
 
-	if c.PostForm("ContractNumber") != "" && !library.ValidateTextInput(c.PostForm("ContractNumber")) {
+	if c.PostForm("LoanProductID") != "" && !library.ValidateUUID(c.PostForm("LoanProductID")) {
 		err := &types.Error{
 			Path:       ".ConsumerTransactionHandler->Create()",
-			Message:    "Contract Number is not valid",
-			Error:      fmt.Errorf("Contract Number is not valid"),
+			Message:    "Loan Product ID is not valid",
+			Error:      fmt.Errorf("Loan Product ID is not valid"),
 			Type:       "validation-error",
 			StatusCode: http.StatusUnprocessableEntity,
 		}
@@ -216,17 +311,21 @@ func (h *ConsumerTransactionHandler) Create(c *gin.Context) {
 		return
 	}
 
-	adminFee, errParseFloat := strconv.ParseFloat(c.PostForm("AdminFee"), 64)
-	if errParseFloat != nil {
-		err := &types.Error{
-			Path:       ".ConsumerTransactionHandler->Create()",
-			Message:    "Admin Fee Invalid",
-			Error:      errParseFloat,
-			Type:       "conversion-error",
-			StatusCode: http.StatusUnprocessableEntity,
+	if c.PostForm("AdminFee") != "" {
+		adminFee, errParseFloat := strconv.ParseFloat(c.PostForm("AdminFee"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".ConsumerTransactionHandler->Create()",
+				Message:    "Admin Fee Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
 		}
-		response.Error(c, err.Message, err.StatusCode, *err)
-		return
+
+		obj.AdminFee = adminFee
 	}
 
 	if c.PostForm("InstallmentAmount") != "" {
@@ -246,30 +345,38 @@ func (h *ConsumerTransactionHandler) Create(c *gin.Context) {
 		obj.InstallmentAmount = installmentAmount
 	}
 
-	loanTerm, errParseInt := strconv.Atoi(c.PostForm("LoanTerm"))
-	if errParseInt != nil {
-		err := &types.Error{
-			Path:       ".ConsumerTransactionHandler->Create()",
-			Message:    "Loan Term Invalid",
-			Error:      errParseInt,
-			Type:       "conversion-error",
-			StatusCode: http.StatusUnprocessableEntity,
+	if c.PostForm("LoanTerm") != "" {
+		loanTerm, errParseInt := strconv.Atoi(c.PostForm("LoanTerm"))
+		if errParseInt != nil {
+			err := &types.Error{
+				Path:       ".ConsumerTransactionHandler->Create()",
+				Message:    "Loan Term Invalid",
+				Error:      errParseInt,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
 		}
-		response.Error(c, err.Message, err.StatusCode, *err)
-		return
+
+		obj.LoanTerm = loanTerm
 	}
 
-	interestAmount, errParseFloat := strconv.ParseFloat(c.PostForm("InterestAmount"), 64)
-	if errParseFloat != nil {
-		err := &types.Error{
-			Path:       ".ConsumerTransactionHandler->Create()",
-			Message:    "Interest Amount Invalid",
-			Error:      errParseFloat,
-			Type:       "conversion-error",
-			StatusCode: http.StatusUnprocessableEntity,
+	if c.PostForm("InterestAmount") != "" {
+		interestAmount, errParseFloat := strconv.ParseFloat(c.PostForm("InterestAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".ConsumerTransactionHandler->Create()",
+				Message:    "Interest Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
 		}
-		response.Error(c, err.Message, err.StatusCode, *err)
-		return
+
+		obj.InterestAmount = interestAmount
 	}
 
 	if c.PostForm("TotalAmount") != "" {
@@ -290,28 +397,53 @@ func (h *ConsumerTransactionHandler) Create(c *gin.Context) {
 	}
 
 	obj.ConsumerID = c.PostForm("ConsumerID")
-	obj.ContractNumber = c.PostForm("ContractNumber")
+	obj.LoanProductID = c.PostForm("LoanProductID")
 	obj.OTR = otr
-	obj.AdminFee = adminFee
-	obj.InterestAmount = interestAmount
-	obj.LoanTerm = loanTerm
 	obj.AssetName = c.PostForm("AssetName")
+	obj.MerchantID = appcontext.MerchantID(c)
+	obj.APIClientID = apiClientID
 
 	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
-		data, err = h.ConsumerTransactionUsecase.Create(c, obj)
+		var idempotency *models.IdempotencyKey
+		if idempotencyKey != "" {
+			idempotency, err = h.IdempotencyKeyUsecase.Reserve(tctx, models.IdempotencyKey{
+				APIClientID:    apiClientID,
+				IdempotencyKey: idempotencyKey,
+				RequestPath:    tctx.FullPath(),
+				RequestHash:    requestHash,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		result, err = h.ConsumerTransactionUsecase.Create(tctx, obj)
 		if err != nil {
 			return err
 		}
 
+		if idempotency != nil {
+			dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data created successfuly", Data: result}
+			err = h.IdempotencyKeyUsecase.Complete(tctx, idempotency, http.StatusOK, gin.H{"result": dataresponse})
+			if err != nil {
+				return err
+			}
+		}
+
 		return nil
 	})
 	if errTransaction != nil {
+		// a retry racing the original request waits for it on the key, and is answered from its stored response
+		if idempotencyKey != "" && errTransaction.Error == data.ErrAlreadyExist && h.replayIdempotentRequest(c, apiClientID, idempotencyKey, requestHash) {
+			return
+		}
+
 		errTransaction.Path = ".ConsumerTransactionHandler->Create()" + errTransaction.Path
 		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
 		return
 	}
 
-	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data created successfuly", Data: data}
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data created successfuly", Data: result}
 	h.Result = gin.H{
 		"result": dataresponse,
 	}
@@ -319,6 +451,37 @@ func (h *ConsumerTransactionHandler) Create(c *gin.Context) {
 	c.JSON(http.StatusOK, h.Result)
 }
 
+// replayIdempotentRequest writes the stored response of an earlier request made with the same key, and reports whether it did.
+// Reusing a key for a different payload is rejected.
+func (h *ConsumerTransactionHandler) replayIdempotentRequest(c *gin.Context, apiClientID int, idempotencyKey string, requestHash string) bool {
+	result, err := h.IdempotencyKeyUsecase.FindByKey(c, apiClientID, idempotencyKey)
+	if err != nil {
+		if err.Error == data.ErrNotFound {
+			return false
+		}
+
+		err.Path = ".ConsumerTransactionHandler->replayIdempotentRequest()" + err.Path
+		response.Error(c, "Internal Server Error", http.StatusInternalServerError, *err)
+		return true
+	}
+
+	if result.RequestHash != requestHash {
+		err := &types.Error{
+			Path:       ".ConsumerTransactionHandler->replayIdempotentRequest()",
+			Message:    "Idempotency-Key was already used for a different request",
+			Error:      fmt.Errorf("Idempotency-Key was already used for a different request"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return true
+	}
+
+	c.Header("Idempotent-Replayed", "true")
+	c.Data(result.ResponseStatusCode, "application/json; charset=utf-8", []byte(result.ResponseBody))
+	return true
+}
+
 func (h *ConsumerTransactionHandler) Update(c *gin.Context) {
 	var err *types.Error
 	var obj models.ConsumerTransaction
@@ -338,11 +501,11 @@ func (h *ConsumerTransactionHandler) Update(c *gin.Context) {
 		return
 	}
 
-	if c.PostForm("AssetName") != "" && !library.ValidateTextInput(c.PostForm("AssetName")) {
+	if c.PostForm("LoanProductID") != "" && !library.ValidateUUID(c.PostForm("LoanProductID")) {
 		err := &types.Error{
 			Path:       ".ConsumerTransactionHandler->Update()",
-			Message:    "Asset Name is not valid",
-			Error:      fmt.Errorf("Asset Name is not valid"),
+			Message:    "Loan Product ID is not valid",
+			Error:      fmt.Errorf("Loan Product ID is not valid"),
 			Type:       "validation-error",
 			StatusCode: http.StatusUnprocessableEntity,
 		}
@@ -350,24 +513,23 @@ func (h *ConsumerTransactionHandler) Update(c *gin.Context) {
 		return
 	}
 
-	otr, errParseFloat := strconv.ParseFloat(c.PostForm("OTR"), 64)
-	if errParseFloat != nil {
+	if c.PostForm("AssetName") != "" && !library.ValidateTextInput(c.PostForm("AssetName")) {
 		err := &types.Error{
 			Path:       ".ConsumerTransactionHandler->Update()",
-			Message:    "OTR Invalid",
-			Error:      errParseFloat,
-			Type:       "conversion-error",
+			Message:    "Asset Name is not valid",
+			Error:      fmt.Errorf("Asset Name is not valid"),
+			Type:       "validation-error",
 			StatusCode: http.StatusUnprocessableEntity,
 		}
 		response.Error(c, err.Message, err.StatusCode, *err)
 		return
 	}
 
-	adminFee, errParseFloat := strconv.ParseFloat(c.PostForm("AdminFee"), 64)
+	otr, errParseFloat := strconv.ParseFloat(c.PostForm("OTR"), 64)
 	if errParseFloat != nil {
 		err := &types.Error{
 			Path:       ".ConsumerTransactionHandler->Update()",
-			Message:    "Admin Fee Invalid",
+			Message:    "OTR Invalid",
 			Error:      errParseFloat,
 			Type:       "conversion-error",
 			StatusCode: http.StatusUnprocessableEntity,
@@ -376,66 +538,95 @@ func (h *ConsumerTransactionHandler) Update(c *gin.Context) {
 		return
 	}
 
-	installmentAmount, errParseFloat := strconv.ParseFloat(c.PostForm("InstallmentAmount"), 64)
-	if errParseFloat != nil {
-		err := &types.Error{
-			Path:       ".ConsumerTransactionHandler->Update()",
-			Message:    "Installment Amount Invalid",
-			Error:      errParseFloat,
-			Type:       "conversion-error",
-			StatusCode: http.StatusUnprocessableEntity,
+	if c.PostForm("AdminFee") != "" {
+		adminFee, errParseFloat := strconv.ParseFloat(c.PostForm("AdminFee"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".ConsumerTransactionHandler->Update()",
+				Message:    "Admin Fee Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
 		}
-		response.Error(c, err.Message, err.StatusCode, *err)
-		return
+
+		obj.AdminFee = adminFee
 	}
 
-	loanTerm, errParseInt := strconv.Atoi(c.PostForm("LoanTerm"))
-	if errParseInt != nil {
-		err := &types.Error{
-			Path:       ".ConsumerTransactionHandler->Update()",
-			Message:    "Loan Term Invalid",
-			Error:      errParseInt,
-			Type:       "conversion-error",
-			StatusCode: http.StatusUnprocessableEntity,
+	if c.PostForm("InstallmentAmount") != "" {
+		installmentAmount, errParseFloat := strconv.ParseFloat(c.PostForm("InstallmentAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".ConsumerTransactionHandler->Update()",
+				Message:    "Installment Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
 		}
-		response.Error(c, err.Message, err.StatusCode, *err)
-		return
+
+		obj.InstallmentAmount = installmentAmount
 	}
 
-	interestAmount, errParseFloat := strconv.ParseFloat(c.PostForm("InterestAmount"), 64)
-	if errParseFloat != nil {
-		err := &types.Error{
-			Path:       ".ConsumerTransactionHandler->Update()",
-			Message:    "Interest Amount Invalid",
-			Error:      errParseFloat,
-			Type:       "conversion-error",
-			StatusCode: http.StatusUnprocessableEntity,
+	if c.PostForm("LoanTerm") != "" {
+		loanTerm, errParseInt := strconv.Atoi(c.PostForm("LoanTerm"))
+		if errParseInt != nil {
+			err := &types.Error{
+				Path:       ".ConsumerTransactionHandler->Update()",
+				Message:    "Loan Term Invalid",
+				Error:      errParseInt,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
 		}
-		response.Error(c, err.Message, err.StatusCode, *err)
-		return
+
+		obj.LoanTerm = loanTerm
 	}
 
-	totalAmount, errParseFloat := strconv.ParseFloat(c.PostForm("TotalAmount"), 64)
-	if errParseFloat != nil {
-		err := &types.Error{
-			Path:       ".ConsumerTransactionHandler->Update()",
-			Message:    "Total Amount Invalid",
-			Error:      errParseFloat,
-			Type:       "conversion-error",
-			StatusCode: http.StatusUnprocessableEntity,
+	if c.PostForm("InterestAmount") != "" {
+		interestAmount, errParseFloat := strconv.ParseFloat(c.PostForm("InterestAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".ConsumerTransactionHandler->Update()",
+				Message:    "Interest Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
 		}
-		response.Error(c, err.Message, err.StatusCode, *err)
-		return
+
+		obj.InterestAmount = interestAmount
+	}
+
+	if c.PostForm("TotalAmount") != "" {
+		totalAmount, errParseFloat := strconv.ParseFloat(c.PostForm("TotalAmount"), 64)
+		if errParseFloat != nil {
+			err := &types.Error{
+				Path:       ".ConsumerTransactionHandler->Update()",
+				Message:    "Total Amount Invalid",
+				Error:      errParseFloat,
+				Type:       "conversion-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+			response.Error(c, err.Message, err.StatusCode, *err)
+			return
+		}
+
+		obj.TotalAmount = totalAmount
 	}
 
 	// obj.ConsumerID = c.PostForm("ConsumerID")
 	// obj.ContractNumber = c.PostForm("ContractNumber")
+	obj.LoanProductID = c.PostForm("LoanProductID")
 	obj.OTR = otr
-	obj.AdminFee = adminFee
-	obj.InstallmentAmount = installmentAmount
-	obj.LoanTerm = loanTerm
-	obj.InterestAmount = interestAmount
-	obj.TotalAmount = totalAmount
 	obj.AssetName = c.PostForm("AssetName")
 
 	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
@@ -519,3 +710,151 @@ func (h *ConsumerTransactionHandler) UpdateStatus(c *gin.Context) {
 
 	c.JSON(http.StatusOK, h.Result)
 }
+
+func (h *ConsumerTransactionHandler) Cancel(c *gin.Context) {
+	var err *types.Error
+	var data *models.ConsumerTransaction
+
+	id := c.Param("id")
+	reason := c.PostForm("Reason")
+
+	if !library.ValidateUUID(id) {
+		err := &types.Error{
+			Path:       ".ConsumerTransactionHandler->Cancel()",
+			Message:    "ID is not valid",
+			Error:      fmt.Errorf("ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	if !library.ValidateTextInput(reason) {
+		err := &types.Error{
+			Path:       ".ConsumerTransactionHandler->Cancel()",
+			Message:    "Reason is not valid",
+			Error:      fmt.Errorf("Reason is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	errTransaction := h.dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+		data, err = h.ConsumerTransactionUsecase.Find(tctx, id)
+		if err != nil {
+			return err
+		}
+
+		err = checkMerchantScope(tctx, data)
+		if err != nil {
+			return err
+		}
+
+		data, err = h.ConsumerTransactionUsecase.Cancel(tctx, id, reason)
+		if err != nil {
+			return err
+		}
+
+		return nil
+	})
+	if errTransaction != nil {
+		errTransaction.Path = ".ConsumerTransactionHandler->Cancel()" + errTransaction.Path
+		response.Error(c, errTransaction.Message, errTransaction.StatusCode, *errTransaction)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Transaction cancelled successfuly", Data: data}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+func (h *ConsumerTransactionHandler) Simulate(c *gin.Context) {
+	var obj models.ConsumerTransaction
+
+	if !library.ValidateUUID(c.PostForm("ConsumerID")) {
+		err := &types.Error{
+			Path:       ".ConsumerTransactionHandler->Simulate()",
+			Message:    "Consumer ID is not valid",
+			Error:      fmt.Errorf("Consumer ID is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	if c.PostForm("AssetName") != "" && !library.ValidateTextInput(c.PostForm("AssetName")) {
+		err := &types.Error{
+			Path:       ".ConsumerTransactionHandler->Simulate()",
+			Message:    "Asset Name is not valid",
+			Error:      fmt.Errorf("Asset Name is not valid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	otr, errParseFloat := strconv.ParseFloat(c.PostForm("OTR"), 64)
+	if errParseFloat != nil {
+		err := &types.Error{
+			Path:       ".ConsumerTransactionHandler->Simulate()",
+			Message:    "OTR Invalid",
+			Error:      errParseFloat,
+			Type:       "conversion-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	// the quote tells whether the consumer's remaining limit covers it, so it is only given for granted consumers
+	err := h.APIClientConsumerUsecase.CheckAccess(c, appcontext.APIClientID(c), c.PostForm("ConsumerID"))
+	if err != nil {
+		err.Path = ".ConsumerTransactionHandler->Simulate()" + err.Path
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	obj.ConsumerID = c.PostForm("ConsumerID")
+	obj.OTR = otr
+	obj.AssetName = c.PostForm("AssetName")
+	obj.MerchantID = appcontext.MerchantID(c)
+
+	result, err := h.ConsumerTransactionUsecase.Simulate(c, obj)
+	if err != nil {
+		err.Path = ".ConsumerTransactionHandler->Simulate()" + err.Path
+		response.Error(c, err.Message, err.StatusCode, *err)
+		return
+	}
+
+	dataresponse := types.Result{Status: "Success", StatusCode: http.StatusOK, Message: "Data shown successfuly", Data: result}
+	h.Result = gin.H{
+		"result": dataresponse,
+	}
+
+	c.JSON(http.StatusOK, h.Result)
+}
+
+// checkMerchantScope hides transactions booked by another merchant from a partner client, as if they did not exist.
+// Clients without a merchant only get here with the merchants:all scope, see middleware.MerchantScope.
+func checkMerchantScope(c *gin.Context, obj *models.ConsumerTransaction) *types.Error {
+	merchantID := appcontext.MerchantID(c)
+	if merchantID == "" || obj.MerchantID == merchantID {
+		return nil
+	}
+
+	return &types.Error{
+		Path:       ".checkMerchantScope()",
+		Message:    "Data Not Found",
+		Error:      data.ErrNotFound,
+		StatusCode: http.StatusNotFound,
+		Type:       "validation-error",
+	}
+}
@@ -0,0 +1,122 @@
+package consumertransaction_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/src/app/external/consumertransaction"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// TestCancelThroughExternalAPI cancels a partner's transaction the way the partner does, through AuthExternal.
+// It needs a migrated MySQL database, e.g.
+// TEST_DB_CONNECTION_STRING="root:secret@tcp(127.0.0.1:3306)/kredit_plus_test?parseTime=true"
+func TestCancelThroughExternalAPI(t *testing.T) {
+	connectionString := os.Getenv("TEST_DB_CONNECTION_STRING")
+	if connectionString == "" {
+		t.Skip("TEST_DB_CONNECTION_STRING is not set")
+	}
+
+	// the middlewares read their configuration from the env file
+	env, _ := json.Marshal(map[string]string{
+		"ACTIVE_WORKER":                   "0",
+		"ANDROID_POS_APP_MINIMUM_VERSION": "1.0.0",
+		"IOS_POS_APP_MINIMUM_VERSION":     "1.0.0",
+		"DB_CONNECTION_STRING":            connectionString,
+		"REDIS_DB":                        "0",
+		"REDIS_TIME_OUT":                  "1",
+		"JWT_TIME_OUT":                    "1",
+		"WHITELISTED_IPS":                 "0.0.0.0",
+	})
+	envFile := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(envFile, env, 0600); err != nil {
+		t.Fatalf("error when writing env file: %v", err)
+	}
+	os.Setenv("CONF_ENV_LOCATION", envFile)
+
+	db, err := sqlx.Open("mysql", connectionString)
+	if err != nil {
+		t.Fatalf("error when open mysql connection: %v", err)
+	}
+	defer db.Close()
+
+	merchantID := uuid.New().String()
+	consumerID := uuid.New().String()
+	transactionID := uuid.New().String()
+	externalToken, externalHash, externalPrefix, _ := library.NewAPIToken()
+	partnerToken, partnerHash, partnerPrefix, _ := library.NewAPIToken()
+
+	fixtures := []string{
+		fmt.Sprintf(`INSERT INTO merchants (id, name) VALUES ('%s', 'Test Merchant')`, merchantID),
+		fmt.Sprintf(`INSERT INTO consumers (id, NIK, full_name, legal_name, place_of_birth, date_of_birth, salary, ktp_img_key, selfie_img_key)
+      VALUES ('%s', '3171000000000001', 'Test Consumer', 'Test Consumer', 'Jakarta', '1990-01-01', '10000000', '', '')`, consumerID),
+		fmt.Sprintf(`INSERT INTO consumer_transactions (id, consumer_id, contract_number, OTR, admin_fee, installment_amount, loan_term, interest_amount, total_amount, asset_name, merchant_id, status_id)
+      VALUES ('%s', '%s', 'TEST-%s', 300000, 0, 300000, 1, 0, 300000, 'Test Asset', '%s', 'pending')`, transactionID, consumerID, transactionID[:8], merchantID),
+		fmt.Sprintf(`INSERT INTO api_client (name, token_hash, token_prefix) VALUES ('External', '%s', '%s')`, externalHash, externalPrefix),
+		fmt.Sprintf(`INSERT INTO api_client (name, token_hash, token_prefix, merchant_id) VALUES ('Test Partner', '%s', '%s', '%s')`, partnerHash, partnerPrefix, merchantID),
+		fmt.Sprintf(`INSERT INTO api_client_scopes (id, api_client_id, scope) SELECT UUID(), id, 'transactions:cancel' FROM api_client WHERE token_hash = '%s'`, partnerHash),
+	}
+	for _, query := range fixtures {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatalf("error when inserting fixture: %v", err)
+		}
+	}
+
+	defer func() {
+		db.Exec(`DELETE FROM user_actions WHERE ref_id = ?`, transactionID)
+		db.Exec(`DELETE FROM consumer_transaction_status_histories WHERE consumer_transaction_id = ?`, transactionID)
+		db.Exec(`DELETE FROM consumer_transactions WHERE id = ?`, transactionID)
+		db.Exec(`DELETE FROM api_client_scopes WHERE api_client_id IN (SELECT id FROM api_client WHERE token_hash IN (?, ?))`, externalHash, partnerHash)
+		db.Exec(`DELETE FROM api_client WHERE token_hash IN (?, ?)`, externalHash, partnerHash)
+		db.Exec(`DELETE FROM consumers WHERE id = ?`, consumerID)
+		db.Exec(`DELETE FROM merchants WHERE id = ?`, merchantID)
+	}()
+
+	var apiClientID int
+	if err := db.Get(&apiClientID, `SELECT id FROM api_client WHERE token_hash = ?`, partnerHash); err != nil {
+		t.Fatalf("error when reading api client: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	consumertransaction.ConsumerTransactionHandler{}.RegisterAPI(db, data.NewManager(db), router, router.Group("/external/v1"))
+
+	form := url.Values{"Reason": {"Returned at the dealer"}}
+	req := httptest.NewRequest(http.MethodPost, "/external/v1/consumers/transactions/"+transactionID+"/cancel", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+partnerToken)
+	req.Header.Set("Access-Token", "Bearer "+externalToken)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("cancel returned %d: %s", w.Code, w.Body.String())
+	}
+
+	var statusID, updatedBy string
+	if err := db.QueryRow(`SELECT status_id, updated_by FROM consumer_transactions WHERE id = ?`, transactionID).Scan(&statusID, &updatedBy); err != nil {
+		t.Fatalf("error when reading transaction: %v", err)
+	}
+
+	if statusID != "cancelled" {
+		t.Errorf("transaction status is %s, want cancelled", statusID)
+	}
+
+	if want := fmt.Sprintf("api-client:%d", apiClientID); updatedBy != want {
+		t.Errorf("transaction was updated by %s, want %s", updatedBy, want)
+	}
+}
@@ -1,6 +1,8 @@
 package external
 
 import (
+	http_consumercreditlimit "case-study-kredit-plus/src/app/external/consumercreditlimit"
+	http_consumerinstallment "case-study-kredit-plus/src/app/external/consumerinstallment"
 	http_consumertransaction "case-study-kredit-plus/src/app/external/consumertransaction"
 
 	"case-study-kredit-plus/library/data"
@@ -10,12 +12,16 @@ import (
 )
 
 var (
+	consumercreditlimitHandler http_consumercreditlimit.ConsumerCreditLimitHandler
+	consumerinstallmentHandler http_consumerinstallment.ConsumerInstallmentHandler
 	consumertransactionHandler http_consumertransaction.ConsumerTransactionHandler
 )
 
 func RegisterRoutes(db *sqlx.DB, dataManager *data.Manager, router *gin.Engine, v *gin.RouterGroup) {
 	v1 := v.Group("")
 	{
+		consumercreditlimitHandler.RegisterAPI(db, dataManager, router, v1)
+		consumerinstallmentHandler.RegisterAPI(db, dataManager, router, v1)
 		consumertransactionHandler.RegisterAPI(db, dataManager, router, v1)
 	}
 }
@@ -0,0 +1,26 @@
+package apiclient
+
+import (
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Repository is the contract between Repository and usecase
+type Repository interface {
+	FindAll(*gin.Context, models.FindAllAPIClientParams) ([]*models.APIClient, *types.Error)
+	Find(*gin.Context, int) (*models.APIClient, *types.Error)
+	Count(*gin.Context, models.FindAllAPIClientParams) (int, *types.Error)
+	Create(ctx *gin.Context, obj *models.APIClient, tokenHash string) (*models.APIClient, *types.Error)
+	Update(*gin.Context, *models.APIClient) (*models.APIClient, *types.Error)
+	UpdateToken(ctx *gin.Context, id int, tokenHash string, tokenPrefix string) *types.Error
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, int, string) (*models.APIClient, *types.Error)
+
+	// Scopes
+	FindAPIClientScopes(*gin.Context, int) ([]*models.APIClientScope, *types.Error)
+	CreateAPIClientScope(*gin.Context, *models.APIClientScope) (*models.APIClientScope, *types.Error)
+	DeleteAPIClientScopes(*gin.Context, int) *types.Error
+}
@@ -0,0 +1,432 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/appcontext"
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIClientRepository writes api_client with its own queries, the generic storage expects UUID primary keys
+type APIClientRepository struct {
+	repository               data.GenericStorage
+	statusRepository         data.GenericStorage
+	apiClientScopeRepository data.GenericStorage
+}
+
+func NewAPIClientRepository(repository data.GenericStorage, statusRepository data.GenericStorage, apiClientScopeRepository data.GenericStorage) APIClientRepository {
+	return APIClientRepository{repository: repository, statusRepository: statusRepository, apiClientScopeRepository: apiClientScopeRepository}
+}
+
+func (s APIClientRepository) FindAll(ctx *gin.Context, params models.FindAllAPIClientParams) ([]*models.APIClient, *types.Error) {
+	data := []*models.APIClient{}
+	bulks := []*models.APIClientBulk{}
+
+	var err error
+
+	where := `TRUE`
+
+	if params.FindAllParams.DataFinder != "" {
+		where += fmt.Sprintf(` AND %s`, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where += fmt.Sprintf(` AND api_client.%s`, params.FindAllParams.StatusID)
+	}
+
+	if params.Name != "" {
+		where += ` AND api_client.name LIKE CONCAT('%', :name, '%')`
+	}
+
+	if params.MerchantID != "" {
+		where += ` AND api_client.merchant_id = :merchant_id`
+	}
+
+	if params.FindAllParams.SortBy != "" {
+		where += fmt.Sprintf(` ORDER BY %s`, params.FindAllParams.SortBy)
+	}
+
+	if params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0 {
+		where += ` LIMIT :limit OFFSET :offset`
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    api_client.id, api_client.name, api_client.token_prefix, api_client.merchant_id, api_client.expires_at,
+    api_client.status_id, status.name status_name
+  FROM api_client
+  JOIN status ON api_client.status_id = status.id
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"limit":       params.FindAllParams.Size,
+		"offset":      ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"status_id":   params.FindAllParams.StatusID,
+		"name":        params.Name,
+		"merchant_id": params.MerchantID,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		data = append(data, apiClientFromBulk(v))
+	}
+
+	return data, nil
+}
+
+func (s APIClientRepository) Find(ctx *gin.Context, id int) (*models.APIClient, *types.Error) {
+	bulks := []*models.APIClientBulk{}
+	var err error
+
+	query := `
+  SELECT
+    api_client.id, api_client.name, api_client.token_prefix, api_client.merchant_id, api_client.expires_at,
+    api_client.status_id, status.name status_name
+  FROM api_client
+  JOIN status ON api_client.status_id = status.id
+  WHERE api_client.id = :id`
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(bulks) == 0 {
+		return nil, &types.Error{
+			Path:       ".APIClientStorage->Find()",
+			Message:    "Data Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return apiClientFromBulk(bulks[0]), nil
+}
+
+func (s APIClientRepository) Count(ctx *gin.Context, params models.FindAllAPIClientParams) (int, *types.Error) {
+	counts := []int{}
+
+	var err error
+
+	where := `TRUE`
+
+	if params.FindAllParams.DataFinder != "" {
+		where += fmt.Sprintf(` AND %s`, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where += fmt.Sprintf(` AND api_client.%s`, params.FindAllParams.StatusID)
+	}
+
+	if params.Name != "" {
+		where += ` AND api_client.name LIKE CONCAT('%', :name, '%')`
+	}
+
+	if params.MerchantID != "" {
+		where += ` AND api_client.merchant_id = :merchant_id`
+	}
+
+	query := fmt.Sprintf(`
+  SELECT COUNT(*)
+  FROM api_client
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &counts, query, map[string]interface{}{
+		"status_id":   params.FindAllParams.StatusID,
+		"name":        params.Name,
+		"merchant_id": params.MerchantID,
+	})
+	if err != nil {
+		return 0, &types.Error{
+			Path:       ".APIClientStorage->Count()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(counts) == 0 {
+		return 0, nil
+	}
+
+	return counts[0], nil
+}
+
+// Create inserts the client and reads back the id MySQL gave it, the token hash is unique enough to find it by
+func (s APIClientRepository) Create(ctx *gin.Context, obj *models.APIClient, tokenHash string) (*models.APIClient, *types.Error) {
+	now := library.UTCPlus7().Format(library.StrToTimestampFormat)
+
+	query := `
+  INSERT INTO api_client (name, token_hash, token_prefix, merchant_id, expires_at, status_id, created_at, created_by, updated_at, updated_by)
+  VALUES (:name, :token_hash, :token_prefix, :merchant_id, :expires_at, :status_id, :now, :user_id, :now, :user_id)`
+
+	err := s.repository.ExecQuery(ctx, query, map[string]interface{}{
+		"name":         obj.Name,
+		"token_hash":   tokenHash,
+		"token_prefix": obj.TokenPrefix,
+		"merchant_id":  obj.MerchantID,
+		"expires_at":   formatExpiresAt(obj.ExpiresAt),
+		"status_id":    obj.StatusID,
+		"now":          now,
+		"user_id":      *appcontext.UserID(ctx),
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	ids := []int{}
+	err = s.repository.SelectWithQuery(ctx, &ids, `SELECT api_client.id FROM api_client WHERE api_client.token_hash = :token_hash`, map[string]interface{}{
+		"token_hash": tokenHash,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(ids) != 1 {
+		return nil, &types.Error{
+			Path:       ".APIClientStorage->Create()",
+			Message:    "API Client Not Created",
+			Error:      fmt.Errorf("%d api clients hold the new token", len(ids)),
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	result, errFind := s.Find(ctx, ids[0])
+	if errFind != nil {
+		errFind.Path = ".APIClientStorage->Create()" + errFind.Path
+		return nil, errFind
+	}
+
+	return result, nil
+}
+
+// Update writes the name and the expiry, the token and the merchant are changed through their own methods
+func (s APIClientRepository) Update(ctx *gin.Context, obj *models.APIClient) (*models.APIClient, *types.Error) {
+	query := `
+  UPDATE api_client SET name = :name, expires_at = :expires_at, updated_at = :now, updated_by = :user_id
+  WHERE id = :id`
+
+	err := s.repository.ExecQuery(ctx, query, map[string]interface{}{
+		"id":         obj.ID,
+		"name":       obj.Name,
+		"expires_at": formatExpiresAt(obj.ExpiresAt),
+		"now":        library.UTCPlus7().Format(library.StrToTimestampFormat),
+		"user_id":    *appcontext.UserID(ctx),
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	result, errFind := s.Find(ctx, obj.ID)
+	if errFind != nil {
+		errFind.Path = ".APIClientStorage->Update()" + errFind.Path
+		return nil, errFind
+	}
+
+	return result, nil
+}
+
+func (s APIClientRepository) UpdateToken(ctx *gin.Context, id int, tokenHash string, tokenPrefix string) *types.Error {
+	query := `
+  UPDATE api_client SET token_hash = :token_hash, token_prefix = :token_prefix, updated_at = :now, updated_by = :user_id
+  WHERE id = :id`
+
+	err := s.repository.ExecQuery(ctx, query, map[string]interface{}{
+		"id":           id,
+		"token_hash":   tokenHash,
+		"token_prefix": tokenPrefix,
+		"now":          library.UTCPlus7().Format(library.StrToTimestampFormat),
+		"user_id":      *appcontext.UserID(ctx),
+	})
+	if err != nil {
+		return &types.Error{
+			Path:       ".APIClientStorage->UpdateToken()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
+
+func (s APIClientRepository) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	status := []*models.Status{}
+
+	err := s.statusRepository.Where(ctx, &status, "1=1", map[string]interface{}{})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientStorage->FindStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return status, nil
+}
+
+func (s APIClientRepository) UpdateStatus(ctx *gin.Context, id int, statusID string) (*models.APIClient, *types.Error) {
+	err := s.repository.UpdateStatus(ctx, strconv.Itoa(id), statusID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	result, errFind := s.Find(ctx, id)
+	if errFind != nil {
+		errFind.Path = ".APIClientStorage->UpdateStatus()" + errFind.Path
+		return nil, errFind
+	}
+
+	return result, nil
+}
+
+// SCOPES
+
+func (s APIClientRepository) FindAPIClientScopes(ctx *gin.Context, apiClientID int) ([]*models.APIClientScope, *types.Error) {
+	data := []*models.APIClientScope{}
+
+	query := `
+  SELECT
+    api_client_scopes.id, api_client_scopes.api_client_id, api_client_scopes.scope, api_client_scopes.status_id
+  FROM api_client_scopes
+  WHERE api_client_scopes.api_client_id = :api_client_id
+  ORDER BY api_client_scopes.scope`
+
+	err := s.apiClientScopeRepository.SelectWithQuery(ctx, &data, query, map[string]interface{}{
+		"api_client_id": apiClientID,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientStorage->FindAPIClientScopes()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return data, nil
+}
+
+func (s APIClientRepository) CreateAPIClientScope(ctx *gin.Context, obj *models.APIClientScope) (*models.APIClientScope, *types.Error) {
+	data := models.APIClientScope{}
+	_, err := s.apiClientScopeRepository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientStorage->CreateAPIClientScope()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.apiClientScopeRepository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientStorage->CreateAPIClientScope()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+func (s APIClientRepository) DeleteAPIClientScopes(ctx *gin.Context, apiClientID int) *types.Error {
+	query := `DELETE FROM api_client_scopes WHERE api_client_id = :api_client_id`
+
+	err := s.apiClientScopeRepository.ExecQuery(ctx, query, map[string]interface{}{
+		"api_client_id": apiClientID,
+	})
+	if err != nil {
+		return &types.Error{
+			Path:       ".APIClientStorage->DeleteAPIClientScopes()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
+
+func apiClientFromBulk(v *models.APIClientBulk) *models.APIClient {
+	return &models.APIClient{
+		ID:          v.ID,
+		Name:        v.Name,
+		TokenPrefix: v.TokenPrefix,
+		MerchantID:  v.MerchantID,
+		ExpiresAt:   v.ExpiresAt,
+		StatusID:    v.StatusID,
+		Status: models.Status{
+			ID:   v.StatusID,
+			Name: v.StatusName,
+		},
+	}
+}
+
+// formatExpiresAt writes the expiry as the wall clock time it was given in, like every other timestamp
+func formatExpiresAt(expiresAt *time.Time) interface{} {
+	if expiresAt == nil {
+		return nil
+	}
+
+	return expiresAt.Format(library.StrToTimestampFormat)
+}
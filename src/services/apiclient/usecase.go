@@ -0,0 +1,31 @@
+package apiclient
+
+import (
+	"time"
+
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Usecase is the contract between Repository and usecase
+type Usecase interface {
+	FindAll(*gin.Context, models.FindAllAPIClientParams) ([]*models.APIClient, *types.Error)
+	Find(*gin.Context, int) (*models.APIClient, *types.Error)
+	Count(*gin.Context, models.FindAllAPIClientParams) (int, *types.Error)
+	Create(*gin.Context, models.APIClient) (*models.APIClient, *types.Error)
+	Update(*gin.Context, int, models.APIClient) (*models.APIClient, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+
+	// Rotate replaces the token of the client, the old one stops working at once
+	Rotate(ctx *gin.Context, id int) (*models.APIClient, *types.Error)
+	// Expire sets the time the token stops working, nil keeps it working until it is revoked
+	Expire(ctx *gin.Context, id int, expiresAt *time.Time) (*models.APIClient, *types.Error)
+	// Revoke stops the client for good
+	Revoke(ctx *gin.Context, id int) (*models.APIClient, *types.Error)
+
+	// Scopes
+	FindScopes(*gin.Context) []string
+}
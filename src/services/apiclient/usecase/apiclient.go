@@ -0,0 +1,383 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/src/services/apiclient"
+
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"github.com/jmoiron/sqlx"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+type APIClientUsecase struct {
+	apiclientRepo  apiclient.Repository
+	contextTimeout time.Duration
+	db             *sqlx.DB
+}
+
+func NewAPIClientUsecase(db *sqlx.DB, apiclientRepo apiclient.Repository) apiclient.Usecase {
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	return &APIClientUsecase{
+		apiclientRepo:  apiclientRepo,
+		contextTimeout: timeoutContext,
+		db:             db,
+	}
+}
+
+func (u *APIClientUsecase) FindAll(ctx *gin.Context, params models.FindAllAPIClientParams) ([]*models.APIClient, *types.Error) {
+	result, err := u.apiclientRepo.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".APIClientUsecase->FindAll()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *APIClientUsecase) Find(ctx *gin.Context, id int) (*models.APIClient, *types.Error) {
+	result, err := u.apiclientRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	result.Scopes, err = u.apiclientRepo.FindAPIClientScopes(ctx, id)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *APIClientUsecase) Count(ctx *gin.Context, params models.FindAllAPIClientParams) (int, *types.Error) {
+	result, err := u.apiclientRepo.Count(ctx, params)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Count()" + err.Path
+		return 0, err
+	}
+
+	return result, nil
+}
+
+// Create makes the client with a new token, which is in the result and cannot be read again
+func (u *APIClientUsecase) Create(ctx *gin.Context, obj models.APIClient) (*models.APIClient, *types.Error) {
+	err := u.validate(obj)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	token, hash, prefix, errToken := library.NewAPIToken()
+	if errToken != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientUsecase->Create()",
+			Message:    "Error Generating Token",
+			Error:      errToken,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	data := models.APIClient{
+		Name:        obj.Name,
+		TokenPrefix: prefix,
+		ExpiresAt:   obj.ExpiresAt,
+		StatusID:    models.DEFAULT_STATUS_ID,
+	}
+
+	result, err := u.apiclientRepo.Create(ctx, &data, hash)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	result.Scopes, err = u.createScopes(ctx, result.ID, obj.Scopes)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	result.Token = token
+
+	return result, nil
+}
+
+// Update replaces the name, the expiry and the scopes of the client, its next request is checked against them
+func (u *APIClientUsecase) Update(ctx *gin.Context, id int, obj models.APIClient) (*models.APIClient, *types.Error) {
+	err := u.validate(obj)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	data, err := u.findActive(ctx, id)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	data.Name = obj.Name
+	data.ExpiresAt = obj.ExpiresAt
+
+	result, err := u.apiclientRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	err = u.apiclientRepo.DeleteAPIClientScopes(ctx, id)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	result.Scopes, err = u.createScopes(ctx, id, obj.Scopes)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *APIClientUsecase) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	result, err := u.apiclientRepo.FindStatus(ctx)
+	if err != nil {
+		err.Path = ".APIClientUsecase->FindStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *APIClientUsecase) Rotate(ctx *gin.Context, id int) (*models.APIClient, *types.Error) {
+	_, err := u.findActive(ctx, id)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Rotate()" + err.Path
+		return nil, err
+	}
+
+	token, hash, prefix, errToken := library.NewAPIToken()
+	if errToken != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientUsecase->Rotate()",
+			Message:    "Error Generating Token",
+			Error:      errToken,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	err = u.apiclientRepo.UpdateToken(ctx, id, hash, prefix)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Rotate()" + err.Path
+		return nil, err
+	}
+
+	result, err := u.Find(ctx, id)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Rotate()" + err.Path
+		return nil, err
+	}
+
+	result.Token = token
+
+	return result, nil
+}
+
+func (u *APIClientUsecase) Expire(ctx *gin.Context, id int, expiresAt *time.Time) (*models.APIClient, *types.Error) {
+	err := u.validateExpiresAt(expiresAt)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Expire()" + err.Path
+		return nil, err
+	}
+
+	data, err := u.findActive(ctx, id)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Expire()" + err.Path
+		return nil, err
+	}
+
+	data.ExpiresAt = expiresAt
+
+	_, err = u.apiclientRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Expire()" + err.Path
+		return nil, err
+	}
+
+	result, err := u.Find(ctx, id)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Expire()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Revoke deactivates the client and forgets its token hash, so it cannot be switched back on by mistake
+func (u *APIClientUsecase) Revoke(ctx *gin.Context, id int) (*models.APIClient, *types.Error) {
+	data, err := u.findActive(ctx, id)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Revoke()" + err.Path
+		return nil, err
+	}
+
+	err = u.apiclientRepo.UpdateToken(ctx, id, "", data.TokenPrefix)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Revoke()" + err.Path
+		return nil, err
+	}
+
+	_, err = u.apiclientRepo.UpdateStatus(ctx, id, models.STATUS_INACTIVE)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Revoke()" + err.Path
+		return nil, err
+	}
+
+	result, err := u.Find(ctx, id)
+	if err != nil {
+		err.Path = ".APIClientUsecase->Revoke()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SCOPES
+
+func (u *APIClientUsecase) FindScopes(ctx *gin.Context) []string {
+	return models.API_CLIENT_SCOPES
+}
+
+// findActive returns the client if it was not revoked
+func (u *APIClientUsecase) findActive(ctx *gin.Context, id int) (*models.APIClient, *types.Error) {
+	result, err := u.apiclientRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".APIClientUsecase->findActive()" + err.Path
+		return nil, err
+	}
+
+	if result.StatusID != models.STATUS_ACTIVE {
+		return nil, &types.Error{
+			Path:       ".APIClientUsecase->findActive()",
+			Message:    "API Client Is Revoked",
+			Error:      fmt.Errorf("API Client Is Revoked"),
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	return result, nil
+}
+
+func (u *APIClientUsecase) validate(obj models.APIClient) *types.Error {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(obj)
+	if errValidation != nil {
+		return &types.Error{
+			Path:       ".APIClientUsecase->validate()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	err := u.validateExpiresAt(obj.ExpiresAt)
+	if err != nil {
+		err.Path = ".APIClientUsecase->validate()" + err.Path
+		return err
+	}
+
+	// scopes are a fixed list, every external route checks one of them
+	known := map[string]bool{}
+	for _, v := range models.API_CLIENT_SCOPES {
+		known[v] = true
+	}
+
+	seen := map[string]bool{}
+	for _, v := range obj.Scopes {
+		if !known[v.Scope] {
+			return &types.Error{
+				Path:       ".APIClientUsecase->validate()",
+				Message:    "Unknown Scope",
+				Error:      fmt.Errorf("unknown scope %s", v.Scope),
+				StatusCode: http.StatusUnprocessableEntity,
+				Type:       "validation-error",
+			}
+		}
+
+		if seen[v.Scope] {
+			return &types.Error{
+				Path:       ".APIClientUsecase->validate()",
+				Message:    "Duplicate Scope",
+				Error:      fmt.Errorf("duplicate scope %s", v.Scope),
+				StatusCode: http.StatusUnprocessableEntity,
+				Type:       "validation-error",
+			}
+		}
+		seen[v.Scope] = true
+	}
+
+	return nil
+}
+
+func (u *APIClientUsecase) validateExpiresAt(expiresAt *time.Time) *types.Error {
+	if expiresAt != nil && !expiresAt.After(library.UTCPlus7()) {
+		return &types.Error{
+			Path:       ".APIClientUsecase->validateExpiresAt()",
+			Message:    "Expires At Must Be In The Future",
+			Error:      fmt.Errorf("expires at must be in the future"),
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	return nil
+}
+
+func (u *APIClientUsecase) createScopes(ctx *gin.Context, apiClientID int, scopes []*models.APIClientScope) ([]*models.APIClientScope, *types.Error) {
+	for _, v := range scopes {
+		data := models.APIClientScope{
+			ID:          uuid.New().String(),
+			APIClientID: apiClientID,
+			Scope:       v.Scope,
+			StatusID:    models.DEFAULT_STATUS_ID,
+		}
+
+		_, err := u.apiclientRepo.CreateAPIClientScope(ctx, &data)
+		if err != nil {
+			err.Path = ".APIClientUsecase->createScopes()" + err.Path
+			return nil, err
+		}
+	}
+
+	result, err := u.apiclientRepo.FindAPIClientScopes(ctx, apiClientID)
+	if err != nil {
+		err.Path = ".APIClientUsecase->createScopes()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,21 @@
+package apiclientconsumer
+
+import (
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Repository is the contract between Repository and usecase
+type Repository interface {
+	FindAll(*gin.Context, models.FindAllAPIClientConsumerParams) ([]*models.APIClientConsumer, *types.Error)
+	Find(*gin.Context, string) (*models.APIClientConsumer, *types.Error)
+	Count(*gin.Context, models.FindAllAPIClientConsumerParams) (int, *types.Error)
+	Create(*gin.Context, *models.APIClientConsumer) (*models.APIClientConsumer, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, string, string) (*models.APIClientConsumer, *types.Error)
+
+	FindAPIClient(*gin.Context, int) (*models.INTIDNameTemplate, *types.Error)
+}
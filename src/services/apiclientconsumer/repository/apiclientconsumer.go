@@ -0,0 +1,317 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type APIClientConsumerRepository struct {
+	repository       data.GenericStorage
+	statusRepository data.GenericStorage
+}
+
+func NewAPIClientConsumerRepository(repository data.GenericStorage, statusRepository data.GenericStorage) APIClientConsumerRepository {
+	return APIClientConsumerRepository{repository: repository, statusRepository: statusRepository}
+}
+
+func (s APIClientConsumerRepository) FindAll(ctx *gin.Context, params models.FindAllAPIClientConsumerParams) ([]*models.APIClientConsumer, *types.Error) {
+	data := []*models.APIClientConsumer{}
+	bulks := []*models.APIClientConsumerBulk{}
+
+	var err error
+
+	where := `TRUE`
+
+	if params.FindAllParams.DataFinder != "" {
+		where += fmt.Sprintf(` AND %s`, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where += fmt.Sprintf(` AND api_client_consumers.%s`, params.FindAllParams.StatusID)
+	}
+
+	if params.APIClientID != 0 {
+		where += ` AND api_client_consumers.api_client_id = :api_client_id`
+	}
+
+	if params.ConsumerID != "" {
+		where += ` AND api_client_consumers.consumer_id = :consumer_id`
+	}
+
+	if params.FindAllParams.SortBy != "" {
+		where += fmt.Sprintf(` ORDER BY %s`, params.FindAllParams.SortBy)
+	}
+
+	if params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0 {
+		where += ` LIMIT :limit OFFSET :offset`
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    api_client_consumers.id, api_client_consumers.api_client_id, api_client_consumers.consumer_id,
+    api_client_consumers.status_id, status.name status_name,
+    api_client.name api_client_name, consumers.full_name consumer_name
+  FROM api_client_consumers
+  JOIN status ON api_client_consumers.status_id = status.id
+  JOIN api_client ON api_client.id = api_client_consumers.api_client_id
+  JOIN consumers ON consumers.id = api_client_consumers.consumer_id
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"limit":         params.FindAllParams.Size,
+		"offset":        ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"status_id":     params.FindAllParams.StatusID,
+		"api_client_id": params.APIClientID,
+		"consumer_id":   params.ConsumerID,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientConsumerStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		obj := &models.APIClientConsumer{
+			ID:          v.ID,
+			APIClientID: v.APIClientID,
+			ConsumerID:  v.ConsumerID,
+			StatusID:    v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+			APIClient: &models.INTIDNameTemplate{
+				ID:   v.APIClientID,
+				Name: v.APIClientName,
+			},
+			Consumer: &models.IDNameTemplate{
+				ID:   v.ConsumerID,
+				Name: v.ConsumerName,
+			},
+		}
+
+		data = append(data, obj)
+	}
+
+	return data, nil
+}
+
+func (s APIClientConsumerRepository) Find(ctx *gin.Context, id string) (*models.APIClientConsumer, *types.Error) {
+	result := models.APIClientConsumer{}
+	bulks := []*models.APIClientConsumerBulk{}
+	var err error
+
+	query := `
+  SELECT
+    api_client_consumers.id, api_client_consumers.api_client_id, api_client_consumers.consumer_id,
+    api_client_consumers.status_id, status.name status_name,
+    api_client.name api_client_name, consumers.full_name consumer_name
+  FROM api_client_consumers
+  JOIN status ON api_client_consumers.status_id = status.id
+  JOIN api_client ON api_client.id = api_client_consumers.api_client_id
+  JOIN consumers ON consumers.id = api_client_consumers.consumer_id
+  WHERE api_client_consumers.id = :id`
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientConsumerStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(bulks) > 0 {
+		v := bulks[0]
+		result = models.APIClientConsumer{
+			ID:          v.ID,
+			APIClientID: v.APIClientID,
+			ConsumerID:  v.ConsumerID,
+			StatusID:    v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+			APIClient: &models.INTIDNameTemplate{
+				ID:   v.APIClientID,
+				Name: v.APIClientName,
+			},
+			Consumer: &models.IDNameTemplate{
+				ID:   v.ConsumerID,
+				Name: v.ConsumerName,
+			},
+		}
+	} else {
+		return nil, &types.Error{
+			Path:       ".APIClientConsumerStorage->Find()",
+			Message:    "Data Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &result, nil
+}
+
+func (s APIClientConsumerRepository) Count(ctx *gin.Context, params models.FindAllAPIClientConsumerParams) (int, *types.Error) {
+	bulks := []*models.APIClientConsumerBulk{}
+
+	var err error
+
+	where := `TRUE`
+
+	if params.FindAllParams.DataFinder != "" {
+		where += fmt.Sprintf(` AND %s`, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where += fmt.Sprintf(` AND api_client_consumers.%s`, params.FindAllParams.StatusID)
+	}
+
+	if params.APIClientID != 0 {
+		where += ` AND api_client_consumers.api_client_id = :api_client_id`
+	}
+
+	if params.ConsumerID != "" {
+		where += ` AND api_client_consumers.consumer_id = :consumer_id`
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    api_client_consumers.id, api_client_consumers.api_client_id, api_client_consumers.consumer_id,
+    api_client_consumers.status_id, status.name status_name
+  FROM api_client_consumers
+  JOIN status ON api_client_consumers.status_id = status.id
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"status_id":     params.FindAllParams.StatusID,
+		"api_client_id": params.APIClientID,
+		"consumer_id":   params.ConsumerID,
+	})
+	if err != nil {
+		return 0, &types.Error{
+			Path:       ".APIClientConsumerStorage->Count()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return len(bulks), nil
+}
+
+func (s APIClientConsumerRepository) Create(ctx *gin.Context, obj *models.APIClientConsumer) (*models.APIClientConsumer, *types.Error) {
+	data := models.APIClientConsumer{}
+	_, err := s.repository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientConsumerStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientConsumerStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+func (s APIClientConsumerRepository) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	status := []*models.Status{}
+
+	err := s.statusRepository.Where(ctx, &status, "1=1", map[string]interface{}{})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientConsumerStorage->FindStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return status, nil
+}
+
+func (s APIClientConsumerRepository) UpdateStatus(ctx *gin.Context, id string, statusID string) (*models.APIClientConsumer, *types.Error) {
+	data := models.APIClientConsumer{}
+	err := s.repository.UpdateStatus(ctx, id, statusID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientConsumerStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, id)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientConsumerStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &data, nil
+}
+
+func (s APIClientConsumerRepository) FindAPIClient(ctx *gin.Context, id int) (*models.INTIDNameTemplate, *types.Error) {
+	rows := []*models.INTIDNameTemplate{}
+
+	query := `SELECT api_client.id, api_client.name FROM api_client WHERE api_client.id = :id`
+
+	err := s.repository.SelectWithQuery(ctx, &rows, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientConsumerStorage->FindAPIClient()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, &types.Error{
+			Path:       ".APIClientConsumerStorage->FindAPIClient()",
+			Message:    "Data Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return rows[0], nil
+}
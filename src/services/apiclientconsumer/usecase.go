@@ -0,0 +1,22 @@
+package apiclientconsumer
+
+import (
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Usecase is the contract between Repository and usecase
+type Usecase interface {
+	FindAll(*gin.Context, models.FindAllAPIClientConsumerParams) ([]*models.APIClientConsumer, *types.Error)
+	Find(*gin.Context, string) (*models.APIClientConsumer, *types.Error)
+	Count(*gin.Context, models.FindAllAPIClientConsumerParams) (int, *types.Error)
+	Create(*gin.Context, models.APIClientConsumer) (*models.APIClientConsumer, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, string, string) (*models.APIClientConsumer, *types.Error)
+
+	// Access
+	CheckAccess(ctx *gin.Context, apiClientID int, consumerID string) *types.Error
+}
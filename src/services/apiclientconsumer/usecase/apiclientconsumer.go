@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/src/services/apiclientconsumer"
+	"case-study-kredit-plus/src/services/consumer"
+
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"github.com/jmoiron/sqlx"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+type APIClientConsumerUsecase struct {
+	apiclientconsumerRepo apiclientconsumer.Repository
+	consumerUsecase       consumer.Usecase
+	contextTimeout        time.Duration
+	db                    *sqlx.DB
+}
+
+func NewAPIClientConsumerUsecase(db *sqlx.DB, apiclientconsumerRepo apiclientconsumer.Repository, consumerUsecase consumer.Usecase) apiclientconsumer.Usecase {
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	return &APIClientConsumerUsecase{
+		apiclientconsumerRepo: apiclientconsumerRepo,
+		consumerUsecase:       consumerUsecase,
+		contextTimeout:        timeoutContext,
+		db:                    db,
+	}
+}
+
+func (u *APIClientConsumerUsecase) FindAll(ctx *gin.Context, params models.FindAllAPIClientConsumerParams) ([]*models.APIClientConsumer, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(params)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientConsumerUsecase->FindAll()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.apiclientconsumerRepo.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".APIClientConsumerUsecase->FindAll()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *APIClientConsumerUsecase) Find(ctx *gin.Context, id string) (*models.APIClientConsumer, *types.Error) {
+	result, err := u.apiclientconsumerRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".APIClientConsumerUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *APIClientConsumerUsecase) Count(ctx *gin.Context, params models.FindAllAPIClientConsumerParams) (int, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(params)
+	if errValidation != nil {
+		return 0, &types.Error{
+			Path:       ".APIClientConsumerUsecase->Count()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.apiclientconsumerRepo.Count(ctx, params)
+	if err != nil {
+		err.Path = ".APIClientConsumerUsecase->Count()" + err.Path
+		return 0, err
+	}
+
+	return result, nil
+}
+
+// Create grants the API client access to the consumer, a revoked grant is switched back on instead of duplicated
+func (u *APIClientConsumerUsecase) Create(ctx *gin.Context, obj models.APIClientConsumer) (*models.APIClientConsumer, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".APIClientConsumerUsecase->Create()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	_, err := u.apiclientconsumerRepo.FindAPIClient(ctx, obj.APIClientID)
+	if err != nil {
+		err.Path = ".APIClientConsumerUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	_, err = u.consumerUsecase.Find(ctx, obj.ConsumerID)
+	if err != nil {
+		err.Path = ".APIClientConsumerUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	var params models.FindAllAPIClientConsumerParams
+	params.APIClientID = obj.APIClientID
+	params.ConsumerID = obj.ConsumerID
+
+	existing, err := u.apiclientconsumerRepo.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".APIClientConsumerUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	if len(existing) > 0 {
+		if existing[0].StatusID == models.STATUS_ACTIVE {
+			return nil, &types.Error{
+				Path:       ".APIClientConsumerUsecase->Create()",
+				Message:    "API Client Already Has Access To Consumer",
+				Error:      fmt.Errorf("API Client Already Has Access To Consumer"),
+				Type:       "validation-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+		}
+
+		result, err := u.apiclientconsumerRepo.UpdateStatus(ctx, existing[0].ID, models.STATUS_ACTIVE)
+		if err != nil {
+			err.Path = ".APIClientConsumerUsecase->Create()" + err.Path
+			return nil, err
+		}
+
+		return result, nil
+	}
+
+	data := models.APIClientConsumer{
+		ID:          uuid.New().String(),
+		APIClientID: obj.APIClientID,
+		ConsumerID:  obj.ConsumerID,
+		StatusID:    models.DEFAULT_STATUS_ID,
+	}
+
+	result, err := u.apiclientconsumerRepo.Create(ctx, &data)
+	if err != nil {
+		err.Path = ".APIClientConsumerUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *APIClientConsumerUsecase) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	result, err := u.apiclientconsumerRepo.FindStatus(ctx)
+	if err != nil {
+		err.Path = ".APIClientConsumerUsecase->FindStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *APIClientConsumerUsecase) UpdateStatus(ctx *gin.Context, id string, newStatusID string) (*models.APIClientConsumer, *types.Error) {
+	result, err := u.apiclientconsumerRepo.UpdateStatus(ctx, id, newStatusID)
+	if err != nil {
+		err.Path = ".APIClientConsumerUsecase->UpdateStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, err
+}
+
+// ACCESS
+
+// CheckAccess fails with 403 unless the API client holds an active grant for the consumer
+func (u *APIClientConsumerUsecase) CheckAccess(ctx *gin.Context, apiClientID int, consumerID string) *types.Error {
+	var params models.FindAllAPIClientConsumerParams
+	params.APIClientID = apiClientID
+	params.ConsumerID = consumerID
+	params.FindAllParams.StatusID = `status_id = "1"`
+
+	result, err := u.apiclientconsumerRepo.Count(ctx, params)
+	if err != nil {
+		err.Path = ".APIClientConsumerUsecase->CheckAccess()" + err.Path
+		return err
+	}
+
+	if apiClientID == 0 || result == 0 {
+		return &types.Error{
+			Path:       ".APIClientConsumerUsecase->CheckAccess()",
+			Message:    "Consumer Not Accessible",
+			Error:      fmt.Errorf("Consumer Not Accessible"),
+			Type:       "validation-error",
+			StatusCode: http.StatusForbidden,
+		}
+	}
+
+	return nil
+}
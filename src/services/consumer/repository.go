@@ -17,4 +17,8 @@ type Repository interface {
 
 	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
 	UpdateStatus(*gin.Context, string, string) (*models.Consumer, *types.Error)
+	Reencrypt(ctx *gin.Context, id string) (bool, *types.Error)
+
+	FindRegionCodes(ctx *gin.Context, provinceCode string) ([]string, *types.Error)
+	CreateDocumentAccessLog(ctx *gin.Context, consumerID string, documentType string, objectKey string) *types.Error
 }
@@ -3,8 +3,13 @@ package repository
 import (
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/appcontext"
 	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/fieldcrypt"
 	"case-study-kredit-plus/library/types"
 	"case-study-kredit-plus/models"
 
@@ -14,10 +19,11 @@ import (
 type ConsumerRepository struct {
 	repository       data.GenericStorage
 	statusRepository data.GenericStorage
+	cipher           *fieldcrypt.Cipher
 }
 
-func NewConsumerRepository(repository data.GenericStorage, statusRepository data.GenericStorage) ConsumerRepository {
-	return ConsumerRepository{repository: repository, statusRepository: statusRepository}
+func NewConsumerRepository(repository data.GenericStorage, statusRepository data.GenericStorage, cipher *fieldcrypt.Cipher) ConsumerRepository {
+	return ConsumerRepository{repository: repository, statusRepository: statusRepository, cipher: cipher}
 }
 
 func (s ConsumerRepository) FindAll(ctx *gin.Context, params models.FindAllConsumerParams) ([]*models.Consumer, *types.Error) {
@@ -37,7 +43,7 @@ func (s ConsumerRepository) FindAll(ctx *gin.Context, params models.FindAllConsu
 	}
 
 	if params.NIK != "" {
-		where += fmt.Sprintf(` AND consumers.NIK LIKE "%%%s%%"`, params.NIK)
+		where += ` AND consumers.nik_index = :nik_index`
 	}
 
 	if params.FullName != "" {
@@ -52,34 +58,20 @@ func (s ConsumerRepository) FindAll(ctx *gin.Context, params models.FindAllConsu
 		where += fmt.Sprintf(` AND consumers.place_of_birth LIKE "%%%s%%"`, params.PlaceOfBirth)
 	}
 
-	if params.MinDateOfBirth != "" {
-		where += fmt.Sprintf(` AND consumers.date_of_birth >= "%s"`, params.MinDateOfBirth)
-	}
-
-	if params.MaxDateOfBirth != "" {
-		where += fmt.Sprintf(` AND consumers.date_of_birth <= "%s"`, params.MaxDateOfBirth)
-	}
-
-	if params.MinSalary > 0 {
-		where += fmt.Sprintf(` AND consumers.salary >= %f`, params.MinSalary)
-	}
-
-	if params.MaxSalary > 0 {
-		where += fmt.Sprintf(` AND consumers.salary <= %f`, params.MaxSalary)
-	}
-
 	if params.FindAllParams.SortBy != "" {
 		where += fmt.Sprintf(` ORDER BY %s`, params.FindAllParams.SortBy)
 	}
 
-	if params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0 {
+	// date of birth and salary are encrypted, their ranges can only be applied after decryption
+	paginate := params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0
+	if paginate && !hasDecryptedFilters(params) {
 		where += ` LIMIT :limit OFFSET :offset`
 	}
 
 	query := fmt.Sprintf(`
   SELECT
     consumers.id, consumers.NIK, consumers.full_name, consumers.legal_name, consumers.place_of_birth, consumers.date_of_birth,
-    consumers.salary, consumers.ktp_img_url, consumers.selfie_img_url,
+    consumers.salary, consumers.ktp_img_key, consumers.selfie_img_key,
     consumers.status_id, status.name status_name
   FROM consumers
   JOIN status ON consumers.status_id = status.id
@@ -93,6 +85,7 @@ func (s ConsumerRepository) FindAll(ctx *gin.Context, params models.FindAllConsu
 		"offset":         ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
 		"status_id":      params.FindAllParams.StatusID,
 		"place_of_birth": params.PlaceOfBirth,
+		"nik_index":      s.cipher.BlindIndex(params.NIK),
 	})
 	if err != nil {
 		return nil, &types.Error{
@@ -105,38 +98,49 @@ func (s ConsumerRepository) FindAll(ctx *gin.Context, params models.FindAllConsu
 	}
 
 	for _, v := range bulks {
-		obj := &models.Consumer{
-			ID:           v.ID,
-			NIK:          v.NIK,
-			FullName:     v.FullName,
-			LegalName:    v.LegalName,
-			PlaceOfBirth: v.PlaceOfBirth,
-			DateOfBirth:  v.DateOfBirth,
-			Salary:       v.Salary,
-			KTPImgURL:    v.KTPImgURL,
-			SelfieImgURL: v.SelfieImgURL,
-			StatusID:     v.StatusID,
-			Status: models.Status{
-				ID:   v.StatusID,
-				Name: v.StatusName,
-			},
+		obj, errDecrypt := s.decrypt(v)
+		if errDecrypt != nil {
+			return nil, &types.Error{
+				Path:       ".ConsumerStorage->FindAll()",
+				Message:    errDecrypt.Error(),
+				Error:      errDecrypt,
+				StatusCode: http.StatusInternalServerError,
+				Type:       "encryption-error",
+			}
+		}
+
+		if !matchesDecryptedFilters(obj, params) {
+			continue
 		}
 
 		data = append(data, obj)
 	}
 
+	if paginate && hasDecryptedFilters(params) {
+		offset := (params.FindAllParams.Page - 1) * params.FindAllParams.Size
+		if offset > len(data) {
+			offset = len(data)
+		}
+
+		end := offset + params.FindAllParams.Size
+		if end > len(data) {
+			end = len(data)
+		}
+
+		data = data[offset:end]
+	}
+
 	return data, nil
 }
 
 func (s ConsumerRepository) Find(ctx *gin.Context, id string) (*models.Consumer, *types.Error) {
-	result := models.Consumer{}
 	bulks := []*models.ConsumerBulk{}
 	var err error
 
 	query := `
   SELECT
     consumers.id, consumers.NIK, consumers.full_name, consumers.legal_name, consumers.place_of_birth, consumers.date_of_birth,
-    consumers.salary, consumers.ktp_img_url, consumers.selfie_img_url,
+    consumers.salary, consumers.ktp_img_key, consumers.selfie_img_key,
     consumers.status_id, status.name status_name
   FROM consumers
   JOIN status ON consumers.status_id = status.id
@@ -153,25 +157,7 @@ func (s ConsumerRepository) Find(ctx *gin.Context, id string) (*models.Consumer,
 		}
 	}
 
-	if len(bulks) > 0 {
-		v := bulks[0]
-		result = models.Consumer{
-			ID:           v.ID,
-			NIK:          v.NIK,
-			FullName:     v.FullName,
-			LegalName:    v.LegalName,
-			PlaceOfBirth: v.PlaceOfBirth,
-			DateOfBirth:  v.DateOfBirth,
-			Salary:       v.Salary,
-			KTPImgURL:    v.KTPImgURL,
-			SelfieImgURL: v.SelfieImgURL,
-			StatusID:     v.StatusID,
-			Status: models.Status{
-				ID:   v.StatusID,
-				Name: v.StatusName,
-			},
-		}
-	} else {
+	if len(bulks) == 0 {
 		return nil, &types.Error{
 			Path:       ".ConsumerStorage->Find()",
 			Message:    "Data Not Found",
@@ -181,7 +167,18 @@ func (s ConsumerRepository) Find(ctx *gin.Context, id string) (*models.Consumer,
 		}
 	}
 
-	return &result, nil
+	result, err := s.decrypt(bulks[0])
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "encryption-error",
+		}
+	}
+
+	return result, nil
 }
 
 func (s ConsumerRepository) Count(ctx *gin.Context, params models.FindAllConsumerParams) (int, *types.Error) {
@@ -199,6 +196,10 @@ func (s ConsumerRepository) Count(ctx *gin.Context, params models.FindAllConsume
 		where += fmt.Sprintf(` AND consumers.%s`, params.FindAllParams.StatusID)
 	}
 
+	if params.NIK != "" {
+		where += ` AND consumers.nik_index = :nik_index`
+	}
+
 	if params.FullName != "" {
 		where += fmt.Sprintf(` AND consumers.full_name LIKE "%s%%"`, params.FullName)
 	}
@@ -211,26 +212,10 @@ func (s ConsumerRepository) Count(ctx *gin.Context, params models.FindAllConsume
 		where += ` AND consumers.place_of_birth = :place_of_birth`
 	}
 
-	if params.MinDateOfBirth != "" {
-		where += fmt.Sprintf(` AND consumers.date_of_birth >= "%s"`, params.MinDateOfBirth)
-	}
-
-	if params.MaxDateOfBirth != "" {
-		where += fmt.Sprintf(` AND consumers.date_of_birth <= "%s"`, params.MaxDateOfBirth)
-	}
-
-	if params.MinSalary >= -1 {
-		where += fmt.Sprintf(` AND consumers.salary >= "%s"`, params.MinSalary)
-	}
-
-	if params.MaxSalary >= -1 {
-		where += fmt.Sprintf(` AND consumers.salary <= "%s"`, params.MaxSalary)
-	}
-
 	query := fmt.Sprintf(`
   SELECT
     consumers.id, consumers.NIK, consumers.full_name, consumers.legal_name, consumers.place_of_birth, consumers.date_of_birth,
-    consumers.salary, consumers.ktp_img_url, consumers.selfie_img_url,
+    consumers.salary, consumers.ktp_img_key, consumers.selfie_img_key,
     consumers.status_id, status.name status_name
   FROM consumers
   JOIN status ON consumers.status_id = status.id
@@ -238,10 +223,9 @@ func (s ConsumerRepository) Count(ctx *gin.Context, params models.FindAllConsume
   `, where)
 
 	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
-		"limit":          params.FindAllParams.Size,
-		"offset":         ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
 		"status_id":      params.FindAllParams.StatusID,
 		"place_of_birth": params.PlaceOfBirth,
+		"nik_index":      s.cipher.BlindIndex(params.NIK),
 	})
 	if err != nil {
 		return 0, &types.Error{
@@ -253,23 +237,44 @@ func (s ConsumerRepository) Count(ctx *gin.Context, params models.FindAllConsume
 		}
 	}
 
-	return len(bulks), nil
+	if !hasDecryptedFilters(params) {
+		return len(bulks), nil
+	}
+
+	count := 0
+	for _, v := range bulks {
+		obj, errDecrypt := s.decrypt(v)
+		if errDecrypt != nil {
+			return 0, &types.Error{
+				Path:       ".ConsumerStorage->Count()",
+				Message:    errDecrypt.Error(),
+				Error:      errDecrypt,
+				StatusCode: http.StatusInternalServerError,
+				Type:       "encryption-error",
+			}
+		}
+
+		if matchesDecryptedFilters(obj, params) {
+			count++
+		}
+	}
+
+	return count, nil
 }
 
 func (s ConsumerRepository) Create(ctx *gin.Context, obj *models.Consumer) (*models.Consumer, *types.Error) {
-	data := models.Consumer{}
-	_, err := s.repository.Insert(ctx, obj)
+	row, err := s.encrypt(obj)
 	if err != nil {
 		return nil, &types.Error{
 			Path:       ".ConsumerStorage->Create()",
 			Message:    err.Error(),
 			Error:      err,
 			StatusCode: http.StatusInternalServerError,
-			Type:       "mysql-error",
+			Type:       "encryption-error",
 		}
 	}
 
-	err = s.repository.FindByID(ctx, &data, obj.ID)
+	_, err = s.repository.Insert(ctx, row)
 	if err != nil {
 		return nil, &types.Error{
 			Path:       ".ConsumerStorage->Create()",
@@ -279,23 +284,29 @@ func (s ConsumerRepository) Create(ctx *gin.Context, obj *models.Consumer) (*mod
 			Type:       "mysql-error",
 		}
 	}
-	return &data, nil
+
+	result, errFind := s.Find(ctx, obj.ID)
+	if errFind != nil {
+		errFind.Path = ".ConsumerStorage->Create()" + errFind.Path
+		return nil, errFind
+	}
+
+	return result, nil
 }
 
 func (s ConsumerRepository) Update(ctx *gin.Context, obj *models.Consumer) (*models.Consumer, *types.Error) {
-	data := models.Consumer{}
-	err := s.repository.Update(ctx, obj)
+	row, err := s.encrypt(obj)
 	if err != nil {
 		return nil, &types.Error{
 			Path:       ".ConsumerStorage->Update()",
 			Message:    err.Error(),
 			Error:      err,
 			StatusCode: http.StatusInternalServerError,
-			Type:       "mysql-error",
+			Type:       "encryption-error",
 		}
 	}
 
-	err = s.repository.FindByID(ctx, &data, obj.ID)
+	err = s.repository.Update(ctx, row)
 	if err != nil {
 		return nil, &types.Error{
 			Path:       ".ConsumerStorage->Update()",
@@ -305,7 +316,14 @@ func (s ConsumerRepository) Update(ctx *gin.Context, obj *models.Consumer) (*mod
 			Type:       "mysql-error",
 		}
 	}
-	return &data, nil
+
+	result, errFind := s.Find(ctx, obj.ID)
+	if errFind != nil {
+		errFind.Path = ".ConsumerStorage->Update()" + errFind.Path
+		return nil, errFind
+	}
+
+	return result, nil
 }
 
 func (s ConsumerRepository) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
@@ -326,7 +344,6 @@ func (s ConsumerRepository) FindStatus(ctx *gin.Context) ([]*models.Status, *typ
 }
 
 func (s ConsumerRepository) UpdateStatus(ctx *gin.Context, id string, statusID string) (*models.Consumer, *types.Error) {
-	data := models.Consumer{}
 	err := s.repository.UpdateStatus(ctx, id, statusID)
 	if err != nil {
 		return nil, &types.Error{
@@ -338,10 +355,93 @@ func (s ConsumerRepository) UpdateStatus(ctx *gin.Context, id string, statusID s
 		}
 	}
 
-	err = s.repository.FindByID(ctx, &data, id)
+	result, errFind := s.Find(ctx, id)
+	if errFind != nil {
+		errFind.Path = ".ConsumerStorage->UpdateStatus()" + errFind.Path
+		return nil, errFind
+	}
+
+	return result, nil
+}
+
+// Reencrypt writes the row again under the current key and blind index key.
+// Rows that are already current are left alone, it reports whether the row was written.
+func (s ConsumerRepository) Reencrypt(ctx *gin.Context, id string) (bool, *types.Error) {
+	row := models.ConsumerEncrypted{}
+	err := s.repository.FindByID(ctx, &row, id)
+	if err != nil {
+		return false, &types.Error{
+			Path:       ".ConsumerStorage->Reencrypt()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	obj, errFind := s.Find(ctx, id)
+	if errFind != nil {
+		errFind.Path = ".ConsumerStorage->Reencrypt()" + errFind.Path
+		return false, errFind
+	}
+
+	current := row.NIKIndex == s.cipher.BlindIndex(obj.NIK)
+	for _, value := range []string{row.NIK, row.DateOfBirth, row.Salary, row.KTPImgKey, row.SelfieImgKey} {
+		current = current && s.cipher.IsCurrent(value)
+	}
+	if current {
+		return false, nil
+	}
+
+	_, errUpdate := s.Update(ctx, obj)
+	if errUpdate != nil {
+		errUpdate.Path = ".ConsumerStorage->Reencrypt()" + errUpdate.Path
+		return false, errUpdate
+	}
+
+	return true, nil
+}
+
+// CreateDocumentAccessLog records who fetched which identity document and from where
+func (s ConsumerRepository) CreateDocumentAccessLog(ctx *gin.Context, consumerID string, documentType string, objectKey string) *types.Error {
+	query := `
+  INSERT INTO consumer_document_access_logs(id, consumer_id, document_type, object_key, user_id, ip_address, accessed_at)
+  VALUES (UUID(), :consumer_id, :document_type, :object_key, :user_id, :ip_address, :accessed_at)`
+
+	err := s.repository.ExecQuery(ctx, query, map[string]interface{}{
+		"consumer_id":   consumerID,
+		"document_type": documentType,
+		"object_key":    objectKey,
+		"user_id":       *appcontext.UserID(ctx),
+		"ip_address":    ctx.ClientIP(),
+		"accessed_at":   library.UTCPlus7().Format("2006-01-02 15:04:05"),
+	})
+	if err != nil {
+		return &types.Error{
+			Path:       ".ConsumerStorage->CreateDocumentAccessLog()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
+
+// FindRegionCodes lists the reference region codes of a province, the province itself included
+func (s ConsumerRepository) FindRegionCodes(ctx *gin.Context, provinceCode string) ([]string, *types.Error) {
+	codes := []string{}
+
+	query := `
+  SELECT regions.code
+  FROM regions
+  WHERE regions.code LIKE CONCAT(:province_code, "%")`
+
+	err := s.repository.SelectWithQuery(ctx, &codes, query, map[string]interface{}{"province_code": provinceCode})
 	if err != nil {
 		return nil, &types.Error{
-			Path:       ".ConsumerStorage->UpdateStatus()",
+			Path:       ".ConsumerStorage->FindRegionCodes()",
 			Message:    err.Error(),
 			Error:      err,
 			StatusCode: http.StatusInternalServerError,
@@ -349,5 +449,100 @@ func (s ConsumerRepository) UpdateStatus(ctx *gin.Context, id string, statusID s
 		}
 	}
 
-	return &data, nil
+	return codes, nil
+}
+
+// encrypt turns the consumer into the row stored, with the sensitive columns encrypted and the NIK blind index set
+func (s ConsumerRepository) encrypt(obj *models.Consumer) (*models.ConsumerEncrypted, error) {
+	row := &models.ConsumerEncrypted{
+		ID:           obj.ID,
+		NIKIndex:     s.cipher.BlindIndex(obj.NIK),
+		FullName:     obj.FullName,
+		LegalName:    obj.LegalName,
+		PlaceOfBirth: obj.PlaceOfBirth,
+		StatusID:     obj.StatusID,
+	}
+
+	plaintexts := map[*string]string{
+		&row.NIK:          obj.NIK,
+		&row.DateOfBirth:  obj.DateOfBirth.Format(library.StrToDateFormat),
+		&row.Salary:       strconv.FormatFloat(obj.Salary, 'f', 2, 64),
+		&row.KTPImgKey:    obj.KTPImgKey,
+		&row.SelfieImgKey: obj.SelfieImgKey,
+	}
+
+	for field, plaintext := range plaintexts {
+		encrypted, err := s.cipher.Encrypt(plaintext)
+		if err != nil {
+			return nil, err
+		}
+		*field = encrypted
+	}
+
+	return row, nil
+}
+
+// decrypt turns a stored row back into the consumer, rows written before encryption are read as they are
+func (s ConsumerRepository) decrypt(v *models.ConsumerBulk) (*models.Consumer, error) {
+	for _, field := range []*string{&v.NIK, &v.DateOfBirth, &v.Salary, &v.KTPImgKey, &v.SelfieImgKey} {
+		plaintext, err := s.cipher.Decrypt(*field)
+		if err != nil {
+			return nil, fmt.Errorf("consumer %s: %v", v.ID, err)
+		}
+		*field = plaintext
+	}
+
+	dateOfBirth, err := time.Parse(library.StrToDateFormat, v.DateOfBirth)
+	if err != nil {
+		return nil, fmt.Errorf("consumer %s: date of birth cannot be read", v.ID)
+	}
+
+	salary, err := strconv.ParseFloat(v.Salary, 64)
+	if err != nil {
+		return nil, fmt.Errorf("consumer %s: salary cannot be read", v.ID)
+	}
+
+	return &models.Consumer{
+		ID:           v.ID,
+		NIK:          v.NIK,
+		FullName:     v.FullName,
+		LegalName:    v.LegalName,
+		PlaceOfBirth: v.PlaceOfBirth,
+		DateOfBirth:  dateOfBirth,
+		Salary:       salary,
+		KTPImgKey:    v.KTPImgKey,
+		SelfieImgKey: v.SelfieImgKey,
+		StatusID:     v.StatusID,
+		Status: models.Status{
+			ID:   v.StatusID,
+			Name: v.StatusName,
+		},
+	}, nil
+}
+
+func hasDecryptedFilters(params models.FindAllConsumerParams) bool {
+	return params.MinDateOfBirth != "" || params.MaxDateOfBirth != "" || params.MinSalary > 0 || params.MaxSalary > 0
+}
+
+// matchesDecryptedFilters applies the date of birth and salary ranges, the dates compare as yyyy-mm-dd strings
+func matchesDecryptedFilters(obj *models.Consumer, params models.FindAllConsumerParams) bool {
+	dateOfBirth := obj.DateOfBirth.Format(library.StrToDateFormat)
+
+	if params.MinDateOfBirth != "" && dateOfBirth < params.MinDateOfBirth {
+		return false
+	}
+
+	if params.MaxDateOfBirth != "" && dateOfBirth > params.MaxDateOfBirth {
+		return false
+	}
+
+	if params.MinSalary > 0 && obj.Salary < params.MinSalary {
+		return false
+	}
+
+	if params.MaxSalary > 0 && obj.Salary > params.MaxSalary {
+		return false
+	}
+
+	return true
 }
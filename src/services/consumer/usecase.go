@@ -17,4 +17,10 @@ type Usecase interface {
 
 	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
 	UpdateStatus(*gin.Context, string, string) (*models.Consumer, *types.Error)
+	Reencrypt(ctx *gin.Context, id string) (bool, *types.Error)
+
+	// Documents
+	UploadDocument(ctx *gin.Context, id string, documentType string, body []byte) (*models.Consumer, *types.Error)
+	FindDocument(ctx *gin.Context, id string, documentType string) ([]byte, string, *types.Error)
+	PrivatizeDocuments(ctx *gin.Context, id string) *types.Error
 }
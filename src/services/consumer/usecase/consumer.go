@@ -7,6 +7,8 @@ import (
 	"strings"
 	"time"
 
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/filestorage"
 	"case-study-kredit-plus/library/types"
 	"case-study-kredit-plus/src/services/consumer"
 
@@ -22,15 +24,17 @@ import (
 
 type ConsumerUsecase struct {
 	consumerRepo   consumer.Repository
+	fileStorage    filestorage.Storage
 	contextTimeout time.Duration
 	db             *sqlx.DB
 }
 
-func NewConsumerUsecase(db *sqlx.DB, consumerRepo consumer.Repository) consumer.Usecase {
+func NewConsumerUsecase(db *sqlx.DB, consumerRepo consumer.Repository, fileStorage filestorage.Storage) consumer.Usecase {
 	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
 
 	return &ConsumerUsecase{
 		consumerRepo:   consumerRepo,
+		fileStorage:    fileStorage,
 		contextTimeout: timeoutContext,
 		db:             db,
 	}
@@ -127,6 +131,12 @@ func (u *ConsumerUsecase) Create(ctx *gin.Context, obj models.Consumer) (*models
 		}
 	}
 
+	err := u.validateNIK(ctx, obj)
+	if err != nil {
+		err.Path = ".ConsumerUsecase->Create()" + err.Path
+		return nil, err
+	}
+
 	// check duplicate NIK
 	var dupeParams models.FindAllConsumerParams
 	dupeParams.NIK = obj.NIK
@@ -154,8 +164,6 @@ func (u *ConsumerUsecase) Create(ctx *gin.Context, obj models.Consumer) (*models
 		PlaceOfBirth: obj.PlaceOfBirth,
 		DateOfBirth:  obj.DateOfBirth,
 		Salary:       obj.Salary,
-		KTPImgURL:    obj.KTPImgURL,
-		SelfieImgURL: obj.SelfieImgURL,
 		StatusID:     models.DEFAULT_STATUS_ID,
 	}
 
@@ -198,6 +206,12 @@ func (u *ConsumerUsecase) Update(ctx *gin.Context, id string, obj models.Consume
 		}
 	}
 
+	err := u.validateNIK(ctx, obj)
+	if err != nil {
+		err.Path = ".ConsumerUsecase->Update()" + err.Path
+		return nil, err
+	}
+
 	// check duplicate NIK
 	var dupeParams models.FindAllConsumerParams
 	dupeParams.NIK = obj.NIK
@@ -230,8 +244,6 @@ func (u *ConsumerUsecase) Update(ctx *gin.Context, id string, obj models.Consume
 	data.PlaceOfBirth = obj.PlaceOfBirth
 	data.DateOfBirth = obj.DateOfBirth
 	data.Salary = obj.Salary
-	data.KTPImgURL = obj.KTPImgURL
-	data.SelfieImgURL = obj.SelfieImgURL
 
 	result, err := u.consumerRepo.Update(ctx, data)
 	if err != nil {
@@ -261,3 +273,246 @@ func (u *ConsumerUsecase) UpdateStatus(ctx *gin.Context, id string, newStatusID
 
 	return result, err
 }
+
+// Reencrypt moves the consumer to the current encryption keys, it reports whether the record had to be written
+func (u *ConsumerUsecase) Reencrypt(ctx *gin.Context, id string) (bool, *types.Error) {
+	written, err := u.consumerRepo.Reencrypt(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerUsecase->Reencrypt()" + err.Path
+		return false, err
+	}
+
+	return written, nil
+}
+
+// validateNIK takes the NIK apart, checks its region against the reference table and its birth date against
+// DateOfBirth. Every problem found is reported on its own field.
+func (u *ConsumerUsecase) validateNIK(ctx *gin.Context, obj models.Consumer) *types.Error {
+	nik, errNIK := library.ParseNIK(obj.NIK)
+	if errNIK != nil {
+		return &types.Error{
+			Path:       ".ConsumerUsecase->validateNIK()",
+			Message:    errNIK.Error(),
+			Error:      types.FieldErrors{"NIK": errNIK.Error()},
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	codes, err := u.consumerRepo.FindRegionCodes(ctx, nik.ProvinceCode)
+	if err != nil {
+		err.Path = ".ConsumerUsecase->validateNIK()" + err.Path
+		return err
+	}
+
+	// the table may hold provinces only, regencies and districts are checked once the province has them listed
+	known := map[string]bool{}
+	hasRegencies, hasDistricts := false, false
+	for _, code := range codes {
+		known[code] = true
+		if len(code) == 4 {
+			hasRegencies = true
+		}
+		if len(code) == 6 && code[0:4] == nik.RegencyCode {
+			hasDistricts = true
+		}
+	}
+
+	fields := types.FieldErrors{}
+	if !known[nik.ProvinceCode] {
+		fields["NIK"] = fmt.Sprintf("province code %s is not a known region", nik.ProvinceCode)
+	} else if hasRegencies && !known[nik.RegencyCode] {
+		fields["NIK"] = fmt.Sprintf("regency code %s is not a known region", nik.RegencyCode)
+	} else if hasDistricts && !known[nik.DistrictCode] {
+		fields["NIK"] = fmt.Sprintf("district code %s is not a known region", nik.DistrictCode)
+	}
+
+	if !obj.DateOfBirth.IsZero() && !nik.MatchesDateOfBirth(obj.DateOfBirth) {
+		fields["DateOfBirth"] = "does not match the date of birth in the NIK"
+	}
+
+	if len(fields) > 0 {
+		return &types.Error{
+			Path:       ".ConsumerUsecase->validateNIK()",
+			Message:    "NIK does not match the consumer data",
+			Error:      fields,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	return nil
+}
+
+// DOCUMENTS
+
+// UploadDocument cleans the KTP or selfie image, stores it and points the consumer at its key.
+// Earlier uploads are kept in storage, only the record moves to the new key.
+func (u *ConsumerUsecase) UploadDocument(ctx *gin.Context, id string, documentType string, body []byte) (*models.Consumer, *types.Error) {
+	if documentType != models.CONSUMER_DOCUMENT_KTP && documentType != models.CONSUMER_DOCUMENT_SELFIE {
+		return nil, &types.Error{
+			Path:       ".ConsumerUsecase->UploadDocument()",
+			Message:    "Document Type Invalid",
+			Error:      fmt.Errorf("Document Type Invalid"),
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	data, err := u.consumerRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerUsecase->UploadDocument()" + err.Path
+		return nil, err
+	}
+
+	cleaned, contentType, errImage := filestorage.SanitizeImage(body)
+	if errImage != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerUsecase->UploadDocument()",
+			Message:    errImage.Error(),
+			Error:      errImage,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	key := fmt.Sprintf("consumers/%s/%s/%s.%s", data.ID, strings.ToLower(documentType), uuid.New().String(), filestorage.IMAGE_EXTENSIONS[contentType])
+
+	errStorage := u.fileStorage.Put(ctx, key, cleaned, contentType)
+	if errStorage != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerUsecase->UploadDocument()",
+			Message:    "Failed To Store Document",
+			Error:      errStorage,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "storage-error",
+		}
+	}
+
+	if documentType == models.CONSUMER_DOCUMENT_KTP {
+		data.KTPImgKey = key
+	} else {
+		data.SelfieImgKey = key
+	}
+
+	result, err := u.consumerRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".ConsumerUsecase->UploadDocument()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindDocument returns the KTP or selfie image of the consumer and its content type.
+// Documents are private, every read goes through here and is written to the access log.
+func (u *ConsumerUsecase) FindDocument(ctx *gin.Context, id string, documentType string) ([]byte, string, *types.Error) {
+	if documentType != models.CONSUMER_DOCUMENT_KTP && documentType != models.CONSUMER_DOCUMENT_SELFIE {
+		return nil, "", &types.Error{
+			Path:       ".ConsumerUsecase->FindDocument()",
+			Message:    "Document Type Invalid",
+			Error:      fmt.Errorf("Document Type Invalid"),
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	data, err := u.consumerRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerUsecase->FindDocument()" + err.Path
+		return nil, "", err
+	}
+
+	key := data.KTPImgKey
+	if documentType == models.CONSUMER_DOCUMENT_SELFIE {
+		key = data.SelfieImgKey
+	}
+
+	if key == "" {
+		return nil, "", &types.Error{
+			Path:       ".ConsumerUsecase->FindDocument()",
+			Message:    "Document Not Uploaded",
+			Error:      fmt.Errorf("Document Not Uploaded"),
+			StatusCode: http.StatusNotFound,
+			Type:       "validation-error",
+		}
+	}
+
+	// a URL means the record was not converted by `privatize-documents` yet
+	if strings.Contains(key, "://") {
+		return nil, "", &types.Error{
+			Path:       ".ConsumerUsecase->FindDocument()",
+			Message:    "Document Not Migrated To Private Storage",
+			Error:      fmt.Errorf("Document Not Migrated To Private Storage"),
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	err = u.consumerRepo.CreateDocumentAccessLog(ctx, data.ID, documentType, key)
+	if err != nil {
+		err.Path = ".ConsumerUsecase->FindDocument()" + err.Path
+		return nil, "", err
+	}
+
+	body, contentType, errStorage := u.fileStorage.Get(ctx, key)
+	if errStorage != nil {
+		return nil, "", &types.Error{
+			Path:       ".ConsumerUsecase->FindDocument()",
+			Message:    "Failed To Read Document",
+			Error:      errStorage,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "storage-error",
+		}
+	}
+
+	return body, contentType, nil
+}
+
+// PrivatizeDocuments takes the documents of a consumer uploaded before storage was private,
+// revokes their public read access and replaces the stored URLs by object keys.
+func (u *ConsumerUsecase) PrivatizeDocuments(ctx *gin.Context, id string) *types.Error {
+	data, err := u.consumerRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerUsecase->PrivatizeDocuments()" + err.Path
+		return err
+	}
+
+	for _, document := range []*string{&data.KTPImgKey, &data.SelfieImgKey} {
+		if !strings.Contains(*document, "://") {
+			continue
+		}
+
+		key, ok := u.fileStorage.ObjectKey(*document)
+		if !ok {
+			return &types.Error{
+				Path:       ".ConsumerUsecase->PrivatizeDocuments()",
+				Message:    fmt.Sprintf("Document URL %s Is Not In The Configured Storage", *document),
+				Error:      fmt.Errorf("document URL %s is not in the configured storage", *document),
+				StatusCode: http.StatusUnprocessableEntity,
+				Type:       "validation-error",
+			}
+		}
+
+		errStorage := u.fileStorage.MakePrivate(ctx, key)
+		if errStorage != nil {
+			return &types.Error{
+				Path:       ".ConsumerUsecase->PrivatizeDocuments()",
+				Message:    "Failed To Make Document Private",
+				Error:      errStorage,
+				StatusCode: http.StatusInternalServerError,
+				Type:       "storage-error",
+			}
+		}
+
+		*document = key
+	}
+
+	_, err = u.consumerRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".ConsumerUsecase->PrivatizeDocuments()" + err.Path
+		return err
+	}
+
+	return nil
+}
@@ -18,6 +18,13 @@ type Repository interface {
 	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
 	UpdateStatus(*gin.Context, string, string) (*models.ConsumerCreditLimit, *types.Error)
 
+	FindDetails(*gin.Context, string) ([]*models.ConsumerCreditLimitDetail, *types.Error)
+	CreateDetail(*gin.Context, *models.ConsumerCreditLimitDetail) (*models.ConsumerCreditLimitDetail, *types.Error)
+	DeleteDetails(*gin.Context, string) *types.Error
+
 	// Check Credit Limit
-	CheckCreditLimitAvailability(ctx *gin.Context, consumerID string, tenor int) (float64, *types.Error) 
+	LockCreditLimit(ctx *gin.Context, consumerID string) *types.Error
+	FindConsumerIDForUpdate(ctx *gin.Context, id string) (string, *types.Error)
+	CheckCreditLimitAvailability(ctx *gin.Context, consumerID string, loanProductID string) (float64, *types.Error)
+	FindCreditLimitAvailabilities(ctx *gin.Context, consumerID string) ([]*models.ConsumerCreditLimitTenorAvailability, *types.Error)
 }
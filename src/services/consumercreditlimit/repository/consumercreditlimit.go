@@ -3,7 +3,6 @@ package repository
 import (
 	"fmt"
 	"net/http"
-	"strings"
 
 	"case-study-kredit-plus/library/data"
 	"case-study-kredit-plus/library/types"
@@ -15,10 +14,11 @@ import (
 type ConsumerCreditLimitRepository struct {
 	repository       data.GenericStorage
 	statusRepository data.GenericStorage
+	detailRepository data.GenericStorage
 }
 
-func NewConsumerCreditLimitRepository(repository data.GenericStorage, statusRepository data.GenericStorage) ConsumerCreditLimitRepository {
-	return ConsumerCreditLimitRepository{repository: repository, statusRepository: statusRepository}
+func NewConsumerCreditLimitRepository(repository data.GenericStorage, statusRepository data.GenericStorage, detailRepository data.GenericStorage) ConsumerCreditLimitRepository {
+	return ConsumerCreditLimitRepository{repository: repository, statusRepository: statusRepository, detailRepository: detailRepository}
 }
 
 func (s ConsumerCreditLimitRepository) FindAll(ctx *gin.Context, params models.FindAllConsumerCreditLimitParams) ([]*models.ConsumerCreditLimit, *types.Error) {
@@ -41,6 +41,11 @@ func (s ConsumerCreditLimitRepository) FindAll(ctx *gin.Context, params models.F
 		where += ` AND consumer_credit_limits.consumer_id = :consumer_id`
 	}
 
+	if params.EffectiveOn != "" {
+		where += ` AND consumer_credit_limits.effective_from <= :effective_on`
+		where += ` AND (consumer_credit_limits.effective_to IS NULL OR consumer_credit_limits.effective_to > :effective_on)`
+	}
+
 	if params.FindAllParams.SortBy != "" {
 		where += fmt.Sprintf(` ORDER BY %s`, params.FindAllParams.SortBy)
 	}
@@ -52,7 +57,8 @@ func (s ConsumerCreditLimitRepository) FindAll(ctx *gin.Context, params models.F
 	query := fmt.Sprintf(`
   SELECT
     consumer_credit_limits.id, consumer_credit_limits.consumer_id,
-    consumer_credit_limits.1_month, consumer_credit_limits.2_month, consumer_credit_limits.3_month, consumer_credit_limits.6_month,
+    consumer_credit_limits.effective_from, consumer_credit_limits.effective_to, consumer_credit_limits.reason,
+    IFNULL(consumer_credit_limits.created_by, '') created_by,
     consumer_credit_limits.status_id, status.name status_name, consumers.full_name consumer_name
   FROM consumer_credit_limits
   JOIN status ON consumer_credit_limits.status_id = status.id
@@ -63,10 +69,11 @@ func (s ConsumerCreditLimitRepository) FindAll(ctx *gin.Context, params models.F
 	// fmt.Println(query)
 
 	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
-		"limit":       params.FindAllParams.Size,
-		"offset":      ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
-		"status_id":   params.FindAllParams.StatusID,
-		"consumer_id": params.ConsumerID,
+		"limit":        params.FindAllParams.Size,
+		"offset":       ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"status_id":    params.FindAllParams.StatusID,
+		"consumer_id":  params.ConsumerID,
+		"effective_on": params.EffectiveOn,
 	})
 	if err != nil {
 		return nil, &types.Error{
@@ -86,11 +93,11 @@ func (s ConsumerCreditLimitRepository) FindAll(ctx *gin.Context, params models.F
 				ID:   v.ConsumerID,
 				Name: v.ConsumerName,
 			},
-			Month1:   v.Month1,
-			Month2:   v.Month2,
-			Month3:   v.Month3,
-			Month6:   v.Month6,
-			StatusID: v.StatusID,
+			EffectiveFrom: v.EffectiveFrom,
+			EffectiveTo:   v.EffectiveTo,
+			Reason:        v.Reason,
+			CreatedBy:     v.CreatedBy,
+			StatusID:      v.StatusID,
 			Status: models.Status{
 				ID:   v.StatusID,
 				Name: v.StatusName,
@@ -111,7 +118,8 @@ func (s ConsumerCreditLimitRepository) Find(ctx *gin.Context, id string) (*model
 	query := `
   SELECT
     consumer_credit_limits.id, consumer_credit_limits.consumer_id,
-    consumer_credit_limits.1_month, consumer_credit_limits.2_month, consumer_credit_limits.3_month, consumer_credit_limits.6_month,
+    consumer_credit_limits.effective_from, consumer_credit_limits.effective_to, consumer_credit_limits.reason,
+    IFNULL(consumer_credit_limits.created_by, '') created_by,
     consumer_credit_limits.status_id, status.name status_name, consumers.full_name consumer_name
   FROM consumer_credit_limits
   JOIN status ON consumer_credit_limits.status_id = status.id
@@ -138,11 +146,11 @@ func (s ConsumerCreditLimitRepository) Find(ctx *gin.Context, id string) (*model
 				ID:   v.ConsumerID,
 				Name: v.ConsumerName,
 			},
-			Month1:   v.Month1,
-			Month2:   v.Month2,
-			Month3:   v.Month3,
-			Month6:   v.Month6,
-			StatusID: v.StatusID,
+			EffectiveFrom: v.EffectiveFrom,
+			EffectiveTo:   v.EffectiveTo,
+			Reason:        v.Reason,
+			CreatedBy:     v.CreatedBy,
+			StatusID:      v.StatusID,
 			Status: models.Status{
 				ID:   v.StatusID,
 				Name: v.StatusName,
@@ -180,10 +188,16 @@ func (s ConsumerCreditLimitRepository) Count(ctx *gin.Context, params models.Fin
 		where += ` AND consumer_credit_limits.consumer_id = :consumer_id`
 	}
 
+	if params.EffectiveOn != "" {
+		where += ` AND consumer_credit_limits.effective_from <= :effective_on`
+		where += ` AND (consumer_credit_limits.effective_to IS NULL OR consumer_credit_limits.effective_to > :effective_on)`
+	}
+
 	query := fmt.Sprintf(`
   SELECT
     consumer_credit_limits.id, consumer_credit_limits.consumer_id,
-    consumer_credit_limits.1_month, consumer_credit_limits.2_month, consumer_credit_limits.3_month, consumer_credit_limits.6_month,
+    consumer_credit_limits.effective_from, consumer_credit_limits.effective_to, consumer_credit_limits.reason,
+    IFNULL(consumer_credit_limits.created_by, '') created_by,
     consumer_credit_limits.status_id, status.name status_name, consumers.full_name consumer_name
   FROM consumer_credit_limits
   JOIN status ON consumer_credit_limits.status_id = status.id
@@ -194,10 +208,11 @@ func (s ConsumerCreditLimitRepository) Count(ctx *gin.Context, params models.Fin
 	// fmt.Println(query)
 
 	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
-		"limit":       params.FindAllParams.Size,
-		"offset":      ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
-		"status_id":   params.FindAllParams.StatusID,
-		"consumer_id": params.ConsumerID,
+		"limit":        params.FindAllParams.Size,
+		"offset":       ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"status_id":    params.FindAllParams.StatusID,
+		"consumer_id":  params.ConsumerID,
+		"effective_on": params.EffectiveOn,
 	})
 	if err != nil {
 		return 0, &types.Error{
@@ -308,8 +323,156 @@ func (s ConsumerCreditLimitRepository) UpdateStatus(ctx *gin.Context, id string,
 	return &data, nil
 }
 
-// CHECK CONSUMER CREDIT LIMIT FOR TENOR
-func (s ConsumerCreditLimitRepository) CheckCreditLimitAvailability(ctx *gin.Context, consumerID string, tenor int) (float64, *types.Error) {
+func (s ConsumerCreditLimitRepository) FindDetails(ctx *gin.Context, consumerCreditLimitID string) ([]*models.ConsumerCreditLimitDetail, *types.Error) {
+	data := []*models.ConsumerCreditLimitDetail{}
+	bulks := []*models.ConsumerCreditLimitDetailBulk{}
+
+	query := `
+  SELECT
+    consumer_credit_limit_details.id, consumer_credit_limit_details.consumer_credit_limit_id,
+    consumer_credit_limit_details.loan_product_id, consumer_credit_limit_details.limit_amount,
+    consumer_credit_limit_details.status_id, loan_products.name loan_product_name, loan_products.tenor
+  FROM consumer_credit_limit_details
+  JOIN loan_products ON loan_products.id = consumer_credit_limit_details.loan_product_id
+  WHERE consumer_credit_limit_details.consumer_credit_limit_id = :consumer_credit_limit_id
+  ORDER BY loan_products.tenor, loan_products.name`
+
+	err := s.detailRepository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"consumer_credit_limit_id": consumerCreditLimitID,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerCreditLimitStorage->FindDetails()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		obj := &models.ConsumerCreditLimitDetail{
+			ID:                    v.ID,
+			ConsumerCreditLimitID: v.ConsumerCreditLimitID,
+			LoanProductID:         v.LoanProductID,
+			LoanProduct: &models.IDNameTemplate{
+				ID:   v.LoanProductID,
+				Name: v.LoanProductName,
+			},
+			Tenor:       v.Tenor,
+			LimitAmount: v.LimitAmount,
+			StatusID:    v.StatusID,
+		}
+
+		data = append(data, obj)
+	}
+
+	return data, nil
+}
+
+func (s ConsumerCreditLimitRepository) CreateDetail(ctx *gin.Context, obj *models.ConsumerCreditLimitDetail) (*models.ConsumerCreditLimitDetail, *types.Error) {
+	data := models.ConsumerCreditLimitDetail{}
+	_, err := s.detailRepository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerCreditLimitStorage->CreateDetail()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.detailRepository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerCreditLimitStorage->CreateDetail()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+func (s ConsumerCreditLimitRepository) DeleteDetails(ctx *gin.Context, consumerCreditLimitID string) *types.Error {
+	query := `DELETE FROM consumer_credit_limit_details WHERE consumer_credit_limit_id = :consumer_credit_limit_id`
+
+	err := s.detailRepository.ExecQuery(ctx, query, map[string]interface{}{
+		"consumer_credit_limit_id": consumerCreditLimitID,
+	})
+	if err != nil {
+		return &types.Error{
+			Path:       ".ConsumerCreditLimitStorage->DeleteDetails()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
+
+// LockCreditLimit takes a row lock on the consumer's active credit limit so concurrent transactions draw on it one after another.
+// It only serializes when called inside a database transaction, and has to be the first read in it,
+// otherwise the availability check that follows reads from a snapshot taken before the lock was granted.
+func (s ConsumerCreditLimitRepository) LockCreditLimit(ctx *gin.Context, consumerID string) *types.Error {
+	rows := []*models.IDNameTemplate{}
+
+	query := `SELECT consumer_credit_limits.id, consumer_credit_limits.consumer_id name FROM consumer_credit_limits WHERE consumer_credit_limits.consumer_id = :consumer_id AND consumer_credit_limits.status_id = 1 FOR UPDATE`
+
+	err := s.repository.SelectWithQuery(ctx, &rows, query, map[string]interface{}{"consumer_id": consumerID})
+	if err != nil {
+		return &types.Error{
+			Path:       ".ConsumerCreditLimitStorage->LockCreditLimit()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
+
+// FindConsumerIDForUpdate locks a version of a credit limit and returns its consumer. It only reads with a lock,
+// so LockCreditLimit can still follow it before the first plain read of the database transaction.
+func (s ConsumerCreditLimitRepository) FindConsumerIDForUpdate(ctx *gin.Context, id string) (string, *types.Error) {
+	rows := []*models.IDNameTemplate{}
+
+	query := `SELECT consumer_credit_limits.id, consumer_credit_limits.consumer_id name FROM consumer_credit_limits WHERE consumer_credit_limits.id = :id FOR UPDATE`
+
+	err := s.repository.SelectWithQuery(ctx, &rows, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return "", &types.Error{
+			Path:       ".ConsumerCreditLimitStorage->FindConsumerIDForUpdate()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(rows) == 0 {
+		return "", &types.Error{
+			Path:       ".ConsumerCreditLimitStorage->FindConsumerIDForUpdate()",
+			Message:    "Data Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return rows[0].Name, nil
+}
+
+// CHECK CONSUMER CREDIT LIMIT FOR LOAN PRODUCT
+
+// CheckCreditLimitAvailability returns the loan product limit minus the principal still outstanding on the consumer's transactions
+// under that product, so repaid principal is available again. Only transactions in a status that consumes limit are counted.
+func (s ConsumerCreditLimitRepository) CheckCreditLimitAvailability(ctx *gin.Context, consumerID string, loanProductID string) (float64, *types.Error) {
 	data := []*models.ConsumerCreditLimitAvailability{}
 
 	var err error
@@ -317,24 +480,25 @@ func (s ConsumerCreditLimitRepository) CheckCreditLimitAvailability(ctx *gin.Con
 	query := `
   SELECT
     cl.consumer_id,
-    CASE
-      WHEN <loanTerm> = 1 THEN cl.1_month - IFNULL(SUM(ct.total_amount), 0)
-      WHEN <loanTerm> = 2 THEN cl.2_month - IFNULL(SUM(ct.total_amount), 0)
-      WHEN <loanTerm> = 3 THEN cl.3_month - IFNULL(SUM(ct.total_amount), 0)
-      WHEN <loanTerm> = 6 THEN cl.6_month - IFNULL(SUM(ct.total_amount), 0)
-      ELSE NULL
-    END remaining_limit
+    cld.limit_amount - IFNULL(SUM(ct.OTR - IFNULL(paid.paid_principal_amount, 0)), 0) remaining_limit
   FROM consumer_credit_limits cl
-  LEFT JOIN consumer_transactions ct ON cl.consumer_id = ct.consumer_id AND ct.loan_term = <loanTerm>
-  WHERE cl.status_id = 1 AND cl.consumer_id = '<consumerID>'
-  GROUP BY cl.consumer_id`
-
-	query = strings.ReplaceAll(query, `<loanTerm>`, fmt.Sprintf(`%d`, tenor))
-	query = strings.ReplaceAll(query, `<consumerID>`, consumerID)
+  JOIN consumer_credit_limit_details cld ON cld.consumer_credit_limit_id = cl.id AND cld.loan_product_id = :loan_product_id
+  LEFT JOIN consumer_transactions ct ON cl.consumer_id = ct.consumer_id AND ct.loan_product_id = cld.loan_product_id
+    AND ct.status_id IN (SELECT cts.id FROM consumer_transaction_statuses cts WHERE cts.consumes_limit = 1)
+  LEFT JOIN (
+    SELECT ci.consumer_transaction_id, SUM(ci.paid_principal_amount) paid_principal_amount
+    FROM consumer_installments ci
+    GROUP BY ci.consumer_transaction_id
+  ) paid ON paid.consumer_transaction_id = ct.id
+  WHERE cl.status_id = 1 AND cl.consumer_id = :consumer_id
+  GROUP BY cl.consumer_id, cld.limit_amount`
 
 	// fmt.Println(query)
 
-	err = s.repository.SelectWithQuery(ctx, &data, query, map[string]interface{}{})
+	err = s.repository.SelectWithQuery(ctx, &data, query, map[string]interface{}{
+		"consumer_id":     consumerID,
+		"loan_product_id": loanProductID,
+	})
 	if err != nil {
 		return 0, &types.Error{
 			Path:       ".ConsumerCreditLimitStorage->CheckCreditLimitAvailability()",
@@ -351,3 +515,58 @@ func (s ConsumerCreditLimitRepository) CheckCreditLimitAvailability(ctx *gin.Con
 
 	return 0, nil
 }
+
+// FindCreditLimitAvailabilities works out every loan product of the consumer's limit in one query,
+// with the used amount counted the same way CheckCreditLimitAvailability counts it
+func (s ConsumerCreditLimitRepository) FindCreditLimitAvailabilities(ctx *gin.Context, consumerID string) ([]*models.ConsumerCreditLimitTenorAvailability, *types.Error) {
+	data := []*models.ConsumerCreditLimitTenorAvailability{}
+	bulks := []*models.ConsumerCreditLimitTenorAvailabilityBulk{}
+
+	query := `
+  SELECT
+    lp.id loan_product_id, lp.name loan_product_name, lp.tenor, cld.limit_amount,
+    IFNULL(SUM(ct.OTR - IFNULL(paid.paid_principal_amount, 0)), 0) used_amount
+  FROM consumer_credit_limits cl
+  JOIN consumer_credit_limit_details cld ON cld.consumer_credit_limit_id = cl.id
+  JOIN loan_products lp ON lp.id = cld.loan_product_id
+  LEFT JOIN consumer_transactions ct ON cl.consumer_id = ct.consumer_id AND ct.loan_product_id = cld.loan_product_id
+    AND ct.status_id IN (SELECT cts.id FROM consumer_transaction_statuses cts WHERE cts.consumes_limit = 1)
+  LEFT JOIN (
+    SELECT ci.consumer_transaction_id, SUM(ci.paid_principal_amount) paid_principal_amount
+    FROM consumer_installments ci
+    GROUP BY ci.consumer_transaction_id
+  ) paid ON paid.consumer_transaction_id = ct.id
+  WHERE cl.status_id = 1 AND cl.consumer_id = :consumer_id
+  GROUP BY lp.id, lp.name, lp.tenor, cld.limit_amount
+  ORDER BY lp.tenor, lp.name`
+
+	err := s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"consumer_id": consumerID,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerCreditLimitStorage->FindCreditLimitAvailabilities()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		obj := &models.ConsumerCreditLimitTenorAvailability{
+			LoanProduct: &models.IDNameTemplate{
+				ID:   v.LoanProductID,
+				Name: v.LoanProductName,
+			},
+			Tenor:           v.Tenor,
+			LimitAmount:     v.LimitAmount,
+			UsedAmount:      v.UsedAmount,
+			RemainingAmount: v.LimitAmount - v.UsedAmount,
+		}
+
+		data = append(data, obj)
+	}
+
+	return data, nil
+}
@@ -1,6 +1,8 @@
 package consumercreditlimit
 
 import (
+	"time"
+
 	"case-study-kredit-plus/library/types"
 	"case-study-kredit-plus/models"
 
@@ -18,6 +20,12 @@ type Usecase interface {
 	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
 	UpdateStatus(*gin.Context, string, string) (*models.ConsumerCreditLimit, *types.Error)
 
+	// History
+	FindTimeline(*gin.Context, string) ([]*models.ConsumerCreditLimit, *types.Error)
+	FindEffective(*gin.Context, string, time.Time) (*models.ConsumerCreditLimit, *types.Error)
+
 	// Check Credit Limit
-	CheckCreditLimitAvailability(ctx *gin.Context, consumerID string, tenor int) (float64, *types.Error)
+	LockCreditLimit(ctx *gin.Context, consumerID string) *types.Error
+	CheckCreditLimitAvailability(ctx *gin.Context, consumerID string, loanProductID string) (float64, *types.Error)
+	FindCreditLimitAvailabilities(ctx *gin.Context, consumerID string) ([]*models.ConsumerCreditLimitTenorAvailability, *types.Error)
 }
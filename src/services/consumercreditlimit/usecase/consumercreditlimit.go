@@ -1,13 +1,17 @@
 package usecase
 
 import (
+	"fmt"
 	"net/http"
 	"reflect"
 	"strings"
 	"time"
 
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/data"
 	"case-study-kredit-plus/library/types"
 	"case-study-kredit-plus/src/services/consumercreditlimit"
+	"case-study-kredit-plus/src/services/loanproduct"
 
 	"case-study-kredit-plus/models"
 
@@ -21,15 +25,17 @@ import (
 
 type ConsumerCreditLimitUsecase struct {
 	consumercreditlimitRepo consumercreditlimit.Repository
+	loanproductUsecase      loanproduct.Usecase
 	contextTimeout          time.Duration
 	db                      *sqlx.DB
 }
 
-func NewConsumerCreditLimitUsecase(db *sqlx.DB, consumercreditlimitRepo consumercreditlimit.Repository) consumercreditlimit.Usecase {
+func NewConsumerCreditLimitUsecase(db *sqlx.DB, consumercreditlimitRepo consumercreditlimit.Repository, loanproductUsecase loanproduct.Usecase) consumercreditlimit.Usecase {
 	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
 
 	return &ConsumerCreditLimitUsecase{
 		consumercreditlimitRepo: consumercreditlimitRepo,
+		loanproductUsecase:      loanproductUsecase,
 		contextTimeout:          timeoutContext,
 		db:                      db,
 	}
@@ -62,6 +68,14 @@ func (u *ConsumerCreditLimitUsecase) FindAll(ctx *gin.Context, params models.Fin
 		return nil, err
 	}
 
+	for _, v := range result {
+		v.Details, err = u.consumercreditlimitRepo.FindDetails(ctx, v.ID)
+		if err != nil {
+			err.Path = ".ConsumerCreditLimitUsecase->FindAll()" + err.Path
+			return nil, err
+		}
+	}
+
 	return result, nil
 }
 
@@ -72,6 +86,12 @@ func (u *ConsumerCreditLimitUsecase) Find(ctx *gin.Context, id string) (*models.
 		return nil, err
 	}
 
+	result.Details, err = u.consumercreditlimitRepo.FindDetails(ctx, result.ID)
+	if err != nil {
+		err.Path = ".ConsumerCreditLimitUsecase->Find()" + err.Path
+		return nil, err
+	}
+
 	return result, nil
 }
 
@@ -105,6 +125,8 @@ func (u *ConsumerCreditLimitUsecase) Count(ctx *gin.Context, params models.FindA
 	return result, nil
 }
 
+// Create starts a new version of the consumer's credit limit. The version in force is closed at the same moment
+// and kept inactive, so the limit at any past date can still be looked up.
 func (u *ConsumerCreditLimitUsecase) Create(ctx *gin.Context, obj models.ConsumerCreditLimit) (*models.ConsumerCreditLimit, *types.Error) {
 	validate := validator.New()
 	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
@@ -126,7 +148,22 @@ func (u *ConsumerCreditLimitUsecase) Create(ctx *gin.Context, obj models.Consume
 		}
 	}
 
-	// check duplicate ConsumerID
+	// transactions drawing on the limit hold the same lock, so the limit never changes under their availability check
+	err := u.consumercreditlimitRepo.LockCreditLimit(ctx, obj.ConsumerID)
+	if err != nil {
+		err.Path = ".ConsumerCreditLimitUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	err = u.validateDetails(ctx, obj.Details)
+	if err != nil {
+		err.Path = ".ConsumerCreditLimitUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	now := library.UTCPlus7()
+
+	// close the version in force
 	var dupeParams models.FindAllConsumerCreditLimitParams
 	dupeParams.ConsumerID = obj.ConsumerID
 	dupeParams.FindAllParams.StatusID = `status_id = "1"`
@@ -138,7 +175,15 @@ func (u *ConsumerCreditLimitUsecase) Create(ctx *gin.Context, obj models.Consume
 
 	if len(dupeData) > 0 {
 		for _, dupe := range dupeData {
-			_, err := u.consumercreditlimitRepo.UpdateStatus(ctx, dupe.ID, models.STATUS_INACTIVE)
+			dupe.EffectiveTo = &now
+
+			_, err := u.consumercreditlimitRepo.Update(ctx, dupe)
+			if err != nil {
+				err.Path = ".ConsumerCreditLimitUsecase->Create()" + err.Path
+				return nil, err
+			}
+
+			_, err = u.consumercreditlimitRepo.UpdateStatus(ctx, dupe.ID, models.STATUS_INACTIVE)
 			if err != nil {
 				err.Path = ".ConsumerCreditLimitUsecase->Create()" + err.Path
 				return nil, err
@@ -147,13 +192,11 @@ func (u *ConsumerCreditLimitUsecase) Create(ctx *gin.Context, obj models.Consume
 	}
 
 	data := models.ConsumerCreditLimit{
-		ID:         uuid.New().String(),
-		ConsumerID: obj.ConsumerID,
-		Month1:     obj.Month1,
-		Month2:     obj.Month2,
-		Month3:     obj.Month3,
-		Month6:     obj.Month6,
-		StatusID:   models.DEFAULT_STATUS_ID,
+		ID:            uuid.New().String(),
+		ConsumerID:    obj.ConsumerID,
+		EffectiveFrom: now,
+		Reason:        obj.Reason,
+		StatusID:      models.DEFAULT_STATUS_ID,
 	}
 
 	result, err := u.consumercreditlimitRepo.Create(ctx, &data)
@@ -162,49 +205,65 @@ func (u *ConsumerCreditLimitUsecase) Create(ctx *gin.Context, obj models.Consume
 		return nil, err
 	}
 
+	result.Details, err = u.createDetails(ctx, result.ID, obj.Details)
+	if err != nil {
+		err.Path = ".ConsumerCreditLimitUsecase->Create()" + err.Path
+		return nil, err
+	}
+
 	return result, nil
 }
 
+// Update changes the limit by starting a new version from the current one, the reason is required
 func (u *ConsumerCreditLimitUsecase) Update(ctx *gin.Context, id string, obj models.ConsumerCreditLimit) (*models.ConsumerCreditLimit, *types.Error) {
-	validate := validator.New()
-	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
-		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
-		if name == "-" {
-			return ""
-		}
-		return name
-	})
-
-	errValidation := validate.Struct(obj)
-	if errValidation != nil {
+	if strings.TrimSpace(obj.Reason) == "" {
 		return nil, &types.Error{
 			Path:       ".ConsumerCreditLimitUsecase->Update()",
-			Message:    errValidation.Error(),
-			Error:      errValidation,
-			StatusCode: http.StatusUnprocessableEntity,
+			Message:    "Reason is required",
+			Error:      fmt.Errorf("Reason is required"),
 			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
 		}
 	}
 
+	// lock the version, then the consumer's limit, before anything is read without a lock
+	consumerID, err := u.consumercreditlimitRepo.FindConsumerIDForUpdate(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerCreditLimitUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	err = u.consumercreditlimitRepo.LockCreditLimit(ctx, consumerID)
+	if err != nil {
+		err.Path = ".ConsumerCreditLimitUsecase->Update()" + err.Path
+		return nil, err
+	}
+
 	data, err := u.consumercreditlimitRepo.Find(ctx, id)
 	if err != nil {
 		err.Path = ".ConsumerCreditLimitUsecase->Update()" + err.Path
 		return nil, err
 	}
 
-	// data.ConsumerID = obj.ConsumerID
-	data.Month1 = obj.Month1
-	data.Month2 = obj.Month2
-	data.Month3 = obj.Month3
-	data.Month6 = obj.Month6
+	if data.StatusID != models.STATUS_ACTIVE {
+		return nil, &types.Error{
+			Path:       ".ConsumerCreditLimitUsecase->Update()",
+			Message:    "Only the credit limit in force can be changed",
+			Error:      fmt.Errorf("Only the credit limit in force can be changed"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	obj.ConsumerID = data.ConsumerID
 
-	result, err := u.consumercreditlimitRepo.Update(ctx, data)
+	result, err := u.Create(ctx, obj)
 	if err != nil {
 		err.Path = ".ConsumerCreditLimitUsecase->Update()" + err.Path
 		return nil, err
 	}
 
-	return result, err
+	return result, nil
 }
 
 func (u *ConsumerCreditLimitUsecase) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
@@ -218,6 +277,18 @@ func (u *ConsumerCreditLimitUsecase) FindStatus(ctx *gin.Context) ([]*models.Sta
 }
 
 func (u *ConsumerCreditLimitUsecase) UpdateStatus(ctx *gin.Context, id string, newStatusID string) (*models.ConsumerCreditLimit, *types.Error) {
+	consumerID, err := u.consumercreditlimitRepo.FindConsumerIDForUpdate(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerCreditLimitUsecase->UpdateStatus()" + err.Path
+		return nil, err
+	}
+
+	err = u.consumercreditlimitRepo.LockCreditLimit(ctx, consumerID)
+	if err != nil {
+		err.Path = ".ConsumerCreditLimitUsecase->UpdateStatus()" + err.Path
+		return nil, err
+	}
+
 	result, err := u.consumercreditlimitRepo.UpdateStatus(ctx, id, newStatusID)
 	if err != nil {
 		err.Path = ".ConsumerCreditLimitUsecase->UpdateStatus()" + err.Path
@@ -227,9 +298,61 @@ func (u *ConsumerCreditLimitUsecase) UpdateStatus(ctx *gin.Context, id string, n
 	return result, err
 }
 
-// CHECK CONSUMER CREDIT LIMIT FOR TENOR
-func (u *ConsumerCreditLimitUsecase) CheckCreditLimitAvailability(ctx *gin.Context, consumerID string, tenor int) (float64, *types.Error) {
-	result, err := u.consumercreditlimitRepo.CheckCreditLimitAvailability(ctx, consumerID, tenor)
+// FindTimeline lists every version of the consumer's credit limit, oldest first
+func (u *ConsumerCreditLimitUsecase) FindTimeline(ctx *gin.Context, consumerID string) ([]*models.ConsumerCreditLimit, *types.Error) {
+	var params models.FindAllConsumerCreditLimitParams
+	params.ConsumerID = consumerID
+	params.FindAllParams.SortBy = "consumer_credit_limits.effective_from ASC"
+
+	result, err := u.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".ConsumerCreditLimitUsecase->FindTimeline()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindEffective returns the credit limit that was in force for the consumer at the given moment
+func (u *ConsumerCreditLimitUsecase) FindEffective(ctx *gin.Context, consumerID string, effectiveOn time.Time) (*models.ConsumerCreditLimit, *types.Error) {
+	var params models.FindAllConsumerCreditLimitParams
+	params.ConsumerID = consumerID
+	params.EffectiveOn = effectiveOn.Format(library.StrToTimestampFormat)
+	params.FindAllParams.SortBy = "consumer_credit_limits.effective_from DESC"
+
+	result, err := u.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".ConsumerCreditLimitUsecase->FindEffective()" + err.Path
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return nil, &types.Error{
+			Path:       ".ConsumerCreditLimitUsecase->FindEffective()",
+			Message:    "No credit limit was in force on that date",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "validation-error",
+		}
+	}
+
+	return result[0], nil
+}
+
+// LockCreditLimit must be called inside RunInTransaction, before anything else reads from the database
+func (u *ConsumerCreditLimitUsecase) LockCreditLimit(ctx *gin.Context, consumerID string) *types.Error {
+	err := u.consumercreditlimitRepo.LockCreditLimit(ctx, consumerID)
+	if err != nil {
+		err.Path = ".ConsumerCreditLimitUsecase->LockCreditLimit()" + err.Path
+		return err
+	}
+
+	return nil
+}
+
+// CHECK CONSUMER CREDIT LIMIT FOR LOAN PRODUCT
+func (u *ConsumerCreditLimitUsecase) CheckCreditLimitAvailability(ctx *gin.Context, consumerID string, loanProductID string) (float64, *types.Error) {
+	result, err := u.consumercreditlimitRepo.CheckCreditLimitAvailability(ctx, consumerID, loanProductID)
 	if err != nil {
 		err.Path = ".ConsumerCreditLimitUsecase->CheckCreditLimitAvailability()" + err.Path
 		return 0, err
@@ -237,3 +360,64 @@ func (u *ConsumerCreditLimitUsecase) CheckCreditLimitAvailability(ctx *gin.Conte
 
 	return result, nil
 }
+
+func (u *ConsumerCreditLimitUsecase) FindCreditLimitAvailabilities(ctx *gin.Context, consumerID string) ([]*models.ConsumerCreditLimitTenorAvailability, *types.Error) {
+	result, err := u.consumercreditlimitRepo.FindCreditLimitAvailabilities(ctx, consumerID)
+	if err != nil {
+		err.Path = ".ConsumerCreditLimitUsecase->FindCreditLimitAvailabilities()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// validateDetails makes sure every limit points to an existing loan product, and that no product is listed twice
+func (u *ConsumerCreditLimitUsecase) validateDetails(ctx *gin.Context, details []*models.ConsumerCreditLimitDetail) *types.Error {
+	seen := map[string]bool{}
+	for _, v := range details {
+		if seen[v.LoanProductID] {
+			return &types.Error{
+				Path:       ".ConsumerCreditLimitUsecase->validateDetails()",
+				Message:    "Duplicate Loan Product In Details",
+				Error:      fmt.Errorf("duplicate loan product %s in details", v.LoanProductID),
+				StatusCode: http.StatusUnprocessableEntity,
+				Type:       "validation-error",
+			}
+		}
+		seen[v.LoanProductID] = true
+
+		_, err := u.loanproductUsecase.Find(ctx, v.LoanProductID)
+		if err != nil {
+			err.Path = ".ConsumerCreditLimitUsecase->validateDetails()" + err.Path
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u *ConsumerCreditLimitUsecase) createDetails(ctx *gin.Context, consumerCreditLimitID string, details []*models.ConsumerCreditLimitDetail) ([]*models.ConsumerCreditLimitDetail, *types.Error) {
+	for _, v := range details {
+		data := models.ConsumerCreditLimitDetail{
+			ID:                    uuid.New().String(),
+			ConsumerCreditLimitID: consumerCreditLimitID,
+			LoanProductID:         v.LoanProductID,
+			LimitAmount:           v.LimitAmount,
+			StatusID:              models.DEFAULT_STATUS_ID,
+		}
+
+		_, err := u.consumercreditlimitRepo.CreateDetail(ctx, &data)
+		if err != nil {
+			err.Path = ".ConsumerCreditLimitUsecase->createDetails()" + err.Path
+			return nil, err
+		}
+	}
+
+	result, err := u.consumercreditlimitRepo.FindDetails(ctx, consumerCreditLimitID)
+	if err != nil {
+		err.Path = ".ConsumerCreditLimitUsecase->createDetails()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,26 @@
+package consumerinstallment
+
+import (
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Repository is the contract between Repository and usecase
+type Repository interface {
+	FindAll(*gin.Context, models.FindAllConsumerInstallmentParams) ([]*models.ConsumerInstallment, *types.Error)
+	Find(*gin.Context, string) (*models.ConsumerInstallment, *types.Error)
+	Count(*gin.Context, models.FindAllConsumerInstallmentParams) (int, *types.Error)
+	Create(*gin.Context, *models.ConsumerInstallment) (*models.ConsumerInstallment, *types.Error)
+	Update(*gin.Context, *models.ConsumerInstallment) (*models.ConsumerInstallment, *types.Error)
+
+	DeleteByConsumerTransactionID(*gin.Context, string) *types.Error
+
+	// Late Fee
+	LockConsumerTransaction(*gin.Context, string) *types.Error
+	FindOverdueConsumerTransactionIDs(*gin.Context, string) ([]string, *types.Error)
+	FindOverdue(*gin.Context, string, string) ([]*models.ConsumerInstallmentOverdue, *types.Error)
+	UpdateLateFee(*gin.Context, *models.ConsumerInstallmentOverdue) *types.Error
+	UpdateConsumerTransactionDaysPastDue(*gin.Context, string) *types.Error
+}
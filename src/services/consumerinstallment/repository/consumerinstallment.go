@@ -0,0 +1,491 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ConsumerInstallmentRepository struct {
+	repository       data.GenericStorage
+	statusRepository data.GenericStorage
+}
+
+func NewConsumerInstallmentRepository(repository data.GenericStorage, statusRepository data.GenericStorage) ConsumerInstallmentRepository {
+	return ConsumerInstallmentRepository{repository: repository, statusRepository: statusRepository}
+}
+
+func (s ConsumerInstallmentRepository) FindAll(ctx *gin.Context, params models.FindAllConsumerInstallmentParams) ([]*models.ConsumerInstallment, *types.Error) {
+	data := []*models.ConsumerInstallment{}
+	bulks := []*models.ConsumerInstallmentBulk{}
+
+	var err error
+
+	where := `TRUE`
+
+	if params.FindAllParams.DataFinder != "" {
+		where += fmt.Sprintf(` AND %s`, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where += fmt.Sprintf(` AND consumer_installments.%s`, params.FindAllParams.StatusID)
+	}
+
+	if params.ConsumerTransactionID != "" {
+		where += ` AND consumer_installments.consumer_transaction_id = :consumer_transaction_id`
+	}
+
+	if params.MinDueDate != "" {
+		where += ` AND consumer_installments.due_date >= :min_due_date`
+	}
+
+	if params.MaxDueDate != "" {
+		where += ` AND consumer_installments.due_date <= :max_due_date`
+	}
+
+	if params.MerchantID != "" {
+		where += ` AND consumer_transactions.merchant_id = :merchant_id`
+	}
+
+	if params.IsUnpaid {
+		where += ` AND consumer_installments.paid_principal_amount + consumer_installments.paid_interest_amount + consumer_installments.paid_fee_amount + consumer_installments.paid_late_fee_amount < consumer_installments.installment_amount + consumer_installments.late_fee_amount`
+	}
+
+	if params.FindAllParams.SortBy != "" {
+		where += fmt.Sprintf(` ORDER BY %s`, params.FindAllParams.SortBy)
+	}
+
+	if params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0 {
+		where += ` LIMIT :limit OFFSET :offset`
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    consumer_installments.id, consumer_installments.consumer_transaction_id, consumer_installments.installment_number,
+    consumer_installments.due_date, consumer_installments.principal_amount, consumer_installments.interest_amount,
+    consumer_installments.fee_amount, consumer_installments.installment_amount, consumer_installments.outstanding_balance,
+    consumer_installments.paid_principal_amount, consumer_installments.paid_interest_amount, consumer_installments.paid_fee_amount,
+    consumer_installments.days_past_due, consumer_installments.late_fee_amount, consumer_installments.paid_late_fee_amount, consumer_installments.late_fee_accrued_on,
+    consumer_installments.status_id, status.name status_name, consumer_transactions.contract_number
+  FROM consumer_installments
+  JOIN status ON consumer_installments.status_id = status.id
+  JOIN consumer_transactions ON consumer_transactions.id = consumer_installments.consumer_transaction_id
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"limit":                   params.FindAllParams.Size,
+		"offset":                  ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"status_id":               params.FindAllParams.StatusID,
+		"consumer_transaction_id": params.ConsumerTransactionID,
+		"min_due_date":            params.MinDueDate,
+		"max_due_date":            params.MaxDueDate,
+		"merchant_id":             params.MerchantID,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerInstallmentStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		obj := &models.ConsumerInstallment{
+			ID:                    v.ID,
+			ConsumerTransactionID: v.ConsumerTransactionID,
+			ConsumerTransaction: &models.IDNameTemplate{
+				ID:   v.ConsumerTransactionID,
+				Name: v.ContractNumber,
+			},
+			InstallmentNumber:   v.InstallmentNumber,
+			DueDate:             v.DueDate,
+			PrincipalAmount:     v.PrincipalAmount,
+			InterestAmount:      v.InterestAmount,
+			FeeAmount:           v.FeeAmount,
+			InstallmentAmount:   v.InstallmentAmount,
+			OutstandingBalance:  v.OutstandingBalance,
+			PaidPrincipalAmount: v.PaidPrincipalAmount,
+			PaidInterestAmount:  v.PaidInterestAmount,
+			PaidFeeAmount:       v.PaidFeeAmount,
+			DaysPastDue:         v.DaysPastDue,
+			LateFeeAmount:       v.LateFeeAmount,
+			PaidLateFeeAmount:   v.PaidLateFeeAmount,
+			LateFeeAccruedOn:    v.LateFeeAccruedOn,
+			StatusID:            v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+
+		data = append(data, obj)
+	}
+
+	return data, nil
+}
+
+func (s ConsumerInstallmentRepository) Find(ctx *gin.Context, id string) (*models.ConsumerInstallment, *types.Error) {
+	result := models.ConsumerInstallment{}
+	bulks := []*models.ConsumerInstallmentBulk{}
+	var err error
+
+	query := `
+  SELECT
+    consumer_installments.id, consumer_installments.consumer_transaction_id, consumer_installments.installment_number,
+    consumer_installments.due_date, consumer_installments.principal_amount, consumer_installments.interest_amount,
+    consumer_installments.fee_amount, consumer_installments.installment_amount, consumer_installments.outstanding_balance,
+    consumer_installments.paid_principal_amount, consumer_installments.paid_interest_amount, consumer_installments.paid_fee_amount,
+    consumer_installments.days_past_due, consumer_installments.late_fee_amount, consumer_installments.paid_late_fee_amount, consumer_installments.late_fee_accrued_on,
+    consumer_installments.status_id, status.name status_name, consumer_transactions.contract_number
+  FROM consumer_installments
+  JOIN status ON consumer_installments.status_id = status.id
+  JOIN consumer_transactions ON consumer_transactions.id = consumer_installments.consumer_transaction_id
+  WHERE consumer_installments.id = :id`
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerInstallmentStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(bulks) > 0 {
+		v := bulks[0]
+		result = models.ConsumerInstallment{
+			ID:                    v.ID,
+			ConsumerTransactionID: v.ConsumerTransactionID,
+			ConsumerTransaction: &models.IDNameTemplate{
+				ID:   v.ConsumerTransactionID,
+				Name: v.ContractNumber,
+			},
+			InstallmentNumber:   v.InstallmentNumber,
+			DueDate:             v.DueDate,
+			PrincipalAmount:     v.PrincipalAmount,
+			InterestAmount:      v.InterestAmount,
+			FeeAmount:           v.FeeAmount,
+			InstallmentAmount:   v.InstallmentAmount,
+			OutstandingBalance:  v.OutstandingBalance,
+			PaidPrincipalAmount: v.PaidPrincipalAmount,
+			PaidInterestAmount:  v.PaidInterestAmount,
+			PaidFeeAmount:       v.PaidFeeAmount,
+			DaysPastDue:         v.DaysPastDue,
+			LateFeeAmount:       v.LateFeeAmount,
+			PaidLateFeeAmount:   v.PaidLateFeeAmount,
+			LateFeeAccruedOn:    v.LateFeeAccruedOn,
+			StatusID:            v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+	} else {
+		return nil, &types.Error{
+			Path:       ".ConsumerInstallmentStorage->Find()",
+			Message:    "Data Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &result, nil
+}
+
+func (s ConsumerInstallmentRepository) Count(ctx *gin.Context, params models.FindAllConsumerInstallmentParams) (int, *types.Error) {
+	bulks := []*models.ConsumerInstallmentBulk{}
+
+	var err error
+
+	where := `TRUE`
+
+	if params.FindAllParams.DataFinder != "" {
+		where += fmt.Sprintf(` AND %s`, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where += fmt.Sprintf(` AND consumer_installments.%s`, params.FindAllParams.StatusID)
+	}
+
+	if params.ConsumerTransactionID != "" {
+		where += ` AND consumer_installments.consumer_transaction_id = :consumer_transaction_id`
+	}
+
+	if params.MinDueDate != "" {
+		where += ` AND consumer_installments.due_date >= :min_due_date`
+	}
+
+	if params.MaxDueDate != "" {
+		where += ` AND consumer_installments.due_date <= :max_due_date`
+	}
+
+	if params.MerchantID != "" {
+		where += ` AND consumer_transactions.merchant_id = :merchant_id`
+	}
+
+	if params.IsUnpaid {
+		where += ` AND consumer_installments.paid_principal_amount + consumer_installments.paid_interest_amount + consumer_installments.paid_fee_amount + consumer_installments.paid_late_fee_amount < consumer_installments.installment_amount + consumer_installments.late_fee_amount`
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    consumer_installments.id, consumer_installments.consumer_transaction_id, consumer_installments.installment_number,
+    consumer_installments.due_date, consumer_installments.principal_amount, consumer_installments.interest_amount,
+    consumer_installments.fee_amount, consumer_installments.installment_amount, consumer_installments.outstanding_balance,
+    consumer_installments.paid_principal_amount, consumer_installments.paid_interest_amount, consumer_installments.paid_fee_amount,
+    consumer_installments.days_past_due, consumer_installments.late_fee_amount, consumer_installments.paid_late_fee_amount, consumer_installments.late_fee_accrued_on,
+    consumer_installments.status_id, status.name status_name, consumer_transactions.contract_number
+  FROM consumer_installments
+  JOIN status ON consumer_installments.status_id = status.id
+  JOIN consumer_transactions ON consumer_transactions.id = consumer_installments.consumer_transaction_id
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"status_id":               params.FindAllParams.StatusID,
+		"consumer_transaction_id": params.ConsumerTransactionID,
+		"min_due_date":            params.MinDueDate,
+		"max_due_date":            params.MaxDueDate,
+		"merchant_id":             params.MerchantID,
+	})
+	if err != nil {
+		return 0, &types.Error{
+			Path:       ".ConsumerInstallmentStorage->Count()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return len(bulks), nil
+}
+
+func (s ConsumerInstallmentRepository) Create(ctx *gin.Context, obj *models.ConsumerInstallment) (*models.ConsumerInstallment, *types.Error) {
+	data := models.ConsumerInstallment{}
+	_, err := s.repository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerInstallmentStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerInstallmentStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+func (s ConsumerInstallmentRepository) Update(ctx *gin.Context, obj *models.ConsumerInstallment) (*models.ConsumerInstallment, *types.Error) {
+	data := models.ConsumerInstallment{}
+	err := s.repository.Update(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerInstallmentStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerInstallmentStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+func (s ConsumerInstallmentRepository) DeleteByConsumerTransactionID(ctx *gin.Context, consumerTransactionID string) *types.Error {
+	query := `DELETE FROM consumer_installments WHERE consumer_transaction_id = :consumer_transaction_id`
+
+	err := s.repository.ExecQuery(ctx, query, map[string]interface{}{
+		"consumer_transaction_id": consumerTransactionID,
+	})
+	if err != nil {
+		return &types.Error{
+			Path:       ".ConsumerInstallmentStorage->DeleteByConsumerTransactionID()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
+
+// LATE FEE
+
+// LockConsumerTransaction takes the same row lock as payments do, so accruing late fees and allocating a payment
+// never work on the same installments at once
+func (s ConsumerInstallmentRepository) LockConsumerTransaction(ctx *gin.Context, consumerTransactionID string) *types.Error {
+	rows := []*models.IDNameTemplate{}
+
+	query := `SELECT consumer_transactions.id, consumer_transactions.contract_number name FROM consumer_transactions WHERE consumer_transactions.id = :id FOR UPDATE`
+
+	err := s.repository.SelectWithQuery(ctx, &rows, query, map[string]interface{}{"id": consumerTransactionID})
+	if err != nil {
+		return &types.Error{
+			Path:       ".ConsumerInstallmentStorage->LockConsumerTransaction()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
+
+// FindOverdueConsumerTransactionIDs lists the disbursed and active transactions with an unpaid installment due before
+// the given date, and any transaction still carrying days past due so it can be cleared once paid
+func (s ConsumerInstallmentRepository) FindOverdueConsumerTransactionIDs(ctx *gin.Context, asOf string) ([]string, *types.Error) {
+	rows := []*models.IDNameTemplate{}
+
+	query := `
+  SELECT DISTINCT consumer_installments.consumer_transaction_id id, '' name
+  FROM consumer_installments
+  JOIN consumer_transactions ON consumer_transactions.id = consumer_installments.consumer_transaction_id
+  WHERE (
+    consumer_transactions.status_id IN (:disbursed_status_id, :active_status_id)
+    AND consumer_installments.due_date < :as_of
+    AND consumer_installments.paid_principal_amount + consumer_installments.paid_interest_amount + consumer_installments.paid_fee_amount < consumer_installments.installment_amount
+  ) OR consumer_installments.days_past_due > 0`
+
+	err := s.repository.SelectWithQuery(ctx, &rows, query, map[string]interface{}{
+		"disbursed_status_id": models.TRANSACTION_STATUS_DISBURSED,
+		"active_status_id":    models.TRANSACTION_STATUS_ACTIVE,
+		"as_of":               asOf,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerInstallmentStorage->FindOverdueConsumerTransactionIDs()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	ids := []string{}
+	for _, v := range rows {
+		ids = append(ids, v.ID)
+	}
+
+	return ids, nil
+}
+
+func (s ConsumerInstallmentRepository) FindOverdue(ctx *gin.Context, consumerTransactionID string, asOf string) ([]*models.ConsumerInstallmentOverdue, *types.Error) {
+	data := []*models.ConsumerInstallmentOverdue{}
+
+	query := `
+  SELECT
+    consumer_installments.id, consumer_installments.consumer_transaction_id, consumer_installments.due_date, consumer_installments.installment_amount,
+    consumer_installments.paid_principal_amount, consumer_installments.paid_interest_amount, consumer_installments.paid_fee_amount,
+    consumer_installments.days_past_due, consumer_installments.late_fee_amount, consumer_installments.late_fee_accrued_on,
+    consumer_transactions.status_id consumer_transaction_status_id,
+    IFNULL(loan_products.late_fee_daily_rate, 0) late_fee_daily_rate, IFNULL(loan_products.late_fee_cap_amount, 0) late_fee_cap_amount,
+    IFNULL(loan_products.late_fee_grace_days, 0) late_fee_grace_days
+  FROM consumer_installments
+  JOIN consumer_transactions ON consumer_transactions.id = consumer_installments.consumer_transaction_id
+  LEFT JOIN loan_products ON loan_products.id = consumer_transactions.loan_product_id
+  WHERE consumer_installments.consumer_transaction_id = :consumer_transaction_id
+    AND (consumer_installments.due_date < :as_of OR consumer_installments.days_past_due > 0)
+  ORDER BY consumer_installments.installment_number`
+
+	err := s.repository.SelectWithQuery(ctx, &data, query, map[string]interface{}{
+		"consumer_transaction_id": consumerTransactionID,
+		"as_of":                   asOf,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerInstallmentStorage->FindOverdue()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return data, nil
+}
+
+// UpdateLateFee only writes the columns the late fee accrual owns
+func (s ConsumerInstallmentRepository) UpdateLateFee(ctx *gin.Context, obj *models.ConsumerInstallmentOverdue) *types.Error {
+	query := `
+  UPDATE consumer_installments
+  SET days_past_due = :days_past_due, late_fee_amount = :late_fee_amount, late_fee_accrued_on = :late_fee_accrued_on
+  WHERE id = :id`
+
+	err := s.repository.ExecQuery(ctx, query, map[string]interface{}{
+		"id":                  obj.ID,
+		"days_past_due":       obj.DaysPastDue,
+		"late_fee_amount":     obj.LateFeeAmount,
+		"late_fee_accrued_on": obj.LateFeeAccruedOn,
+	})
+	if err != nil {
+		return &types.Error{
+			Path:       ".ConsumerInstallmentStorage->UpdateLateFee()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
+
+// UpdateConsumerTransactionDaysPastDue rolls the worst installment up to the transaction so it can be filtered on
+func (s ConsumerInstallmentRepository) UpdateConsumerTransactionDaysPastDue(ctx *gin.Context, consumerTransactionID string) *types.Error {
+	query := `
+  UPDATE consumer_transactions
+  SET days_past_due = (
+    SELECT IFNULL(MAX(consumer_installments.days_past_due), 0)
+    FROM consumer_installments
+    WHERE consumer_installments.consumer_transaction_id = :id
+  )
+  WHERE id = :id`
+
+	err := s.repository.ExecQuery(ctx, query, map[string]interface{}{"id": consumerTransactionID})
+	if err != nil {
+		return &types.Error{
+			Path:       ".ConsumerInstallmentStorage->UpdateConsumerTransactionDaysPastDue()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package consumerinstallment
+
+import (
+	"time"
+
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Usecase is the contract between Repository and usecase
+type Usecase interface {
+	FindAll(*gin.Context, models.FindAllConsumerInstallmentParams) ([]*models.ConsumerInstallment, *types.Error)
+	Find(*gin.Context, string) (*models.ConsumerInstallment, *types.Error)
+	Count(*gin.Context, models.FindAllConsumerInstallmentParams) (int, *types.Error)
+	Update(*gin.Context, string, models.ConsumerInstallment) (*models.ConsumerInstallment, *types.Error)
+	Settle(*gin.Context, string, models.ConsumerInstallment) (*models.ConsumerInstallment, *types.Error)
+
+	// Schedule
+	GenerateSchedule(*gin.Context, *models.ConsumerTransaction) ([]*models.ConsumerInstallment, *types.Error)
+
+	// Late Fee
+	LockConsumerTransaction(*gin.Context, string) *types.Error
+	FindOverdueConsumerTransactionIDs(*gin.Context, time.Time) ([]string, *types.Error)
+	AccrueLateFees(*gin.Context, string, time.Time) *types.Error
+}
@@ -0,0 +1,347 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/src/services/consumerinstallment"
+
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"github.com/jmoiron/sqlx"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+type ConsumerInstallmentUsecase struct {
+	consumerinstallmentRepo consumerinstallment.Repository
+	contextTimeout          time.Duration
+	db                      *sqlx.DB
+}
+
+func NewConsumerInstallmentUsecase(db *sqlx.DB, consumerinstallmentRepo consumerinstallment.Repository) consumerinstallment.Usecase {
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	return &ConsumerInstallmentUsecase{
+		consumerinstallmentRepo: consumerinstallmentRepo,
+		contextTimeout:          timeoutContext,
+		db:                      db,
+	}
+}
+
+func (u *ConsumerInstallmentUsecase) FindAll(ctx *gin.Context, params models.FindAllConsumerInstallmentParams) ([]*models.ConsumerInstallment, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(params)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerInstallmentUsecase->FindAll()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.consumerinstallmentRepo.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".ConsumerInstallmentUsecase->FindAll()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *ConsumerInstallmentUsecase) Find(ctx *gin.Context, id string) (*models.ConsumerInstallment, *types.Error) {
+	result, err := u.consumerinstallmentRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerInstallmentUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *ConsumerInstallmentUsecase) Count(ctx *gin.Context, params models.FindAllConsumerInstallmentParams) (int, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(params)
+	if errValidation != nil {
+		return 0, &types.Error{
+			Path:       ".ConsumerInstallmentUsecase->Count()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.consumerinstallmentRepo.Count(ctx, params)
+	if err != nil {
+		err.Path = ".ConsumerInstallmentUsecase->Count()" + err.Path
+		return 0, err
+	}
+
+	return result, nil
+}
+
+// GENERATE INSTALLMENT SCHEDULE FOR TRANSACTION
+
+// GenerateSchedule replaces the installment schedule of a transaction with one row per tenor month.
+// Flat transactions split principal and interest evenly, annuity transactions amortize them over a fixed payment.
+// Fees are split evenly, and the rounding remainder is put on the last installment.
+func (u *ConsumerInstallmentUsecase) GenerateSchedule(ctx *gin.Context, trx *models.ConsumerTransaction) ([]*models.ConsumerInstallment, *types.Error) {
+	if trx.LoanTerm <= 0 {
+		return nil, &types.Error{
+			Path:       ".ConsumerInstallmentUsecase->GenerateSchedule()",
+			Message:    "Loan Term Invalid",
+			Error:      fmt.Errorf("Loan Term Invalid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	existing, err := u.consumerinstallmentRepo.FindAll(ctx, models.FindAllConsumerInstallmentParams{ConsumerTransactionID: trx.ID})
+	if err != nil {
+		err.Path = ".ConsumerInstallmentUsecase->GenerateSchedule()" + err.Path
+		return nil, err
+	}
+
+	// a schedule that already received payments cannot be rebuilt without losing the allocations
+	for _, v := range existing {
+		if v.PaidPrincipalAmount > 0 || v.PaidInterestAmount > 0 || v.PaidFeeAmount > 0 || v.PaidLateFeeAmount > 0 {
+			return nil, &types.Error{
+				Path:       ".ConsumerInstallmentUsecase->GenerateSchedule()",
+				Message:    "Transaction already has payments",
+				Error:      fmt.Errorf("Transaction already has payments"),
+				Type:       "validation-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+		}
+	}
+
+	err = u.consumerinstallmentRepo.DeleteByConsumerTransactionID(ctx, trx.ID)
+	if err != nil {
+		err.Path = ".ConsumerInstallmentUsecase->GenerateSchedule()" + err.Path
+		return nil, err
+	}
+
+	startDate := trx.CreatedAt
+	if startDate.IsZero() {
+		startDate = library.UTCPlus7()
+	}
+	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, startDate.Location())
+
+	term := float64(trx.LoanTerm)
+	principalAmount := library.RoundCurrency(trx.OTR / term)
+	interestAmount := library.RoundCurrency(trx.InterestAmount / term)
+	feeAmount := library.RoundCurrency(trx.AdminFee / term)
+	annuityPayment := library.RoundCurrency(library.AnnuityPayment(trx.OTR, trx.InterestRate, trx.LoanTerm))
+
+	outstandingBalance := trx.OTR
+	scheduledInterest := 0.0
+
+	results := []*models.ConsumerInstallment{}
+	for i := 1; i <= trx.LoanTerm; i++ {
+		principal, interest, fee := principalAmount, interestAmount, feeAmount
+
+		// annuity charges interest on the outstanding balance, so principal grows as interest shrinks
+		if trx.InterestMethod == library.INTEREST_METHOD_ANNUITY {
+			interest = library.RoundCurrency(outstandingBalance * trx.InterestRate / 100)
+			principal = library.RoundCurrency(annuityPayment - interest)
+		}
+
+		// last installment absorbs the rounding remainder
+		if i == trx.LoanTerm {
+			principal = outstandingBalance
+			interest = library.RoundCurrency(trx.InterestAmount - scheduledInterest)
+			fee = library.RoundCurrency(trx.AdminFee - feeAmount*(term-1))
+		}
+
+		scheduledInterest = library.RoundCurrency(scheduledInterest + interest)
+		outstandingBalance = library.RoundCurrency(outstandingBalance - principal)
+
+		data := models.ConsumerInstallment{
+			ID:                    uuid.New().String(),
+			ConsumerTransactionID: trx.ID,
+			InstallmentNumber:     i,
+			DueDate:               library.AddMonths(startDate, i),
+			PrincipalAmount:       principal,
+			InterestAmount:        interest,
+			FeeAmount:             fee,
+			InstallmentAmount:     library.RoundCurrency(principal + interest + fee),
+			OutstandingBalance:    outstandingBalance,
+			StatusID:              models.DEFAULT_STATUS_ID,
+		}
+
+		result, err := u.consumerinstallmentRepo.Create(ctx, &data)
+		if err != nil {
+			err.Path = ".ConsumerInstallmentUsecase->GenerateSchedule()" + err.Path
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (u *ConsumerInstallmentUsecase) Update(ctx *gin.Context, id string, obj models.ConsumerInstallment) (*models.ConsumerInstallment, *types.Error) {
+	data, err := u.consumerinstallmentRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerInstallmentUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	data.PaidPrincipalAmount = obj.PaidPrincipalAmount
+	data.PaidInterestAmount = obj.PaidInterestAmount
+	data.PaidFeeAmount = obj.PaidFeeAmount
+	data.PaidLateFeeAmount = obj.PaidLateFeeAmount
+
+	result, err := u.consumerinstallmentRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".ConsumerInstallmentUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Settle closes the installment at the amounts agreed in a payoff. Interest that had not accrued yet is waived
+// and the early settlement penalty is carried as fee, so the installment ends up fully paid.
+func (u *ConsumerInstallmentUsecase) Settle(ctx *gin.Context, id string, obj models.ConsumerInstallment) (*models.ConsumerInstallment, *types.Error) {
+	data, err := u.consumerinstallmentRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerInstallmentUsecase->Settle()" + err.Path
+		return nil, err
+	}
+
+	data.InterestAmount = obj.InterestAmount
+	data.FeeAmount = obj.FeeAmount
+	data.InstallmentAmount = library.RoundCurrency(data.PrincipalAmount + obj.InterestAmount + obj.FeeAmount)
+	data.PaidPrincipalAmount = obj.PaidPrincipalAmount
+	data.PaidInterestAmount = obj.PaidInterestAmount
+	data.PaidFeeAmount = obj.PaidFeeAmount
+	data.PaidLateFeeAmount = obj.PaidLateFeeAmount
+
+	result, err := u.consumerinstallmentRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".ConsumerInstallmentUsecase->Settle()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// LATE FEE
+
+// FindOverdueConsumerTransactionIDs lists the transactions the late fee accrual has to look at on the given date
+func (u *ConsumerInstallmentUsecase) FindOverdueConsumerTransactionIDs(ctx *gin.Context, asOf time.Time) ([]string, *types.Error) {
+	result, err := u.consumerinstallmentRepo.FindOverdueConsumerTransactionIDs(ctx, asOf.Format("2006-01-02"))
+	if err != nil {
+		err.Path = ".ConsumerInstallmentUsecase->FindOverdueConsumerTransactionIDs()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// AccrueLateFees brings days past due and late fees of a transaction up to the given date.
+// Late fees run per day on the unpaid part of an installment once the grace period is over, and stop at the
+// loan product's cap. Fees are only added for the days since the last run, so running it twice on a day is harmless.
+// Only disbursed and active transactions, the ones that take payments, run up days past due.
+// The caller must hold the transaction lock.
+func (u *ConsumerInstallmentUsecase) AccrueLateFees(ctx *gin.Context, consumerTransactionID string, asOf time.Time) *types.Error {
+	asOf = time.Date(asOf.Year(), asOf.Month(), asOf.Day(), 0, 0, 0, 0, asOf.Location())
+
+	installments, err := u.consumerinstallmentRepo.FindOverdue(ctx, consumerTransactionID, asOf.Format("2006-01-02"))
+	if err != nil {
+		err.Path = ".ConsumerInstallmentUsecase->AccrueLateFees()" + err.Path
+		return err
+	}
+
+	for _, v := range installments {
+		unpaid := library.RoundCurrency(v.InstallmentAmount - v.PaidPrincipalAmount - v.PaidInterestAmount - v.PaidFeeAmount)
+
+		dueDate := time.Date(v.DueDate.Year(), v.DueDate.Month(), v.DueDate.Day(), 0, 0, 0, 0, asOf.Location())
+		daysPastDue := 0
+		repaying := v.ConsumerTransactionStatusID == models.TRANSACTION_STATUS_DISBURSED || v.ConsumerTransactionStatusID == models.TRANSACTION_STATUS_ACTIVE
+		if unpaid > 0 && asOf.After(dueDate) && repaying {
+			daysPastDue = int(asOf.Sub(dueDate).Hours() / 24)
+		}
+
+		changed := daysPastDue != v.DaysPastDue
+		v.DaysPastDue = daysPastDue
+
+		if daysPastDue > 0 && v.LateFeeDailyRate > 0 {
+			accrueFrom := dueDate.AddDate(0, 0, v.LateFeeGraceDays)
+			if v.LateFeeAccruedOn != nil && v.LateFeeAccruedOn.After(accrueFrom) {
+				accrueFrom = time.Date(v.LateFeeAccruedOn.Year(), v.LateFeeAccruedOn.Month(), v.LateFeeAccruedOn.Day(), 0, 0, 0, 0, asOf.Location())
+			}
+
+			days := int(asOf.Sub(accrueFrom).Hours() / 24)
+			if days > 0 {
+				lateFee := v.LateFeeAmount + unpaid*v.LateFeeDailyRate/100*float64(days)
+				if v.LateFeeCapAmount > 0 {
+					lateFee = math.Min(lateFee, v.LateFeeCapAmount)
+				}
+
+				v.LateFeeAmount = math.Max(v.LateFeeAmount, library.RoundCurrency(lateFee))
+				v.LateFeeAccruedOn = &asOf
+				changed = true
+			}
+		}
+
+		if !changed {
+			continue
+		}
+
+		err = u.consumerinstallmentRepo.UpdateLateFee(ctx, v)
+		if err != nil {
+			err.Path = ".ConsumerInstallmentUsecase->AccrueLateFees()" + err.Path
+			return err
+		}
+	}
+
+	err = u.consumerinstallmentRepo.UpdateConsumerTransactionDaysPastDue(ctx, consumerTransactionID)
+	if err != nil {
+		err.Path = ".ConsumerInstallmentUsecase->AccrueLateFees()" + err.Path
+		return err
+	}
+
+	return nil
+}
+
+// LockConsumerTransaction holds the transaction row until the surrounding database transaction ends
+func (u *ConsumerInstallmentUsecase) LockConsumerTransaction(ctx *gin.Context, consumerTransactionID string) *types.Error {
+	err := u.consumerinstallmentRepo.LockConsumerTransaction(ctx, consumerTransactionID)
+	if err != nil {
+		err.Path = ".ConsumerInstallmentUsecase->LockConsumerTransaction()" + err.Path
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package consumerpayment
+
+import (
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Repository is the contract between Repository and usecase
+type Repository interface {
+	FindAll(*gin.Context, models.FindAllConsumerPaymentParams) ([]*models.ConsumerPayment, *types.Error)
+	Find(*gin.Context, string) (*models.ConsumerPayment, *types.Error)
+	Count(*gin.Context, models.FindAllConsumerPaymentParams) (int, *types.Error)
+	Create(*gin.Context, *models.ConsumerPayment) (*models.ConsumerPayment, *types.Error)
+
+	UpdateStatus(*gin.Context, string, string) (*models.ConsumerPayment, *types.Error)
+
+	// Allocation
+	FindAllocations(*gin.Context, string) ([]*models.ConsumerPaymentAllocation, *types.Error)
+	CreateAllocation(*gin.Context, *models.ConsumerPaymentAllocation) (*models.ConsumerPaymentAllocation, *types.Error)
+
+	LockConsumerTransaction(*gin.Context, string) *types.Error
+
+	// Payoff Quote
+	FindPayoffQuote(*gin.Context, string) (*models.ConsumerPayoffQuote, *types.Error)
+	CreatePayoffQuote(*gin.Context, *models.ConsumerPayoffQuote) (*models.ConsumerPayoffQuote, *types.Error)
+	UpdatePayoffQuote(*gin.Context, *models.ConsumerPayoffQuote) (*models.ConsumerPayoffQuote, *types.Error)
+}
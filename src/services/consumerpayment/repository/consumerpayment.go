@@ -0,0 +1,457 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ConsumerPaymentRepository struct {
+	repository            data.GenericStorage
+	allocationRepository  data.GenericStorage
+	statusRepository      data.GenericStorage
+	payoffQuoteRepository data.GenericStorage
+}
+
+func NewConsumerPaymentRepository(repository data.GenericStorage, allocationRepository data.GenericStorage, statusRepository data.GenericStorage, payoffQuoteRepository data.GenericStorage) ConsumerPaymentRepository {
+	return ConsumerPaymentRepository{repository: repository, allocationRepository: allocationRepository, statusRepository: statusRepository, payoffQuoteRepository: payoffQuoteRepository}
+}
+
+func (s ConsumerPaymentRepository) FindAll(ctx *gin.Context, params models.FindAllConsumerPaymentParams) ([]*models.ConsumerPayment, *types.Error) {
+	data := []*models.ConsumerPayment{}
+	bulks := []*models.ConsumerPaymentBulk{}
+
+	var err error
+
+	where := `TRUE`
+
+	if params.FindAllParams.DataFinder != "" {
+		where += fmt.Sprintf(` AND %s`, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where += fmt.Sprintf(` AND consumer_payments.%s`, params.FindAllParams.StatusID)
+	}
+
+	if params.ConsumerTransactionID != "" {
+		where += ` AND consumer_payments.consumer_transaction_id = :consumer_transaction_id`
+	}
+
+	if params.PaymentType != "" {
+		where += ` AND consumer_payments.payment_type = :payment_type`
+	}
+
+	if params.MinPaymentDate != "" {
+		where += ` AND consumer_payments.payment_date >= :min_payment_date`
+	}
+
+	if params.MaxPaymentDate != "" {
+		where += ` AND consumer_payments.payment_date <= :max_payment_date`
+	}
+
+	if params.FindAllParams.SortBy != "" {
+		where += fmt.Sprintf(` ORDER BY %s`, params.FindAllParams.SortBy)
+	}
+
+	if params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0 {
+		where += ` LIMIT :limit OFFSET :offset`
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    consumer_payments.id, consumer_payments.consumer_transaction_id, consumer_payments.payment_type,
+    consumer_payments.payment_date, consumer_payments.amount, consumer_payments.reference_number,
+    consumer_payments.reversal_of_id, consumer_payments.reason,
+    consumer_payments.status_id, status.name status_name, consumer_transactions.contract_number
+  FROM consumer_payments
+  JOIN status ON consumer_payments.status_id = status.id
+  JOIN consumer_transactions ON consumer_transactions.id = consumer_payments.consumer_transaction_id
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"limit":                   params.FindAllParams.Size,
+		"offset":                  ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"status_id":               params.FindAllParams.StatusID,
+		"consumer_transaction_id": params.ConsumerTransactionID,
+		"payment_type":            params.PaymentType,
+		"min_payment_date":        params.MinPaymentDate,
+		"max_payment_date":        params.MaxPaymentDate,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		obj := &models.ConsumerPayment{
+			ID:                    v.ID,
+			ConsumerTransactionID: v.ConsumerTransactionID,
+			ConsumerTransaction: &models.IDNameTemplate{
+				ID:   v.ConsumerTransactionID,
+				Name: v.ContractNumber,
+			},
+			PaymentType:     v.PaymentType,
+			PaymentDate:     v.PaymentDate,
+			Amount:          v.Amount,
+			ReferenceNumber: v.ReferenceNumber,
+			ReversalOfID:    v.ReversalOfID,
+			Reason:          v.Reason,
+			StatusID:        v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+
+		data = append(data, obj)
+	}
+
+	return data, nil
+}
+
+func (s ConsumerPaymentRepository) Find(ctx *gin.Context, id string) (*models.ConsumerPayment, *types.Error) {
+	result := models.ConsumerPayment{}
+	bulks := []*models.ConsumerPaymentBulk{}
+	var err error
+
+	query := `
+  SELECT
+    consumer_payments.id, consumer_payments.consumer_transaction_id, consumer_payments.payment_type,
+    consumer_payments.payment_date, consumer_payments.amount, consumer_payments.reference_number,
+    consumer_payments.reversal_of_id, consumer_payments.reason,
+    consumer_payments.status_id, status.name status_name, consumer_transactions.contract_number
+  FROM consumer_payments
+  JOIN status ON consumer_payments.status_id = status.id
+  JOIN consumer_transactions ON consumer_transactions.id = consumer_payments.consumer_transaction_id
+  WHERE consumer_payments.id = :id`
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(bulks) > 0 {
+		v := bulks[0]
+		result = models.ConsumerPayment{
+			ID:                    v.ID,
+			ConsumerTransactionID: v.ConsumerTransactionID,
+			ConsumerTransaction: &models.IDNameTemplate{
+				ID:   v.ConsumerTransactionID,
+				Name: v.ContractNumber,
+			},
+			PaymentType:     v.PaymentType,
+			PaymentDate:     v.PaymentDate,
+			Amount:          v.Amount,
+			ReferenceNumber: v.ReferenceNumber,
+			ReversalOfID:    v.ReversalOfID,
+			Reason:          v.Reason,
+			StatusID:        v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+	} else {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentStorage->Find()",
+			Message:    "Data Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &result, nil
+}
+
+func (s ConsumerPaymentRepository) Count(ctx *gin.Context, params models.FindAllConsumerPaymentParams) (int, *types.Error) {
+	bulks := []*models.ConsumerPaymentBulk{}
+
+	var err error
+
+	where := `TRUE`
+
+	if params.FindAllParams.DataFinder != "" {
+		where += fmt.Sprintf(` AND %s`, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where += fmt.Sprintf(` AND consumer_payments.%s`, params.FindAllParams.StatusID)
+	}
+
+	if params.ConsumerTransactionID != "" {
+		where += ` AND consumer_payments.consumer_transaction_id = :consumer_transaction_id`
+	}
+
+	if params.PaymentType != "" {
+		where += ` AND consumer_payments.payment_type = :payment_type`
+	}
+
+	if params.MinPaymentDate != "" {
+		where += ` AND consumer_payments.payment_date >= :min_payment_date`
+	}
+
+	if params.MaxPaymentDate != "" {
+		where += ` AND consumer_payments.payment_date <= :max_payment_date`
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    consumer_payments.id, consumer_payments.consumer_transaction_id, consumer_payments.payment_type,
+    consumer_payments.payment_date, consumer_payments.amount, consumer_payments.reference_number,
+    consumer_payments.reversal_of_id, consumer_payments.reason,
+    consumer_payments.status_id, status.name status_name, consumer_transactions.contract_number
+  FROM consumer_payments
+  JOIN status ON consumer_payments.status_id = status.id
+  JOIN consumer_transactions ON consumer_transactions.id = consumer_payments.consumer_transaction_id
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"status_id":               params.FindAllParams.StatusID,
+		"consumer_transaction_id": params.ConsumerTransactionID,
+		"payment_type":            params.PaymentType,
+		"min_payment_date":        params.MinPaymentDate,
+		"max_payment_date":        params.MaxPaymentDate,
+	})
+	if err != nil {
+		return 0, &types.Error{
+			Path:       ".ConsumerPaymentStorage->Count()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return len(bulks), nil
+}
+
+func (s ConsumerPaymentRepository) Create(ctx *gin.Context, obj *models.ConsumerPayment) (*models.ConsumerPayment, *types.Error) {
+	data := models.ConsumerPayment{}
+	_, err := s.repository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+func (s ConsumerPaymentRepository) UpdateStatus(ctx *gin.Context, id string, statusID string) (*models.ConsumerPayment, *types.Error) {
+	data := models.ConsumerPayment{}
+	err := s.repository.UpdateStatus(ctx, id, statusID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, id)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &data, nil
+}
+
+// ALLOCATION
+
+func (s ConsumerPaymentRepository) FindAllocations(ctx *gin.Context, consumerPaymentID string) ([]*models.ConsumerPaymentAllocation, *types.Error) {
+	data := []*models.ConsumerPaymentAllocation{}
+
+	err := s.allocationRepository.Where(ctx, &data, "consumer_payment_id = :consumer_payment_id", map[string]interface{}{
+		"consumer_payment_id": consumerPaymentID,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentStorage->FindAllocations()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return data, nil
+}
+
+func (s ConsumerPaymentRepository) CreateAllocation(ctx *gin.Context, obj *models.ConsumerPaymentAllocation) (*models.ConsumerPaymentAllocation, *types.Error) {
+	data := models.ConsumerPaymentAllocation{}
+	_, err := s.allocationRepository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentStorage->CreateAllocation()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.allocationRepository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentStorage->CreateAllocation()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+// LockConsumerTransaction takes a row lock on the transaction so concurrent payments on one contract are allocated one after another.
+// It only serializes when called inside a database transaction.
+func (s ConsumerPaymentRepository) LockConsumerTransaction(ctx *gin.Context, consumerTransactionID string) *types.Error {
+	rows := []*models.IDNameTemplate{}
+
+	query := `SELECT consumer_transactions.id, consumer_transactions.contract_number name FROM consumer_transactions WHERE consumer_transactions.id = :id FOR UPDATE`
+
+	err := s.repository.SelectWithQuery(ctx, &rows, query, map[string]interface{}{"id": consumerTransactionID})
+	if err != nil {
+		return &types.Error{
+			Path:       ".ConsumerPaymentStorage->LockConsumerTransaction()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(rows) == 0 {
+		return &types.Error{
+			Path:       ".ConsumerPaymentStorage->LockConsumerTransaction()",
+			Message:    "Consumer Transaction Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
+
+// PAYOFF QUOTE
+
+func (s ConsumerPaymentRepository) FindPayoffQuote(ctx *gin.Context, id string) (*models.ConsumerPayoffQuote, *types.Error) {
+	result := models.ConsumerPayoffQuote{}
+
+	err := s.payoffQuoteRepository.FindByID(ctx, &result, id)
+	if err != nil {
+		if err == data.ErrNotFound {
+			return nil, &types.Error{
+				Path:       ".ConsumerPaymentStorage->FindPayoffQuote()",
+				Message:    "Data Not Found",
+				Error:      data.ErrNotFound,
+				StatusCode: http.StatusNotFound,
+				Type:       "mysql-error",
+			}
+		}
+
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentStorage->FindPayoffQuote()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &result, nil
+}
+
+func (s ConsumerPaymentRepository) CreatePayoffQuote(ctx *gin.Context, obj *models.ConsumerPayoffQuote) (*models.ConsumerPayoffQuote, *types.Error) {
+	data := models.ConsumerPayoffQuote{}
+	_, err := s.payoffQuoteRepository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentStorage->CreatePayoffQuote()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.payoffQuoteRepository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentStorage->CreatePayoffQuote()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+func (s ConsumerPaymentRepository) UpdatePayoffQuote(ctx *gin.Context, obj *models.ConsumerPayoffQuote) (*models.ConsumerPayoffQuote, *types.Error) {
+	data := models.ConsumerPayoffQuote{}
+	err := s.payoffQuoteRepository.Update(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentStorage->UpdatePayoffQuote()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.payoffQuoteRepository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentStorage->UpdatePayoffQuote()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
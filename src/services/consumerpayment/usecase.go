@@ -0,0 +1,23 @@
+package consumerpayment
+
+import (
+	"time"
+
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Usecase is the contract between Repository and usecase
+type Usecase interface {
+	FindAll(*gin.Context, models.FindAllConsumerPaymentParams) ([]*models.ConsumerPayment, *types.Error)
+	Find(*gin.Context, string) (*models.ConsumerPayment, *types.Error)
+	Count(*gin.Context, models.FindAllConsumerPaymentParams) (int, *types.Error)
+	Create(*gin.Context, models.ConsumerPayment) (*models.ConsumerPayment, *types.Error)
+	Reverse(*gin.Context, string, string) (*models.ConsumerPayment, *types.Error)
+
+	// Early Settlement
+	Quote(*gin.Context, string, time.Time) (*models.ConsumerPayoffQuote, *types.Error)
+	Settle(*gin.Context, string, string) (*models.ConsumerPayment, *types.Error)
+}
@@ -0,0 +1,748 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/src/services/consumerinstallment"
+	"case-study-kredit-plus/src/services/consumerpayment"
+	"case-study-kredit-plus/src/services/consumertransaction"
+	"case-study-kredit-plus/src/services/loanproduct"
+
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"github.com/jmoiron/sqlx"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+type ConsumerPaymentUsecase struct {
+	consumerpaymentRepo        consumerpayment.Repository
+	consumerinstallmentUsecase consumerinstallment.Usecase
+	consumertransactionUsecase consumertransaction.Usecase
+	loanproductUsecase         loanproduct.Usecase
+	contextTimeout             time.Duration
+	db                         *sqlx.DB
+}
+
+func NewConsumerPaymentUsecase(db *sqlx.DB, consumerpaymentRepo consumerpayment.Repository, consumerinstallmentUsecase consumerinstallment.Usecase, consumertransactionUsecase consumertransaction.Usecase, loanproductUsecase loanproduct.Usecase) consumerpayment.Usecase {
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	return &ConsumerPaymentUsecase{
+		consumerpaymentRepo:        consumerpaymentRepo,
+		consumerinstallmentUsecase: consumerinstallmentUsecase,
+		consumertransactionUsecase: consumertransactionUsecase,
+		loanproductUsecase:         loanproductUsecase,
+		contextTimeout:             timeoutContext,
+		db:                         db,
+	}
+}
+
+func (u *ConsumerPaymentUsecase) FindAll(ctx *gin.Context, params models.FindAllConsumerPaymentParams) ([]*models.ConsumerPayment, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(params)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentUsecase->FindAll()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.consumerpaymentRepo.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->FindAll()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *ConsumerPaymentUsecase) Find(ctx *gin.Context, id string) (*models.ConsumerPayment, *types.Error) {
+	result, err := u.consumerpaymentRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	allocationID := result.ID
+	if result.PaymentType == models.PAYMENT_TYPE_REVERSAL {
+		allocationID = result.ReversalOfID
+	}
+
+	result.Allocations, err = u.consumerpaymentRepo.FindAllocations(ctx, allocationID)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *ConsumerPaymentUsecase) Count(ctx *gin.Context, params models.FindAllConsumerPaymentParams) (int, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(params)
+	if errValidation != nil {
+		return 0, &types.Error{
+			Path:       ".ConsumerPaymentUsecase->Count()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.consumerpaymentRepo.Count(ctx, params)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Count()" + err.Path
+		return 0, err
+	}
+
+	return result, nil
+}
+
+// Create records a payment against a transaction and allocates it over the unpaid installments in due order.
+// Within each installment the amount settles late fees first, then fees, interest and principal. Partial payments leave
+// the remainder on the installment; paying more than the outstanding balance is rejected.
+func (u *ConsumerPaymentUsecase) Create(ctx *gin.Context, obj models.ConsumerPayment) (*models.ConsumerPayment, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentUsecase->Create()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	err := u.consumerpaymentRepo.LockConsumerTransaction(ctx, obj.ConsumerTransactionID)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	trx, err := u.findOpenTransaction(ctx, obj.ConsumerTransactionID)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	var params models.FindAllConsumerInstallmentParams
+	params.ConsumerTransactionID = obj.ConsumerTransactionID
+	params.IsUnpaid = true
+	params.FindAllParams.SortBy = "consumer_installments.installment_number ASC"
+
+	installments, err := u.consumerinstallmentUsecase.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	amount := library.RoundCurrency(obj.Amount)
+
+	outstanding := 0.0
+	for _, v := range installments {
+		outstanding += v.InstallmentAmount - v.PaidPrincipalAmount - v.PaidInterestAmount - v.PaidFeeAmount
+		outstanding += v.LateFeeAmount - v.PaidLateFeeAmount
+	}
+
+	if amount > library.RoundCurrency(outstanding) {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentUsecase->Create()",
+			Message:    "Payment Amount Exceeds Outstanding Balance",
+			Error:      fmt.Errorf("Payment Amount Exceeds Outstanding Balance"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	paymentDate := obj.PaymentDate
+	if paymentDate.IsZero() {
+		paymentDate = library.UTCPlus7()
+	}
+
+	data := models.ConsumerPayment{
+		ID:                    uuid.New().String(),
+		ConsumerTransactionID: obj.ConsumerTransactionID,
+		PaymentType:           models.PAYMENT_TYPE_PAYMENT,
+		PaymentDate:           time.Date(paymentDate.Year(), paymentDate.Month(), paymentDate.Day(), 0, 0, 0, 0, paymentDate.Location()),
+		Amount:                amount,
+		ReferenceNumber:       obj.ReferenceNumber,
+		StatusID:              models.DEFAULT_STATUS_ID,
+	}
+
+	result, err := u.consumerpaymentRepo.Create(ctx, &data)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	remaining := amount
+	allocations := []*models.ConsumerPaymentAllocation{}
+	for _, v := range installments {
+		if remaining <= 0 {
+			break
+		}
+
+		lateFee := library.RoundCurrency(math.Min(remaining, v.LateFeeAmount-v.PaidLateFeeAmount))
+		remaining = library.RoundCurrency(remaining - lateFee)
+
+		fee := library.RoundCurrency(math.Min(remaining, v.FeeAmount-v.PaidFeeAmount))
+		remaining = library.RoundCurrency(remaining - fee)
+
+		interest := library.RoundCurrency(math.Min(remaining, v.InterestAmount-v.PaidInterestAmount))
+		remaining = library.RoundCurrency(remaining - interest)
+
+		principal := library.RoundCurrency(math.Min(remaining, v.PrincipalAmount-v.PaidPrincipalAmount))
+		remaining = library.RoundCurrency(remaining - principal)
+
+		if lateFee+fee+interest+principal <= 0 {
+			continue
+		}
+
+		v.PaidLateFeeAmount = library.RoundCurrency(v.PaidLateFeeAmount + lateFee)
+		v.PaidFeeAmount = library.RoundCurrency(v.PaidFeeAmount + fee)
+		v.PaidInterestAmount = library.RoundCurrency(v.PaidInterestAmount + interest)
+		v.PaidPrincipalAmount = library.RoundCurrency(v.PaidPrincipalAmount + principal)
+
+		_, err = u.consumerinstallmentUsecase.Update(ctx, v.ID, *v)
+		if err != nil {
+			err.Path = ".ConsumerPaymentUsecase->Create()" + err.Path
+			return nil, err
+		}
+
+		allocation := models.ConsumerPaymentAllocation{
+			ID:                    uuid.New().String(),
+			ConsumerPaymentID:     result.ID,
+			ConsumerInstallmentID: v.ID,
+			LateFeeAmount:         lateFee,
+			FeeAmount:             fee,
+			InterestAmount:        interest,
+			PrincipalAmount:       principal,
+			StatusID:              models.DEFAULT_STATUS_ID,
+		}
+
+		allocationResult, err := u.consumerpaymentRepo.CreateAllocation(ctx, &allocation)
+		if err != nil {
+			err.Path = ".ConsumerPaymentUsecase->Create()" + err.Path
+			return nil, err
+		}
+
+		allocations = append(allocations, allocationResult)
+	}
+
+	result.Allocations = allocations
+
+	err = u.payOffWhenSettled(ctx, trx)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Reverse undoes the allocations of a payment and records a reversal entry pointing at it.
+// The reversed payment is set inactive so it cannot be reversed twice.
+func (u *ConsumerPaymentUsecase) Reverse(ctx *gin.Context, id string, reason string) (*models.ConsumerPayment, *types.Error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentUsecase->Reverse()",
+			Message:    "Reason is required",
+			Error:      fmt.Errorf("Reason is required"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	payment, err := u.consumerpaymentRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Reverse()" + err.Path
+		return nil, err
+	}
+
+	if payment.PaymentType != models.PAYMENT_TYPE_PAYMENT {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentUsecase->Reverse()",
+			Message:    "Only payments can be reversed",
+			Error:      fmt.Errorf("Only payments can be reversed"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	err = u.consumerpaymentRepo.LockConsumerTransaction(ctx, payment.ConsumerTransactionID)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Reverse()" + err.Path
+		return nil, err
+	}
+
+	// a paid off transaction is reopened below, other closed ones already gave their limit back for good
+	trx, err := u.consumertransactionUsecase.Find(ctx, payment.ConsumerTransactionID)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Reverse()" + err.Path
+		return nil, err
+	}
+
+	if trx.StatusID != models.TRANSACTION_STATUS_PAID_OFF {
+		_, err = u.findOpenTransaction(ctx, payment.ConsumerTransactionID)
+		if err != nil {
+			err.Path = ".ConsumerPaymentUsecase->Reverse()" + err.Path
+			return nil, err
+		}
+	}
+
+	// read the status again under the lock so two reversals of the same payment cannot both pass
+	payment, err = u.consumerpaymentRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Reverse()" + err.Path
+		return nil, err
+	}
+
+	if payment.StatusID != models.STATUS_ACTIVE {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentUsecase->Reverse()",
+			Message:    "Payment already reversed",
+			Error:      fmt.Errorf("Payment already reversed"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	allocations, err := u.consumerpaymentRepo.FindAllocations(ctx, payment.ID)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Reverse()" + err.Path
+		return nil, err
+	}
+
+	for _, v := range allocations {
+		installment, err := u.consumerinstallmentUsecase.Find(ctx, v.ConsumerInstallmentID)
+		if err != nil {
+			err.Path = ".ConsumerPaymentUsecase->Reverse()" + err.Path
+			return nil, err
+		}
+
+		installment.PaidLateFeeAmount = math.Max(0, library.RoundCurrency(installment.PaidLateFeeAmount-v.LateFeeAmount))
+		installment.PaidFeeAmount = math.Max(0, library.RoundCurrency(installment.PaidFeeAmount-v.FeeAmount))
+		installment.PaidInterestAmount = math.Max(0, library.RoundCurrency(installment.PaidInterestAmount-v.InterestAmount))
+		installment.PaidPrincipalAmount = math.Max(0, library.RoundCurrency(installment.PaidPrincipalAmount-v.PrincipalAmount))
+
+		_, err = u.consumerinstallmentUsecase.Update(ctx, installment.ID, *installment)
+		if err != nil {
+			err.Path = ".ConsumerPaymentUsecase->Reverse()" + err.Path
+			return nil, err
+		}
+	}
+
+	paymentDate := library.UTCPlus7()
+
+	data := models.ConsumerPayment{
+		ID:                    uuid.New().String(),
+		ConsumerTransactionID: payment.ConsumerTransactionID,
+		PaymentType:           models.PAYMENT_TYPE_REVERSAL,
+		PaymentDate:           time.Date(paymentDate.Year(), paymentDate.Month(), paymentDate.Day(), 0, 0, 0, 0, paymentDate.Location()),
+		Amount:                payment.Amount,
+		ReferenceNumber:       payment.ReferenceNumber,
+		ReversalOfID:          payment.ID,
+		Reason:                reason,
+		StatusID:              models.DEFAULT_STATUS_ID,
+	}
+
+	result, err := u.consumerpaymentRepo.Create(ctx, &data)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Reverse()" + err.Path
+		return nil, err
+	}
+
+	_, err = u.consumerpaymentRepo.UpdateStatus(ctx, payment.ID, models.STATUS_INACTIVE)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Reverse()" + err.Path
+		return nil, err
+	}
+
+	// the installments it paid are owed again, so the contract is no longer paid off
+	if trx.StatusID == models.TRANSACTION_STATUS_PAID_OFF {
+		_, err = u.consumertransactionUsecase.Reopen(ctx, trx.ID, reason)
+		if err != nil {
+			err.Path = ".ConsumerPaymentUsecase->Reverse()" + err.Path
+			return nil, err
+		}
+	}
+
+	result.Allocations = allocations
+
+	return result, nil
+}
+
+// findOpenTransaction returns the transaction if it is being repaid, payments only go to disbursed and active contracts
+func (u *ConsumerPaymentUsecase) findOpenTransaction(ctx *gin.Context, id string) (*models.ConsumerTransaction, *types.Error) {
+	result, err := u.consumertransactionUsecase.Find(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->findOpenTransaction()" + err.Path
+		return nil, err
+	}
+
+	if result.StatusID != models.TRANSACTION_STATUS_DISBURSED && result.StatusID != models.TRANSACTION_STATUS_ACTIVE {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentUsecase->findOpenTransaction()",
+			Message:    "Only disbursed and active transactions take payments",
+			Error:      fmt.Errorf("Only disbursed and active transactions take payments"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	return result, nil
+}
+
+// payOffWhenSettled marks the transaction paid off once no installment is left unpaid, which gives the principal
+// back to the credit limit. A disbursed transaction goes through active first, as the lifecycle requires.
+func (u *ConsumerPaymentUsecase) payOffWhenSettled(ctx *gin.Context, trx *models.ConsumerTransaction) *types.Error {
+	var params models.FindAllConsumerInstallmentParams
+	params.ConsumerTransactionID = trx.ID
+	params.IsUnpaid = true
+
+	count, err := u.consumerinstallmentUsecase.Count(ctx, params)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->payOffWhenSettled()" + err.Path
+		return err
+	}
+
+	if count > 0 {
+		return nil
+	}
+
+	if trx.StatusID == models.TRANSACTION_STATUS_DISBURSED {
+		_, err = u.consumertransactionUsecase.UpdateStatus(ctx, trx.ID, models.TRANSACTION_STATUS_ACTIVE)
+		if err != nil {
+			err.Path = ".ConsumerPaymentUsecase->payOffWhenSettled()" + err.Path
+			return err
+		}
+	}
+
+	_, err = u.consumertransactionUsecase.UpdateStatus(ctx, trx.ID, models.TRANSACTION_STATUS_PAID_OFF)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->payOffWhenSettled()" + err.Path
+		return err
+	}
+
+	return nil
+}
+
+// EARLY SETTLEMENT
+
+// payoffAllocation is what a settlement pays into one installment
+type payoffAllocation struct {
+	installment *models.ConsumerInstallment
+	lateFee     float64
+	fee         float64
+	interest    float64
+	principal   float64
+}
+
+// Quote computes what it takes to close a disbursed or active transaction on the given date: the remaining principal,
+// interest accrued up to that date, outstanding fees and late fees, and the loan product's early settlement penalty.
+// The quote is stored and can only be settled on its quote date.
+func (u *ConsumerPaymentUsecase) Quote(ctx *gin.Context, consumerTransactionID string, quoteDate time.Time) (*models.ConsumerPayoffQuote, *types.Error) {
+	today := library.UTCPlus7()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+
+	if quoteDate.IsZero() {
+		quoteDate = today
+	}
+	quoteDate = time.Date(quoteDate.Year(), quoteDate.Month(), quoteDate.Day(), 0, 0, 0, 0, today.Location())
+
+	if quoteDate.Before(today) {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentUsecase->Quote()",
+			Message:    "Quote Date must not be in the past",
+			Error:      fmt.Errorf("Quote Date must not be in the past"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	quote, _, err := u.calculatePayoff(ctx, consumerTransactionID, quoteDate)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Quote()" + err.Path
+		return nil, err
+	}
+
+	result, err := u.consumerpaymentRepo.CreatePayoffQuote(ctx, quote)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Quote()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Settle pays a payoff quote in full, closes the installments and marks the transaction paid off,
+// which gives the principal back to the credit limit. The quote is recalculated under the transaction lock
+// and rejected when a payment in between changed the figures.
+func (u *ConsumerPaymentUsecase) Settle(ctx *gin.Context, payoffQuoteID string, referenceNumber string) (*models.ConsumerPayment, *types.Error) {
+	quote, err := u.consumerpaymentRepo.FindPayoffQuote(ctx, payoffQuoteID)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Settle()" + err.Path
+		return nil, err
+	}
+
+	err = u.consumerpaymentRepo.LockConsumerTransaction(ctx, quote.ConsumerTransactionID)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Settle()" + err.Path
+		return nil, err
+	}
+
+	// read the quote again under the lock so it cannot be settled twice
+	quote, err = u.consumerpaymentRepo.FindPayoffQuote(ctx, payoffQuoteID)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Settle()" + err.Path
+		return nil, err
+	}
+
+	if quote.ConsumerPaymentID != "" {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentUsecase->Settle()",
+			Message:    "Payoff Quote already settled",
+			Error:      fmt.Errorf("Payoff Quote already settled"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	now := library.UTCPlus7()
+	if now.Before(quote.QuoteDate) || now.After(quote.ExpiresAt) {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentUsecase->Settle()",
+			Message:    "Payoff Quote is only valid on its Quote Date",
+			Error:      fmt.Errorf("Payoff Quote is only valid on its Quote Date"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	trx, err := u.findOpenTransaction(ctx, quote.ConsumerTransactionID)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Settle()" + err.Path
+		return nil, err
+	}
+
+	current, allocations, err := u.calculatePayoff(ctx, quote.ConsumerTransactionID, quote.QuoteDate)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Settle()" + err.Path
+		return nil, err
+	}
+
+	if current.TotalAmount != quote.TotalAmount {
+		return nil, &types.Error{
+			Path:       ".ConsumerPaymentUsecase->Settle()",
+			Message:    "Payoff Quote is outdated, request a new one",
+			Error:      fmt.Errorf("Payoff Quote is outdated, request a new one"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	data := models.ConsumerPayment{
+		ID:                    uuid.New().String(),
+		ConsumerTransactionID: quote.ConsumerTransactionID,
+		PaymentType:           models.PAYMENT_TYPE_SETTLEMENT,
+		PaymentDate:           quote.QuoteDate,
+		Amount:                quote.TotalAmount,
+		ReferenceNumber:       referenceNumber,
+		StatusID:              models.DEFAULT_STATUS_ID,
+	}
+
+	result, err := u.consumerpaymentRepo.Create(ctx, &data)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Settle()" + err.Path
+		return nil, err
+	}
+
+	results := []*models.ConsumerPaymentAllocation{}
+	for _, v := range allocations {
+		installment := *v.installment
+		installment.InterestAmount = library.RoundCurrency(installment.PaidInterestAmount + v.interest)
+		installment.FeeAmount = library.RoundCurrency(installment.PaidFeeAmount + v.fee)
+		installment.PaidInterestAmount = installment.InterestAmount
+		installment.PaidFeeAmount = installment.FeeAmount
+		installment.PaidPrincipalAmount = installment.PrincipalAmount
+		installment.PaidLateFeeAmount = installment.LateFeeAmount
+
+		_, err = u.consumerinstallmentUsecase.Settle(ctx, installment.ID, installment)
+		if err != nil {
+			err.Path = ".ConsumerPaymentUsecase->Settle()" + err.Path
+			return nil, err
+		}
+
+		allocation := models.ConsumerPaymentAllocation{
+			ID:                    uuid.New().String(),
+			ConsumerPaymentID:     result.ID,
+			ConsumerInstallmentID: installment.ID,
+			LateFeeAmount:         v.lateFee,
+			FeeAmount:             v.fee,
+			InterestAmount:        v.interest,
+			PrincipalAmount:       v.principal,
+			StatusID:              models.DEFAULT_STATUS_ID,
+		}
+
+		allocationResult, err := u.consumerpaymentRepo.CreateAllocation(ctx, &allocation)
+		if err != nil {
+			err.Path = ".ConsumerPaymentUsecase->Settle()" + err.Path
+			return nil, err
+		}
+
+		results = append(results, allocationResult)
+	}
+
+	quote.ConsumerPaymentID = result.ID
+
+	_, err = u.consumerpaymentRepo.UpdatePayoffQuote(ctx, quote)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Settle()" + err.Path
+		return nil, err
+	}
+
+	// a disbursed transaction goes through active first, as the lifecycle requires
+	if trx.StatusID == models.TRANSACTION_STATUS_DISBURSED {
+		_, err = u.consumertransactionUsecase.UpdateStatus(ctx, quote.ConsumerTransactionID, models.TRANSACTION_STATUS_ACTIVE)
+		if err != nil {
+			err.Path = ".ConsumerPaymentUsecase->Settle()" + err.Path
+			return nil, err
+		}
+	}
+
+	_, err = u.consumertransactionUsecase.UpdateStatus(ctx, quote.ConsumerTransactionID, models.TRANSACTION_STATUS_PAID_OFF)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->Settle()" + err.Path
+		return nil, err
+	}
+
+	result.Allocations = results
+
+	return result, nil
+}
+
+// calculatePayoff splits the payoff over the open installments. Interest of installments due by the quote date
+// is owed in full, the running installment accrues it by day, and later interest is waived.
+// The penalty is charged on the remaining principal and booked as fee on the first open installment.
+func (u *ConsumerPaymentUsecase) calculatePayoff(ctx *gin.Context, consumerTransactionID string, quoteDate time.Time) (*models.ConsumerPayoffQuote, []*payoffAllocation, *types.Error) {
+	// a contract can be settled early whenever it takes payments
+	trx, err := u.findOpenTransaction(ctx, consumerTransactionID)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->calculatePayoff()" + err.Path
+		return nil, nil, err
+	}
+
+	product, err := u.loanproductUsecase.Find(ctx, trx.LoanProductID)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->calculatePayoff()" + err.Path
+		return nil, nil, err
+	}
+
+	var params models.FindAllConsumerInstallmentParams
+	params.ConsumerTransactionID = consumerTransactionID
+	params.FindAllParams.SortBy = "consumer_installments.installment_number ASC"
+
+	installments, err := u.consumerinstallmentUsecase.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".ConsumerPaymentUsecase->calculatePayoff()" + err.Path
+		return nil, nil, err
+	}
+
+	quote := &models.ConsumerPayoffQuote{
+		ID:                    uuid.New().String(),
+		ConsumerTransactionID: consumerTransactionID,
+		QuoteDate:             quoteDate,
+		ExpiresAt:             time.Date(quoteDate.Year(), quoteDate.Month(), quoteDate.Day(), 23, 59, 59, 0, quoteDate.Location()),
+		StatusID:              models.DEFAULT_STATUS_ID,
+	}
+
+	// the schedule runs from the day the transaction was booked
+	periodStart := time.Date(trx.CreatedAt.Year(), trx.CreatedAt.Month(), trx.CreatedAt.Day(), 0, 0, 0, 0, trx.CreatedAt.Location())
+
+	allocations := []*payoffAllocation{}
+	for _, v := range installments {
+		periodEnd := v.DueDate
+		accrued := v.InterestAmount
+		if periodEnd.After(quoteDate) {
+			accrued = 0
+			if quoteDate.After(periodStart) {
+				accrued = v.InterestAmount * quoteDate.Sub(periodStart).Hours() / periodEnd.Sub(periodStart).Hours()
+			}
+		}
+		periodStart = periodEnd
+
+		if v.PaidPrincipalAmount+v.PaidInterestAmount+v.PaidFeeAmount+v.PaidLateFeeAmount >= v.InstallmentAmount+v.LateFeeAmount {
+			continue
+		}
+
+		allocation := &payoffAllocation{
+			installment: v,
+			lateFee:     library.RoundCurrency(v.LateFeeAmount - v.PaidLateFeeAmount),
+			fee:         library.RoundCurrency(v.FeeAmount - v.PaidFeeAmount),
+			interest:    math.Max(0, library.RoundCurrency(accrued-v.PaidInterestAmount)),
+			principal:   library.RoundCurrency(v.PrincipalAmount - v.PaidPrincipalAmount),
+		}
+
+		quote.FeeAmount = library.RoundCurrency(quote.FeeAmount + allocation.lateFee + allocation.fee)
+		quote.InterestAmount = library.RoundCurrency(quote.InterestAmount + allocation.interest)
+		quote.PrincipalAmount = library.RoundCurrency(quote.PrincipalAmount + allocation.principal)
+
+		allocations = append(allocations, allocation)
+	}
+
+	if len(allocations) == 0 {
+		return nil, nil, &types.Error{
+			Path:       ".ConsumerPaymentUsecase->calculatePayoff()",
+			Message:    "Transaction has nothing left to settle",
+			Error:      fmt.Errorf("Transaction has nothing left to settle"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	quote.PenaltyAmount = library.RoundCurrency(product.EarlySettlementPenaltyAmount + quote.PrincipalAmount*product.EarlySettlementPenaltyRate/100)
+	allocations[0].fee = library.RoundCurrency(allocations[0].fee + quote.PenaltyAmount)
+
+	quote.TotalAmount = library.RoundCurrency(quote.PrincipalAmount + quote.InterestAmount + quote.FeeAmount + quote.PenaltyAmount)
+
+	return quote, allocations, nil
+}
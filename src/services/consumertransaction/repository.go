@@ -15,6 +15,13 @@ type Repository interface {
 	Create(*gin.Context, *models.ConsumerTransaction) (*models.ConsumerTransaction, *types.Error)
 	Update(*gin.Context, *models.ConsumerTransaction) (*models.ConsumerTransaction, *types.Error)
 
-	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	FindStatus(*gin.Context) ([]*models.ConsumerTransactionStatus, *types.Error)
 	UpdateStatus(*gin.Context, string, string) (*models.ConsumerTransaction, *types.Error)
+	LockConsumerTransaction(*gin.Context, string) *types.Error
+	FindConsumerIDForUpdate(*gin.Context, string) (string, *types.Error)
+	NextContractNumberSequence(*gin.Context, string) (int, *types.Error)
+
+	FindStatusHistories(*gin.Context, string) ([]*models.ConsumerTransactionStatusHistory, *types.Error)
+	CreateStatusHistory(*gin.Context, *models.ConsumerTransactionStatusHistory) *types.Error
+	CreateReversalTrail(*gin.Context, string) *types.Error
 }
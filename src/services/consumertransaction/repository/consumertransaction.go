@@ -3,7 +3,10 @@ package repository
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/appcontext"
 	"case-study-kredit-plus/library/data"
 	"case-study-kredit-plus/library/types"
 	"case-study-kredit-plus/models"
@@ -12,12 +15,13 @@ import (
 )
 
 type ConsumerTransactionRepository struct {
-	repository       data.GenericStorage
-	statusRepository data.GenericStorage
+	repository        data.GenericStorage
+	statusRepository  data.GenericStorage
+	historyRepository data.GenericStorage
 }
 
-func NewConsumerTransactionRepository(repository data.GenericStorage, statusRepository data.GenericStorage) ConsumerTransactionRepository {
-	return ConsumerTransactionRepository{repository: repository, statusRepository: statusRepository}
+func NewConsumerTransactionRepository(repository data.GenericStorage, statusRepository data.GenericStorage, historyRepository data.GenericStorage) ConsumerTransactionRepository {
+	return ConsumerTransactionRepository{repository: repository, statusRepository: statusRepository, historyRepository: historyRepository}
 }
 
 func (s ConsumerTransactionRepository) FindAll(ctx *gin.Context, params models.FindAllConsumerTransactionParams) ([]*models.ConsumerTransaction, *types.Error) {
@@ -44,10 +48,22 @@ func (s ConsumerTransactionRepository) FindAll(ctx *gin.Context, params models.F
 		where += fmt.Sprintf(` AND consumer_transactions.contract_number LIKE "%%%s%%"`, params.ContractNumber)
 	}
 
+	if params.LoanProductID != "" {
+		where += ` AND consumer_transactions.loan_product_id = :loan_product_id`
+	}
+
 	if params.LoanTerm != 0 {
 		where += fmt.Sprintf(` AND consumer_transactions.loan_term = %d`, params.LoanTerm)
 	}
 
+	if params.MinDaysPastDue != 0 {
+		where += fmt.Sprintf(` AND consumer_transactions.days_past_due >= %d`, params.MinDaysPastDue)
+	}
+
+	if params.MerchantID != "" {
+		where += ` AND consumer_transactions.merchant_id = :merchant_id`
+	}
+
 	if params.FindAllParams.SortBy != "" {
 		where += fmt.Sprintf(` ORDER BY %s`, params.FindAllParams.SortBy)
 	}
@@ -58,23 +74,30 @@ func (s ConsumerTransactionRepository) FindAll(ctx *gin.Context, params models.F
 
 	query := fmt.Sprintf(`
   SELECT
-    consumer_transactions.id, consumer_transactions.consumer_id, consumer_transactions.contract_number, consumer_transactions.OTR,
+    consumer_transactions.id, consumer_transactions.consumer_id, consumer_transactions.contract_number, consumer_transactions.loan_product_id, consumer_transactions.OTR,
     consumer_transactions.admin_fee, consumer_transactions.installment_amount, consumer_transactions.loan_term, consumer_transactions.interest_amount,
-    consumer_transactions.asset_name, consumer_transactions.total_amount,
-    consumer_transactions.status_id, status.name status_name, consumers.full_name consumer_name
+    consumer_transactions.interest_method, consumer_transactions.interest_rate,
+    consumer_transactions.asset_name, consumer_transactions.total_amount, consumer_transactions.created_at, consumer_transactions.days_past_due,
+    consumer_transactions.merchant_id, consumer_transactions.api_client_id, consumer_transactions.merchant_fee_rate, consumer_transactions.merchant_fee_amount,
+    consumer_transactions.status_id, consumer_transaction_statuses.name status_name, consumers.full_name consumer_name, IFNULL(loan_products.name, '') loan_product_name,
+    IFNULL(merchants.name, '') merchant_name
   FROM consumer_transactions
-  JOIN status ON consumer_transactions.status_id = status.id
+  JOIN consumer_transaction_statuses ON consumer_transactions.status_id = consumer_transaction_statuses.id
   JOIN consumers ON consumers.id = consumer_transactions.consumer_id
+  LEFT JOIN loan_products ON loan_products.id = consumer_transactions.loan_product_id
+  LEFT JOIN merchants ON merchants.id = consumer_transactions.merchant_id
   WHERE %s
   `, where)
 
 	// fmt.Println(query)
 
 	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
-		"limit":       params.FindAllParams.Size,
-		"offset":      ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
-		"status_id":   params.FindAllParams.StatusID,
-		"consumer_id": params.ConsumerID,
+		"limit":           params.FindAllParams.Size,
+		"offset":          ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"status_id":       params.FindAllParams.StatusID,
+		"consumer_id":     params.ConsumerID,
+		"loan_product_id": params.LoanProductID,
+		"merchant_id":     params.MerchantID,
 	})
 	if err != nil {
 		return nil, &types.Error{
@@ -94,14 +117,27 @@ func (s ConsumerTransactionRepository) FindAll(ctx *gin.Context, params models.F
 				ID:   v.ConsumerID,
 				Name: v.ConsumerName,
 			},
-			ContractNumber:    v.ContractNumber,
+			ContractNumber: v.ContractNumber,
+			LoanProductID:  v.LoanProductID,
+			LoanProduct: &models.IDNameTemplate{
+				ID:   v.LoanProductID,
+				Name: v.LoanProductName,
+			},
 			OTR:               v.OTR,
 			AdminFee:          v.AdminFee,
 			InstallmentAmount: v.InstallmentAmount,
 			LoanTerm:          v.LoanTerm,
 			InterestAmount:    v.InterestAmount,
+			InterestMethod:    v.InterestMethod,
+			InterestRate:      v.InterestRate,
 			TotalAmount:       v.TotalAmount,
 			AssetName:         v.AssetName,
+			MerchantID:        v.MerchantID,
+			APIClientID:       v.APIClientID,
+			MerchantFeeRate:   v.MerchantFeeRate,
+			MerchantFeeAmount: v.MerchantFeeAmount,
+			DaysPastDue:       v.DaysPastDue,
+			CreatedAt:         v.CreatedAt,
 			StatusID:          v.StatusID,
 			Status: models.Status{
 				ID:   v.StatusID,
@@ -109,6 +145,13 @@ func (s ConsumerTransactionRepository) FindAll(ctx *gin.Context, params models.F
 			},
 		}
 
+		if v.MerchantID != "" {
+			obj.Merchant = &models.IDNameTemplate{
+				ID:   v.MerchantID,
+				Name: v.MerchantName,
+			}
+		}
+
 		data = append(data, obj)
 	}
 
@@ -122,13 +165,18 @@ func (s ConsumerTransactionRepository) Find(ctx *gin.Context, id string) (*model
 
 	query := `
   SELECT
-    consumer_transactions.id, consumer_transactions.consumer_id, consumer_transactions.contract_number, consumer_transactions.OTR,
+    consumer_transactions.id, consumer_transactions.consumer_id, consumer_transactions.contract_number, consumer_transactions.loan_product_id, consumer_transactions.OTR,
     consumer_transactions.admin_fee, consumer_transactions.installment_amount, consumer_transactions.loan_term, consumer_transactions.interest_amount,
-    consumer_transactions.asset_name, consumer_transactions.total_amount,
-    consumer_transactions.status_id, status.name status_name, consumers.full_name consumer_name
+    consumer_transactions.interest_method, consumer_transactions.interest_rate,
+    consumer_transactions.asset_name, consumer_transactions.total_amount, consumer_transactions.created_at, consumer_transactions.days_past_due,
+    consumer_transactions.merchant_id, consumer_transactions.api_client_id, consumer_transactions.merchant_fee_rate, consumer_transactions.merchant_fee_amount,
+    consumer_transactions.status_id, consumer_transaction_statuses.name status_name, consumers.full_name consumer_name, IFNULL(loan_products.name, '') loan_product_name,
+    IFNULL(merchants.name, '') merchant_name
   FROM consumer_transactions
-  JOIN status ON consumer_transactions.status_id = status.id
+  JOIN consumer_transaction_statuses ON consumer_transactions.status_id = consumer_transaction_statuses.id
   JOIN consumers ON consumers.id = consumer_transactions.consumer_id
+  LEFT JOIN loan_products ON loan_products.id = consumer_transactions.loan_product_id
+  LEFT JOIN merchants ON merchants.id = consumer_transactions.merchant_id
   WHERE consumer_transactions.id = :id`
 
 	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{"id": id})
@@ -151,20 +199,40 @@ func (s ConsumerTransactionRepository) Find(ctx *gin.Context, id string) (*model
 				ID:   v.ConsumerID,
 				Name: v.ConsumerName,
 			},
-			ContractNumber:    v.ContractNumber,
+			ContractNumber: v.ContractNumber,
+			LoanProductID:  v.LoanProductID,
+			LoanProduct: &models.IDNameTemplate{
+				ID:   v.LoanProductID,
+				Name: v.LoanProductName,
+			},
 			OTR:               v.OTR,
 			AdminFee:          v.AdminFee,
 			InstallmentAmount: v.InstallmentAmount,
 			LoanTerm:          v.LoanTerm,
 			InterestAmount:    v.InterestAmount,
+			InterestMethod:    v.InterestMethod,
+			InterestRate:      v.InterestRate,
 			TotalAmount:       v.TotalAmount,
 			AssetName:         v.AssetName,
+			MerchantID:        v.MerchantID,
+			APIClientID:       v.APIClientID,
+			MerchantFeeRate:   v.MerchantFeeRate,
+			MerchantFeeAmount: v.MerchantFeeAmount,
+			DaysPastDue:       v.DaysPastDue,
+			CreatedAt:         v.CreatedAt,
 			StatusID:          v.StatusID,
 			Status: models.Status{
 				ID:   v.StatusID,
 				Name: v.StatusName,
 			},
 		}
+
+		if v.MerchantID != "" {
+			result.Merchant = &models.IDNameTemplate{
+				ID:   v.MerchantID,
+				Name: v.MerchantName,
+			}
+		}
 	} else {
 		return nil, &types.Error{
 			Path:       ".ConsumerTransactionStorage->Find()",
@@ -201,29 +269,48 @@ func (s ConsumerTransactionRepository) Count(ctx *gin.Context, params models.Fin
 		where += fmt.Sprintf(` AND consumer_transactions.contract_number LIKE "%%%s%%"`, params.ContractNumber)
 	}
 
+	if params.LoanProductID != "" {
+		where += ` AND consumer_transactions.loan_product_id = :loan_product_id`
+	}
+
 	if params.LoanTerm != 0 {
 		where += fmt.Sprintf(` AND consumer_transactions.loan_term = %d`, params.LoanTerm)
 	}
 
+	if params.MinDaysPastDue != 0 {
+		where += fmt.Sprintf(` AND consumer_transactions.days_past_due >= %d`, params.MinDaysPastDue)
+	}
+
+	if params.MerchantID != "" {
+		where += ` AND consumer_transactions.merchant_id = :merchant_id`
+	}
+
 	query := fmt.Sprintf(`
   SELECT
-    consumer_transactions.id, consumer_transactions.consumer_id, consumer_transactions.contract_number, consumer_transactions.OTR,
+    consumer_transactions.id, consumer_transactions.consumer_id, consumer_transactions.contract_number, consumer_transactions.loan_product_id, consumer_transactions.OTR,
     consumer_transactions.admin_fee, consumer_transactions.installment_amount, consumer_transactions.loan_term, consumer_transactions.interest_amount,
-    consumer_transactions.asset_name, consumer_transactions.total_amount,
-    consumer_transactions.status_id, status.name status_name, consumers.full_name consumer_name
+    consumer_transactions.interest_method, consumer_transactions.interest_rate,
+    consumer_transactions.asset_name, consumer_transactions.total_amount, consumer_transactions.created_at, consumer_transactions.days_past_due,
+    consumer_transactions.merchant_id, consumer_transactions.api_client_id, consumer_transactions.merchant_fee_rate, consumer_transactions.merchant_fee_amount,
+    consumer_transactions.status_id, consumer_transaction_statuses.name status_name, consumers.full_name consumer_name, IFNULL(loan_products.name, '') loan_product_name,
+    IFNULL(merchants.name, '') merchant_name
   FROM consumer_transactions
-  JOIN status ON consumer_transactions.status_id = status.id
+  JOIN consumer_transaction_statuses ON consumer_transactions.status_id = consumer_transaction_statuses.id
   JOIN consumers ON consumers.id = consumer_transactions.consumer_id
+  LEFT JOIN loan_products ON loan_products.id = consumer_transactions.loan_product_id
+  LEFT JOIN merchants ON merchants.id = consumer_transactions.merchant_id
   WHERE %s
   `, where)
 
 	// fmt.Println(query)
 
 	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
-		"limit":       params.FindAllParams.Size,
-		"offset":      ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
-		"status_id":   params.FindAllParams.StatusID,
-		"consumer_id": params.ConsumerID,
+		"limit":           params.FindAllParams.Size,
+		"offset":          ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"status_id":       params.FindAllParams.StatusID,
+		"consumer_id":     params.ConsumerID,
+		"loan_product_id": params.LoanProductID,
+		"merchant_id":     params.MerchantID,
 	})
 	if err != nil {
 		return 0, &types.Error{
@@ -290,8 +377,8 @@ func (s ConsumerTransactionRepository) Update(ctx *gin.Context, obj *models.Cons
 	return &data, nil
 }
 
-func (s ConsumerTransactionRepository) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
-	status := []*models.Status{}
+func (s ConsumerTransactionRepository) FindStatus(ctx *gin.Context) ([]*models.ConsumerTransactionStatus, *types.Error) {
+	status := []*models.ConsumerTransactionStatus{}
 
 	err := s.statusRepository.Where(ctx, &status, "1=1", map[string]interface{}{})
 	if err != nil {
@@ -307,9 +394,18 @@ func (s ConsumerTransactionRepository) FindStatus(ctx *gin.Context) ([]*models.S
 	return status, nil
 }
 
+// UpdateStatus writes the lifecycle status directly, the generic storage only knows the active and inactive codes
 func (s ConsumerTransactionRepository) UpdateStatus(ctx *gin.Context, id string, statusID string) (*models.ConsumerTransaction, *types.Error) {
 	data := models.ConsumerTransaction{}
-	err := s.repository.UpdateStatus(ctx, id, statusID)
+
+	query := `UPDATE consumer_transactions SET status_id = :status_id, updated_at = :updated_at, updated_by = :updated_by WHERE id = :id`
+
+	err := s.repository.ExecQuery(ctx, query, map[string]interface{}{
+		"id":         id,
+		"status_id":  statusID,
+		"updated_at": library.UTCPlus7().Format("2006-01-02 15:04:05"),
+		"updated_by": *appcontext.UserID(ctx),
+	})
 	if err != nil {
 		return nil, &types.Error{
 			Path:       ".ConsumerTransactionStorage->UpdateStatus()",
@@ -333,3 +429,209 @@ func (s ConsumerTransactionRepository) UpdateStatus(ctx *gin.Context, id string,
 
 	return &data, nil
 }
+
+// LockConsumerTransaction takes a row lock on the transaction so two status changes cannot both start from the same status
+func (s ConsumerTransactionRepository) LockConsumerTransaction(ctx *gin.Context, id string) *types.Error {
+	rows := []*models.IDNameTemplate{}
+
+	query := `SELECT consumer_transactions.id, consumer_transactions.status_id name FROM consumer_transactions WHERE consumer_transactions.id = :id FOR UPDATE`
+
+	err := s.repository.SelectWithQuery(ctx, &rows, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return &types.Error{
+			Path:       ".ConsumerTransactionStorage->LockConsumerTransaction()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
+
+// FindConsumerIDForUpdate locks the transaction like LockConsumerTransaction and returns its consumer. It only reads
+// with a lock, so the consumer's credit limit can still be locked before the first plain read of the database transaction.
+func (s ConsumerTransactionRepository) FindConsumerIDForUpdate(ctx *gin.Context, id string) (string, *types.Error) {
+	rows := []*models.IDNameTemplate{}
+
+	query := `SELECT consumer_transactions.id, consumer_transactions.consumer_id name FROM consumer_transactions WHERE consumer_transactions.id = :id FOR UPDATE`
+
+	err := s.repository.SelectWithQuery(ctx, &rows, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return "", &types.Error{
+			Path:       ".ConsumerTransactionStorage->FindConsumerIDForUpdate()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(rows) == 0 {
+		return "", &types.Error{
+			Path:       ".ConsumerTransactionStorage->FindConsumerIDForUpdate()",
+			Message:    "Data Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return rows[0].Name, nil
+}
+
+// NextContractNumberSequence increments the counter of the key and returns the new value. The counter row stays
+// locked until the surrounding transaction ends, so a rolled back transaction gives its number back and no gap is left.
+func (s ConsumerTransactionRepository) NextContractNumberSequence(ctx *gin.Context, key string) (int, *types.Error) {
+	rows := []*models.IDNameTemplate{}
+
+	query := `
+  INSERT INTO contract_number_sequences(sequence_key, last_value, created_at, updated_at)
+  VALUES (:sequence_key, 1, :now, :now)
+  ON DUPLICATE KEY UPDATE last_value = last_value + 1, updated_at = :now`
+
+	err := s.repository.ExecQuery(ctx, query, map[string]interface{}{
+		"sequence_key": key,
+		"now":          library.UTCPlus7().Format("2006-01-02 15:04:05"),
+	})
+	if err != nil {
+		return 0, &types.Error{
+			Path:       ".ConsumerTransactionStorage->NextContractNumberSequence()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	query = `SELECT sequence_key id, last_value name FROM contract_number_sequences WHERE sequence_key = :sequence_key`
+
+	err = s.repository.SelectWithQuery(ctx, &rows, query, map[string]interface{}{"sequence_key": key})
+	if err != nil {
+		return 0, &types.Error{
+			Path:       ".ConsumerTransactionStorage->NextContractNumberSequence()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(rows) == 0 {
+		return 0, &types.Error{
+			Path:       ".ConsumerTransactionStorage->NextContractNumberSequence()",
+			Message:    "Contract number sequence not found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	sequence, errConv := strconv.Atoi(rows[0].Name)
+	if errConv != nil {
+		return 0, &types.Error{
+			Path:       ".ConsumerTransactionStorage->NextContractNumberSequence()",
+			Message:    errConv.Error(),
+			Error:      errConv,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "conversion-error",
+		}
+	}
+
+	return sequence, nil
+}
+
+// STATUS HISTORIES
+
+func (s ConsumerTransactionRepository) FindStatusHistories(ctx *gin.Context, consumerTransactionID string) ([]*models.ConsumerTransactionStatusHistory, *types.Error) {
+	data := []*models.ConsumerTransactionStatusHistory{}
+	bulks := []*models.ConsumerTransactionStatusHistoryBulk{}
+
+	query := `
+  SELECT
+    h.id, h.consumer_transaction_id, h.from_status_id, h.to_status_id, h.reason, h.created_at, IFNULL(h.created_by, '') created_by, h.status_id,
+    IFNULL(from_status.name, '') from_status_name, to_status.name to_status_name
+  FROM consumer_transaction_status_histories h
+  LEFT JOIN consumer_transaction_statuses from_status ON from_status.id = h.from_status_id
+  JOIN consumer_transaction_statuses to_status ON to_status.id = h.to_status_id
+  WHERE h.consumer_transaction_id = :consumer_transaction_id
+  ORDER BY h.created_at, h.from_status_id = '' DESC`
+
+	err := s.historyRepository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"consumer_transaction_id": consumerTransactionID,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerTransactionStorage->FindStatusHistories()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		obj := &models.ConsumerTransactionStatusHistory{
+			ID:                    v.ID,
+			ConsumerTransactionID: v.ConsumerTransactionID,
+			FromStatusID:          v.FromStatusID,
+			ToStatusID:            v.ToStatusID,
+			Reason:                v.Reason,
+			CreatedAt:             v.CreatedAt,
+			CreatedBy:             v.CreatedBy,
+			StatusID:              v.StatusID,
+			FromStatus: &models.IDNameTemplate{
+				ID:   v.FromStatusID,
+				Name: v.FromStatusName,
+			},
+			ToStatus: &models.IDNameTemplate{
+				ID:   v.ToStatusID,
+				Name: v.ToStatusName,
+			},
+		}
+
+		data = append(data, obj)
+	}
+
+	return data, nil
+}
+
+func (s ConsumerTransactionRepository) CreateStatusHistory(ctx *gin.Context, obj *models.ConsumerTransactionStatusHistory) *types.Error {
+	_, err := s.historyRepository.Insert(ctx, obj)
+	if err != nil {
+		return &types.Error{
+			Path:       ".ConsumerTransactionStorage->CreateStatusHistory()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
+
+// CreateReversalTrail records the cancellation in the audit trail next to the generic create and update entries
+func (s ConsumerTransactionRepository) CreateReversalTrail(ctx *gin.Context, id string) *types.Error {
+	query := `
+  INSERT INTO user_actions(id, user_id, table_name, action, created_at, ref_id)
+  VALUES (UUID(), :user_id, 'consumer_transactions', 'Reversal', :created_at, :ref_id)`
+
+	err := s.repository.ExecQuery(ctx, query, map[string]interface{}{
+		"user_id":    *appcontext.UserID(ctx),
+		"created_at": library.UTCPlus7().Format("2006-01-02 15:04:05"),
+		"ref_id":     id,
+	})
+	if err != nil {
+		return &types.Error{
+			Path:       ".ConsumerTransactionStorage->CreateReversalTrail()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
@@ -15,6 +15,10 @@ type Usecase interface {
 	Create(*gin.Context, models.ConsumerTransaction) (*models.ConsumerTransaction, *types.Error)
 	Update(*gin.Context, string, models.ConsumerTransaction) (*models.ConsumerTransaction, *types.Error)
 
-	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	FindStatus(*gin.Context) ([]*models.ConsumerTransactionStatus, *types.Error)
 	UpdateStatus(*gin.Context, string, string) (*models.ConsumerTransaction, *types.Error)
+
+	Cancel(*gin.Context, string, string) (*models.ConsumerTransaction, *types.Error)
+	Reopen(*gin.Context, string, string) (*models.ConsumerTransaction, *types.Error)
+	Simulate(*gin.Context, models.ConsumerTransaction) ([]*models.ConsumerTransactionSimulation, *types.Error)
 }
@@ -2,15 +2,20 @@ package usecase
 
 import (
 	"fmt"
+	"math"
 	"net/http"
 	"reflect"
 	"strings"
 	"time"
 
+	"case-study-kredit-plus/configs"
 	"case-study-kredit-plus/library"
 	"case-study-kredit-plus/library/types"
 	"case-study-kredit-plus/src/services/consumercreditlimit"
+	"case-study-kredit-plus/src/services/consumerinstallment"
 	"case-study-kredit-plus/src/services/consumertransaction"
+	"case-study-kredit-plus/src/services/loanproduct"
+	"case-study-kredit-plus/src/services/merchant"
 
 	"case-study-kredit-plus/models"
 
@@ -25,16 +30,22 @@ import (
 type ConsumerTransactionUsecase struct {
 	consumertransactionRepo    consumertransaction.Repository
 	consumercreditlimitUsecase consumercreditlimit.Usecase
+	consumerinstallmentUsecase consumerinstallment.Usecase
+	loanproductUsecase         loanproduct.Usecase
+	merchantUsecase            merchant.Usecase
 	contextTimeout             time.Duration
 	db                         *sqlx.DB
 }
 
-func NewConsumerTransactionUsecase(db *sqlx.DB, consumertransactionRepo consumertransaction.Repository, consumercreditlimitUsecase consumercreditlimit.Usecase) consumertransaction.Usecase {
+func NewConsumerTransactionUsecase(db *sqlx.DB, consumertransactionRepo consumertransaction.Repository, consumercreditlimitUsecase consumercreditlimit.Usecase, consumerinstallmentUsecase consumerinstallment.Usecase, loanproductUsecase loanproduct.Usecase, merchantUsecase merchant.Usecase) consumertransaction.Usecase {
 	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
 
 	return &ConsumerTransactionUsecase{
 		consumertransactionRepo:    consumertransactionRepo,
 		consumercreditlimitUsecase: consumercreditlimitUsecase,
+		consumerinstallmentUsecase: consumerinstallmentUsecase,
+		loanproductUsecase:         loanproductUsecase,
+		merchantUsecase:            merchantUsecase,
 		contextTimeout:             timeoutContext,
 		db:                         db,
 	}
@@ -77,6 +88,12 @@ func (u *ConsumerTransactionUsecase) Find(ctx *gin.Context, id string) (*models.
 		return nil, err
 	}
 
+	result.StatusHistories, err = u.consumertransactionRepo.FindStatusHistories(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Find()" + err.Path
+		return nil, err
+	}
+
 	return result, nil
 }
 
@@ -131,48 +148,73 @@ func (u *ConsumerTransactionUsecase) Create(ctx *gin.Context, obj models.Consume
 		}
 	}
 
-	if !library.ValidateTenor(obj.LoanTerm) {
-		return nil, &types.Error{
-			Path:       ".ConsumerTransactionUsecase->Create()",
-			Message:    "Loan Term Invalid",
-			Error:      fmt.Errorf("Loan Term Invalid"),
-			Type:       "validation-error",
-			StatusCode: http.StatusUnprocessableEntity,
-		}
+	// serialize limit consumption per consumer, the lock is held until the surrounding transaction ends
+	err := u.consumercreditlimitUsecase.LockCreditLimit(ctx, obj.ConsumerID)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	product, err := u.resolveLoanProduct(ctx, &obj)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Create()" + err.Path
+		return nil, err
 	}
 
-	totalAmount, installmentAmount := obj.TotalAmount, obj.InstallmentAmount
+	err = u.calculatePricing(&obj, product)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Create()" + err.Path
+		return nil, err
+	}
 
-	if obj.TotalAmount == 0 {
-		totalAmount = obj.OTR + obj.AdminFee + obj.InterestAmount
+	// transactions booked by a partner are stamped with its fee share, the rate stays fixed afterwards
+	if obj.MerchantID != "" {
+		seller, err := u.merchantUsecase.FindSellingMerchant(ctx, obj.MerchantID, product.ID)
+		if err != nil {
+			err.Path = ".ConsumerTransactionUsecase->Create()" + err.Path
+			return nil, err
+		}
+
+		obj.MerchantFeeRate = seller.FeeShareRate
+		obj.MerchantFeeAmount = library.RoundCurrency(obj.AdminFee * obj.MerchantFeeRate / 100)
 	}
 
-	if obj.InstallmentAmount <= 0 {
-		installmentAmount = totalAmount / float64(obj.LoanTerm)
+	contractNumber, err := u.generateContractNumber(ctx, product)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Create()" + err.Path
+		return nil, err
 	}
 
 	data := models.ConsumerTransaction{
 		ID:                uuid.New().String(),
 		ConsumerID:        obj.ConsumerID,
-		ContractNumber:    obj.ContractNumber,
+		ContractNumber:    contractNumber,
+		LoanProductID:     obj.LoanProductID,
 		OTR:               obj.OTR,
 		AdminFee:          obj.AdminFee,
-		InstallmentAmount: installmentAmount,
+		InstallmentAmount: obj.InstallmentAmount,
 		LoanTerm:          obj.LoanTerm,
 		InterestAmount:    obj.InterestAmount,
-		TotalAmount:       totalAmount,
+		InterestMethod:    obj.InterestMethod,
+		InterestRate:      obj.InterestRate,
+		TotalAmount:       obj.TotalAmount,
 		AssetName:         obj.AssetName,
-		StatusID:          models.DEFAULT_STATUS_ID,
+		MerchantID:        obj.MerchantID,
+		APIClientID:       obj.APIClientID,
+		MerchantFeeRate:   obj.MerchantFeeRate,
+		MerchantFeeAmount: obj.MerchantFeeAmount,
+		StatusID:          models.TRANSACTION_STATUS_PENDING,
 	}
 
-	// check tenor limit availability
-	remainingLimit, err := u.consumercreditlimitUsecase.CheckCreditLimitAvailability(ctx, obj.ConsumerID, obj.LoanTerm)
+	// check loan product limit availability
+	remainingLimit, err := u.consumercreditlimitUsecase.CheckCreditLimitAvailability(ctx, obj.ConsumerID, obj.LoanProductID)
 	if err != nil {
 		err.Path = ".ConsumerTransactionUsecase->Create()" + err.Path
 		return nil, err
 	}
 
-	if remainingLimit < totalAmount {
+	// the limit is consumed by the financed principal only
+	if remainingLimit < obj.OTR {
 		return nil, &types.Error{
 			Path:       ".ConsumerTransactionUsecase->Create()",
 			Message:    "Insufficient Credit Limit",
@@ -188,10 +230,47 @@ func (u *ConsumerTransactionUsecase) Create(ctx *gin.Context, obj models.Consume
 		return nil, err
 	}
 
+	err = u.createStatusHistory(ctx, data.ID, "", data.StatusID, "")
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	installments, err := u.consumerinstallmentUsecase.GenerateSchedule(ctx, result)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	result.Installments = installments
+
 	return result, nil
 }
 
 func (u *ConsumerTransactionUsecase) Update(ctx *gin.Context, id string, obj models.ConsumerTransaction) (*models.ConsumerTransaction, *types.Error) {
+	// lock the transaction, then the limit of its consumer, before anything is read without a lock
+	consumerID, err := u.consumertransactionRepo.FindConsumerIDForUpdate(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	// serialize limit consumption per consumer, the lock is held until the surrounding transaction ends
+	err = u.consumercreditlimitUsecase.LockCreditLimit(ctx, consumerID)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	data, err := u.consumertransactionRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	// the consumer cannot change, the limit is checked against the one the transaction belongs to
+	obj.ConsumerID = data.ConsumerID
+
 	validate := validator.New()
 	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
 		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
@@ -212,40 +291,42 @@ func (u *ConsumerTransactionUsecase) Update(ctx *gin.Context, id string, obj mod
 		}
 	}
 
-	if !library.ValidateTenor(obj.LoanTerm) {
+	// terms are fixed once the transaction leaves pending
+	if data.StatusID != models.TRANSACTION_STATUS_PENDING {
 		return nil, &types.Error{
 			Path:       ".ConsumerTransactionUsecase->Update()",
-			Message:    "Loan Term Invalid",
-			Error:      fmt.Errorf("Loan Term Invalid"),
+			Message:    "Only Pending Transactions Can Be Updated",
+			Error:      fmt.Errorf("Only Pending Transactions Can Be Updated"),
 			Type:       "validation-error",
 			StatusCode: http.StatusUnprocessableEntity,
 		}
 	}
 
-	data, err := u.consumertransactionRepo.Find(ctx, id)
+	product, err := u.resolveLoanProduct(ctx, &obj)
 	if err != nil {
 		err.Path = ".ConsumerTransactionUsecase->Update()" + err.Path
 		return nil, err
 	}
 
-	totalAmount, installmentAmount := obj.TotalAmount, obj.InstallmentAmount
-
-	if obj.TotalAmount == 0 {
-		totalAmount = obj.OTR + obj.AdminFee + obj.InterestAmount
-	}
-
-	if obj.InstallmentAmount <= 0 {
-		installmentAmount = totalAmount / float64(obj.LoanTerm)
+	err = u.calculatePricing(&obj, product)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Update()" + err.Path
+		return nil, err
 	}
 
-	// check tenor limit availability
-	remainingLimit, err := u.consumercreditlimitUsecase.CheckCreditLimitAvailability(ctx, obj.ConsumerID, obj.LoanTerm)
+	// check loan product limit availability
+	remainingLimit, err := u.consumercreditlimitUsecase.CheckCreditLimitAvailability(ctx, data.ConsumerID, obj.LoanProductID)
 	if err != nil {
 		err.Path = ".ConsumerTransactionUsecase->Update()" + err.Path
 		return nil, err
 	}
 
-	if remainingLimit+data.TotalAmount < totalAmount {
+	// the current principal only counts back towards the limit it was drawn from
+	if data.LoanProductID == obj.LoanProductID {
+		remainingLimit += data.OTR
+	}
+
+	if remainingLimit < obj.OTR {
 		return nil, &types.Error{
 			Path:       ".ConsumerTransactionUsecase->Update()",
 			Message:    "Insufficient Credit Limit",
@@ -257,13 +338,17 @@ func (u *ConsumerTransactionUsecase) Update(ctx *gin.Context, id string, obj mod
 
 	// data.ConsumerID = obj.ConsumerID
 	// data.ContractNumber = obj.ContractNumber
+	data.LoanProductID = obj.LoanProductID
 	data.OTR = obj.OTR
 	data.AdminFee = obj.AdminFee
-	data.InstallmentAmount = installmentAmount
+	data.InstallmentAmount = obj.InstallmentAmount
 	data.LoanTerm = obj.LoanTerm
 	data.InterestAmount = obj.InterestAmount
-	data.TotalAmount = totalAmount
+	data.InterestMethod = obj.InterestMethod
+	data.InterestRate = obj.InterestRate
+	data.TotalAmount = obj.TotalAmount
 	data.AssetName = obj.AssetName
+	data.MerchantFeeAmount = library.RoundCurrency(data.AdminFee * data.MerchantFeeRate / 100)
 
 	result, err := u.consumertransactionRepo.Update(ctx, data)
 	if err != nil {
@@ -271,10 +356,18 @@ func (u *ConsumerTransactionUsecase) Update(ctx *gin.Context, id string, obj mod
 		return nil, err
 	}
 
+	installments, err := u.consumerinstallmentUsecase.GenerateSchedule(ctx, result)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	result.Installments = installments
+
 	return result, err
 }
 
-func (u *ConsumerTransactionUsecase) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+func (u *ConsumerTransactionUsecase) FindStatus(ctx *gin.Context) ([]*models.ConsumerTransactionStatus, *types.Error) {
 	result, err := u.consumertransactionRepo.FindStatus(ctx)
 	if err != nil {
 		err.Path = ".ConsumerTransactionUsecase->FindStatus()" + err.Path
@@ -284,12 +377,475 @@ func (u *ConsumerTransactionUsecase) FindStatus(ctx *gin.Context) ([]*models.Sta
 	return result, nil
 }
 
+// UpdateStatus moves the transaction along its lifecycle and records who moved it.
+// No transition leads from a status that releases the credit limit back to one that consumes it,
+// so the limit does not need to be checked again here.
 func (u *ConsumerTransactionUsecase) UpdateStatus(ctx *gin.Context, id string, newStatusID string) (*models.ConsumerTransaction, *types.Error) {
+	err := u.consumertransactionRepo.LockConsumerTransaction(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->UpdateStatus()" + err.Path
+		return nil, err
+	}
+
+	data, err := u.consumertransactionRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->UpdateStatus()" + err.Path
+		return nil, err
+	}
+
+	// cancelling needs a reason and is bound to a window, it goes through Cancel
+	if newStatusID == models.TRANSACTION_STATUS_CANCELLED {
+		return nil, &types.Error{
+			Path:       ".ConsumerTransactionUsecase->UpdateStatus()",
+			Message:    "Use Cancel To Cancel A Transaction",
+			Error:      fmt.Errorf("Use Cancel To Cancel A Transaction"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	if !isValidStatusTransition(data.StatusID, newStatusID) {
+		errTransition := fmt.Errorf("Status Cannot Change From %s To %s", data.StatusID, newStatusID)
+		return nil, &types.Error{
+			Path:       ".ConsumerTransactionUsecase->UpdateStatus()",
+			Message:    errTransition.Error(),
+			Error:      errTransition,
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
 	result, err := u.consumertransactionRepo.UpdateStatus(ctx, id, newStatusID)
 	if err != nil {
 		err.Path = ".ConsumerTransactionUsecase->UpdateStatus()" + err.Path
 		return nil, err
 	}
 
+	err = u.createStatusHistory(ctx, id, data.StatusID, newStatusID, "")
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->UpdateStatus()" + err.Path
+		return nil, err
+	}
+
 	return result, err
 }
+
+// CANCELLATION
+
+// Cancel reverses a transaction, e.g. when the purchase was returned at the dealer. Once the money has left,
+// it is only allowed within the loan product's cancellation window and before any payment was recorded.
+// The cancelled status does not consume limit, so the principal is available again right away.
+func (u *ConsumerTransactionUsecase) Cancel(ctx *gin.Context, id string, reason string) (*models.ConsumerTransaction, *types.Error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, &types.Error{
+			Path:       ".ConsumerTransactionUsecase->Cancel()",
+			Message:    "Reason is required",
+			Error:      fmt.Errorf("Reason is required"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	err := u.consumertransactionRepo.LockConsumerTransaction(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Cancel()" + err.Path
+		return nil, err
+	}
+
+	data, err := u.consumertransactionRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Cancel()" + err.Path
+		return nil, err
+	}
+
+	if !isValidStatusTransition(data.StatusID, models.TRANSACTION_STATUS_CANCELLED) {
+		errTransition := fmt.Errorf("Status Cannot Change From %s To %s", data.StatusID, models.TRANSACTION_STATUS_CANCELLED)
+		return nil, &types.Error{
+			Path:       ".ConsumerTransactionUsecase->Cancel()",
+			Message:    errTransition.Error(),
+			Error:      errTransition,
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	// once disbursed the money has left, so cancelling is bound to the loan product's window
+	if data.StatusID == models.TRANSACTION_STATUS_DISBURSED || data.StatusID == models.TRANSACTION_STATUS_ACTIVE {
+		product, err := u.loanproductUsecase.Find(ctx, data.LoanProductID)
+		if err != nil {
+			err.Path = ".ConsumerTransactionUsecase->Cancel()" + err.Path
+			return nil, err
+		}
+
+		if library.UTCPlus7().After(data.CreatedAt.AddDate(0, 0, product.CancellationWindowDays)) {
+			return nil, &types.Error{
+				Path:       ".ConsumerTransactionUsecase->Cancel()",
+				Message:    "Cancellation Window Has Passed",
+				Error:      fmt.Errorf("Cancellation Window Has Passed"),
+				Type:       "validation-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+		}
+
+		var params models.FindAllConsumerInstallmentParams
+		params.ConsumerTransactionID = id
+
+		installments, err := u.consumerinstallmentUsecase.FindAll(ctx, params)
+		if err != nil {
+			err.Path = ".ConsumerTransactionUsecase->Cancel()" + err.Path
+			return nil, err
+		}
+
+		for _, v := range installments {
+			if v.PaidPrincipalAmount+v.PaidInterestAmount+v.PaidFeeAmount > 0 {
+				return nil, &types.Error{
+					Path:       ".ConsumerTransactionUsecase->Cancel()",
+					Message:    "Transaction Has Payments, Reverse Them First",
+					Error:      fmt.Errorf("Transaction Has Payments, Reverse Them First"),
+					Type:       "validation-error",
+					StatusCode: http.StatusUnprocessableEntity,
+				}
+			}
+		}
+	}
+
+	result, err := u.consumertransactionRepo.UpdateStatus(ctx, id, models.TRANSACTION_STATUS_CANCELLED)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Cancel()" + err.Path
+		return nil, err
+	}
+
+	err = u.createStatusHistory(ctx, id, data.StatusID, models.TRANSACTION_STATUS_CANCELLED, reason)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Cancel()" + err.Path
+		return nil, err
+	}
+
+	err = u.consumertransactionRepo.CreateReversalTrail(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Cancel()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// REOPENING
+
+// Reopen moves a paid off transaction back to active, when a payment that settled it is reversed. Paid off is
+// otherwise final, so this is the only way back. Active consumes limit again, the principal is owed once more.
+func (u *ConsumerTransactionUsecase) Reopen(ctx *gin.Context, id string, reason string) (*models.ConsumerTransaction, *types.Error) {
+	err := u.consumertransactionRepo.LockConsumerTransaction(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Reopen()" + err.Path
+		return nil, err
+	}
+
+	data, err := u.consumertransactionRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Reopen()" + err.Path
+		return nil, err
+	}
+
+	if data.StatusID != models.TRANSACTION_STATUS_PAID_OFF {
+		errTransition := fmt.Errorf("Status Cannot Change From %s To %s", data.StatusID, models.TRANSACTION_STATUS_ACTIVE)
+		return nil, &types.Error{
+			Path:       ".ConsumerTransactionUsecase->Reopen()",
+			Message:    errTransition.Error(),
+			Error:      errTransition,
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	result, err := u.consumertransactionRepo.UpdateStatus(ctx, id, models.TRANSACTION_STATUS_ACTIVE)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Reopen()" + err.Path
+		return nil, err
+	}
+
+	err = u.createStatusHistory(ctx, id, data.StatusID, models.TRANSACTION_STATUS_ACTIVE, reason)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Reopen()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// STATUS LIFECYCLE
+
+func isValidStatusTransition(fromStatusID string, toStatusID string) bool {
+	for _, v := range models.TRANSACTION_STATUS_TRANSITIONS[fromStatusID] {
+		if v == toStatusID {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (u *ConsumerTransactionUsecase) createStatusHistory(ctx *gin.Context, consumerTransactionID string, fromStatusID string, toStatusID string, reason string) *types.Error {
+	history := models.ConsumerTransactionStatusHistory{
+		ID:                    uuid.New().String(),
+		ConsumerTransactionID: consumerTransactionID,
+		FromStatusID:          fromStatusID,
+		ToStatusID:            toStatusID,
+		Reason:                reason,
+		StatusID:              models.DEFAULT_STATUS_ID,
+	}
+
+	err := u.consumertransactionRepo.CreateStatusHistory(ctx, &history)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->createStatusHistory()" + err.Path
+		return err
+	}
+
+	return nil
+}
+
+// LOAN PRODUCT
+
+// resolveLoanProduct looks up the active loan product for the transaction, either by ID or by tenor,
+// and takes the tenor from it
+func (u *ConsumerTransactionUsecase) resolveLoanProduct(ctx *gin.Context, obj *models.ConsumerTransaction) (*models.LoanProduct, *types.Error) {
+	product, err := u.loanproductUsecase.FindAvailable(ctx, obj.LoanProductID, obj.LoanTerm)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->resolveLoanProduct()" + err.Path
+		return nil, err
+	}
+
+	if !withinAmountRange(obj.OTR, product) {
+		return nil, &types.Error{
+			Path:       ".ConsumerTransactionUsecase->resolveLoanProduct()",
+			Message:    "OTR Outside Loan Product Range",
+			Error:      fmt.Errorf("OTR Outside Loan Product Range"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	obj.LoanProductID = product.ID
+	obj.LoanTerm = product.Tenor
+
+	return product, nil
+}
+
+func withinAmountRange(otr float64, product *models.LoanProduct) bool {
+	return otr >= product.MinAmount && (product.MaxAmount == 0 || otr <= product.MaxAmount)
+}
+
+// SIMULATION
+
+// Simulate prices the OTR against every loan product available today, one per tenor, the way Create would.
+// Products whose amount range does not cover the OTR are left out. Nothing is locked or persisted.
+func (u *ConsumerTransactionUsecase) Simulate(ctx *gin.Context, obj models.ConsumerTransaction) ([]*models.ConsumerTransactionSimulation, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".ConsumerTransactionUsecase->Simulate()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	if obj.OTR <= 0 {
+		return nil, &types.Error{
+			Path:       ".ConsumerTransactionUsecase->Simulate()",
+			Message:    "OTR Invalid",
+			Error:      fmt.Errorf("OTR Invalid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	// same choice as FindAvailable makes for a tenor: the most recently activated product wins
+	var params models.FindAllLoanProductParams
+	params.ActiveOn = library.UTCPlus7().Format(library.StrToDateFormat)
+	params.FindAllParams.StatusID = `status_id = "1"`
+	params.FindAllParams.SortBy = "loan_products.tenor ASC, loan_products.active_from DESC, loan_products.created_at DESC"
+
+	products, err := u.loanproductUsecase.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->Simulate()" + err.Path
+		return nil, err
+	}
+
+	// a partner only gets offered the products it may sell
+	var allowed map[string]bool
+	if obj.MerchantID != "" {
+		seller, err := u.merchantUsecase.FindSellingMerchant(ctx, obj.MerchantID, "")
+		if err != nil {
+			err.Path = ".ConsumerTransactionUsecase->Simulate()" + err.Path
+			return nil, err
+		}
+
+		allowed = map[string]bool{}
+		for _, v := range seller.LoanProducts {
+			allowed[v.LoanProductID] = true
+		}
+	}
+
+	result := []*models.ConsumerTransactionSimulation{}
+	seen := map[int]bool{}
+	for _, product := range products {
+		if allowed != nil && !allowed[product.ID] {
+			continue
+		}
+
+		if seen[product.Tenor] {
+			continue
+		}
+		seen[product.Tenor] = true
+
+		if !withinAmountRange(obj.OTR, product) {
+			continue
+		}
+
+		option := models.ConsumerTransaction{ConsumerID: obj.ConsumerID, OTR: obj.OTR}
+
+		err = u.calculatePricing(&option, product)
+		if err != nil {
+			err.Path = ".ConsumerTransactionUsecase->Simulate()" + err.Path
+			return nil, err
+		}
+
+		remainingLimit, err := u.consumercreditlimitUsecase.CheckCreditLimitAvailability(ctx, obj.ConsumerID, product.ID)
+		if err != nil {
+			err.Path = ".ConsumerTransactionUsecase->Simulate()" + err.Path
+			return nil, err
+		}
+
+		result = append(result, &models.ConsumerTransactionSimulation{
+			LoanProduct: &models.IDNameTemplate{
+				ID:   product.ID,
+				Name: product.Name,
+			},
+			LoanTerm:          product.Tenor,
+			OTR:               option.OTR,
+			AdminFee:          option.AdminFee,
+			InterestMethod:    option.InterestMethod,
+			InterestRate:      option.InterestRate,
+			InterestAmount:    option.InterestAmount,
+			InstallmentAmount: option.InstallmentAmount,
+			TotalAmount:       option.TotalAmount,
+			AssetName:         obj.AssetName,
+			RemainingLimit:    remainingLimit,
+			LimitCovered:      remainingLimit >= option.OTR,
+		})
+	}
+
+	return result, nil
+}
+
+// CONTRACT NUMBER
+
+// generateContractNumber draws the next number for the branch, product and period of the configured format.
+// It must run inside the transaction that creates the consumer transaction, see NextContractNumberSequence.
+func (u *ConsumerTransactionUsecase) generateContractNumber(ctx *gin.Context, product *models.LoanProduct) (string, *types.Error) {
+	pattern := library.DEFAULT_CONTRACT_NUMBER_FORMAT
+	branch := library.DEFAULT_BRANCH_CODE
+	if configs.AppConfig != nil {
+		if configs.AppConfig.ContractNumberFormat != "" {
+			pattern = configs.AppConfig.ContractNumberFormat
+		}
+		if configs.AppConfig.BranchCode != "" {
+			branch = configs.AppConfig.BranchCode
+		}
+	}
+
+	productCode := product.Code
+	if productCode == "" {
+		productCode = fmt.Sprintf("M%02d", product.Tenor)
+	}
+
+	// the configured format is checked when the configuration loads, this only fails on a broken default
+	key, errKey := library.ContractNumberSequenceKey(pattern, branch, productCode, library.UTCPlus7())
+	if errKey != nil {
+		return "", &types.Error{
+			Path:       ".ConsumerTransactionUsecase->generateContractNumber()",
+			Message:    errKey.Error(),
+			Error:      errKey,
+			Type:       "golang-error",
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	sequence, err := u.consumertransactionRepo.NextContractNumberSequence(ctx, key)
+	if err != nil {
+		err.Path = ".ConsumerTransactionUsecase->generateContractNumber()" + err.Path
+		return "", err
+	}
+
+	return library.FormatContractNumber(key, sequence), nil
+}
+
+// PRICING
+
+// calculatePricing derives the admin fee, interest, installment and total amounts from the loan product.
+// Figures posted by the client are optional, but are rejected when they disagree with the derived ones.
+func (u *ConsumerTransactionUsecase) calculatePricing(obj *models.ConsumerTransaction, product *models.LoanProduct) *types.Error {
+	adminFee := library.RoundCurrency(product.AdminFeeAmount + obj.OTR*product.AdminFeeRate/100)
+
+	interestAmount, errInterest := library.CalculateInterest(product.InterestMethod, obj.OTR, product.InterestRate, product.Tenor)
+	if errInterest != nil {
+		return &types.Error{
+			Path:       ".ConsumerTransactionUsecase->calculatePricing()",
+			Message:    errInterest.Error(),
+			Error:      errInterest,
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	term := float64(product.Tenor)
+	totalAmount := library.RoundCurrency(obj.OTR + adminFee + interestAmount)
+	installmentAmount := library.RoundCurrency(totalAmount / term)
+	if product.InterestMethod == library.INTEREST_METHOD_ANNUITY {
+		installmentAmount = library.RoundCurrency(library.AnnuityPayment(obj.OTR, product.InterestRate, product.Tenor) + adminFee/term)
+	}
+
+	figures := []struct {
+		name     string
+		supplied float64
+		derived  float64
+	}{
+		{"Admin Fee", obj.AdminFee, adminFee},
+		{"Interest Amount", obj.InterestAmount, interestAmount},
+		{"Installment Amount", obj.InstallmentAmount, installmentAmount},
+		{"Total Amount", obj.TotalAmount, totalAmount},
+	}
+
+	for _, v := range figures {
+		if v.supplied != 0 && math.Abs(library.RoundCurrency(v.supplied)-v.derived) > 0.01 {
+			errMismatch := fmt.Errorf("%s Mismatch, expected %.2f", v.name, v.derived)
+			return &types.Error{
+				Path:       ".ConsumerTransactionUsecase->calculatePricing()",
+				Message:    errMismatch.Error(),
+				Error:      errMismatch,
+				Type:       "validation-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+		}
+	}
+
+	obj.AdminFee = adminFee
+	obj.InterestMethod = product.InterestMethod
+	obj.InterestRate = product.InterestRate
+	obj.InterestAmount = interestAmount
+	obj.InstallmentAmount = installmentAmount
+	obj.TotalAmount = totalAmount
+
+	return nil
+}
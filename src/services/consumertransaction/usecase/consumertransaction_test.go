@@ -0,0 +1,167 @@
+package usecase_test
+
+import (
+	"fmt"
+	"math"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	consumercreditlimitRepository "case-study-kredit-plus/src/services/consumercreditlimit/repository"
+	consumercreditlimitUsecase "case-study-kredit-plus/src/services/consumercreditlimit/usecase"
+	consumerinstallmentRepository "case-study-kredit-plus/src/services/consumerinstallment/repository"
+	consumerinstallmentUsecase "case-study-kredit-plus/src/services/consumerinstallment/usecase"
+	consumertransactionRepository "case-study-kredit-plus/src/services/consumertransaction/repository"
+	consumertransactionUsecase "case-study-kredit-plus/src/services/consumertransaction/usecase"
+	loanproductRepository "case-study-kredit-plus/src/services/loanproduct/repository"
+	loanproductUsecase "case-study-kredit-plus/src/services/loanproduct/usecase"
+	merchantRepository "case-study-kredit-plus/src/services/merchant/repository"
+	merchantUsecase "case-study-kredit-plus/src/services/merchant/usecase"
+)
+
+// TestCreateDoesNotExceedCreditLimit fires concurrent transactions at one consumer limit.
+// It needs a migrated MySQL database, e.g.
+// TEST_DB_CONNECTION_STRING="root:secret@tcp(127.0.0.1:3306)/kredit_plus_test?parseTime=true"
+func TestCreateDoesNotExceedCreditLimit(t *testing.T) {
+	connectionString := os.Getenv("TEST_DB_CONNECTION_STRING")
+	if connectionString == "" {
+		t.Skip("TEST_DB_CONNECTION_STRING is not set")
+	}
+
+	db, err := sqlx.Open("mysql", connectionString)
+	if err != nil {
+		t.Fatalf("error when open mysql connection: %v", err)
+	}
+	defer db.Close()
+
+	const (
+		limitAmount = 1000000.0
+		otr         = 300000.0
+		attempts    = 10
+	)
+
+	consumerID := uuid.New().String()
+	creditLimitID := uuid.New().String()
+	loanProductID := uuid.New().String()
+
+	fixtures := []string{
+		fmt.Sprintf(`INSERT INTO loan_products (id, name, tenor) VALUES ('%s', 'Test 1 Month', 1)`, loanProductID),
+		fmt.Sprintf(`INSERT INTO consumers (id, NIK, full_name, legal_name, place_of_birth, date_of_birth, salary, ktp_img_key, selfie_img_key)
+      VALUES ('%s', '3171000000000001', 'Test Consumer', 'Test Consumer', 'Jakarta', '1990-01-01', 10000000, '', '')`, consumerID),
+		fmt.Sprintf(`INSERT INTO consumer_credit_limits (id, consumer_id) VALUES ('%s', '%s')`, creditLimitID, consumerID),
+		fmt.Sprintf(`INSERT INTO consumer_credit_limit_details (id, consumer_credit_limit_id, loan_product_id, limit_amount) VALUES ('%s', '%s', '%s', %f)`, uuid.New().String(), creditLimitID, loanProductID, limitAmount),
+	}
+	for _, query := range fixtures {
+		if _, err := db.Exec(query); err != nil {
+			t.Fatalf("error when inserting fixture: %v", err)
+		}
+	}
+
+	defer func() {
+		db.Exec(`DELETE FROM consumer_installments WHERE consumer_transaction_id IN (SELECT id FROM consumer_transactions WHERE consumer_id = ?)`, consumerID)
+		db.Exec(`DELETE FROM consumer_transactions WHERE consumer_id = ?`, consumerID)
+		db.Exec(`DELETE FROM consumer_credit_limit_details WHERE consumer_credit_limit_id = ?`, creditLimitID)
+		db.Exec(`DELETE FROM consumer_credit_limits WHERE id = ?`, creditLimitID)
+		db.Exec(`DELETE FROM consumers WHERE id = ?`, consumerID)
+		db.Exec(`DELETE FROM loan_products WHERE id = ?`, loanProductID)
+	}()
+
+	consumertransactionRepo := consumertransactionRepository.NewConsumerTransactionRepository(
+		data.NewMySQLStorage(db, "consumer_transactions", models.ConsumerTransaction{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumer_transaction_statuses", models.ConsumerTransactionStatus{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumer_transaction_status_histories", models.ConsumerTransactionStatusHistory{}, data.MysqlConfig{}),
+	)
+
+	consumercreditlimitRepo := consumercreditlimitRepository.NewConsumerCreditLimitRepository(
+		data.NewMySQLStorage(db, "consumer_credit_limits", models.ConsumerCreditLimit{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "consumer_credit_limit_details", models.ConsumerCreditLimitDetail{}, data.MysqlConfig{}),
+	)
+
+	consumerinstallmentRepo := consumerinstallmentRepository.NewConsumerInstallmentRepository(
+		data.NewMySQLStorage(db, "consumer_installments", models.ConsumerInstallment{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	loanproductRepo := loanproductRepository.NewLoanProductRepository(
+		data.NewMySQLStorage(db, "loan_products", models.LoanProduct{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	merchantRepo := merchantRepository.NewMerchantRepository(
+		data.NewMySQLStorage(db, "merchants", models.Merchant{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "merchant_loan_products", models.MerchantLoanProduct{}, data.MysqlConfig{}),
+	)
+
+	uLoanProduct := loanproductUsecase.NewLoanProductUsecase(db, &loanproductRepo)
+	uMerchant := merchantUsecase.NewMerchantUsecase(db, &merchantRepo, uLoanProduct)
+	uConsumerCreditLimit := consumercreditlimitUsecase.NewConsumerCreditLimitUsecase(db, &consumercreditlimitRepo, uLoanProduct)
+	uConsumerInstallment := consumerinstallmentUsecase.NewConsumerInstallmentUsecase(db, &consumerinstallmentRepo)
+	uConsumerTransaction := consumertransactionUsecase.NewConsumerTransactionUsecase(db, &consumertransactionRepo, uConsumerCreditLimit, uConsumerInstallment, uLoanProduct, uMerchant)
+
+	dataManager := data.NewManager(db)
+	gin.SetMode(gin.TestMode)
+
+	var succeeded int32
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Set("UserID", consumerID)
+
+			obj := models.ConsumerTransaction{
+				ConsumerID:     consumerID,
+				ContractNumber: fmt.Sprintf("TEST-%s-%d", consumerID[:8], i),
+				LoanProductID:  loanProductID,
+				OTR:            otr,
+				AssetName:      "Test Asset",
+			}
+
+			<-start
+			errTransaction := dataManager.RunInTransaction(c, func(tctx *gin.Context) *types.Error {
+				_, err := uConsumerTransaction.Create(tctx, obj)
+				return err
+			})
+			if errTransaction == nil {
+				atomic.AddInt32(&succeeded, 1)
+			} else if errTransaction.Message != "Insufficient Credit Limit" {
+				t.Errorf("unexpected error: %s %s", errTransaction.Path, errTransaction.Message)
+			}
+		}(i)
+	}
+
+	close(start)
+	wg.Wait()
+
+	expected := int32(math.Floor(limitAmount / otr))
+	if succeeded != expected {
+		t.Fatalf("expected %d transactions to fit the limit, got %d", expected, succeeded)
+	}
+
+	var drawn float64
+	err = db.Get(&drawn, `SELECT IFNULL(SUM(OTR), 0) FROM consumer_transactions WHERE consumer_id = ?`, consumerID)
+	if err != nil {
+		t.Fatalf("error when summing transactions: %v", err)
+	}
+
+	if drawn > limitAmount {
+		t.Fatalf("limit of %.2f exceeded, %.2f drawn", limitAmount, drawn)
+	}
+}
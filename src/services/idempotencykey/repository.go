@@ -0,0 +1,15 @@
+package idempotencykey
+
+import (
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Repository is the contract between Repository and usecase
+type Repository interface {
+	FindByKey(ctx *gin.Context, apiClientID int, key string) (*models.IdempotencyKey, *types.Error)
+	Create(*gin.Context, *models.IdempotencyKey) (*models.IdempotencyKey, *types.Error)
+	Update(*gin.Context, *models.IdempotencyKey) (*models.IdempotencyKey, *types.Error)
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"errors"
+	"net/http"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlErrDuplicateEntry is the MySQL error number for a unique index violation
+const mysqlErrDuplicateEntry = 1062
+
+type IdempotencyKeyRepository struct {
+	repository data.GenericStorage
+}
+
+func NewIdempotencyKeyRepository(repository data.GenericStorage) IdempotencyKeyRepository {
+	return IdempotencyKeyRepository{repository: repository}
+}
+
+func (s IdempotencyKeyRepository) FindByKey(ctx *gin.Context, apiClientID int, key string) (*models.IdempotencyKey, *types.Error) {
+	result := []*models.IdempotencyKey{}
+
+	err := s.repository.Where(ctx, &result, "api_client_id = :api_client_id AND idempotency_key = :idempotency_key", map[string]interface{}{
+		"api_client_id":   apiClientID,
+		"idempotency_key": key,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".IdempotencyKeyStorage->FindByKey()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, &types.Error{
+			Path:       ".IdempotencyKeyStorage->FindByKey()",
+			Message:    "Data Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return result[0], nil
+}
+
+// Create fails with data.ErrAlreadyExist when the client already used the key.
+// A concurrent insert of the same key waits on the unique index until the first transaction ends.
+// The row is not read back, so reserving a key does not take the snapshot of the transaction it guards.
+func (s IdempotencyKeyRepository) Create(ctx *gin.Context, obj *models.IdempotencyKey) (*models.IdempotencyKey, *types.Error) {
+	_, err := s.repository.Insert(ctx, obj)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry {
+			return nil, &types.Error{
+				Path:       ".IdempotencyKeyStorage->Create()",
+				Message:    "Idempotency Key Already Used",
+				Error:      data.ErrAlreadyExist,
+				StatusCode: http.StatusConflict,
+				Type:       "mysql-error",
+			}
+		}
+
+		return nil, &types.Error{
+			Path:       ".IdempotencyKeyStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return obj, nil
+}
+
+func (s IdempotencyKeyRepository) Update(ctx *gin.Context, obj *models.IdempotencyKey) (*models.IdempotencyKey, *types.Error) {
+	result := models.IdempotencyKey{}
+	err := s.repository.Update(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".IdempotencyKeyStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &result, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".IdempotencyKeyStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &result, nil
+}
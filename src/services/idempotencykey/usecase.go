@@ -0,0 +1,15 @@
+package idempotencykey
+
+import (
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Usecase is the contract between Repository and usecase
+type Usecase interface {
+	FindByKey(ctx *gin.Context, apiClientID int, key string) (*models.IdempotencyKey, *types.Error)
+	Reserve(*gin.Context, models.IdempotencyKey) (*models.IdempotencyKey, *types.Error)
+	Complete(ctx *gin.Context, obj *models.IdempotencyKey, statusCode int, body interface{}) *types.Error
+}
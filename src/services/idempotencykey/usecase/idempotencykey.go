@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/src/services/idempotencykey"
+
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"github.com/jmoiron/sqlx"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+type IdempotencyKeyUsecase struct {
+	idempotencykeyRepo idempotencykey.Repository
+	contextTimeout     time.Duration
+	db                 *sqlx.DB
+}
+
+func NewIdempotencyKeyUsecase(db *sqlx.DB, idempotencykeyRepo idempotencykey.Repository) idempotencykey.Usecase {
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	return &IdempotencyKeyUsecase{
+		idempotencykeyRepo: idempotencykeyRepo,
+		contextTimeout:     timeoutContext,
+		db:                 db,
+	}
+}
+
+func (u *IdempotencyKeyUsecase) FindByKey(ctx *gin.Context, apiClientID int, key string) (*models.IdempotencyKey, *types.Error) {
+	result, err := u.idempotencykeyRepo.FindByKey(ctx, apiClientID, key)
+	if err != nil {
+		err.Path = ".IdempotencyKeyUsecase->FindByKey()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Reserve claims the key for the client. It is meant to run in the same database transaction as the request it guards,
+// so a failed request releases the key and a concurrent retry waits for the first one to finish.
+func (u *IdempotencyKeyUsecase) Reserve(ctx *gin.Context, obj models.IdempotencyKey) (*models.IdempotencyKey, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".IdempotencyKeyUsecase->Reserve()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	data := models.IdempotencyKey{
+		ID:             uuid.New().String(),
+		APIClientID:    obj.APIClientID,
+		IdempotencyKey: obj.IdempotencyKey,
+		RequestPath:    obj.RequestPath,
+		RequestHash:    obj.RequestHash,
+		StatusID:       models.DEFAULT_STATUS_ID,
+	}
+
+	result, err := u.idempotencykeyRepo.Create(ctx, &data)
+	if err != nil {
+		err.Path = ".IdempotencyKeyUsecase->Reserve()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Complete stores the response so retries with the same key can replay it
+func (u *IdempotencyKeyUsecase) Complete(ctx *gin.Context, obj *models.IdempotencyKey, statusCode int, body interface{}) *types.Error {
+	responseBody, errJson := json.Marshal(body)
+	if errJson != nil {
+		return &types.Error{
+			Path:       ".IdempotencyKeyUsecase->Complete()",
+			Message:    errJson.Error(),
+			Error:      errJson,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "conversion-error",
+		}
+	}
+
+	obj.ResponseStatusCode = statusCode
+	obj.ResponseBody = string(responseBody)
+
+	_, err := u.idempotencykeyRepo.Update(ctx, obj)
+	if err != nil {
+		err.Path = ".IdempotencyKeyUsecase->Complete()" + err.Path
+		return err
+	}
+
+	return nil
+}
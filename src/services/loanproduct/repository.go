@@ -0,0 +1,20 @@
+package loanproduct
+
+import (
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Repository is the contract between Repository and usecase
+type Repository interface {
+	FindAll(*gin.Context, models.FindAllLoanProductParams) ([]*models.LoanProduct, *types.Error)
+	Find(*gin.Context, string) (*models.LoanProduct, *types.Error)
+	Count(*gin.Context, models.FindAllLoanProductParams) (int, *types.Error)
+	Create(*gin.Context, *models.LoanProduct) (*models.LoanProduct, *types.Error)
+	Update(*gin.Context, *models.LoanProduct) (*models.LoanProduct, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, string, string) (*models.LoanProduct, *types.Error)
+}
@@ -0,0 +1,350 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LoanProductRepository struct {
+	repository       data.GenericStorage
+	statusRepository data.GenericStorage
+}
+
+func NewLoanProductRepository(repository data.GenericStorage, statusRepository data.GenericStorage) LoanProductRepository {
+	return LoanProductRepository{repository: repository, statusRepository: statusRepository}
+}
+
+func (s LoanProductRepository) FindAll(ctx *gin.Context, params models.FindAllLoanProductParams) ([]*models.LoanProduct, *types.Error) {
+	data := []*models.LoanProduct{}
+	bulks := []*models.LoanProductBulk{}
+
+	var err error
+
+	where := `TRUE`
+
+	if params.FindAllParams.DataFinder != "" {
+		where += fmt.Sprintf(` AND %s`, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where += fmt.Sprintf(` AND loan_products.%s`, params.FindAllParams.StatusID)
+	}
+
+	if params.ID != "" {
+		where += ` AND loan_products.id = :id`
+	}
+
+	if params.Tenor != 0 {
+		where += ` AND loan_products.tenor = :tenor`
+	}
+
+	if params.ActiveOn != "" {
+		where += ` AND (loan_products.active_from IS NULL OR loan_products.active_from <= :active_on)`
+		where += ` AND (loan_products.active_to IS NULL OR loan_products.active_to >= :active_on)`
+	}
+
+	if params.FindAllParams.SortBy != "" {
+		where += fmt.Sprintf(` ORDER BY %s`, params.FindAllParams.SortBy)
+	}
+
+	if params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0 {
+		where += ` LIMIT :limit OFFSET :offset`
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    loan_products.id, loan_products.name, loan_products.code, loan_products.tenor, loan_products.interest_method, loan_products.interest_rate,
+    loan_products.admin_fee_amount, loan_products.admin_fee_rate, loan_products.min_amount, loan_products.max_amount,
+    loan_products.active_from, loan_products.active_to, loan_products.cancellation_window_days,
+    loan_products.early_settlement_penalty_amount, loan_products.early_settlement_penalty_rate,
+    loan_products.late_fee_daily_rate, loan_products.late_fee_cap_amount, loan_products.late_fee_grace_days,
+    loan_products.status_id, status.name status_name
+  FROM loan_products
+  JOIN status ON loan_products.status_id = status.id
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"limit":     params.FindAllParams.Size,
+		"offset":    ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"status_id": params.FindAllParams.StatusID,
+		"id":        params.ID,
+		"tenor":     params.Tenor,
+		"active_on": params.ActiveOn,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".LoanProductStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		obj := &models.LoanProduct{
+			ID:             v.ID,
+			Name:           v.Name,
+			Code:           v.Code,
+			Tenor:          v.Tenor,
+			InterestMethod: v.InterestMethod,
+			InterestRate:   v.InterestRate,
+			AdminFeeAmount: v.AdminFeeAmount,
+			AdminFeeRate:   v.AdminFeeRate,
+			MinAmount:      v.MinAmount,
+			MaxAmount:      v.MaxAmount,
+			ActiveFrom:     v.ActiveFrom,
+			ActiveTo:       v.ActiveTo,
+
+			CancellationWindowDays:       v.CancellationWindowDays,
+			EarlySettlementPenaltyAmount: v.EarlySettlementPenaltyAmount,
+			EarlySettlementPenaltyRate:   v.EarlySettlementPenaltyRate,
+			LateFeeDailyRate:             v.LateFeeDailyRate,
+			LateFeeCapAmount:             v.LateFeeCapAmount,
+			LateFeeGraceDays:             v.LateFeeGraceDays,
+
+			StatusID: v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+
+		data = append(data, obj)
+	}
+
+	return data, nil
+}
+
+func (s LoanProductRepository) Find(ctx *gin.Context, id string) (*models.LoanProduct, *types.Error) {
+	result := models.LoanProduct{}
+	bulks := []*models.LoanProductBulk{}
+	var err error
+
+	query := `
+  SELECT
+    loan_products.id, loan_products.name, loan_products.code, loan_products.tenor, loan_products.interest_method, loan_products.interest_rate,
+    loan_products.admin_fee_amount, loan_products.admin_fee_rate, loan_products.min_amount, loan_products.max_amount,
+    loan_products.active_from, loan_products.active_to, loan_products.cancellation_window_days,
+    loan_products.early_settlement_penalty_amount, loan_products.early_settlement_penalty_rate,
+    loan_products.late_fee_daily_rate, loan_products.late_fee_cap_amount, loan_products.late_fee_grace_days,
+    loan_products.status_id, status.name status_name
+  FROM loan_products
+  JOIN status ON loan_products.status_id = status.id
+  WHERE loan_products.id = :id`
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".LoanProductStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(bulks) > 0 {
+		v := bulks[0]
+		result = models.LoanProduct{
+			ID:             v.ID,
+			Name:           v.Name,
+			Code:           v.Code,
+			Tenor:          v.Tenor,
+			InterestMethod: v.InterestMethod,
+			InterestRate:   v.InterestRate,
+			AdminFeeAmount: v.AdminFeeAmount,
+			AdminFeeRate:   v.AdminFeeRate,
+			MinAmount:      v.MinAmount,
+			MaxAmount:      v.MaxAmount,
+			ActiveFrom:     v.ActiveFrom,
+			ActiveTo:       v.ActiveTo,
+
+			CancellationWindowDays:       v.CancellationWindowDays,
+			EarlySettlementPenaltyAmount: v.EarlySettlementPenaltyAmount,
+			EarlySettlementPenaltyRate:   v.EarlySettlementPenaltyRate,
+			LateFeeDailyRate:             v.LateFeeDailyRate,
+			LateFeeCapAmount:             v.LateFeeCapAmount,
+			LateFeeGraceDays:             v.LateFeeGraceDays,
+
+			StatusID: v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+	} else {
+		return nil, &types.Error{
+			Path:       ".LoanProductStorage->Find()",
+			Message:    "Data Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &result, nil
+}
+
+func (s LoanProductRepository) Count(ctx *gin.Context, params models.FindAllLoanProductParams) (int, *types.Error) {
+	bulks := []*models.LoanProductBulk{}
+
+	var err error
+
+	where := `TRUE`
+
+	if params.FindAllParams.DataFinder != "" {
+		where += fmt.Sprintf(` AND %s`, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where += fmt.Sprintf(` AND loan_products.%s`, params.FindAllParams.StatusID)
+	}
+
+	if params.ID != "" {
+		where += ` AND loan_products.id = :id`
+	}
+
+	if params.Tenor != 0 {
+		where += ` AND loan_products.tenor = :tenor`
+	}
+
+	if params.ActiveOn != "" {
+		where += ` AND (loan_products.active_from IS NULL OR loan_products.active_from <= :active_on)`
+		where += ` AND (loan_products.active_to IS NULL OR loan_products.active_to >= :active_on)`
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    loan_products.id, loan_products.name, loan_products.code, loan_products.tenor, loan_products.interest_method, loan_products.interest_rate,
+    loan_products.admin_fee_amount, loan_products.admin_fee_rate, loan_products.min_amount, loan_products.max_amount,
+    loan_products.active_from, loan_products.active_to, loan_products.cancellation_window_days,
+    loan_products.early_settlement_penalty_amount, loan_products.early_settlement_penalty_rate,
+    loan_products.late_fee_daily_rate, loan_products.late_fee_cap_amount, loan_products.late_fee_grace_days,
+    loan_products.status_id, status.name status_name
+  FROM loan_products
+  JOIN status ON loan_products.status_id = status.id
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"status_id": params.FindAllParams.StatusID,
+		"id":        params.ID,
+		"tenor":     params.Tenor,
+		"active_on": params.ActiveOn,
+	})
+	if err != nil {
+		return 0, &types.Error{
+			Path:       ".LoanProductStorage->Count()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return len(bulks), nil
+}
+
+func (s LoanProductRepository) Create(ctx *gin.Context, obj *models.LoanProduct) (*models.LoanProduct, *types.Error) {
+	data := models.LoanProduct{}
+	_, err := s.repository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".LoanProductStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".LoanProductStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+func (s LoanProductRepository) Update(ctx *gin.Context, obj *models.LoanProduct) (*models.LoanProduct, *types.Error) {
+	data := models.LoanProduct{}
+	err := s.repository.Update(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".LoanProductStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".LoanProductStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+func (s LoanProductRepository) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	status := []*models.Status{}
+
+	err := s.statusRepository.Where(ctx, &status, "1=1", map[string]interface{}{})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".LoanProductStorage->FindStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return status, nil
+}
+
+func (s LoanProductRepository) UpdateStatus(ctx *gin.Context, id string, statusID string) (*models.LoanProduct, *types.Error) {
+	data := models.LoanProduct{}
+	err := s.repository.UpdateStatus(ctx, id, statusID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".LoanProductStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, id)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".LoanProductStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &data, nil
+}
@@ -0,0 +1,314 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/src/services/loanproduct"
+
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"github.com/jmoiron/sqlx"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+type LoanProductUsecase struct {
+	loanproductRepo loanproduct.Repository
+	contextTimeout  time.Duration
+	db              *sqlx.DB
+}
+
+func NewLoanProductUsecase(db *sqlx.DB, loanproductRepo loanproduct.Repository) loanproduct.Usecase {
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	return &LoanProductUsecase{
+		loanproductRepo: loanproductRepo,
+		contextTimeout:  timeoutContext,
+		db:              db,
+	}
+}
+
+func (u *LoanProductUsecase) FindAll(ctx *gin.Context, params models.FindAllLoanProductParams) ([]*models.LoanProduct, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(params)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".LoanProductUsecase->FindAll()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.loanproductRepo.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".LoanProductUsecase->FindAll()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *LoanProductUsecase) Find(ctx *gin.Context, id string) (*models.LoanProduct, *types.Error) {
+	result, err := u.loanproductRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".LoanProductUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *LoanProductUsecase) Count(ctx *gin.Context, params models.FindAllLoanProductParams) (int, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(params)
+	if errValidation != nil {
+		return 0, &types.Error{
+			Path:       ".LoanProductUsecase->Count()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.loanproductRepo.Count(ctx, params)
+	if err != nil {
+		err.Path = ".LoanProductUsecase->Count()" + err.Path
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (u *LoanProductUsecase) Create(ctx *gin.Context, obj models.LoanProduct) (*models.LoanProduct, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".LoanProductUsecase->Create()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	err := validateLoanProductRules(obj)
+	if err != nil {
+		err.Path = ".LoanProductUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	data := models.LoanProduct{
+		ID:             uuid.New().String(),
+		Name:           obj.Name,
+		Code:           obj.Code,
+		Tenor:          obj.Tenor,
+		InterestMethod: obj.InterestMethod,
+		InterestRate:   obj.InterestRate,
+		AdminFeeAmount: obj.AdminFeeAmount,
+		AdminFeeRate:   obj.AdminFeeRate,
+		MinAmount:      obj.MinAmount,
+		MaxAmount:      obj.MaxAmount,
+		ActiveFrom:     obj.ActiveFrom,
+		ActiveTo:       obj.ActiveTo,
+		StatusID:       models.DEFAULT_STATUS_ID,
+
+		CancellationWindowDays:       obj.CancellationWindowDays,
+		EarlySettlementPenaltyAmount: obj.EarlySettlementPenaltyAmount,
+		EarlySettlementPenaltyRate:   obj.EarlySettlementPenaltyRate,
+		LateFeeDailyRate:             obj.LateFeeDailyRate,
+		LateFeeCapAmount:             obj.LateFeeCapAmount,
+		LateFeeGraceDays:             obj.LateFeeGraceDays,
+	}
+
+	result, err := u.loanproductRepo.Create(ctx, &data)
+	if err != nil {
+		err.Path = ".LoanProductUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *LoanProductUsecase) Update(ctx *gin.Context, id string, obj models.LoanProduct) (*models.LoanProduct, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".LoanProductUsecase->Update()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	err := validateLoanProductRules(obj)
+	if err != nil {
+		err.Path = ".LoanProductUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	data, err := u.loanproductRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".LoanProductUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	// existing transactions keep the pricing they were booked with, so every field may change
+	data.Name = obj.Name
+	data.Code = obj.Code
+	data.Tenor = obj.Tenor
+	data.InterestMethod = obj.InterestMethod
+	data.InterestRate = obj.InterestRate
+	data.AdminFeeAmount = obj.AdminFeeAmount
+	data.AdminFeeRate = obj.AdminFeeRate
+	data.MinAmount = obj.MinAmount
+	data.MaxAmount = obj.MaxAmount
+	data.ActiveFrom = obj.ActiveFrom
+	data.ActiveTo = obj.ActiveTo
+	data.CancellationWindowDays = obj.CancellationWindowDays
+	data.EarlySettlementPenaltyAmount = obj.EarlySettlementPenaltyAmount
+	data.EarlySettlementPenaltyRate = obj.EarlySettlementPenaltyRate
+	data.LateFeeDailyRate = obj.LateFeeDailyRate
+	data.LateFeeCapAmount = obj.LateFeeCapAmount
+	data.LateFeeGraceDays = obj.LateFeeGraceDays
+
+	result, err := u.loanproductRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".LoanProductUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	return result, err
+}
+
+func (u *LoanProductUsecase) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	result, err := u.loanproductRepo.FindStatus(ctx)
+	if err != nil {
+		err.Path = ".LoanProductUsecase->FindStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *LoanProductUsecase) UpdateStatus(ctx *gin.Context, id string, newStatusID string) (*models.LoanProduct, *types.Error) {
+	result, err := u.loanproductRepo.UpdateStatus(ctx, id, newStatusID)
+	if err != nil {
+		err.Path = ".LoanProductUsecase->UpdateStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, err
+}
+
+// AVAILABILITY
+
+// FindAvailable returns the active product a transaction can be booked on today, picked by ID when given, otherwise by tenor.
+// When several products share a tenor, the one with the latest active window wins.
+func (u *LoanProductUsecase) FindAvailable(ctx *gin.Context, id string, tenor int) (*models.LoanProduct, *types.Error) {
+	var params models.FindAllLoanProductParams
+	params.ID = id
+	params.Tenor = tenor
+	params.ActiveOn = library.UTCPlus7().Format(library.StrToDateFormat)
+	params.FindAllParams.StatusID = `status_id = "1"`
+	params.FindAllParams.SortBy = "loan_products.active_from DESC, loan_products.created_at DESC"
+
+	if id == "" && tenor <= 0 {
+		return nil, &types.Error{
+			Path:       ".LoanProductUsecase->FindAvailable()",
+			Message:    "Loan Product Invalid",
+			Error:      fmt.Errorf("Loan Product Invalid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	result, err := u.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".LoanProductUsecase->FindAvailable()" + err.Path
+		return nil, err
+	}
+
+	if len(result) == 0 {
+		return nil, &types.Error{
+			Path:       ".LoanProductUsecase->FindAvailable()",
+			Message:    "Loan Product Not Available",
+			Error:      data.ErrNotFound,
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	return result[0], nil
+}
+
+func validateLoanProductRules(obj models.LoanProduct) *types.Error {
+	if obj.MaxAmount > 0 && obj.MaxAmount < obj.MinAmount {
+		return &types.Error{
+			Path:       ".validateLoanProductRules()",
+			Message:    "Max Amount must not be lower than Min Amount",
+			Error:      fmt.Errorf("Max Amount must not be lower than Min Amount"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	if obj.ActiveFrom != nil && obj.ActiveTo != nil && obj.ActiveTo.Before(*obj.ActiveFrom) {
+		return &types.Error{
+			Path:       ".validateLoanProductRules()",
+			Message:    "Active To must not be before Active From",
+			Error:      fmt.Errorf("Active To must not be before Active From"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	return nil
+}
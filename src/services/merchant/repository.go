@@ -0,0 +1,29 @@
+package merchant
+
+import (
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Repository is the contract between Repository and usecase
+type Repository interface {
+	FindAll(*gin.Context, models.FindAllMerchantParams) ([]*models.Merchant, *types.Error)
+	Find(*gin.Context, string) (*models.Merchant, *types.Error)
+	Count(*gin.Context, models.FindAllMerchantParams) (int, *types.Error)
+	Create(*gin.Context, *models.Merchant) (*models.Merchant, *types.Error)
+	Update(*gin.Context, *models.Merchant) (*models.Merchant, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, string, string) (*models.Merchant, *types.Error)
+
+	FindLoanProducts(*gin.Context, string) ([]*models.MerchantLoanProduct, *types.Error)
+	CreateLoanProduct(*gin.Context, *models.MerchantLoanProduct) (*models.MerchantLoanProduct, *types.Error)
+	DeleteLoanProducts(*gin.Context, string) *types.Error
+
+	// API Clients
+	FindAPIClients(*gin.Context, string) ([]*models.INTIDNameTemplate, *types.Error)
+	FindAPIClientMerchantID(ctx *gin.Context, apiClientID int) (string, *types.Error)
+	UpdateAPIClientMerchantID(ctx *gin.Context, apiClientID int, merchantID string) *types.Error
+}
@@ -0,0 +1,459 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MerchantRepository struct {
+	repository            data.GenericStorage
+	statusRepository      data.GenericStorage
+	loanProductRepository data.GenericStorage
+}
+
+func NewMerchantRepository(repository data.GenericStorage, statusRepository data.GenericStorage, loanProductRepository data.GenericStorage) MerchantRepository {
+	return MerchantRepository{repository: repository, statusRepository: statusRepository, loanProductRepository: loanProductRepository}
+}
+
+func (s MerchantRepository) FindAll(ctx *gin.Context, params models.FindAllMerchantParams) ([]*models.Merchant, *types.Error) {
+	data := []*models.Merchant{}
+	bulks := []*models.MerchantBulk{}
+
+	var err error
+
+	where := `TRUE`
+
+	if params.FindAllParams.DataFinder != "" {
+		where += fmt.Sprintf(` AND %s`, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where += fmt.Sprintf(` AND merchants.%s`, params.FindAllParams.StatusID)
+	}
+
+	if params.Name != "" {
+		where += ` AND merchants.name LIKE CONCAT('%', :name, '%')`
+	}
+
+	if params.FindAllParams.SortBy != "" {
+		where += fmt.Sprintf(` ORDER BY %s`, params.FindAllParams.SortBy)
+	}
+
+	if params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0 {
+		where += ` LIMIT :limit OFFSET :offset`
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    merchants.id, merchants.name, merchants.email, merchants.phone_number, merchants.address,
+    merchants.settlement_bank_name, merchants.settlement_account_number, merchants.settlement_account_name,
+    merchants.fee_share_rate, merchants.status_id, status.name status_name
+  FROM merchants
+  JOIN status ON merchants.status_id = status.id
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"limit":     params.FindAllParams.Size,
+		"offset":    ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"status_id": params.FindAllParams.StatusID,
+		"name":      params.Name,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".MerchantStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		obj := &models.Merchant{
+			ID:                      v.ID,
+			Name:                    v.Name,
+			Email:                   v.Email,
+			PhoneNumber:             v.PhoneNumber,
+			Address:                 v.Address,
+			SettlementBankName:      v.SettlementBankName,
+			SettlementAccountNumber: v.SettlementAccountNumber,
+			SettlementAccountName:   v.SettlementAccountName,
+			FeeShareRate:            v.FeeShareRate,
+			StatusID:                v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+
+		data = append(data, obj)
+	}
+
+	return data, nil
+}
+
+func (s MerchantRepository) Find(ctx *gin.Context, id string) (*models.Merchant, *types.Error) {
+	result := models.Merchant{}
+	bulks := []*models.MerchantBulk{}
+	var err error
+
+	query := `
+  SELECT
+    merchants.id, merchants.name, merchants.email, merchants.phone_number, merchants.address,
+    merchants.settlement_bank_name, merchants.settlement_account_number, merchants.settlement_account_name,
+    merchants.fee_share_rate, merchants.status_id, status.name status_name
+  FROM merchants
+  JOIN status ON merchants.status_id = status.id
+  WHERE merchants.id = :id`
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".MerchantStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(bulks) > 0 {
+		v := bulks[0]
+		result = models.Merchant{
+			ID:                      v.ID,
+			Name:                    v.Name,
+			Email:                   v.Email,
+			PhoneNumber:             v.PhoneNumber,
+			Address:                 v.Address,
+			SettlementBankName:      v.SettlementBankName,
+			SettlementAccountNumber: v.SettlementAccountNumber,
+			SettlementAccountName:   v.SettlementAccountName,
+			FeeShareRate:            v.FeeShareRate,
+			StatusID:                v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+	} else {
+		return nil, &types.Error{
+			Path:       ".MerchantStorage->Find()",
+			Message:    "Data Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &result, nil
+}
+
+func (s MerchantRepository) Count(ctx *gin.Context, params models.FindAllMerchantParams) (int, *types.Error) {
+	bulks := []*models.MerchantBulk{}
+
+	var err error
+
+	where := `TRUE`
+
+	if params.FindAllParams.DataFinder != "" {
+		where += fmt.Sprintf(` AND %s`, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where += fmt.Sprintf(` AND merchants.%s`, params.FindAllParams.StatusID)
+	}
+
+	if params.Name != "" {
+		where += ` AND merchants.name LIKE CONCAT('%', :name, '%')`
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    merchants.id, merchants.name, merchants.status_id, status.name status_name
+  FROM merchants
+  JOIN status ON merchants.status_id = status.id
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"status_id": params.FindAllParams.StatusID,
+		"name":      params.Name,
+	})
+	if err != nil {
+		return 0, &types.Error{
+			Path:       ".MerchantStorage->Count()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return len(bulks), nil
+}
+
+func (s MerchantRepository) Create(ctx *gin.Context, obj *models.Merchant) (*models.Merchant, *types.Error) {
+	data := models.Merchant{}
+	_, err := s.repository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".MerchantStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".MerchantStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+func (s MerchantRepository) Update(ctx *gin.Context, obj *models.Merchant) (*models.Merchant, *types.Error) {
+	data := models.Merchant{}
+	err := s.repository.Update(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".MerchantStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".MerchantStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+func (s MerchantRepository) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	status := []*models.Status{}
+
+	err := s.statusRepository.Where(ctx, &status, "1=1", map[string]interface{}{})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".MerchantStorage->FindStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return status, nil
+}
+
+func (s MerchantRepository) UpdateStatus(ctx *gin.Context, id string, statusID string) (*models.Merchant, *types.Error) {
+	data := models.Merchant{}
+	err := s.repository.UpdateStatus(ctx, id, statusID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".MerchantStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, id)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".MerchantStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &data, nil
+}
+
+// LOAN PRODUCTS
+
+func (s MerchantRepository) FindLoanProducts(ctx *gin.Context, merchantID string) ([]*models.MerchantLoanProduct, *types.Error) {
+	data := []*models.MerchantLoanProduct{}
+	bulks := []*models.MerchantLoanProductBulk{}
+
+	query := `
+  SELECT
+    merchant_loan_products.id, merchant_loan_products.merchant_id, merchant_loan_products.loan_product_id,
+    merchant_loan_products.status_id, loan_products.name loan_product_name, loan_products.tenor
+  FROM merchant_loan_products
+  JOIN loan_products ON loan_products.id = merchant_loan_products.loan_product_id
+  WHERE merchant_loan_products.merchant_id = :merchant_id
+  ORDER BY loan_products.tenor, loan_products.name`
+
+	err := s.loanProductRepository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"merchant_id": merchantID,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".MerchantStorage->FindLoanProducts()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		obj := &models.MerchantLoanProduct{
+			ID:            v.ID,
+			MerchantID:    v.MerchantID,
+			LoanProductID: v.LoanProductID,
+			LoanProduct: &models.IDNameTemplate{
+				ID:   v.LoanProductID,
+				Name: v.LoanProductName,
+			},
+			Tenor:    v.Tenor,
+			StatusID: v.StatusID,
+		}
+
+		data = append(data, obj)
+	}
+
+	return data, nil
+}
+
+func (s MerchantRepository) CreateLoanProduct(ctx *gin.Context, obj *models.MerchantLoanProduct) (*models.MerchantLoanProduct, *types.Error) {
+	data := models.MerchantLoanProduct{}
+	_, err := s.loanProductRepository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".MerchantStorage->CreateLoanProduct()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.loanProductRepository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".MerchantStorage->CreateLoanProduct()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+func (s MerchantRepository) DeleteLoanProducts(ctx *gin.Context, merchantID string) *types.Error {
+	query := `DELETE FROM merchant_loan_products WHERE merchant_id = :merchant_id`
+
+	err := s.loanProductRepository.ExecQuery(ctx, query, map[string]interface{}{
+		"merchant_id": merchantID,
+	})
+	if err != nil {
+		return &types.Error{
+			Path:       ".MerchantStorage->DeleteLoanProducts()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
+
+// API CLIENTS
+
+func (s MerchantRepository) FindAPIClients(ctx *gin.Context, merchantID string) ([]*models.INTIDNameTemplate, *types.Error) {
+	data := []*models.INTIDNameTemplate{}
+
+	query := `SELECT api_client.id, api_client.name FROM api_client WHERE api_client.merchant_id = :merchant_id ORDER BY api_client.id`
+
+	err := s.repository.SelectWithQuery(ctx, &data, query, map[string]interface{}{"merchant_id": merchantID})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".MerchantStorage->FindAPIClients()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return data, nil
+}
+
+// FindAPIClientMerchantID returns the merchant the API client belongs to, empty when it is not linked to one
+func (s MerchantRepository) FindAPIClientMerchantID(ctx *gin.Context, apiClientID int) (string, *types.Error) {
+	rows := []*models.INTIDNameTemplate{}
+
+	query := `SELECT api_client.id, api_client.merchant_id name FROM api_client WHERE api_client.id = :id`
+
+	err := s.repository.SelectWithQuery(ctx, &rows, query, map[string]interface{}{"id": apiClientID})
+	if err != nil {
+		return "", &types.Error{
+			Path:       ".MerchantStorage->FindAPIClientMerchantID()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(rows) == 0 {
+		return "", &types.Error{
+			Path:       ".MerchantStorage->FindAPIClientMerchantID()",
+			Message:    "API Client Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return rows[0].Name, nil
+}
+
+func (s MerchantRepository) UpdateAPIClientMerchantID(ctx *gin.Context, apiClientID int, merchantID string) *types.Error {
+	query := `UPDATE api_client SET merchant_id = :merchant_id WHERE id = :id`
+
+	err := s.repository.ExecQuery(ctx, query, map[string]interface{}{
+		"id":          apiClientID,
+		"merchant_id": merchantID,
+	})
+	if err != nil {
+		return &types.Error{
+			Path:       ".MerchantStorage->UpdateAPIClientMerchantID()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
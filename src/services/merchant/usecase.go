@@ -0,0 +1,27 @@
+package merchant
+
+import (
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Usecase is the contract between Repository and usecase
+type Usecase interface {
+	FindAll(*gin.Context, models.FindAllMerchantParams) ([]*models.Merchant, *types.Error)
+	Find(*gin.Context, string) (*models.Merchant, *types.Error)
+	Count(*gin.Context, models.FindAllMerchantParams) (int, *types.Error)
+	Create(*gin.Context, models.Merchant) (*models.Merchant, *types.Error)
+	Update(*gin.Context, string, models.Merchant) (*models.Merchant, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, string, string) (*models.Merchant, *types.Error)
+
+	// API Clients
+	LinkAPIClient(ctx *gin.Context, merchantID string, apiClientID int) (*models.Merchant, *types.Error)
+	UnlinkAPIClient(ctx *gin.Context, merchantID string, apiClientID int) (*models.Merchant, *types.Error)
+
+	// Selling
+	FindSellingMerchant(ctx *gin.Context, merchantID string, loanProductID string) (*models.Merchant, *types.Error)
+}
@@ -0,0 +1,415 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/src/services/loanproduct"
+	"case-study-kredit-plus/src/services/merchant"
+
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"github.com/jmoiron/sqlx"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+type MerchantUsecase struct {
+	merchantRepo       merchant.Repository
+	loanproductUsecase loanproduct.Usecase
+	contextTimeout     time.Duration
+	db                 *sqlx.DB
+}
+
+func NewMerchantUsecase(db *sqlx.DB, merchantRepo merchant.Repository, loanproductUsecase loanproduct.Usecase) merchant.Usecase {
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	return &MerchantUsecase{
+		merchantRepo:       merchantRepo,
+		loanproductUsecase: loanproductUsecase,
+		contextTimeout:     timeoutContext,
+		db:                 db,
+	}
+}
+
+func (u *MerchantUsecase) FindAll(ctx *gin.Context, params models.FindAllMerchantParams) ([]*models.Merchant, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(params)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".MerchantUsecase->FindAll()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.merchantRepo.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".MerchantUsecase->FindAll()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *MerchantUsecase) Find(ctx *gin.Context, id string) (*models.Merchant, *types.Error) {
+	result, err := u.merchantRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".MerchantUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	result.LoanProducts, err = u.merchantRepo.FindLoanProducts(ctx, id)
+	if err != nil {
+		err.Path = ".MerchantUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	result.APIClients, err = u.merchantRepo.FindAPIClients(ctx, id)
+	if err != nil {
+		err.Path = ".MerchantUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *MerchantUsecase) Count(ctx *gin.Context, params models.FindAllMerchantParams) (int, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(params)
+	if errValidation != nil {
+		return 0, &types.Error{
+			Path:       ".MerchantUsecase->Count()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.merchantRepo.Count(ctx, params)
+	if err != nil {
+		err.Path = ".MerchantUsecase->Count()" + err.Path
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (u *MerchantUsecase) Create(ctx *gin.Context, obj models.Merchant) (*models.Merchant, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".MerchantUsecase->Create()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	err := u.validateLoanProducts(ctx, obj.LoanProducts)
+	if err != nil {
+		err.Path = ".MerchantUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	data := models.Merchant{
+		ID:                      uuid.New().String(),
+		Name:                    obj.Name,
+		Email:                   obj.Email,
+		PhoneNumber:             obj.PhoneNumber,
+		Address:                 obj.Address,
+		SettlementBankName:      obj.SettlementBankName,
+		SettlementAccountNumber: obj.SettlementAccountNumber,
+		SettlementAccountName:   obj.SettlementAccountName,
+		FeeShareRate:            obj.FeeShareRate,
+		StatusID:                models.DEFAULT_STATUS_ID,
+	}
+
+	result, err := u.merchantRepo.Create(ctx, &data)
+	if err != nil {
+		err.Path = ".MerchantUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	result.LoanProducts, err = u.createLoanProducts(ctx, result.ID, obj.LoanProducts)
+	if err != nil {
+		err.Path = ".MerchantUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Update replaces the profile and the list of loan products the merchant may sell
+func (u *MerchantUsecase) Update(ctx *gin.Context, id string, obj models.Merchant) (*models.Merchant, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".MerchantUsecase->Update()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	err := u.validateLoanProducts(ctx, obj.LoanProducts)
+	if err != nil {
+		err.Path = ".MerchantUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	data, err := u.merchantRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".MerchantUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	// transactions already booked keep the fee share they were stamped with
+	data.Name = obj.Name
+	data.Email = obj.Email
+	data.PhoneNumber = obj.PhoneNumber
+	data.Address = obj.Address
+	data.SettlementBankName = obj.SettlementBankName
+	data.SettlementAccountNumber = obj.SettlementAccountNumber
+	data.SettlementAccountName = obj.SettlementAccountName
+	data.FeeShareRate = obj.FeeShareRate
+
+	result, err := u.merchantRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".MerchantUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	err = u.merchantRepo.DeleteLoanProducts(ctx, result.ID)
+	if err != nil {
+		err.Path = ".MerchantUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	result.LoanProducts, err = u.createLoanProducts(ctx, result.ID, obj.LoanProducts)
+	if err != nil {
+		err.Path = ".MerchantUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *MerchantUsecase) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	result, err := u.merchantRepo.FindStatus(ctx)
+	if err != nil {
+		err.Path = ".MerchantUsecase->FindStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *MerchantUsecase) UpdateStatus(ctx *gin.Context, id string, newStatusID string) (*models.Merchant, *types.Error) {
+	result, err := u.merchantRepo.UpdateStatus(ctx, id, newStatusID)
+	if err != nil {
+		err.Path = ".MerchantUsecase->UpdateStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, err
+}
+
+// API CLIENTS
+
+// LinkAPIClient moves the API client to the merchant, transactions it books from now on are stamped with it
+func (u *MerchantUsecase) LinkAPIClient(ctx *gin.Context, merchantID string, apiClientID int) (*models.Merchant, *types.Error) {
+	_, err := u.merchantRepo.Find(ctx, merchantID)
+	if err != nil {
+		err.Path = ".MerchantUsecase->LinkAPIClient()" + err.Path
+		return nil, err
+	}
+
+	_, err = u.merchantRepo.FindAPIClientMerchantID(ctx, apiClientID)
+	if err != nil {
+		err.Path = ".MerchantUsecase->LinkAPIClient()" + err.Path
+		return nil, err
+	}
+
+	err = u.merchantRepo.UpdateAPIClientMerchantID(ctx, apiClientID, merchantID)
+	if err != nil {
+		err.Path = ".MerchantUsecase->LinkAPIClient()" + err.Path
+		return nil, err
+	}
+
+	result, err := u.Find(ctx, merchantID)
+	if err != nil {
+		err.Path = ".MerchantUsecase->LinkAPIClient()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *MerchantUsecase) UnlinkAPIClient(ctx *gin.Context, merchantID string, apiClientID int) (*models.Merchant, *types.Error) {
+	currentMerchantID, err := u.merchantRepo.FindAPIClientMerchantID(ctx, apiClientID)
+	if err != nil {
+		err.Path = ".MerchantUsecase->UnlinkAPIClient()" + err.Path
+		return nil, err
+	}
+
+	if currentMerchantID != merchantID {
+		return nil, &types.Error{
+			Path:       ".MerchantUsecase->UnlinkAPIClient()",
+			Message:    "API Client Does Not Belong To Merchant",
+			Error:      fmt.Errorf("API Client Does Not Belong To Merchant"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	err = u.merchantRepo.UpdateAPIClientMerchantID(ctx, apiClientID, "")
+	if err != nil {
+		err.Path = ".MerchantUsecase->UnlinkAPIClient()" + err.Path
+		return nil, err
+	}
+
+	result, err := u.Find(ctx, merchantID)
+	if err != nil {
+		err.Path = ".MerchantUsecase->UnlinkAPIClient()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SELLING
+
+// FindSellingMerchant returns the merchant if it is active and allowed to sell the loan product.
+// An empty loanProductID only checks the merchant.
+func (u *MerchantUsecase) FindSellingMerchant(ctx *gin.Context, merchantID string, loanProductID string) (*models.Merchant, *types.Error) {
+	result, err := u.Find(ctx, merchantID)
+	if err != nil {
+		err.Path = ".MerchantUsecase->FindSellingMerchant()" + err.Path
+		return nil, err
+	}
+
+	if result.StatusID != models.STATUS_ACTIVE {
+		return nil, &types.Error{
+			Path:       ".MerchantUsecase->FindSellingMerchant()",
+			Message:    "Merchant Is Not Active",
+			Error:      fmt.Errorf("Merchant Is Not Active"),
+			Type:       "validation-error",
+			StatusCode: http.StatusForbidden,
+		}
+	}
+
+	if loanProductID == "" {
+		return result, nil
+	}
+
+	for _, v := range result.LoanProducts {
+		if v.LoanProductID == loanProductID {
+			return result, nil
+		}
+	}
+
+	return nil, &types.Error{
+		Path:       ".MerchantUsecase->FindSellingMerchant()",
+		Message:    "Loan Product Not Allowed For Merchant",
+		Error:      fmt.Errorf("Loan Product Not Allowed For Merchant"),
+		Type:       "validation-error",
+		StatusCode: http.StatusUnprocessableEntity,
+	}
+}
+
+// validateLoanProducts makes sure every loan product exists, and that none is listed twice
+func (u *MerchantUsecase) validateLoanProducts(ctx *gin.Context, loanProducts []*models.MerchantLoanProduct) *types.Error {
+	seen := map[string]bool{}
+	for _, v := range loanProducts {
+		if seen[v.LoanProductID] {
+			return &types.Error{
+				Path:       ".MerchantUsecase->validateLoanProducts()",
+				Message:    "Duplicate Loan Product",
+				Error:      fmt.Errorf("duplicate loan product %s", v.LoanProductID),
+				StatusCode: http.StatusUnprocessableEntity,
+				Type:       "validation-error",
+			}
+		}
+		seen[v.LoanProductID] = true
+
+		_, err := u.loanproductUsecase.Find(ctx, v.LoanProductID)
+		if err != nil {
+			err.Path = ".MerchantUsecase->validateLoanProducts()" + err.Path
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u *MerchantUsecase) createLoanProducts(ctx *gin.Context, merchantID string, loanProducts []*models.MerchantLoanProduct) ([]*models.MerchantLoanProduct, *types.Error) {
+	for _, v := range loanProducts {
+		data := models.MerchantLoanProduct{
+			ID:            uuid.New().String(),
+			MerchantID:    merchantID,
+			LoanProductID: v.LoanProductID,
+			StatusID:      models.DEFAULT_STATUS_ID,
+		}
+
+		_, err := u.merchantRepo.CreateLoanProduct(ctx, &data)
+		if err != nil {
+			err.Path = ".MerchantUsecase->createLoanProducts()" + err.Path
+			return nil, err
+		}
+	}
+
+	result, err := u.merchantRepo.FindLoanProducts(ctx, merchantID)
+	if err != nil {
+		err.Path = ".MerchantUsecase->createLoanProducts()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
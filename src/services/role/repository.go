@@ -0,0 +1,26 @@
+package role
+
+import (
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Repository is the contract between Repository and usecase
+type Repository interface {
+	FindAll(*gin.Context, models.FindAllRoleParams) ([]*models.Role, *types.Error)
+	Find(*gin.Context, string) (*models.Role, *types.Error)
+	Count(*gin.Context, models.FindAllRoleParams) (int, *types.Error)
+	Create(*gin.Context, *models.Role) (*models.Role, *types.Error)
+	Update(*gin.Context, *models.Role) (*models.Role, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, string, string) (*models.Role, *types.Error)
+
+	// Permissions
+	FindPermissions(*gin.Context) ([]*models.Permission, *types.Error)
+	FindRolePermissions(*gin.Context, string) ([]*models.RolePermission, *types.Error)
+	CreateRolePermission(*gin.Context, *models.RolePermission) (*models.RolePermission, *types.Error)
+	DeleteRolePermissions(*gin.Context, string) *types.Error
+}
@@ -0,0 +1,390 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleRepository struct {
+	repository               data.GenericStorage
+	statusRepository         data.GenericStorage
+	rolePermissionRepository data.GenericStorage
+}
+
+func NewRoleRepository(repository data.GenericStorage, statusRepository data.GenericStorage, rolePermissionRepository data.GenericStorage) RoleRepository {
+	return RoleRepository{repository: repository, statusRepository: statusRepository, rolePermissionRepository: rolePermissionRepository}
+}
+
+func (s RoleRepository) FindAll(ctx *gin.Context, params models.FindAllRoleParams) ([]*models.Role, *types.Error) {
+	data := []*models.Role{}
+	bulks := []*models.RoleBulk{}
+
+	var err error
+
+	where := `TRUE`
+
+	if params.FindAllParams.DataFinder != "" {
+		where += fmt.Sprintf(` AND %s`, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where += fmt.Sprintf(` AND roles.%s`, params.FindAllParams.StatusID)
+	}
+
+	if params.Name != "" {
+		where += ` AND roles.name LIKE CONCAT('%', :name, '%')`
+	}
+
+	if params.FindAllParams.SortBy != "" {
+		where += fmt.Sprintf(` ORDER BY %s`, params.FindAllParams.SortBy)
+	}
+
+	if params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0 {
+		where += ` LIMIT :limit OFFSET :offset`
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    roles.id, roles.name, roles.description, roles.status_id, status.name status_name
+  FROM roles
+  JOIN status ON roles.status_id = status.id
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"limit":     params.FindAllParams.Size,
+		"offset":    ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"status_id": params.FindAllParams.StatusID,
+		"name":      params.Name,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".RoleStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		obj := &models.Role{
+			ID:          v.ID,
+			Name:        v.Name,
+			Description: v.Description,
+			StatusID:    v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+
+		data = append(data, obj)
+	}
+
+	return data, nil
+}
+
+func (s RoleRepository) Find(ctx *gin.Context, id string) (*models.Role, *types.Error) {
+	result := models.Role{}
+	bulks := []*models.RoleBulk{}
+	var err error
+
+	query := `
+  SELECT
+    roles.id, roles.name, roles.description, roles.status_id, status.name status_name
+  FROM roles
+  JOIN status ON roles.status_id = status.id
+  WHERE roles.id = :id`
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".RoleStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(bulks) > 0 {
+		v := bulks[0]
+		result = models.Role{
+			ID:          v.ID,
+			Name:        v.Name,
+			Description: v.Description,
+			StatusID:    v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+	} else {
+		return nil, &types.Error{
+			Path:       ".RoleStorage->Find()",
+			Message:    "Data Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &result, nil
+}
+
+func (s RoleRepository) Count(ctx *gin.Context, params models.FindAllRoleParams) (int, *types.Error) {
+	bulks := []*models.RoleBulk{}
+
+	var err error
+
+	where := `TRUE`
+
+	if params.FindAllParams.DataFinder != "" {
+		where += fmt.Sprintf(` AND %s`, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where += fmt.Sprintf(` AND roles.%s`, params.FindAllParams.StatusID)
+	}
+
+	if params.Name != "" {
+		where += ` AND roles.name LIKE CONCAT('%', :name, '%')`
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    roles.id, roles.name, roles.status_id, status.name status_name
+  FROM roles
+  JOIN status ON roles.status_id = status.id
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"status_id": params.FindAllParams.StatusID,
+		"name":      params.Name,
+	})
+	if err != nil {
+		return 0, &types.Error{
+			Path:       ".RoleStorage->Count()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return len(bulks), nil
+}
+
+func (s RoleRepository) Create(ctx *gin.Context, obj *models.Role) (*models.Role, *types.Error) {
+	data := models.Role{}
+	_, err := s.repository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".RoleStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".RoleStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+func (s RoleRepository) Update(ctx *gin.Context, obj *models.Role) (*models.Role, *types.Error) {
+	data := models.Role{}
+	err := s.repository.Update(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".RoleStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".RoleStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+func (s RoleRepository) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	status := []*models.Status{}
+
+	err := s.statusRepository.Where(ctx, &status, "1=1", map[string]interface{}{})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".RoleStorage->FindStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return status, nil
+}
+
+func (s RoleRepository) UpdateStatus(ctx *gin.Context, id string, statusID string) (*models.Role, *types.Error) {
+	data := models.Role{}
+	err := s.repository.UpdateStatus(ctx, id, statusID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".RoleStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, id)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".RoleStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &data, nil
+}
+
+// PERMISSIONS
+
+func (s RoleRepository) FindPermissions(ctx *gin.Context) ([]*models.Permission, *types.Error) {
+	data := []*models.Permission{}
+
+	query := `SELECT permissions.id, permissions.description FROM permissions ORDER BY permissions.id`
+
+	err := s.rolePermissionRepository.SelectWithQuery(ctx, &data, query, map[string]interface{}{})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".RoleStorage->FindPermissions()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return data, nil
+}
+
+func (s RoleRepository) FindRolePermissions(ctx *gin.Context, roleID string) ([]*models.RolePermission, *types.Error) {
+	data := []*models.RolePermission{}
+	bulks := []*models.RolePermissionBulk{}
+
+	query := `
+  SELECT
+    role_permissions.id, role_permissions.role_id, role_permissions.permission_id, role_permissions.status_id,
+    permissions.description permission_description
+  FROM role_permissions
+  JOIN permissions ON permissions.id = role_permissions.permission_id
+  WHERE role_permissions.role_id = :role_id
+  ORDER BY role_permissions.permission_id`
+
+	err := s.rolePermissionRepository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"role_id": roleID,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".RoleStorage->FindRolePermissions()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		obj := &models.RolePermission{
+			ID:           v.ID,
+			RoleID:       v.RoleID,
+			PermissionID: v.PermissionID,
+			StatusID:     v.StatusID,
+			Permission: &models.Permission{
+				ID:          v.PermissionID,
+				Description: v.PermissionDescription,
+			},
+		}
+
+		data = append(data, obj)
+	}
+
+	return data, nil
+}
+
+func (s RoleRepository) CreateRolePermission(ctx *gin.Context, obj *models.RolePermission) (*models.RolePermission, *types.Error) {
+	data := models.RolePermission{}
+	_, err := s.rolePermissionRepository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".RoleStorage->CreateRolePermission()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.rolePermissionRepository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".RoleStorage->CreateRolePermission()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+func (s RoleRepository) DeleteRolePermissions(ctx *gin.Context, roleID string) *types.Error {
+	query := `DELETE FROM role_permissions WHERE role_id = :role_id`
+
+	err := s.rolePermissionRepository.ExecQuery(ctx, query, map[string]interface{}{
+		"role_id": roleID,
+	})
+	if err != nil {
+		return &types.Error{
+			Path:       ".RoleStorage->DeleteRolePermissions()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
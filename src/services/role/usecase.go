@@ -0,0 +1,26 @@
+package role
+
+import (
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Usecase is the contract between Repository and usecase
+type Usecase interface {
+	FindAll(*gin.Context, models.FindAllRoleParams) ([]*models.Role, *types.Error)
+	Find(*gin.Context, string) (*models.Role, *types.Error)
+	Count(*gin.Context, models.FindAllRoleParams) (int, *types.Error)
+	Create(*gin.Context, models.Role) (*models.Role, *types.Error)
+	Update(*gin.Context, string, models.Role) (*models.Role, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, string, string) (*models.Role, *types.Error)
+
+	// Permissions
+	FindPermissions(*gin.Context) ([]*models.Permission, *types.Error)
+
+	// FindActive returns the role if it can be given to a user
+	FindActive(ctx *gin.Context, id string) (*models.Role, *types.Error)
+}
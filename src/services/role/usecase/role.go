@@ -0,0 +1,358 @@
+package usecase
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/src/services/role"
+
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"github.com/jmoiron/sqlx"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+type RoleUsecase struct {
+	roleRepo       role.Repository
+	contextTimeout time.Duration
+	db             *sqlx.DB
+}
+
+func NewRoleUsecase(db *sqlx.DB, roleRepo role.Repository) role.Usecase {
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	return &RoleUsecase{
+		roleRepo:       roleRepo,
+		contextTimeout: timeoutContext,
+		db:             db,
+	}
+}
+
+func (u *RoleUsecase) FindAll(ctx *gin.Context, params models.FindAllRoleParams) ([]*models.Role, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(params)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".RoleUsecase->FindAll()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.roleRepo.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".RoleUsecase->FindAll()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *RoleUsecase) Find(ctx *gin.Context, id string) (*models.Role, *types.Error) {
+	result, err := u.roleRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".RoleUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	result.Permissions, err = u.roleRepo.FindRolePermissions(ctx, id)
+	if err != nil {
+		err.Path = ".RoleUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *RoleUsecase) Count(ctx *gin.Context, params models.FindAllRoleParams) (int, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(params)
+	if errValidation != nil {
+		return 0, &types.Error{
+			Path:       ".RoleUsecase->Count()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.roleRepo.Count(ctx, params)
+	if err != nil {
+		err.Path = ".RoleUsecase->Count()" + err.Path
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (u *RoleUsecase) Create(ctx *gin.Context, obj models.Role) (*models.Role, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".RoleUsecase->Create()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	err := u.validatePermissions(ctx, obj.Permissions)
+	if err != nil {
+		err.Path = ".RoleUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	data := models.Role{
+		ID:          uuid.New().String(),
+		Name:        obj.Name,
+		Description: obj.Description,
+		StatusID:    models.DEFAULT_STATUS_ID,
+	}
+
+	result, err := u.roleRepo.Create(ctx, &data)
+	if err != nil {
+		err.Path = ".RoleUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	result.Permissions, err = u.createPermissions(ctx, result.ID, obj.Permissions)
+	if err != nil {
+		err.Path = ".RoleUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Update replaces the name and the permissions of the role, users holding it get the new permissions on their next request
+func (u *RoleUsecase) Update(ctx *gin.Context, id string, obj models.Role) (*models.Role, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".RoleUsecase->Update()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	// stripping the admin role would lock everybody out of user and role management
+	if id == models.ROLE_ADMIN {
+		return nil, &types.Error{
+			Path:       ".RoleUsecase->Update()",
+			Message:    "Admin Role Cannot Be Changed",
+			Error:      fmt.Errorf("Admin Role Cannot Be Changed"),
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	err := u.validatePermissions(ctx, obj.Permissions)
+	if err != nil {
+		err.Path = ".RoleUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	data, err := u.roleRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".RoleUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	data.Name = obj.Name
+	data.Description = obj.Description
+
+	result, err := u.roleRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".RoleUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	err = u.roleRepo.DeleteRolePermissions(ctx, result.ID)
+	if err != nil {
+		err.Path = ".RoleUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	result.Permissions, err = u.createPermissions(ctx, result.ID, obj.Permissions)
+	if err != nil {
+		err.Path = ".RoleUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *RoleUsecase) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	result, err := u.roleRepo.FindStatus(ctx)
+	if err != nil {
+		err.Path = ".RoleUsecase->FindStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *RoleUsecase) UpdateStatus(ctx *gin.Context, id string, newStatusID string) (*models.Role, *types.Error) {
+	if id == models.ROLE_ADMIN && newStatusID != models.STATUS_ACTIVE {
+		return nil, &types.Error{
+			Path:       ".RoleUsecase->UpdateStatus()",
+			Message:    "Admin Role Cannot Be Deactivated",
+			Error:      fmt.Errorf("Admin Role Cannot Be Deactivated"),
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.roleRepo.UpdateStatus(ctx, id, newStatusID)
+	if err != nil {
+		err.Path = ".RoleUsecase->UpdateStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, err
+}
+
+// PERMISSIONS
+
+func (u *RoleUsecase) FindPermissions(ctx *gin.Context) ([]*models.Permission, *types.Error) {
+	result, err := u.roleRepo.FindPermissions(ctx)
+	if err != nil {
+		err.Path = ".RoleUsecase->FindPermissions()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *RoleUsecase) FindActive(ctx *gin.Context, id string) (*models.Role, *types.Error) {
+	result, err := u.roleRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".RoleUsecase->FindActive()" + err.Path
+		return nil, err
+	}
+
+	if result.StatusID != models.STATUS_ACTIVE {
+		return nil, &types.Error{
+			Path:       ".RoleUsecase->FindActive()",
+			Message:    "Role Is Not Active",
+			Error:      fmt.Errorf("Role Is Not Active"),
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	return result, nil
+}
+
+// validatePermissions makes sure every permission exists, and that none is listed twice
+func (u *RoleUsecase) validatePermissions(ctx *gin.Context, permissions []*models.RolePermission) *types.Error {
+	available, err := u.roleRepo.FindPermissions(ctx)
+	if err != nil {
+		err.Path = ".RoleUsecase->validatePermissions()" + err.Path
+		return err
+	}
+
+	known := map[string]bool{}
+	for _, v := range available {
+		known[v.ID] = true
+	}
+
+	seen := map[string]bool{}
+	for _, v := range permissions {
+		if !known[v.PermissionID] {
+			return &types.Error{
+				Path:       ".RoleUsecase->validatePermissions()",
+				Message:    "Unknown Permission",
+				Error:      fmt.Errorf("unknown permission %s", v.PermissionID),
+				StatusCode: http.StatusUnprocessableEntity,
+				Type:       "validation-error",
+			}
+		}
+
+		if seen[v.PermissionID] {
+			return &types.Error{
+				Path:       ".RoleUsecase->validatePermissions()",
+				Message:    "Duplicate Permission",
+				Error:      fmt.Errorf("duplicate permission %s", v.PermissionID),
+				StatusCode: http.StatusUnprocessableEntity,
+				Type:       "validation-error",
+			}
+		}
+		seen[v.PermissionID] = true
+	}
+
+	return nil
+}
+
+func (u *RoleUsecase) createPermissions(ctx *gin.Context, roleID string, permissions []*models.RolePermission) ([]*models.RolePermission, *types.Error) {
+	for _, v := range permissions {
+		data := models.RolePermission{
+			ID:           uuid.New().String(),
+			RoleID:       roleID,
+			PermissionID: v.PermissionID,
+			StatusID:     models.DEFAULT_STATUS_ID,
+		}
+
+		_, err := u.roleRepo.CreateRolePermission(ctx, &data)
+		if err != nil {
+			err.Path = ".RoleUsecase->createPermissions()" + err.Path
+			return nil, err
+		}
+	}
+
+	result, err := u.roleRepo.FindRolePermissions(ctx, roleID)
+	if err != nil {
+		err.Path = ".RoleUsecase->createPermissions()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
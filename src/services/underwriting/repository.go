@@ -0,0 +1,35 @@
+package underwriting
+
+import (
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Repository is the contract between Repository and usecase
+type Repository interface {
+	FindAll(*gin.Context, models.FindAllUnderwritingRuleSetParams) ([]*models.UnderwritingRuleSet, *types.Error)
+	Find(*gin.Context, string) (*models.UnderwritingRuleSet, *types.Error)
+	Count(*gin.Context, models.FindAllUnderwritingRuleSetParams) (int, *types.Error)
+	Create(*gin.Context, *models.UnderwritingRuleSet) (*models.UnderwritingRuleSet, *types.Error)
+	Update(*gin.Context, *models.UnderwritingRuleSet) (*models.UnderwritingRuleSet, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, string, string) (*models.UnderwritingRuleSet, *types.Error)
+
+	// Proposal
+	FindAllProposals(*gin.Context, models.FindAllConsumerCreditLimitProposalParams) ([]*models.ConsumerCreditLimitProposal, *types.Error)
+	FindProposal(*gin.Context, string) (*models.ConsumerCreditLimitProposal, *types.Error)
+	CountProposals(*gin.Context, models.FindAllConsumerCreditLimitProposalParams) (int, *types.Error)
+	CreateProposal(*gin.Context, *models.ConsumerCreditLimitProposal) (*models.ConsumerCreditLimitProposal, *types.Error)
+	UpdateProposal(*gin.Context, *models.ConsumerCreditLimitProposal) (*models.ConsumerCreditLimitProposal, *types.Error)
+	LockProposal(*gin.Context, string) *types.Error
+
+	FindProposalDetails(*gin.Context, string) ([]*models.ConsumerCreditLimitProposalDetail, *types.Error)
+	CreateProposalDetail(*gin.Context, *models.ConsumerCreditLimitProposalDetail) (*models.ConsumerCreditLimitProposalDetail, *types.Error)
+	UpdateProposalDetail(*gin.Context, *models.ConsumerCreditLimitProposalDetail) (*models.ConsumerCreditLimitProposalDetail, *types.Error)
+
+	// Obligation
+	FindConsumerObligation(*gin.Context, string) (float64, *types.Error)
+}
@@ -0,0 +1,699 @@
+package repository
+
+import (
+	"fmt"
+	"net/http"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UnderwritingRepository struct {
+	repository               data.GenericStorage
+	statusRepository         data.GenericStorage
+	proposalRepository       data.GenericStorage
+	proposalDetailRepository data.GenericStorage
+}
+
+func NewUnderwritingRepository(repository data.GenericStorage, statusRepository data.GenericStorage, proposalRepository data.GenericStorage, proposalDetailRepository data.GenericStorage) UnderwritingRepository {
+	return UnderwritingRepository{repository: repository, statusRepository: statusRepository, proposalRepository: proposalRepository, proposalDetailRepository: proposalDetailRepository}
+}
+
+func (s UnderwritingRepository) FindAll(ctx *gin.Context, params models.FindAllUnderwritingRuleSetParams) ([]*models.UnderwritingRuleSet, *types.Error) {
+	data := []*models.UnderwritingRuleSet{}
+	bulks := []*models.UnderwritingRuleSetBulk{}
+
+	var err error
+
+	where := `TRUE`
+
+	if params.FindAllParams.DataFinder != "" {
+		where += fmt.Sprintf(` AND %s`, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where += fmt.Sprintf(` AND underwriting_rule_sets.%s`, params.FindAllParams.StatusID)
+	}
+
+	if params.Age != 0 {
+		where += ` AND underwriting_rule_sets.min_age <= :age AND underwriting_rule_sets.max_age >= :age`
+	}
+
+	if params.Salary != 0 {
+		where += ` AND underwriting_rule_sets.min_salary <= :salary`
+	}
+
+	if params.FindAllParams.SortBy != "" {
+		where += fmt.Sprintf(` ORDER BY %s`, params.FindAllParams.SortBy)
+	}
+
+	if params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0 {
+		where += ` LIMIT :limit OFFSET :offset`
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    underwriting_rule_sets.id, underwriting_rule_sets.name, underwriting_rule_sets.min_age, underwriting_rule_sets.max_age,
+    underwriting_rule_sets.min_salary, underwriting_rule_sets.max_debt_service_ratio, underwriting_rule_sets.max_limit_amount,
+    underwriting_rule_sets.status_id, status.name status_name
+  FROM underwriting_rule_sets
+  JOIN status ON underwriting_rule_sets.status_id = status.id
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"limit":     params.FindAllParams.Size,
+		"offset":    ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"status_id": params.FindAllParams.StatusID,
+		"age":       params.Age,
+		"salary":    params.Salary,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->FindAll()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		obj := &models.UnderwritingRuleSet{
+			ID:                  v.ID,
+			Name:                v.Name,
+			MinAge:              v.MinAge,
+			MaxAge:              v.MaxAge,
+			MinSalary:           v.MinSalary,
+			MaxDebtServiceRatio: v.MaxDebtServiceRatio,
+			MaxLimitAmount:      v.MaxLimitAmount,
+			StatusID:            v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+
+		data = append(data, obj)
+	}
+
+	return data, nil
+}
+
+func (s UnderwritingRepository) Find(ctx *gin.Context, id string) (*models.UnderwritingRuleSet, *types.Error) {
+	result := models.UnderwritingRuleSet{}
+	bulks := []*models.UnderwritingRuleSetBulk{}
+	var err error
+
+	query := `
+  SELECT
+    underwriting_rule_sets.id, underwriting_rule_sets.name, underwriting_rule_sets.min_age, underwriting_rule_sets.max_age,
+    underwriting_rule_sets.min_salary, underwriting_rule_sets.max_debt_service_ratio, underwriting_rule_sets.max_limit_amount,
+    underwriting_rule_sets.status_id, status.name status_name
+  FROM underwriting_rule_sets
+  JOIN status ON underwriting_rule_sets.status_id = status.id
+  WHERE underwriting_rule_sets.id = :id`
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->Find()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(bulks) > 0 {
+		v := bulks[0]
+		result = models.UnderwritingRuleSet{
+			ID:                  v.ID,
+			Name:                v.Name,
+			MinAge:              v.MinAge,
+			MaxAge:              v.MaxAge,
+			MinSalary:           v.MinSalary,
+			MaxDebtServiceRatio: v.MaxDebtServiceRatio,
+			MaxLimitAmount:      v.MaxLimitAmount,
+			StatusID:            v.StatusID,
+			Status: models.Status{
+				ID:   v.StatusID,
+				Name: v.StatusName,
+			},
+		}
+	} else {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->Find()",
+			Message:    "Data Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &result, nil
+}
+
+func (s UnderwritingRepository) Count(ctx *gin.Context, params models.FindAllUnderwritingRuleSetParams) (int, *types.Error) {
+	bulks := []*models.UnderwritingRuleSetBulk{}
+
+	var err error
+
+	where := `TRUE`
+
+	if params.FindAllParams.DataFinder != "" {
+		where += fmt.Sprintf(` AND %s`, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where += fmt.Sprintf(` AND underwriting_rule_sets.%s`, params.FindAllParams.StatusID)
+	}
+
+	if params.Age != 0 {
+		where += ` AND underwriting_rule_sets.min_age <= :age AND underwriting_rule_sets.max_age >= :age`
+	}
+
+	if params.Salary != 0 {
+		where += ` AND underwriting_rule_sets.min_salary <= :salary`
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    underwriting_rule_sets.id, underwriting_rule_sets.name, underwriting_rule_sets.min_age, underwriting_rule_sets.max_age,
+    underwriting_rule_sets.min_salary, underwriting_rule_sets.max_debt_service_ratio, underwriting_rule_sets.max_limit_amount,
+    underwriting_rule_sets.status_id, status.name status_name
+  FROM underwriting_rule_sets
+  JOIN status ON underwriting_rule_sets.status_id = status.id
+  WHERE %s
+  `, where)
+
+	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"status_id": params.FindAllParams.StatusID,
+		"age":       params.Age,
+		"salary":    params.Salary,
+	})
+	if err != nil {
+		return 0, &types.Error{
+			Path:       ".UnderwritingStorage->Count()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return len(bulks), nil
+}
+
+func (s UnderwritingRepository) Create(ctx *gin.Context, obj *models.UnderwritingRuleSet) (*models.UnderwritingRuleSet, *types.Error) {
+	data := models.UnderwritingRuleSet{}
+	_, err := s.repository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->Create()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+func (s UnderwritingRepository) Update(ctx *gin.Context, obj *models.UnderwritingRuleSet) (*models.UnderwritingRuleSet, *types.Error) {
+	data := models.UnderwritingRuleSet{}
+	err := s.repository.Update(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->Update()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+func (s UnderwritingRepository) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	status := []*models.Status{}
+
+	err := s.statusRepository.Where(ctx, &status, "1=1", map[string]interface{}{})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->FindStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return status, nil
+}
+
+func (s UnderwritingRepository) UpdateStatus(ctx *gin.Context, id string, statusID string) (*models.UnderwritingRuleSet, *types.Error) {
+	data := models.UnderwritingRuleSet{}
+	err := s.repository.UpdateStatus(ctx, id, statusID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.repository.FindByID(ctx, &data, id)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->UpdateStatus()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return &data, nil
+}
+
+// PROPOSAL
+
+func (s UnderwritingRepository) FindAllProposals(ctx *gin.Context, params models.FindAllConsumerCreditLimitProposalParams) ([]*models.ConsumerCreditLimitProposal, *types.Error) {
+	data := []*models.ConsumerCreditLimitProposal{}
+	bulks := []*models.ConsumerCreditLimitProposalBulk{}
+
+	var err error
+
+	where := `TRUE`
+
+	if params.FindAllParams.DataFinder != "" {
+		where += fmt.Sprintf(` AND %s`, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where += fmt.Sprintf(` AND consumer_credit_limit_proposals.%s`, params.FindAllParams.StatusID)
+	}
+
+	if params.ConsumerID != "" {
+		where += ` AND consumer_credit_limit_proposals.consumer_id = :consumer_id`
+	}
+
+	if params.Decision != "" {
+		where += ` AND consumer_credit_limit_proposals.decision = :decision`
+	}
+
+	if params.FindAllParams.SortBy != "" {
+		where += fmt.Sprintf(` ORDER BY %s`, params.FindAllParams.SortBy)
+	}
+
+	if params.FindAllParams.Page > 0 && params.FindAllParams.Size > 0 {
+		where += ` LIMIT :limit OFFSET :offset`
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    consumer_credit_limit_proposals.id, consumer_credit_limit_proposals.consumer_id, consumer_credit_limit_proposals.underwriting_rule_set_id,
+    consumer_credit_limit_proposals.salary, consumer_credit_limit_proposals.age, consumer_credit_limit_proposals.max_debt_service_ratio,
+    consumer_credit_limit_proposals.existing_obligation_amount, consumer_credit_limit_proposals.external_obligation_amount,
+    consumer_credit_limit_proposals.affordable_installment_amount, consumer_credit_limit_proposals.decision,
+    consumer_credit_limit_proposals.reason, consumer_credit_limit_proposals.consumer_credit_limit_id, consumer_credit_limit_proposals.created_at,
+    consumer_credit_limit_proposals.status_id, status.name status_name, consumers.full_name consumer_name,
+    underwriting_rule_sets.name underwriting_rule_set_name
+  FROM consumer_credit_limit_proposals
+  JOIN status ON consumer_credit_limit_proposals.status_id = status.id
+  JOIN consumers ON consumers.id = consumer_credit_limit_proposals.consumer_id
+  JOIN underwriting_rule_sets ON underwriting_rule_sets.id = consumer_credit_limit_proposals.underwriting_rule_set_id
+  WHERE %s
+  `, where)
+
+	err = s.proposalRepository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"limit":       params.FindAllParams.Size,
+		"offset":      ((params.FindAllParams.Page - 1) * params.FindAllParams.Size),
+		"status_id":   params.FindAllParams.StatusID,
+		"consumer_id": params.ConsumerID,
+		"decision":    params.Decision,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->FindAllProposals()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		data = append(data, proposalFromBulk(v))
+	}
+
+	return data, nil
+}
+
+func (s UnderwritingRepository) FindProposal(ctx *gin.Context, id string) (*models.ConsumerCreditLimitProposal, *types.Error) {
+	bulks := []*models.ConsumerCreditLimitProposalBulk{}
+	var err error
+
+	query := `
+  SELECT
+    consumer_credit_limit_proposals.id, consumer_credit_limit_proposals.consumer_id, consumer_credit_limit_proposals.underwriting_rule_set_id,
+    consumer_credit_limit_proposals.salary, consumer_credit_limit_proposals.age, consumer_credit_limit_proposals.max_debt_service_ratio,
+    consumer_credit_limit_proposals.existing_obligation_amount, consumer_credit_limit_proposals.external_obligation_amount,
+    consumer_credit_limit_proposals.affordable_installment_amount, consumer_credit_limit_proposals.decision,
+    consumer_credit_limit_proposals.reason, consumer_credit_limit_proposals.consumer_credit_limit_id, consumer_credit_limit_proposals.created_at,
+    consumer_credit_limit_proposals.status_id, status.name status_name, consumers.full_name consumer_name,
+    underwriting_rule_sets.name underwriting_rule_set_name
+  FROM consumer_credit_limit_proposals
+  JOIN status ON consumer_credit_limit_proposals.status_id = status.id
+  JOIN consumers ON consumers.id = consumer_credit_limit_proposals.consumer_id
+  JOIN underwriting_rule_sets ON underwriting_rule_sets.id = consumer_credit_limit_proposals.underwriting_rule_set_id
+  WHERE consumer_credit_limit_proposals.id = :id`
+
+	err = s.proposalRepository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->FindProposal()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(bulks) == 0 {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->FindProposal()",
+			Message:    "Data Not Found",
+			Error:      data.ErrNotFound,
+			StatusCode: http.StatusNotFound,
+			Type:       "mysql-error",
+		}
+	}
+
+	return proposalFromBulk(bulks[0]), nil
+}
+
+func (s UnderwritingRepository) CountProposals(ctx *gin.Context, params models.FindAllConsumerCreditLimitProposalParams) (int, *types.Error) {
+	bulks := []*models.ConsumerCreditLimitProposalBulk{}
+
+	var err error
+
+	where := `TRUE`
+
+	if params.FindAllParams.DataFinder != "" {
+		where += fmt.Sprintf(` AND %s`, params.FindAllParams.DataFinder)
+	}
+
+	if params.FindAllParams.StatusID != "" {
+		where += fmt.Sprintf(` AND consumer_credit_limit_proposals.%s`, params.FindAllParams.StatusID)
+	}
+
+	if params.ConsumerID != "" {
+		where += ` AND consumer_credit_limit_proposals.consumer_id = :consumer_id`
+	}
+
+	if params.Decision != "" {
+		where += ` AND consumer_credit_limit_proposals.decision = :decision`
+	}
+
+	query := fmt.Sprintf(`
+  SELECT
+    consumer_credit_limit_proposals.id, consumer_credit_limit_proposals.consumer_id, consumer_credit_limit_proposals.decision,
+    consumer_credit_limit_proposals.status_id
+  FROM consumer_credit_limit_proposals
+  WHERE %s
+  `, where)
+
+	err = s.proposalRepository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"status_id":   params.FindAllParams.StatusID,
+		"consumer_id": params.ConsumerID,
+		"decision":    params.Decision,
+	})
+	if err != nil {
+		return 0, &types.Error{
+			Path:       ".UnderwritingStorage->CountProposals()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return len(bulks), nil
+}
+
+func (s UnderwritingRepository) CreateProposal(ctx *gin.Context, obj *models.ConsumerCreditLimitProposal) (*models.ConsumerCreditLimitProposal, *types.Error) {
+	_, err := s.proposalRepository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->CreateProposal()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	result, errFind := s.FindProposal(ctx, obj.ID)
+	if errFind != nil {
+		errFind.Path = ".UnderwritingStorage->CreateProposal()" + errFind.Path
+		return nil, errFind
+	}
+
+	return result, nil
+}
+
+func (s UnderwritingRepository) UpdateProposal(ctx *gin.Context, obj *models.ConsumerCreditLimitProposal) (*models.ConsumerCreditLimitProposal, *types.Error) {
+	err := s.proposalRepository.Update(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->UpdateProposal()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	result, errFind := s.FindProposal(ctx, obj.ID)
+	if errFind != nil {
+		errFind.Path = ".UnderwritingStorage->UpdateProposal()" + errFind.Path
+		return nil, errFind
+	}
+
+	return result, nil
+}
+
+// LockProposal keeps a proposal from being decided twice at the same time
+func (s UnderwritingRepository) LockProposal(ctx *gin.Context, id string) *types.Error {
+	rows := []*models.IDNameTemplate{}
+
+	query := `SELECT consumer_credit_limit_proposals.id, consumer_credit_limit_proposals.decision name FROM consumer_credit_limit_proposals WHERE consumer_credit_limit_proposals.id = :id FOR UPDATE`
+
+	err := s.proposalRepository.SelectWithQuery(ctx, &rows, query, map[string]interface{}{"id": id})
+	if err != nil {
+		return &types.Error{
+			Path:       ".UnderwritingStorage->LockProposal()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	return nil
+}
+
+func (s UnderwritingRepository) FindProposalDetails(ctx *gin.Context, consumerCreditLimitProposalID string) ([]*models.ConsumerCreditLimitProposalDetail, *types.Error) {
+	data := []*models.ConsumerCreditLimitProposalDetail{}
+	bulks := []*models.ConsumerCreditLimitProposalDetailBulk{}
+
+	query := `
+  SELECT
+    consumer_credit_limit_proposal_details.id, consumer_credit_limit_proposal_details.consumer_credit_limit_proposal_id,
+    consumer_credit_limit_proposal_details.loan_product_id, consumer_credit_limit_proposal_details.proposed_limit_amount,
+    consumer_credit_limit_proposal_details.approved_limit_amount,
+    consumer_credit_limit_proposal_details.status_id, loan_products.name loan_product_name, loan_products.tenor
+  FROM consumer_credit_limit_proposal_details
+  JOIN loan_products ON loan_products.id = consumer_credit_limit_proposal_details.loan_product_id
+  WHERE consumer_credit_limit_proposal_details.consumer_credit_limit_proposal_id = :consumer_credit_limit_proposal_id
+  ORDER BY loan_products.tenor, loan_products.name`
+
+	err := s.proposalDetailRepository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{
+		"consumer_credit_limit_proposal_id": consumerCreditLimitProposalID,
+	})
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->FindProposalDetails()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	for _, v := range bulks {
+		obj := &models.ConsumerCreditLimitProposalDetail{
+			ID:                            v.ID,
+			ConsumerCreditLimitProposalID: v.ConsumerCreditLimitProposalID,
+			LoanProductID:                 v.LoanProductID,
+			LoanProduct: &models.IDNameTemplate{
+				ID:   v.LoanProductID,
+				Name: v.LoanProductName,
+			},
+			Tenor:               v.Tenor,
+			ProposedLimitAmount: v.ProposedLimitAmount,
+			ApprovedLimitAmount: v.ApprovedLimitAmount,
+			StatusID:            v.StatusID,
+		}
+
+		data = append(data, obj)
+	}
+
+	return data, nil
+}
+
+func (s UnderwritingRepository) CreateProposalDetail(ctx *gin.Context, obj *models.ConsumerCreditLimitProposalDetail) (*models.ConsumerCreditLimitProposalDetail, *types.Error) {
+	data := models.ConsumerCreditLimitProposalDetail{}
+	_, err := s.proposalDetailRepository.Insert(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->CreateProposalDetail()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.proposalDetailRepository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->CreateProposalDetail()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+func (s UnderwritingRepository) UpdateProposalDetail(ctx *gin.Context, obj *models.ConsumerCreditLimitProposalDetail) (*models.ConsumerCreditLimitProposalDetail, *types.Error) {
+	data := models.ConsumerCreditLimitProposalDetail{}
+	err := s.proposalDetailRepository.Update(ctx, obj)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->UpdateProposalDetail()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	err = s.proposalDetailRepository.FindByID(ctx, &data, obj.ID)
+	if err != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingStorage->UpdateProposalDetail()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+	return &data, nil
+}
+
+// OBLIGATION
+
+// FindConsumerObligation sums the monthly installments of every transaction that still holds the consumer's limit
+func (s UnderwritingRepository) FindConsumerObligation(ctx *gin.Context, consumerID string) (float64, *types.Error) {
+	data := []*models.ConsumerObligation{}
+
+	query := `
+  SELECT
+    ct.consumer_id, IFNULL(SUM(ct.installment_amount), 0) installment_amount
+  FROM consumer_transactions ct
+  WHERE ct.consumer_id = :consumer_id
+    AND ct.status_id IN (SELECT cts.id FROM consumer_transaction_statuses cts WHERE cts.consumes_limit = 1)
+  GROUP BY ct.consumer_id`
+
+	err := s.proposalRepository.SelectWithQuery(ctx, &data, query, map[string]interface{}{
+		"consumer_id": consumerID,
+	})
+	if err != nil {
+		return 0, &types.Error{
+			Path:       ".UnderwritingStorage->FindConsumerObligation()",
+			Message:    err.Error(),
+			Error:      err,
+			StatusCode: http.StatusInternalServerError,
+			Type:       "mysql-error",
+		}
+	}
+
+	if len(data) > 0 {
+		return data[0].InstallmentAmount, nil
+	}
+
+	return 0, nil
+}
+
+func proposalFromBulk(v *models.ConsumerCreditLimitProposalBulk) *models.ConsumerCreditLimitProposal {
+	return &models.ConsumerCreditLimitProposal{
+		ID:         v.ID,
+		ConsumerID: v.ConsumerID,
+		Consumer: &models.IDNameTemplate{
+			ID:   v.ConsumerID,
+			Name: v.ConsumerName,
+		},
+		UnderwritingRuleSetID: v.UnderwritingRuleSetID,
+		UnderwritingRuleSet: &models.IDNameTemplate{
+			ID:   v.UnderwritingRuleSetID,
+			Name: v.UnderwritingRuleSetName,
+		},
+		Salary:                      v.Salary,
+		Age:                         v.Age,
+		MaxDebtServiceRatio:         v.MaxDebtServiceRatio,
+		ExistingObligationAmount:    v.ExistingObligationAmount,
+		ExternalObligationAmount:    v.ExternalObligationAmount,
+		AffordableInstallmentAmount: v.AffordableInstallmentAmount,
+		Decision:                    v.Decision,
+		Reason:                      v.Reason,
+		ConsumerCreditLimitID:       v.ConsumerCreditLimitID,
+		CreatedAt:                   v.CreatedAt,
+		StatusID:                    v.StatusID,
+		Status: models.Status{
+			ID:   v.StatusID,
+			Name: v.StatusName,
+		},
+	}
+}
@@ -0,0 +1,28 @@
+package underwriting
+
+import (
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Usecase is the contract between Repository and usecase
+type Usecase interface {
+	FindAll(*gin.Context, models.FindAllUnderwritingRuleSetParams) ([]*models.UnderwritingRuleSet, *types.Error)
+	Find(*gin.Context, string) (*models.UnderwritingRuleSet, *types.Error)
+	Count(*gin.Context, models.FindAllUnderwritingRuleSetParams) (int, *types.Error)
+	Create(*gin.Context, models.UnderwritingRuleSet) (*models.UnderwritingRuleSet, *types.Error)
+	Update(*gin.Context, string, models.UnderwritingRuleSet) (*models.UnderwritingRuleSet, *types.Error)
+
+	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
+	UpdateStatus(*gin.Context, string, string) (*models.UnderwritingRuleSet, *types.Error)
+
+	// Proposal
+	FindAllProposals(*gin.Context, models.FindAllConsumerCreditLimitProposalParams) ([]*models.ConsumerCreditLimitProposal, *types.Error)
+	FindProposal(*gin.Context, string) (*models.ConsumerCreditLimitProposal, *types.Error)
+	CountProposals(*gin.Context, models.FindAllConsumerCreditLimitProposalParams) (int, *types.Error)
+	Propose(*gin.Context, string, float64) (*models.ConsumerCreditLimitProposal, *types.Error)
+	Accept(*gin.Context, string, string) (*models.ConsumerCreditLimitProposal, *types.Error)
+	Override(*gin.Context, string, []*models.ConsumerCreditLimitProposalDetail, string) (*models.ConsumerCreditLimitProposal, *types.Error)
+}
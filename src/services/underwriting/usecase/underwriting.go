@@ -0,0 +1,632 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/src/services/consumer"
+	"case-study-kredit-plus/src/services/consumercreditlimit"
+	"case-study-kredit-plus/src/services/loanproduct"
+	"case-study-kredit-plus/src/services/underwriting"
+
+	"case-study-kredit-plus/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/spf13/viper"
+
+	"github.com/jmoiron/sqlx"
+	validator "gopkg.in/go-playground/validator.v9"
+)
+
+type UnderwritingUsecase struct {
+	underwritingRepo           underwriting.Repository
+	consumerUsecase            consumer.Usecase
+	consumercreditlimitUsecase consumercreditlimit.Usecase
+	loanproductUsecase         loanproduct.Usecase
+	contextTimeout             time.Duration
+	db                         *sqlx.DB
+}
+
+func NewUnderwritingUsecase(db *sqlx.DB, underwritingRepo underwriting.Repository, consumerUsecase consumer.Usecase, consumercreditlimitUsecase consumercreditlimit.Usecase, loanproductUsecase loanproduct.Usecase) underwriting.Usecase {
+	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
+
+	return &UnderwritingUsecase{
+		underwritingRepo:           underwritingRepo,
+		consumerUsecase:            consumerUsecase,
+		consumercreditlimitUsecase: consumercreditlimitUsecase,
+		loanproductUsecase:         loanproductUsecase,
+		contextTimeout:             timeoutContext,
+		db:                         db,
+	}
+}
+
+func (u *UnderwritingUsecase) FindAll(ctx *gin.Context, params models.FindAllUnderwritingRuleSetParams) ([]*models.UnderwritingRuleSet, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(params)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingUsecase->FindAll()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.underwritingRepo.FindAll(ctx, params)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->FindAll()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *UnderwritingUsecase) Find(ctx *gin.Context, id string) (*models.UnderwritingRuleSet, *types.Error) {
+	result, err := u.underwritingRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->Find()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *UnderwritingUsecase) Count(ctx *gin.Context, params models.FindAllUnderwritingRuleSetParams) (int, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(params)
+	if errValidation != nil {
+		return 0, &types.Error{
+			Path:       ".UnderwritingUsecase->Count()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.underwritingRepo.Count(ctx, params)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->Count()" + err.Path
+		return 0, err
+	}
+
+	return result, nil
+}
+
+func (u *UnderwritingUsecase) Create(ctx *gin.Context, obj models.UnderwritingRuleSet) (*models.UnderwritingRuleSet, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingUsecase->Create()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	data := models.UnderwritingRuleSet{
+		ID:                  uuid.New().String(),
+		Name:                obj.Name,
+		MinAge:              obj.MinAge,
+		MaxAge:              obj.MaxAge,
+		MinSalary:           obj.MinSalary,
+		MaxDebtServiceRatio: obj.MaxDebtServiceRatio,
+		MaxLimitAmount:      obj.MaxLimitAmount,
+		StatusID:            models.DEFAULT_STATUS_ID,
+	}
+
+	result, err := u.underwritingRepo.Create(ctx, &data)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *UnderwritingUsecase) Update(ctx *gin.Context, id string, obj models.UnderwritingRuleSet) (*models.UnderwritingRuleSet, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(obj)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingUsecase->Update()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	data, err := u.underwritingRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	data.Name = obj.Name
+	data.MinAge = obj.MinAge
+	data.MaxAge = obj.MaxAge
+	data.MinSalary = obj.MinSalary
+	data.MaxDebtServiceRatio = obj.MaxDebtServiceRatio
+	data.MaxLimitAmount = obj.MaxLimitAmount
+
+	result, err := u.underwritingRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->Update()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *UnderwritingUsecase) FindStatus(ctx *gin.Context) ([]*models.Status, *types.Error) {
+	result, err := u.underwritingRepo.FindStatus(ctx)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->FindStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *UnderwritingUsecase) UpdateStatus(ctx *gin.Context, id string, newStatusID string) (*models.UnderwritingRuleSet, *types.Error) {
+	result, err := u.underwritingRepo.UpdateStatus(ctx, id, newStatusID)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->UpdateStatus()" + err.Path
+		return nil, err
+	}
+
+	return result, err
+}
+
+// PROPOSAL
+
+func (u *UnderwritingUsecase) FindAllProposals(ctx *gin.Context, params models.FindAllConsumerCreditLimitProposalParams) ([]*models.ConsumerCreditLimitProposal, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(params)
+	if errValidation != nil {
+		return nil, &types.Error{
+			Path:       ".UnderwritingUsecase->FindAllProposals()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.underwritingRepo.FindAllProposals(ctx, params)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->FindAllProposals()" + err.Path
+		return nil, err
+	}
+
+	for _, v := range result {
+		v.Details, err = u.underwritingRepo.FindProposalDetails(ctx, v.ID)
+		if err != nil {
+			err.Path = ".UnderwritingUsecase->FindAllProposals()" + err.Path
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+func (u *UnderwritingUsecase) FindProposal(ctx *gin.Context, id string) (*models.ConsumerCreditLimitProposal, *types.Error) {
+	result, err := u.underwritingRepo.FindProposal(ctx, id)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->FindProposal()" + err.Path
+		return nil, err
+	}
+
+	result.Details, err = u.underwritingRepo.FindProposalDetails(ctx, result.ID)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->FindProposal()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (u *UnderwritingUsecase) CountProposals(ctx *gin.Context, params models.FindAllConsumerCreditLimitProposalParams) (int, *types.Error) {
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name := strings.SplitN(fld.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+
+	errValidation := validate.Struct(params)
+	if errValidation != nil {
+		return 0, &types.Error{
+			Path:       ".UnderwritingUsecase->CountProposals()",
+			Message:    errValidation.Error(),
+			Error:      errValidation,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	result, err := u.underwritingRepo.CountProposals(ctx, params)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->CountProposals()" + err.Path
+		return 0, err
+	}
+
+	return result, nil
+}
+
+// Propose works out a limit per active loan product from the consumer's salary and age.
+// The matching rule set with the highest salary floor decides how much of the salary may go to installments,
+// what the consumer already pays on open transactions and elsewhere is taken off, and the remaining installment
+// is turned back into the largest principal each product can carry. Products whose tenor would run past the
+// rule set's max age, or whose min amount is out of reach, are proposed at zero.
+func (u *UnderwritingUsecase) Propose(ctx *gin.Context, consumerID string, externalObligationAmount float64) (*models.ConsumerCreditLimitProposal, *types.Error) {
+	if externalObligationAmount < 0 {
+		return nil, &types.Error{
+			Path:       ".UnderwritingUsecase->Propose()",
+			Message:    "External Obligation Amount Invalid",
+			Error:      fmt.Errorf("External Obligation Amount Invalid"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	consumer, err := u.consumerUsecase.Find(ctx, consumerID)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->Propose()" + err.Path
+		return nil, err
+	}
+
+	if consumer.Salary <= 0 {
+		return nil, &types.Error{
+			Path:       ".UnderwritingUsecase->Propose()",
+			Message:    "Consumer Salary Is Required For Underwriting",
+			Error:      fmt.Errorf("Consumer Salary Is Required For Underwriting"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	today := library.UTCPlus7()
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+
+	age := ageOn(consumer.DateOfBirth, today)
+
+	var ruleSetParams models.FindAllUnderwritingRuleSetParams
+	ruleSetParams.Age = age
+	ruleSetParams.Salary = consumer.Salary
+	ruleSetParams.FindAllParams.StatusID = `status_id = "1"`
+	ruleSetParams.FindAllParams.SortBy = "underwriting_rule_sets.min_salary DESC, underwriting_rule_sets.min_age DESC"
+
+	ruleSets, err := u.underwritingRepo.FindAll(ctx, ruleSetParams)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->Propose()" + err.Path
+		return nil, err
+	}
+
+	if len(ruleSets) == 0 {
+		return nil, &types.Error{
+			Path:       ".UnderwritingUsecase->Propose()",
+			Message:    "No Underwriting Rule Set Matches The Consumer",
+			Error:      fmt.Errorf("No Underwriting Rule Set Matches The Consumer"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+	ruleSet := ruleSets[0]
+
+	existingObligationAmount, err := u.underwritingRepo.FindConsumerObligation(ctx, consumerID)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->Propose()" + err.Path
+		return nil, err
+	}
+
+	affordable := library.RoundCurrency(consumer.Salary*ruleSet.MaxDebtServiceRatio/100 - existingObligationAmount - externalObligationAmount)
+	affordable = math.Max(0, affordable)
+
+	var productParams models.FindAllLoanProductParams
+	productParams.ActiveOn = today.Format(library.StrToDateFormat)
+	productParams.FindAllParams.StatusID = `status_id = "1"`
+	productParams.FindAllParams.SortBy = "loan_products.tenor ASC"
+
+	products, err := u.loanproductUsecase.FindAll(ctx, productParams)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->Propose()" + err.Path
+		return nil, err
+	}
+
+	data := models.ConsumerCreditLimitProposal{
+		ID:                          uuid.New().String(),
+		ConsumerID:                  consumerID,
+		UnderwritingRuleSetID:       ruleSet.ID,
+		Salary:                      consumer.Salary,
+		Age:                         age,
+		MaxDebtServiceRatio:         ruleSet.MaxDebtServiceRatio,
+		ExistingObligationAmount:    existingObligationAmount,
+		ExternalObligationAmount:    externalObligationAmount,
+		AffordableInstallmentAmount: affordable,
+		Decision:                    models.CREDIT_LIMIT_PROPOSAL_DECISION_PROPOSED,
+		StatusID:                    models.DEFAULT_STATUS_ID,
+	}
+
+	result, err := u.underwritingRepo.CreateProposal(ctx, &data)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->Propose()" + err.Path
+		return nil, err
+	}
+
+	lastBirthday := consumer.DateOfBirth.AddDate(ruleSet.MaxAge+1, 0, 0)
+	for _, v := range products {
+		limit := 0.0
+		if library.AddMonths(today, v.Tenor).Before(lastBirthday) {
+			limit = maxPrincipal(v, affordable)
+		}
+
+		if ruleSet.MaxLimitAmount > 0 {
+			limit = math.Min(limit, ruleSet.MaxLimitAmount)
+		}
+
+		if v.MaxAmount > 0 {
+			limit = math.Min(limit, v.MaxAmount)
+		}
+
+		if limit < v.MinAmount {
+			limit = 0
+		}
+
+		detail := models.ConsumerCreditLimitProposalDetail{
+			ID:                            uuid.New().String(),
+			ConsumerCreditLimitProposalID: result.ID,
+			LoanProductID:                 v.ID,
+			ProposedLimitAmount:           limit,
+			StatusID:                      models.DEFAULT_STATUS_ID,
+		}
+
+		_, err = u.underwritingRepo.CreateProposalDetail(ctx, &detail)
+		if err != nil {
+			err.Path = ".UnderwritingUsecase->Propose()" + err.Path
+			return nil, err
+		}
+	}
+
+	result.Details, err = u.underwritingRepo.FindProposalDetails(ctx, result.ID)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->Propose()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Accept turns the proposal into the consumer's credit limit as proposed
+func (u *UnderwritingUsecase) Accept(ctx *gin.Context, id string, reason string) (*models.ConsumerCreditLimitProposal, *types.Error) {
+	proposal, err := u.findOpenProposal(ctx, id)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->Accept()" + err.Path
+		return nil, err
+	}
+
+	for _, v := range proposal.Details {
+		v.ApprovedLimitAmount = v.ProposedLimitAmount
+	}
+
+	result, err := u.decide(ctx, proposal, models.CREDIT_LIMIT_PROPOSAL_DECISION_ACCEPTED, reason)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->Accept()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Override creates the credit limit from the analyst's own amounts. Loan products left out keep the proposed amount,
+// and the reason is required so the deviation from underwriting can be traced.
+func (u *UnderwritingUsecase) Override(ctx *gin.Context, id string, details []*models.ConsumerCreditLimitProposalDetail, reason string) (*models.ConsumerCreditLimitProposal, *types.Error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, &types.Error{
+			Path:       ".UnderwritingUsecase->Override()",
+			Message:    "Reason is required",
+			Error:      fmt.Errorf("Reason is required"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	proposal, err := u.findOpenProposal(ctx, id)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->Override()" + err.Path
+		return nil, err
+	}
+
+	overrides := map[string]float64{}
+	for _, v := range details {
+		if v.ApprovedLimitAmount < 0 {
+			return nil, &types.Error{
+				Path:       ".UnderwritingUsecase->Override()",
+				Message:    "Approved Limit Amount Invalid",
+				Error:      fmt.Errorf("Approved Limit Amount Invalid"),
+				Type:       "validation-error",
+				StatusCode: http.StatusUnprocessableEntity,
+			}
+		}
+		overrides[v.LoanProductID] = v.ApprovedLimitAmount
+	}
+
+	for _, v := range proposal.Details {
+		v.ApprovedLimitAmount = v.ProposedLimitAmount
+		if amount, ok := overrides[v.LoanProductID]; ok {
+			v.ApprovedLimitAmount = amount
+			delete(overrides, v.LoanProductID)
+		}
+	}
+
+	if len(overrides) > 0 {
+		return nil, &types.Error{
+			Path:       ".UnderwritingUsecase->Override()",
+			Message:    "Loan Product Is Not Part Of The Proposal",
+			Error:      fmt.Errorf("Loan Product Is Not Part Of The Proposal"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	result, err := u.decide(ctx, proposal, models.CREDIT_LIMIT_PROPOSAL_DECISION_OVERRIDDEN, reason)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->Override()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// findOpenProposal locks the proposal and makes sure nobody has decided on it yet
+func (u *UnderwritingUsecase) findOpenProposal(ctx *gin.Context, id string) (*models.ConsumerCreditLimitProposal, *types.Error) {
+	err := u.underwritingRepo.LockProposal(ctx, id)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->findOpenProposal()" + err.Path
+		return nil, err
+	}
+
+	proposal, err := u.FindProposal(ctx, id)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->findOpenProposal()" + err.Path
+		return nil, err
+	}
+
+	if proposal.Decision != models.CREDIT_LIMIT_PROPOSAL_DECISION_PROPOSED {
+		return nil, &types.Error{
+			Path:       ".UnderwritingUsecase->findOpenProposal()",
+			Message:    "Proposal already decided",
+			Error:      fmt.Errorf("Proposal already decided"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	return proposal, nil
+}
+
+// decide stores the approved amounts, creates the credit limit from them and records the decision on the proposal
+func (u *UnderwritingUsecase) decide(ctx *gin.Context, proposal *models.ConsumerCreditLimitProposal, decision string, reason string) (*models.ConsumerCreditLimitProposal, *types.Error) {
+	limit := models.ConsumerCreditLimit{ConsumerID: proposal.ConsumerID, Reason: reason}
+	if limit.Reason == "" {
+		limit.Reason = fmt.Sprintf("Underwriting proposal %s", decision)
+	}
+	for _, v := range proposal.Details {
+		_, err := u.underwritingRepo.UpdateProposalDetail(ctx, v)
+		if err != nil {
+			err.Path = ".UnderwritingUsecase->decide()" + err.Path
+			return nil, err
+		}
+
+		limit.Details = append(limit.Details, &models.ConsumerCreditLimitDetail{
+			LoanProductID: v.LoanProductID,
+			LimitAmount:   v.ApprovedLimitAmount,
+		})
+	}
+
+	creditLimit, err := u.consumercreditlimitUsecase.Create(ctx, limit)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->decide()" + err.Path
+		return nil, err
+	}
+
+	proposal.Decision = decision
+	proposal.Reason = reason
+	proposal.ConsumerCreditLimitID = creditLimit.ID
+
+	_, err = u.underwritingRepo.UpdateProposal(ctx, proposal)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->decide()" + err.Path
+		return nil, err
+	}
+
+	result, err := u.FindProposal(ctx, proposal.ID)
+	if err != nil {
+		err.Path = ".UnderwritingUsecase->decide()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ageOn returns the age in full years on the given date
+func ageOn(dateOfBirth time.Time, date time.Time) int {
+	age := date.Year() - dateOfBirth.Year()
+	if date.Month() < dateOfBirth.Month() || (date.Month() == dateOfBirth.Month() && date.Day() < dateOfBirth.Day()) {
+		age--
+	}
+
+	return age
+}
+
+// maxPrincipal is the largest principal whose monthly installment, admin fee included, fits in the given amount
+func maxPrincipal(product *models.LoanProduct, installment float64) float64 {
+	tenor := float64(product.Tenor)
+
+	// the installment per unit of principal
+	perUnit := 1/tenor + product.InterestRate/100
+	if product.InterestMethod == library.INTEREST_METHOD_ANNUITY {
+		perUnit = library.AnnuityPayment(1, product.InterestRate, product.Tenor)
+	}
+	perUnit += product.AdminFeeRate / 100 / tenor
+
+	principal := (installment - product.AdminFeeAmount/tenor) / perUnit
+	if principal <= 0 {
+		return 0
+	}
+
+	return math.Floor(principal)
+}
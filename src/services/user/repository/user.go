@@ -60,6 +60,10 @@ func (s UserRepository) FindAll(ctx *gin.Context, params models.FindAllUserParam
 		where += ` AND users.phone_number = :phone_number`
 	}
 
+	if params.RoleID != "" {
+		where += ` AND users.role_id = :role_id`
+	}
+
 	if params.FindAllParams.SortBy != "" {
 		where += fmt.Sprintf(` ORDER BY %s`, params.FindAllParams.SortBy)
 	}
@@ -71,9 +75,10 @@ func (s UserRepository) FindAll(ctx *gin.Context, params models.FindAllUserParam
 	query := fmt.Sprintf(`
   SELECT
     users.id, users.name, users.email, users.username, users.country_calling_code, users.phone_number,
-    users.password, users.status_id, status.name status_name
+    users.password, users.role_id, users.status_id, status.name status_name, IFNULL(roles.name, "") role_name
   FROM users
   JOIN status ON users.status_id = status.id
+  LEFT JOIN roles ON roles.id = users.role_id
   WHERE %s
   `, where)
 
@@ -86,6 +91,7 @@ func (s UserRepository) FindAll(ctx *gin.Context, params models.FindAllUserParam
 		"password":             params.Password,
 		"country_calling_code": params.CountryCallingCode,
 		"phone_number":         params.PhoneNumber,
+		"role_id":              params.RoleID,
 	})
 	if err != nil {
 		return nil, &types.Error{
@@ -106,12 +112,16 @@ func (s UserRepository) FindAll(ctx *gin.Context, params models.FindAllUserParam
 			CountryCallingCode: v.CountryCallingCode,
 			PhoneNumber:        v.PhoneNumber,
 			Password:           v.Password,
+			RoleID:             v.RoleID,
 			StatusID:           v.StatusID,
 			Status: models.Status{
 				ID:   v.StatusID,
 				Name: v.StatusName,
 			},
 		}
+		if v.RoleID != "" {
+			obj.Role = &models.IDNameTemplate{ID: v.RoleID, Name: v.RoleName}
+		}
 		data = append(data, obj)
 	}
 
@@ -126,9 +136,10 @@ func (s UserRepository) Find(ctx *gin.Context, id string) (*models.User, *types.
 	query := `
   SELECT
     users.id, users.name, users.email, users.username, users.country_calling_code, users.phone_number,
-    users.password, users.status_id, status.name status_name
+    users.password, users.role_id, users.status_id, status.name status_name, IFNULL(roles.name, "") role_name
   FROM users
   JOIN status ON users.status_id = status.id
+  LEFT JOIN roles ON roles.id = users.role_id
   WHERE users.id = :id`
 
 	err = s.repository.SelectWithQuery(ctx, &bulks, query, map[string]interface{}{"id": id})
@@ -152,12 +163,16 @@ func (s UserRepository) Find(ctx *gin.Context, id string) (*models.User, *types.
 			CountryCallingCode: v.CountryCallingCode,
 			PhoneNumber:        v.PhoneNumber,
 			Password:           v.Password,
+			RoleID:             v.RoleID,
 			StatusID:           v.StatusID,
 			Status: models.Status{
 				ID:   v.StatusID,
 				Name: v.StatusName,
 			},
 		}
+		if v.RoleID != "" {
+			result.Role = &models.IDNameTemplate{ID: v.RoleID, Name: v.RoleName}
+		}
 	} else {
 		return nil, &types.Error{
 			Path:       ".UserStorage->Find()",
@@ -210,12 +225,17 @@ func (s UserRepository) Count(ctx *gin.Context, params models.FindAllUserParams)
 		where += ` AND users.phone_number = :phone_number`
 	}
 
+	if params.RoleID != "" {
+		where += ` AND users.role_id = :role_id`
+	}
+
 	query := fmt.Sprintf(`
   SELECT
     users.id, users.name, users.email, users.username, users.country_calling_code, users.phone_number,
-    users.password, users.status_id, status.name status_name
+    users.password, users.role_id, users.status_id, status.name status_name, IFNULL(roles.name, "") role_name
   FROM users
   JOIN status ON users.status_id = status.id
+  LEFT JOIN roles ON roles.id = users.role_id
   WHERE %s
   `, where)
 
@@ -228,6 +248,7 @@ func (s UserRepository) Count(ctx *gin.Context, params models.FindAllUserParams)
 		"password":             params.Password,
 		"country_calling_code": params.CountryCallingCode,
 		"phone_number":         params.PhoneNumber,
+		"role_id":              params.RoleID,
 	})
 	if err != nil {
 		return 0, &types.Error{
@@ -18,6 +18,14 @@ type Usecase interface {
 	FindStatus(*gin.Context) ([]*models.Status, *types.Error)
 	UpdateStatus(*gin.Context, string, string) (*models.User, *types.Error)
 
+	UpdateRole(ctx *gin.Context, id string, roleID string) (*models.User, *types.Error)
+
+	// HashLegacyPassword hashes a password stored before passwords were hashed
+	HashLegacyPassword(ctx *gin.Context, id string) (bool, *types.Error)
+
 	// LOGIN
 	Login(*gin.Context, models.FindAllUserParams) (*models.UserJWTContent, *types.Error)
+	Refresh(ctx *gin.Context, refreshToken string) (*models.UserJWTContent, *types.Error)
+	Logout(*gin.Context) *types.Error
+	LogoutAll(*gin.Context) *types.Error
 }
@@ -7,8 +7,12 @@ import (
 	"strings"
 	"time"
 
+	"case-study-kredit-plus/configs"
 	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/appcontext"
+	"case-study-kredit-plus/library/session"
 	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/src/services/role"
 	"case-study-kredit-plus/src/services/user"
 
 	"case-study-kredit-plus/models"
@@ -23,15 +27,19 @@ import (
 
 type UserUsecase struct {
 	userRepo       user.Repository
+	roleUsecase    role.Usecase
+	sessionStore   session.Store
 	contextTimeout time.Duration
 	db             *sqlx.DB
 }
 
-func NewUserUsecase(db *sqlx.DB, userRepo user.Repository) user.Usecase {
+func NewUserUsecase(db *sqlx.DB, userRepo user.Repository, roleUsecase role.Usecase, sessionStore session.Store) user.Usecase {
 	timeoutContext := time.Duration(viper.GetInt("context.timeout")) * time.Second
 
 	return &UserUsecase{
 		userRepo:       userRepo,
+		roleUsecase:    roleUsecase,
+		sessionStore:   sessionStore,
 		contextTimeout: timeoutContext,
 		db:             db,
 	}
@@ -107,6 +115,33 @@ func (u *UserUsecase) Create(ctx *gin.Context, obj models.User) (*models.User, *
 		}
 	}
 
+	errPassword := library.ValidatePassword(obj.Password, obj.Email)
+	if errPassword != nil {
+		return nil, &types.Error{
+			Path:       ".UserUsecase->Create()",
+			Message:    errPassword.Error(),
+			Error:      errPassword,
+			StatusCode: http.StatusUnprocessableEntity,
+			Type:       "validation-error",
+		}
+	}
+
+	_, err = u.roleUsecase.FindActive(ctx, obj.RoleID)
+	if err != nil {
+		err.Path = ".UserUsecase->Create()" + err.Path
+		return nil, err
+	}
+
+	password, errHash := library.HashPassword(obj.Password)
+	if errHash != nil {
+		return nil, &types.Error{
+			Path:       ".UserUsecase->Create()",
+			Message:    "Error Hashing Password",
+			Error:      errHash,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
 	data := models.User{
 		ID:                 uuid.New().String(),
 		Name:               obj.Name,
@@ -114,7 +149,8 @@ func (u *UserUsecase) Create(ctx *gin.Context, obj models.User) (*models.User, *
 		Username:           obj.Username,
 		CountryCallingCode: obj.CountryCallingCode,
 		PhoneNumber:        obj.PhoneNumber,
-		Password:           obj.Password,
+		Password:           password,
+		RoleID:             obj.RoleID,
 		StatusID:           models.DEFAULT_STATUS_ID,
 	}
 
@@ -209,6 +245,53 @@ func (u *UserUsecase) UpdateStatus(ctx *gin.Context, id string, newStatusID stri
 	return result, err
 }
 
+// UpdateRole moves the user to another role, the user gets it with the next refresh of their token
+func (u *UserUsecase) UpdateRole(ctx *gin.Context, id string, roleID string) (*models.User, *types.Error) {
+	_, err := u.roleUsecase.FindActive(ctx, roleID)
+	if err != nil {
+		err.Path = ".UserUsecase->UpdateRole()" + err.Path
+		return nil, err
+	}
+
+	data, err := u.userRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".UserUsecase->UpdateRole()" + err.Path
+		return nil, err
+	}
+
+	// somebody has to be left to manage users and roles
+	if data.RoleID == models.ROLE_ADMIN && roleID != models.ROLE_ADMIN {
+		var adminParams models.FindAllUserParams
+		adminParams.RoleID = models.ROLE_ADMIN
+		adminParams.FindAllParams.StatusID = `status_id = "1"`
+		count, err := u.userRepo.Count(ctx, adminParams)
+		if err != nil {
+			err.Path = ".UserUsecase->UpdateRole()" + err.Path
+			return nil, err
+		}
+
+		if count <= 1 {
+			return nil, &types.Error{
+				Path:       ".UserUsecase->UpdateRole()",
+				Message:    "The Last Admin Cannot Be Moved To Another Role",
+				Error:      fmt.Errorf("The Last Admin Cannot Be Moved To Another Role"),
+				StatusCode: http.StatusUnprocessableEntity,
+				Type:       "validation-error",
+			}
+		}
+	}
+
+	data.RoleID = roleID
+
+	result, err := u.userRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".UserUsecase->UpdateRole()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
 // LOGIN
 
 func (u *UserUsecase) Login(ctx *gin.Context, params models.FindAllUserParams) (*models.UserJWTContent, *types.Error) {
@@ -232,6 +315,10 @@ func (u *UserUsecase) Login(ctx *gin.Context, params models.FindAllUserParams) (
 		}
 	}
 
+	// the user is found by email, the password is checked against the hash
+	password := params.Password
+	params.Password = ""
+
 	result, err := u.userRepo.FindAll(ctx, params)
 	if err != nil {
 		err.Path = ".UserService->Login()" + err.Path
@@ -239,6 +326,10 @@ func (u *UserUsecase) Login(ctx *gin.Context, params models.FindAllUserParams) (
 	}
 
 	if len(result) < 1 {
+		library.CheckNoPassword(password)
+	}
+
+	if len(result) < 1 || !library.CheckPassword(result[0].Password, password) {
 		var err types.Error
 		err.Message = "username atau password salah"
 		err.Type = "authentication"
@@ -247,28 +338,207 @@ func (u *UserUsecase) Login(ctx *gin.Context, params models.FindAllUserParams) (
 		return nil, &err
 	}
 
-	credentials := library.Credential{ID: result[0].ID, Email: result[0].Email, Type: "Web"}
+	if !library.IsPasswordCurrent(result[0].Password) {
+		err = u.rehashPassword(ctx, result[0], password)
+		if err != nil {
+			err.Path = ".UserService->Login()" + err.Path
+			return nil, err
+		}
+	}
+
+	userLogin, err := u.issueTokens(result[0], uuid.New().String(), time.Now())
+	if err != nil {
+		err.Path = ".UserService->Login()" + err.Path
+		return nil, err
+	}
+
+	return userLogin, nil
+}
+
+// Refresh hands out a new access token and refresh token for the refresh token, which cannot be used again.
+// A refresh token that is used twice was stolen or replayed, its session is ended.
+func (u *UserUsecase) Refresh(ctx *gin.Context, refreshToken string) (*models.UserJWTContent, *types.Error) {
+	token, errStore := u.sessionStore.UseRefreshToken(session.HashRefreshToken(refreshToken))
+	if errStore == session.ErrRefreshTokenReused {
+		errRevoke := u.sessionStore.RevokeSession(token.SessionID, time.Now().Add(u.sessionTimeOut()))
+		if errRevoke != nil {
+			return nil, &types.Error{
+				Path:       ".UserUsecase->Refresh()",
+				Message:    "Error Revoking Session",
+				Error:      errRevoke,
+				StatusCode: http.StatusInternalServerError,
+			}
+		}
+
+		return nil, &types.Error{
+			Path:       ".UserUsecase->Refresh()",
+			Message:    "Refresh Token Was Already Used, Please Log In Again",
+			Error:      errStore,
+			Type:       "authentication",
+			StatusCode: http.StatusUnauthorized,
+		}
+	}
+	if errStore == session.ErrRefreshTokenNotFound {
+		return nil, &types.Error{
+			Path:       ".UserUsecase->Refresh()",
+			Message:    "Refresh Token Invalid",
+			Error:      errStore,
+			Type:       "authentication",
+			StatusCode: http.StatusUnauthorized,
+		}
+	}
+	if errStore != nil {
+		return nil, &types.Error{
+			Path:       ".UserUsecase->Refresh()",
+			Message:    "Error Reading Refresh Token",
+			Error:      errStore,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	revoked, errStore := session.Revoked(u.sessionStore, token.SessionID, token.UserID, token.LoginTime)
+	if errStore != nil {
+		return nil, &types.Error{
+			Path:       ".UserUsecase->Refresh()",
+			Message:    "Error Reading Session",
+			Error:      errStore,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	if revoked {
+		return nil, &types.Error{
+			Path:       ".UserUsecase->Refresh()",
+			Message:    "Session Has Ended, Please Log In Again",
+			Error:      fmt.Errorf("session revoked"),
+			Type:       "authentication",
+			StatusCode: http.StatusUnauthorized,
+		}
+	}
+
+	// the user is read again, so a deactivated user is let go and a new role is picked up
+	data, err := u.userRepo.Find(ctx, token.UserID)
+	if err != nil {
+		err.Path = ".UserUsecase->Refresh()" + err.Path
+		return nil, err
+	}
+
+	if data.StatusID != models.STATUS_ACTIVE {
+		return nil, &types.Error{
+			Path:       ".UserUsecase->Refresh()",
+			Message:    "User Is Not Active",
+			Error:      fmt.Errorf("user is not active"),
+			Type:       "authentication",
+			StatusCode: http.StatusUnauthorized,
+		}
+	}
+
+	result, err := u.issueTokens(data, token.SessionID, token.LoginTime)
+	if err != nil {
+		err.Path = ".UserUsecase->Refresh()" + err.Path
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// Logout ends the session of the current access token, with the refresh tokens handed out in it
+func (u *UserUsecase) Logout(ctx *gin.Context) *types.Error {
+	errStore := u.sessionStore.RevokeSession(appcontext.LoginSessionID(ctx), time.Now().Add(u.sessionTimeOut()))
+	if errStore != nil {
+		return &types.Error{
+			Path:       ".UserUsecase->Logout()",
+			Message:    "Error Revoking Session",
+			Error:      errStore,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	return nil
+}
+
+// LogoutAll ends every session of the current user, on every device
+func (u *UserUsecase) LogoutAll(ctx *gin.Context) *types.Error {
+	now := time.Now()
+
+	errStore := u.sessionStore.RevokeUser(*appcontext.UserID(ctx), now, now.Add(u.sessionTimeOut()))
+	if errStore != nil {
+		return &types.Error{
+			Path:       ".UserUsecase->LogoutAll()",
+			Message:    "Error Revoking Sessions",
+			Error:      errStore,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	return nil
+}
+
+// issueTokens signs an access token for the session and hands out the next refresh token of it
+func (u *UserUsecase) issueTokens(data *models.User, sessionID string, loginTime time.Time) (*models.UserJWTContent, *types.Error) {
+	credentials := library.Credential{ID: data.ID, Email: data.Email, Type: "Web", RoleID: data.RoleID, SessionID: sessionID}
 
 	token, errorJwtSign := library.JwtSignString(credentials)
 	if errorJwtSign != nil {
 		return nil, &types.Error{
 			Error:      errorJwtSign,
 			Message:    "Error JWT Sign String",
-			Path:       ".UserService->Login()",
+			Path:       ".UserUsecase->issueTokens()",
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	refreshToken, hash, errToken := session.NewRefreshToken()
+	if errToken == nil {
+		errToken = u.sessionStore.SaveRefreshToken(hash, session.RefreshToken{
+			SessionID: sessionID,
+			UserID:    data.ID,
+			LoginTime: loginTime,
+			ExpiresAt: time.Now().Add(u.refreshTokenTimeOut()),
+		})
+	}
+	if errToken != nil {
+		return nil, &types.Error{
+			Error:      errToken,
+			Message:    "Error Storing Refresh Token",
+			Path:       ".UserUsecase->issueTokens()",
 			StatusCode: http.StatusInternalServerError,
 		}
 	}
 
 	var userLogin models.UserJWTContent
-	userLogin.ID = result[0].ID
-	userLogin.Name = result[0].Name
+	userLogin.ID = data.ID
+	userLogin.Name = data.Name
 	userLogin.Token = token
-	userLogin.Email = result[0].Email
-	userLogin.StatusID = result[0].StatusID
+	userLogin.RefreshToken = refreshToken
+	userLogin.Email = data.Email
+	userLogin.RoleID = data.RoleID
+	userLogin.StatusID = data.StatusID
 
 	return &userLogin, nil
 }
 
+func (u *UserUsecase) refreshTokenTimeOut() time.Duration {
+	config, err := configs.GetConfiguration()
+	if err != nil {
+		return time.Duration(session.DEFAULT_REFRESH_TOKEN_TIME_OUT) * time.Second
+	}
+
+	return session.RefreshTokenTimeOut(config)
+}
+
+// sessionTimeOut is how long a token of a session can live, a revocation is kept that long
+func (u *UserUsecase) sessionTimeOut() time.Duration {
+	timeOut := u.refreshTokenTimeOut()
+
+	config, err := configs.GetConfiguration()
+	if err == nil && time.Duration(config.JwtTimeOut)*time.Second > timeOut {
+		timeOut = time.Duration(config.JwtTimeOut) * time.Second
+	}
+
+	return timeOut
+}
+
 // //
 
 // UpdatePassword()  Updates the password of the user
@@ -299,7 +569,7 @@ func (u *UserUsecase) UpdatePassword(ctx *gin.Context, obj models.UserUpdatePass
 		return nil, err
 	}
 
-	if data.Password != obj.OldPassword {
+	if !library.CheckPassword(data.Password, obj.OldPassword) {
 		err = &types.Error{
 			Path:       ".UserUsecase->UpdatePassword()",
 			Message:    "The erstwhile password fails to harmonize with the current, necessitating adjustment",
@@ -310,7 +580,38 @@ func (u *UserUsecase) UpdatePassword(ctx *gin.Context, obj models.UserUpdatePass
 		return nil, err
 	}
 
-	data.Password = obj.NewPassword
+	if obj.NewPassword != obj.NewPasswordConfirm {
+		return nil, &types.Error{
+			Path:       ".UserUsecase->UpdatePassword()",
+			Message:    "New password confirmation does not match",
+			Error:      fmt.Errorf("new password confirmation does not match"),
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	errPassword := library.ValidatePassword(obj.NewPassword, data.Email)
+	if errPassword != nil {
+		return nil, &types.Error{
+			Path:       ".UserUsecase->UpdatePassword()",
+			Message:    errPassword.Error(),
+			Error:      errPassword,
+			Type:       "validation-error",
+			StatusCode: http.StatusUnprocessableEntity,
+		}
+	}
+
+	password, errHash := library.HashPassword(obj.NewPassword)
+	if errHash != nil {
+		return nil, &types.Error{
+			Path:       ".UserUsecase->UpdatePassword()",
+			Message:    "Error Hashing Password",
+			Error:      errHash,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	data.Password = password
 
 	result, err := u.userRepo.Update(ctx, data)
 	if err != nil {
@@ -320,3 +621,63 @@ func (u *UserUsecase) UpdatePassword(ctx *gin.Context, obj models.UserUpdatePass
 
 	return result, err
 }
+
+// HashLegacyPassword hashes the stored password of a user who has not logged in since passwords were hashed.
+// It returns false when the password was already hashed.
+func (u *UserUsecase) HashLegacyPassword(ctx *gin.Context, id string) (bool, *types.Error) {
+	data, err := u.userRepo.Find(ctx, id)
+	if err != nil {
+		err.Path = ".UserUsecase->HashLegacyPassword()" + err.Path
+		return false, err
+	}
+
+	if library.IsPasswordCurrent(data.Password) || strings.HasPrefix(data.Password, library.PASSWORD_MD5_PREFIX) {
+		return false, nil
+	}
+
+	password, errHash := library.HashLegacyPassword(data.Password)
+	if errHash != nil {
+		return false, &types.Error{
+			Path:       ".UserUsecase->HashLegacyPassword()",
+			Message:    "Error Hashing Password",
+			Error:      errHash,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	data.Password = password
+
+	_, err = u.userRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".UserUsecase->HashLegacyPassword()" + err.Path
+		return false, err
+	}
+
+	return true, nil
+}
+
+// rehashPassword replaces a legacy or outdated stored password with a current hash, right after a successful login
+func (u *UserUsecase) rehashPassword(ctx *gin.Context, data *models.User, password string) *types.Error {
+	hash, errHash := library.HashPassword(password)
+	if errHash != nil {
+		return &types.Error{
+			Path:       ".UserUsecase->rehashPassword()",
+			Message:    "Error Hashing Password",
+			Error:      errHash,
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+
+	// the login has no session yet, the record is updated by the user logging in
+	ctx.Set("UserID", data.ID)
+
+	data.Password = hash
+
+	_, err := u.userRepo.Update(ctx, data)
+	if err != nil {
+		err.Path = ".UserUsecase->rehashPassword()" + err.Path
+		return err
+	}
+
+	return nil
+}
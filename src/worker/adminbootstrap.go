@@ -0,0 +1,92 @@
+package worker
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/session"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+	"case-study-kredit-plus/src/services/user"
+
+	roleRepository "case-study-kredit-plus/src/services/role/repository"
+	roleUsecase "case-study-kredit-plus/src/services/role/usecase"
+	userRepository "case-study-kredit-plus/src/services/user/repository"
+	userUsecase "case-study-kredit-plus/src/services/user/usecase"
+)
+
+// AdminBootstrap gives the admin role to a user from the command line. Registering users needs an admin,
+// so this is how the first one comes to be, on a new database or right after roles were introduced.
+type AdminBootstrap struct {
+	UserUsecase user.Usecase
+	dataManager *data.Manager
+}
+
+func NewAdminBootstrap(db *sqlx.DB, dataManager *data.Manager) *AdminBootstrap {
+	userRepo := userRepository.NewUserRepository(
+		data.NewMySQLStorage(db, "users", models.User{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	roleRepo := roleRepository.NewRoleRepository(
+		data.NewMySQLStorage(db, "roles", models.Role{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "role_permissions", models.RolePermission{}, data.MysqlConfig{}),
+	)
+
+	uRole := roleUsecase.NewRoleUsecase(db, &roleRepo)
+	uUser := userUsecase.NewUserUsecase(db, &userRepo, uRole, session.FromConfiguration())
+
+	return &AdminBootstrap{UserUsecase: uUser, dataManager: dataManager}
+}
+
+// Run makes the active user with the email an admin. When there is none, a user is registered with the name and
+// password, which are only needed in that case.
+func (w *AdminBootstrap) Run(email string, name string, password string) {
+	// the record writes the audit trail, which needs a user, "0" is the system
+	ctx := &gin.Context{}
+	ctx.Set("UserID", "0")
+
+	var params models.FindAllUserParams
+	params.Email = email
+	params.FindAllParams.StatusID = `status_id = "1"`
+
+	var result *models.User
+	errTransaction := w.dataManager.RunInTransaction(ctx, func(tctx *gin.Context) *types.Error {
+		users, err := w.UserUsecase.FindAll(tctx, params)
+		if err != nil && err.Error != data.ErrNotFound {
+			return err
+		}
+
+		if len(users) > 0 {
+			result, err = w.UserUsecase.UpdateRole(tctx, users[0].ID, models.ROLE_ADMIN)
+			return err
+		}
+
+		if name == "" || password == "" {
+			return &types.Error{
+				Path:    ".AdminBootstrap->Run()",
+				Message: "No active user has this email, a name and a password are needed to register one",
+				Error:   fmt.Errorf("name or password missing"),
+			}
+		}
+
+		result, err = w.UserUsecase.Create(tctx, models.User{
+			Name:     name,
+			Email:    email,
+			Username: name,
+			Password: password,
+			RoleID:   models.ROLE_ADMIN,
+		})
+		return err
+	})
+	if errTransaction != nil {
+		fmt.Printf("\n[AdminBootstrap - Run] Error: %v\n", errTransaction.Message)
+		return
+	}
+
+	fmt.Printf("\n[AdminBootstrap - Run] %s (%s) is an admin\n", result.Email, result.ID)
+}
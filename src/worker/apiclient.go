@@ -0,0 +1,179 @@
+package worker
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+	"case-study-kredit-plus/src/services/apiclient"
+
+	apiclientRepository "case-study-kredit-plus/src/services/apiclient/repository"
+	apiclientUsecase "case-study-kredit-plus/src/services/apiclient/usecase"
+)
+
+// APIClientCommand manages API clients from the command line, the same as the /api-clients endpoints:
+//
+//	api-client create <name> <scope,scope,...> [expires at]
+//	api-client rotate <id>
+//	api-client expire <id> <expires at|never>
+//	api-client revoke <id>
+//	api-client scopes <id> <scope,scope,...>
+//
+// Expires at is a timestamp, or a date meaning the end of that day. New tokens are printed once.
+type APIClientCommand struct {
+	APIClientUsecase apiclient.Usecase
+	dataManager      *data.Manager
+}
+
+func NewAPIClientCommand(db *sqlx.DB, dataManager *data.Manager) *APIClientCommand {
+	apiclientRepo := apiclientRepository.NewAPIClientRepository(
+		data.NewMySQLStorage(db, "api_client", models.APIClient{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "api_client_scopes", models.APIClientScope{}, data.MysqlConfig{}),
+	)
+
+	uAPIClient := apiclientUsecase.NewAPIClientUsecase(db, &apiclientRepo)
+
+	return &APIClientCommand{APIClientUsecase: uAPIClient, dataManager: dataManager}
+}
+
+func (w *APIClientCommand) Run(args []string) {
+	// the records write the audit trail, which needs a user, "0" is the system
+	ctx := &gin.Context{}
+	ctx.Set("UserID", "0")
+
+	var result *models.APIClient
+	errTransaction := w.dataManager.RunInTransaction(ctx, func(tctx *gin.Context) *types.Error {
+		var err *types.Error
+		result, err = w.run(tctx, args)
+		return err
+	})
+	if errTransaction != nil {
+		fmt.Printf("\n[APIClientCommand - Run] Error: %v\n", errTransaction.Message)
+		return
+	}
+
+	fmt.Printf("\n[APIClientCommand - Run] API client %d (%s), status %s\n", result.ID, result.Name, result.StatusID)
+	if result.ExpiresAt != nil {
+		fmt.Printf("[APIClientCommand - Run] Expires at %s\n", result.ExpiresAt.Format(library.StrToTimestampFormat))
+	}
+	if result.Token != "" {
+		fmt.Printf("[APIClientCommand - Run] Token, shown only this once: %s\n", result.Token)
+	}
+}
+
+func (w *APIClientCommand) run(ctx *gin.Context, args []string) (*models.APIClient, *types.Error) {
+	switch {
+	case len(args) >= 3 && args[0] == "create":
+		obj := models.APIClient{Name: args[1]}
+		for _, v := range strings.Split(args[2], ",") {
+			obj.Scopes = append(obj.Scopes, &models.APIClientScope{Scope: strings.TrimSpace(v)})
+		}
+
+		if len(args) > 3 {
+			expiresAt, err := parseExpiresAt(args[3])
+			if err != nil {
+				return nil, err
+			}
+			obj.ExpiresAt = expiresAt
+		}
+
+		return w.APIClientUsecase.Create(ctx, obj)
+
+	case len(args) == 2 && args[0] == "rotate":
+		id, err := parseAPIClientID(args[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return w.APIClientUsecase.Rotate(ctx, id)
+
+	case len(args) == 3 && args[0] == "expire":
+		id, err := parseAPIClientID(args[1])
+		if err != nil {
+			return nil, err
+		}
+
+		var expiresAt *time.Time
+		if args[2] != "never" {
+			expiresAt, err = parseExpiresAt(args[2])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return w.APIClientUsecase.Expire(ctx, id, expiresAt)
+
+	case len(args) == 2 && args[0] == "revoke":
+		id, err := parseAPIClientID(args[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return w.APIClientUsecase.Revoke(ctx, id)
+
+	case len(args) == 3 && args[0] == "scopes":
+		id, err := parseAPIClientID(args[1])
+		if err != nil {
+			return nil, err
+		}
+
+		data, err := w.APIClientUsecase.Find(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		// the scopes given replace the ones the client has, the name and expiry are kept
+		obj := models.APIClient{Name: data.Name, ExpiresAt: data.ExpiresAt}
+		for _, v := range strings.Split(args[2], ",") {
+			obj.Scopes = append(obj.Scopes, &models.APIClientScope{Scope: strings.TrimSpace(v)})
+		}
+
+		return w.APIClientUsecase.Update(ctx, id, obj)
+	}
+
+	return nil, &types.Error{
+		Path:    ".APIClientCommand->run()",
+		Message: "Usage: api-client create <name> <scope,scope,...> [expires at] | rotate <id> | expire <id> <expires at|never> | revoke <id> | scopes <id> <scope,scope,...>",
+		Error:   fmt.Errorf("unknown command %v", args),
+	}
+}
+
+func parseAPIClientID(value string) (int, *types.Error) {
+	id, errParseInt := strconv.Atoi(value)
+	if errParseInt != nil {
+		return 0, &types.Error{
+			Path:    ".APIClientCommand->parseAPIClientID()",
+			Message: "API Client ID Invalid",
+			Error:   errParseInt,
+		}
+	}
+
+	return id, nil
+}
+
+func parseExpiresAt(value string) (*time.Time, *types.Error) {
+	expiresAt, errParseTime := time.Parse(library.StrToTimestampFormat, value)
+	if errParseTime != nil {
+		expiresAt, errParseTime = time.Parse(library.StrToDateFormat, value)
+		if errParseTime != nil {
+			return nil, &types.Error{
+				Path:    ".APIClientCommand->parseExpiresAt()",
+				Message: "Expires At Invalid",
+				Error:   errParseTime,
+			}
+		}
+
+		expiresAt = expiresAt.Add(24*time.Hour - time.Second)
+	}
+
+	return &expiresAt, nil
+}
@@ -0,0 +1,66 @@
+package worker
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/fieldcrypt"
+	"case-study-kredit-plus/library/filestorage"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+	"case-study-kredit-plus/src/services/consumer"
+
+	consumerRepository "case-study-kredit-plus/src/services/consumer/repository"
+	consumerUsecase "case-study-kredit-plus/src/services/consumer/usecase"
+)
+
+// ConsumerDocumentBackfill moves identity documents uploaded as public files to private storage
+type ConsumerDocumentBackfill struct {
+	ConsumerUsecase consumer.Usecase
+	dataManager     *data.Manager
+}
+
+func NewConsumerDocumentBackfill(db *sqlx.DB, dataManager *data.Manager) *ConsumerDocumentBackfill {
+	consumerRepo := consumerRepository.NewConsumerRepository(
+		data.NewMySQLStorage(db, "consumers", models.ConsumerEncrypted{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		fieldcrypt.NewFromConfiguration(),
+	)
+
+	uConsumer := consumerUsecase.NewConsumerUsecase(db, &consumerRepo, filestorage.NewFromConfiguration())
+
+	return &ConsumerDocumentBackfill{ConsumerUsecase: uConsumer, dataManager: dataManager}
+}
+
+// Run privatizes every consumer still holding a document URL, consumers already holding keys are passed over. Each consumer is committed on its own,
+// so it can be run again to pick up the ones that failed.
+func (w *ConsumerDocumentBackfill) Run() {
+	var params models.FindAllConsumerParams
+	params.FindAllParams.DataFinder = `(consumers.ktp_img_key <> "" OR consumers.selfie_img_key <> "")`
+
+	consumers, err := w.ConsumerUsecase.FindAll(&gin.Context{}, params)
+	if err != nil && err.Error != data.ErrNotFound {
+		fmt.Printf("\n[ConsumerDocumentBackfill - Run] Error: %v\n", err.Message)
+		return
+	}
+
+	failed := 0
+	for _, v := range consumers {
+		// the record update writes the audit trail, which needs a user, "0" is the system
+		ctx := &gin.Context{}
+		ctx.Set("UserID", "0")
+
+		err := w.dataManager.RunInTransaction(ctx, func(tctx *gin.Context) *types.Error {
+			return w.ConsumerUsecase.PrivatizeDocuments(tctx, v.ID)
+		})
+		if err != nil {
+			failed++
+			fmt.Printf("\n[ConsumerDocumentBackfill - Run] Consumer %s Error: %v\n", v.ID, err.Message)
+		}
+	}
+
+	fmt.Printf("\n[ConsumerDocumentBackfill - Run] %d consumers privatized, %d failed\n", len(consumers)-failed, failed)
+}
@@ -0,0 +1,87 @@
+package worker
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/fieldcrypt"
+	"case-study-kredit-plus/library/filestorage"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+	"case-study-kredit-plus/src/services/consumer"
+
+	consumerRepository "case-study-kredit-plus/src/services/consumer/repository"
+	consumerUsecase "case-study-kredit-plus/src/services/consumer/usecase"
+)
+
+// ConsumerReencryption rewrites the encrypted consumer columns under the current keys, after a key rotation
+// or to encrypt rows written before encryption
+type ConsumerReencryption struct {
+	ConsumerUsecase consumer.Usecase
+	dataManager     *data.Manager
+}
+
+func NewConsumerReencryption(db *sqlx.DB, dataManager *data.Manager) *ConsumerReencryption {
+	consumerRepo := consumerRepository.NewConsumerRepository(
+		data.NewMySQLStorage(db, "consumers", models.ConsumerEncrypted{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		fieldcrypt.NewFromConfiguration(),
+	)
+
+	uConsumer := consumerUsecase.NewConsumerUsecase(db, &consumerRepo, filestorage.NewFromConfiguration())
+
+	return &ConsumerReencryption{ConsumerUsecase: uConsumer, dataManager: dataManager}
+}
+
+// Run goes through every consumer, each one committed on its own. The old keys must stay configured until
+// it reports no failures.
+func (w *ConsumerReencryption) Run() {
+	w.run(models.FindAllConsumerParams{})
+}
+
+// RunUnindexed encrypts and indexes the consumers stored before encryption, which have no NIK blind index yet
+// and so are missed by the duplicate NIK check and by NIK lookups. It reports whether none are left.
+func (w *ConsumerReencryption) RunUnindexed() bool {
+	var params models.FindAllConsumerParams
+	params.FindAllParams.DataFinder = `consumers.nik_index = ""`
+
+	return w.run(params)
+}
+
+func (w *ConsumerReencryption) run(params models.FindAllConsumerParams) bool {
+	consumers, err := w.ConsumerUsecase.FindAll(&gin.Context{}, params)
+	if err != nil && err.Error != data.ErrNotFound {
+		fmt.Printf("\n[ConsumerReencryption - Run] Error: %v\n", err.Message)
+		return false
+	}
+
+	written, failed := 0, 0
+	for _, v := range consumers {
+		// the record update writes the audit trail, which needs a user, "0" is the system
+		ctx := &gin.Context{}
+		ctx.Set("UserID", "0")
+
+		var ok bool
+		err := w.dataManager.RunInTransaction(ctx, func(tctx *gin.Context) *types.Error {
+			var err *types.Error
+			ok, err = w.ConsumerUsecase.Reencrypt(tctx, v.ID)
+			return err
+		})
+		if err != nil {
+			failed++
+			fmt.Printf("\n[ConsumerReencryption - Run] Consumer %s Error: %v\n", v.ID, err.Message)
+			continue
+		}
+
+		if ok {
+			written++
+		}
+	}
+
+	fmt.Printf("\n[ConsumerReencryption - Run] %d consumers re-encrypted, %d already current, %d failed\n", written, len(consumers)-written-failed, failed)
+
+	return failed == 0
+}
@@ -0,0 +1,74 @@
+package worker
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+
+	"case-study-kredit-plus/library"
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+	"case-study-kredit-plus/src/services/consumerinstallment"
+
+	consumerinstallmentRepository "case-study-kredit-plus/src/services/consumerinstallment/repository"
+	consumerinstallmentUsecase "case-study-kredit-plus/src/services/consumerinstallment/usecase"
+)
+
+// LateFeeWorker brings days past due and late fees of every open transaction up to date once a day
+type LateFeeWorker struct {
+	ConsumerInstallmentUsecase consumerinstallment.Usecase
+	dataManager                *data.Manager
+}
+
+func NewLateFeeWorker(db *sqlx.DB, dataManager *data.Manager) *LateFeeWorker {
+	consumerinstallmentRepo := consumerinstallmentRepository.NewConsumerInstallmentRepository(
+		data.NewMySQLStorage(db, "consumer_installments", models.ConsumerInstallment{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	uConsumerInstallment := consumerinstallmentUsecase.NewConsumerInstallmentUsecase(db, &consumerinstallmentRepo)
+
+	return &LateFeeWorker{ConsumerInstallmentUsecase: uConsumerInstallment, dataManager: dataManager}
+}
+
+// Start runs the accrual right away, then every day shortly after midnight
+func (w *LateFeeWorker) Start() {
+	for {
+		w.Run(library.UTCPlus7())
+
+		now := library.UTCPlus7()
+		next := time.Date(now.Year(), now.Month(), now.Day(), 0, 5, 0, 0, now.Location()).AddDate(0, 0, 1)
+		time.Sleep(next.Sub(now))
+	}
+}
+
+// Run accrues every overdue transaction as of the given date. Each transaction is committed on its own,
+// so one failing transaction does not hold back the rest.
+func (w *LateFeeWorker) Run(asOf time.Time) {
+	ids, err := w.ConsumerInstallmentUsecase.FindOverdueConsumerTransactionIDs(&gin.Context{}, asOf)
+	if err != nil {
+		fmt.Printf("\n[LateFeeWorker - Run] Error: %v\n", err.Message)
+		return
+	}
+
+	failed := 0
+	for _, id := range ids {
+		err := w.dataManager.RunInTransaction(&gin.Context{}, func(tctx *gin.Context) *types.Error {
+			err := w.ConsumerInstallmentUsecase.LockConsumerTransaction(tctx, id)
+			if err != nil {
+				return err
+			}
+
+			return w.ConsumerInstallmentUsecase.AccrueLateFees(tctx, id, asOf)
+		})
+		if err != nil {
+			failed++
+			fmt.Printf("\n[LateFeeWorker - Run] Consumer Transaction %s Error: %v\n", id, err.Message)
+		}
+	}
+
+	fmt.Printf("\n[LateFeeWorker - Run] %s: %d transactions accrued, %d failed\n", asOf.Format("2006-01-02"), len(ids)-failed, failed)
+}
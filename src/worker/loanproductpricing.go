@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+	"case-study-kredit-plus/src/services/loanproduct"
+
+	loanproductRepository "case-study-kredit-plus/src/services/loanproduct/repository"
+	loanproductUsecase "case-study-kredit-plus/src/services/loanproduct/usecase"
+)
+
+// LoanProductPricing prices the loan products seeded by the migrations with the rates that were configured before
+// loan products, INTEREST_METHOD and INTEREST_RATES. The seeded products start inactive with no rate, so no loan is
+// booked at 0% in the meantime.
+type LoanProductPricing struct {
+	LoanProductUsecase loanproduct.Usecase
+	dataManager        *data.Manager
+}
+
+func NewLoanProductPricing(db *sqlx.DB, dataManager *data.Manager) *LoanProductPricing {
+	loanproductRepo := loanproductRepository.NewLoanProductRepository(
+		data.NewMySQLStorage(db, "loan_products", models.LoanProduct{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	uLoanProduct := loanproductUsecase.NewLoanProductUsecase(db, &loanproductRepo)
+
+	return &LoanProductPricing{LoanProductUsecase: uLoanProduct, dataManager: dataManager}
+}
+
+// Run prices and activates every seeded product nobody has touched yet whose tenor has a configured rate. The ones
+// without a rate are reported and stay inactive, bookings on their tenor are refused until they are priced through
+// /loan-products. Once every seeded product is priced it finds nothing to do.
+func (w *LoanProductPricing) Run(interestMethod string, interestRates map[int]float64) {
+	var params models.FindAllLoanProductParams
+	params.FindAllParams.DataFinder = `loan_products.created_by IS NULL AND loan_products.updated_by IS NULL AND loan_products.interest_rate = 0`
+	params.FindAllParams.StatusID = `status_id = "0"`
+
+	products, err := w.LoanProductUsecase.FindAll(&gin.Context{}, params)
+	if err != nil && err.Error != data.ErrNotFound {
+		fmt.Printf("\n[LoanProductPricing - Run] Error: %v\n", err.Message)
+		return
+	}
+
+	for _, v := range products {
+		rate, ok := interestRates[v.Tenor]
+		if !ok {
+			fmt.Printf("\n[LoanProductPricing - Run] Loan product %s has no rate in INTEREST_RATES, it stays inactive until it is priced through /loan-products\n", v.Name)
+			continue
+		}
+
+		// the record update writes the audit trail, which needs a user, "0" is the system
+		ctx := &gin.Context{}
+		ctx.Set("UserID", "0")
+
+		err := w.dataManager.RunInTransaction(ctx, func(tctx *gin.Context) *types.Error {
+			obj := *v
+			obj.InterestMethod = interestMethod
+			obj.InterestRate = rate
+
+			_, err := w.LoanProductUsecase.Update(tctx, v.ID, obj)
+			if err != nil {
+				return err
+			}
+
+			_, err = w.LoanProductUsecase.UpdateStatus(tctx, v.ID, models.STATUS_ACTIVE)
+			return err
+		})
+		if err != nil {
+			fmt.Printf("\n[LoanProductPricing - Run] Loan product %s Error: %v\n", v.Name, err.Message)
+			continue
+		}
+
+		fmt.Printf("\n[LoanProductPricing - Run] Loan product %s priced at %v%% a month, %s\n", v.Name, rate, interestMethod)
+	}
+}
@@ -0,0 +1,78 @@
+package worker
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+
+	"case-study-kredit-plus/library/data"
+	"case-study-kredit-plus/library/session"
+	"case-study-kredit-plus/library/types"
+	"case-study-kredit-plus/models"
+	"case-study-kredit-plus/src/services/user"
+
+	roleRepository "case-study-kredit-plus/src/services/role/repository"
+	roleUsecase "case-study-kredit-plus/src/services/role/usecase"
+	userRepository "case-study-kredit-plus/src/services/user/repository"
+	userUsecase "case-study-kredit-plus/src/services/user/usecase"
+)
+
+// PasswordHash hashes the passwords stored before passwords were hashed, for the users who have not logged in
+// since. Logging in does the same for one user.
+type PasswordHash struct {
+	UserUsecase user.Usecase
+	dataManager *data.Manager
+}
+
+func NewPasswordHash(db *sqlx.DB, dataManager *data.Manager) *PasswordHash {
+	userRepo := userRepository.NewUserRepository(
+		data.NewMySQLStorage(db, "users", models.User{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+	)
+
+	roleRepo := roleRepository.NewRoleRepository(
+		data.NewMySQLStorage(db, "roles", models.Role{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "status", models.Status{}, data.MysqlConfig{}),
+		data.NewMySQLStorage(db, "role_permissions", models.RolePermission{}, data.MysqlConfig{}),
+	)
+
+	uRole := roleUsecase.NewRoleUsecase(db, &roleRepo)
+	uUser := userUsecase.NewUserUsecase(db, &userRepo, uRole, session.FromConfiguration())
+
+	return &PasswordHash{UserUsecase: uUser, dataManager: dataManager}
+}
+
+// Run goes through every user, each one committed on its own. It can be run again until it reports no failures.
+func (w *PasswordHash) Run() {
+	users, err := w.UserUsecase.FindAll(&gin.Context{}, models.FindAllUserParams{})
+	if err != nil && err.Error != data.ErrNotFound {
+		fmt.Printf("\n[PasswordHash - Run] Error: %v\n", err.Message)
+		return
+	}
+
+	hashed, failed := 0, 0
+	for _, v := range users {
+		// the record update writes the audit trail, which needs a user, "0" is the system
+		ctx := &gin.Context{}
+		ctx.Set("UserID", "0")
+
+		var ok bool
+		err := w.dataManager.RunInTransaction(ctx, func(tctx *gin.Context) *types.Error {
+			var err *types.Error
+			ok, err = w.UserUsecase.HashLegacyPassword(tctx, v.ID)
+			return err
+		})
+		if err != nil {
+			failed++
+			fmt.Printf("\n[PasswordHash - Run] User %s Error: %v\n", v.ID, err.Message)
+			continue
+		}
+
+		if ok {
+			hashed++
+		}
+	}
+
+	fmt.Printf("\n[PasswordHash - Run] %d passwords hashed, %d already hashed, %d failed\n", hashed, len(users)-hashed-failed, failed)
+}